@@ -3,6 +3,7 @@ package main
 import (
     "bytes"
     "context"
+    "encoding/json"
     "errors"
     "flag"
     "fmt"
@@ -10,8 +11,11 @@ import (
     "os"
     "path/filepath"
     "reflect"
+    "runtime"
     "sort"
+    "strconv"
     "strings"
+    "text/tabwriter"
     "time"
 
     "github.com/rs/zerolog"
@@ -20,6 +24,8 @@ import (
     "syscall"
 
     "github.com/hyperifyio/goresearch/internal/app"
+    "github.com/hyperifyio/goresearch/internal/fetch"
+    "github.com/hyperifyio/goresearch/internal/sourcetype"
     "github.com/hyperifyio/goresearch/internal/synth"
 )
 
@@ -58,32 +64,25 @@ func main() {
         return
     }
 
+    // Subcommand: goresearch search — run one query through search and selection
+    if len(os.Args) > 1 && os.Args[1] == "search" {
+        os.Exit(runSearch(os.Args[2:], os.Stdout))
+    }
+
+    // Subcommand: goresearch doctor — preflight search and LLM reachability
+    if len(os.Args) > 1 && os.Args[1] == "doctor" {
+        os.Exit(runDoctor(os.Args[2:], os.Stdout))
+    }
+
     cfg, verbose, err := parseConfig(os.Args[1:], os.Getenv)
     if err != nil {
         log.Error().Err(err).Msg("parse flags failed")
         os.Exit(2)
     }
-    // Single-file config discovery: prefer goresearch.yaml then goresearch.json in cwd
-    if _, statErr := os.Stat("goresearch.yaml"); statErr == nil {
-        if fc, err := app.LoadConfigFile("goresearch.yaml"); err == nil {
-            app.ApplyFileConfig(&cfg, fc)
-        } else {
-            log.Error().Err(err).Msg("failed to parse goresearch.yaml")
-            os.Exit(2)
-        }
-    } else if _, statErr := os.Stat("goresearch.json"); statErr == nil {
-        if fc, err := app.LoadConfigFile("goresearch.json"); err == nil {
-            app.ApplyFileConfig(&cfg, fc)
-        } else {
-            log.Error().Err(err).Msg("failed to parse goresearch.json")
-            os.Exit(2)
-        }
+    if err := applyConfigSources(&cfg); err != nil {
+        log.Error().Err(err).Msg("load config failed")
+        os.Exit(2)
     }
-
-    // Populate unset fields from environment (after flags and file), so explicit flags win.
-    app.ApplyEnvToConfig(&cfg)
-    // Then apply env overrides to force env > file when explicitly set
-    app.ApplyEnvOverrides(&cfg)
     // Configure logging: concise console output to stderr; structured JSON to file.
     // Level precedence: --log.level > -v (debug) > info default.
     level := zerolog.InfoLevel
@@ -136,6 +135,184 @@ func main() {
 	}
 }
 
+// applyConfigSources layers the single config file (goresearch.yaml, then
+// goresearch.json in cwd) and the environment over flag-parsed cfg.
+func applyConfigSources(cfg *app.Config) error {
+    // Single-file config discovery: prefer goresearch.yaml then goresearch.json in cwd
+    for _, name := range []string{"goresearch.yaml", "goresearch.json"} {
+        if _, statErr := os.Stat(name); statErr != nil {
+            continue
+        }
+        fc, err := app.LoadConfigFile(name)
+        if err != nil {
+            return fmt.Errorf("failed to parse %s: %w", name, err)
+        }
+        app.ApplyFileConfig(cfg, fc)
+        break
+    }
+
+    // Populate unset fields from environment (after flags and file), so explicit flags win.
+    app.ApplyEnvToConfig(cfg)
+    // Then apply env overrides to force env > file when explicitly set
+    app.ApplyEnvOverrides(cfg)
+    return nil
+}
+
+// runDoctor implements `goresearch doctor`: it resolves configuration the same
+// way as a normal run, probes the LLM endpoint and search providers, prints
+// one line per check and returns the process exit code.
+func runDoctor(args []string, w io.Writer) int {
+    cfg, _, err := parseConfig(args, os.Getenv)
+    if err != nil {
+        log.Error().Err(err).Msg("parse flags failed")
+        return 2
+    }
+    if err := applyConfigSources(&cfg); err != nil {
+        log.Error().Err(err).Msg("load config failed")
+        return 2
+    }
+    ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    defer stop()
+    checks := app.Doctor(ctx, cfg)
+    fmt.Fprint(w, renderDoctorReport(checks))
+    for _, c := range checks {
+        if !c.OK {
+            return 1
+        }
+    }
+    return 0
+}
+
+// runSearch implements `goresearch search [flags] <query>`: it resolves
+// configuration like a normal run, queries the selected provider(s), applies
+// domain policy and selection, and prints every candidate with the reason it
+// was dropped. It returns the process exit code.
+func runSearch(args []string, w io.Writer) int {
+    var format string
+    var limit int
+    cfg, _, rest, err := parseConfigWith(args, os.Getenv, func(fs *flag.FlagSet) {
+        fs.StringVar(&format, "format", "table", "Output format: table|json")
+        fs.IntVar(&limit, "limit", 10, "Maximum results to request from the provider")
+    })
+    if err != nil {
+        log.Error().Err(err).Msg("parse flags failed")
+        return 2
+    }
+    if err := applyConfigSources(&cfg); err != nil {
+        log.Error().Err(err).Msg("load config failed")
+        return 2
+    }
+    query := strings.TrimSpace(strings.Join(rest, " "))
+    if query == "" {
+        log.Error().Msg("usage: goresearch search [flags] <query>")
+        return 2
+    }
+    if format != "table" && format != "json" {
+        log.Error().Str("format", format).Msg("unknown format; use table or json")
+        return 2
+    }
+    ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    defer stop()
+    rep, err := app.RunSearch(ctx, cfg, query, limit)
+    if err != nil {
+        log.Error().Err(err).Str("query", query).Msg("search failed")
+        return 1
+    }
+    if format == "json" {
+        enc := json.NewEncoder(w)
+        enc.SetIndent("", "  ")
+        if err := enc.Encode(rep); err != nil {
+            log.Error().Err(err).Msg("encode report")
+            return 1
+        }
+        return 0
+    }
+    fmt.Fprint(w, renderSearchTable(rep))
+    return 0
+}
+
+// renderSearchTable formats a search report as an aligned table with the
+// snippet on an indented line below each row.
+func renderSearchTable(rep app.SearchReport) string {
+    var b strings.Builder
+    fmt.Fprintf(&b, "query: %s\nprovider: %s\n\n", rep.Query, rep.Provider)
+    tw := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+    fmt.Fprintln(tw, "RANK\tSTATUS\tSCORE\tTYPE\tLANG\tPUBLISHED\tSOURCE\tTITLE\tURL")
+    selected := 0
+    for _, r := range rep.Results {
+        status := "selected"
+        if r.Selected {
+            selected++
+        } else {
+            status = "dropped: " + r.Reason
+        }
+        rank, score, typ, lang, published := "-", "-", "-", "-", "-"
+        if r.Rank > 0 {
+            rank = strconv.Itoa(r.Rank)
+        }
+        if r.Credibility != nil {
+            score = strconv.FormatFloat(r.Credibility.Score, 'f', 2, 64)
+        }
+        if r.SourceType != "" {
+            typ = r.SourceType
+        }
+        if r.Language != "" {
+            lang = r.Language
+        }
+        if r.Published != "" {
+            published = r.Published
+            if r.Stale {
+                published += " (stale)"
+            }
+        }
+        fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", rank, status, score, typ, lang, published, r.Source, truncateRunes(r.Title, 60), r.URL)
+        if s := strings.Join(strings.Fields(r.Snippet), " "); s != "" {
+            fmt.Fprintf(tw, "\t\t\t\t\t\t\t  %s\t\n", truncateRunes(s, 100))
+        }
+    }
+    _ = tw.Flush()
+    fmt.Fprintf(&b, "\n%d of %d candidates selected\n", selected, len(rep.Results))
+    return b.String()
+}
+
+// truncateRunes shortens s to at most n runes, marking the cut with an ellipsis.
+func truncateRunes(s string, n int) string {
+    r := []rune(s)
+    if len(r) <= n {
+        return s
+    }
+    return string(r[:n-1]) + "…"
+}
+
+// renderVersion formats build metadata for `goresearch version`.
+func renderVersion() string {
+    return fmt.Sprintf("goresearch %s\ncommit: %s\nbuilt: %s\ngo: %s\n", app.BuildVersion, app.BuildCommit, app.BuildDate, runtime.Version())
+}
+
+// renderDoctorReport formats preflight checks as aligned status lines.
+func renderDoctorReport(checks []app.DoctorCheck) string {
+    var b strings.Builder
+    failed := 0
+    for _, c := range checks {
+        status := "ok  "
+        if !c.OK {
+            status = "FAIL"
+            failed++
+        }
+        fmt.Fprintf(&b, "%s %-18s %s", status, c.Name, c.Detail)
+        if c.Elapsed > 0 {
+            fmt.Fprintf(&b, " [%s]", c.Elapsed.Round(time.Millisecond))
+        }
+        b.WriteString("\n")
+    }
+    if failed == 0 {
+        b.WriteString("all checks passed\n")
+    } else {
+        fmt.Fprintf(&b, "%d of %d checks failed\n", failed, len(checks))
+    }
+    return b.String()
+}
+
 // initScaffold writes a starter goresearch.yaml and .env.example if they don't exist.
 func initScaffold(dir string) error {
     // Create goresearch.yaml if missing
@@ -206,17 +383,45 @@ type boundVars struct {
     inputPath, outputPath                 *string
     searxURL, searxKey, searxUA           *string
     fileSearchPath                        *string
+    searchProvider                        *string
+    wikipediaLang, wikipediaURL           *string
+    searchMaxAttempts, searchCircuitThreshold *int
+    searchBackoff                         *time.Duration
     llmBaseURL, llmModel, llmKey          *string
     maxSources, perDomain, perSourceChars *int
     minSnippetChars                       *int
+    recencyMonths                         *int
+    recencyExempt                         *string
+    trackerParams                         *string
+    credibilityFile                       *string
+    rerankModel                           *string
+    typesMin                              *string
+    typesMax                              *string
+    rerankLambda                          *float64
+    fetchConcurrency                      *int
+    fetchMaxAttempts                      *int
+    fetchHostRPS                          *float64
+    fetchHostBurst                        *int
+    fetchGlobalRPS                        *float64
     language                              *string
+    langAllow                             *string
     dryRun, verbose, debugVerbose         *bool
     cacheDir                              *string
     cacheMaxAge                           *time.Duration
     cacheClear, cacheStrict               *bool
+    httpCacheOnly                         *bool
+    warcInputs                            *string
     sslVerify                             *bool
+    sslCAFile                             *string
+    proxyURL                              *string
+    noProxy                               *string
     topicHash                             *string
     enablePDF                             *bool
+    fetchAccept                           *string
+    extractMode                           *string
+    extractFormat                         *string
+    fetchMaxBytes, fetchMaxBytesByType    *string
+    fetchMemoryBudget                     *string
     synthSystemPrompt, synthSystemPromptFile *string
     verifySystemPrompt, verifySystemPromptFile *string
     robotsOverrideAllowlist               *string
@@ -230,6 +435,7 @@ type boundVars struct {
     noVerify                               *bool
     reportsDir                              *string
     reportsTar                              *bool
+    reportsWARC                             *bool
     logLevel                                *string
     logFile                                 *string
 }
@@ -251,6 +457,12 @@ func newFlagSet(getenv func(string) string) (*flag.FlagSet, flagMeta) {
     bv.searxKey = fs.String("searx.key", getenv("SEARX_KEY"), "SearxNG API key (optional)")
     bv.searxUA = fs.String("searx.ua", "goresearch/1.0 (+https://github.com/hyperifyio/goresearch)", "Custom User-Agent for SearxNG requests")
     bv.fileSearchPath = fs.String("search.file", getenv("SEARCH_FILE"), "Path to JSON file for offline file-based search provider")
+    bv.searchProvider = fs.String("search.provider", getenv("SEARCH_PROVIDER"), "Comma-separated search providers: searxng|wikipedia|file (default: file when -search.file is set, else searxng)")
+    bv.wikipediaLang = fs.String("wikipedia.lang", getenv("WIKIPEDIA_LANG"), "Wikipedia language edition for the wikipedia provider (default: -lang, then en)")
+    bv.wikipediaURL = fs.String("wikipedia.url", getenv("WIKIPEDIA_URL"), "Override the Wikipedia base URL (e.g. a mirror); takes precedence over -wikipedia.lang")
+    bv.searchMaxAttempts = fs.Int("search.maxAttempts", 2, "Total tries per query and search provider before the query counts as failed")
+    bv.searchBackoff = fs.Duration("search.backoff", 500*time.Millisecond, "Delay before the first search retry; doubles per retry")
+    bv.searchCircuitThreshold = fs.Int("search.circuitThreshold", 3, "Skip a search provider for the rest of the run after this many consecutive failed queries (0 disables)")
     bv.llmBaseURL = fs.String("llm.base", getenv("LLM_BASE_URL"), "OpenAI-compatible base URL")
     bv.llmModel = fs.String("llm.model", getenv("LLM_MODEL"), "Model name")
     bv.llmKey = fs.String("llm.key", getenv("LLM_API_KEY"), "API key for OpenAI-compatible server")
@@ -258,7 +470,21 @@ func newFlagSet(getenv func(string) string) (*flag.FlagSet, flagMeta) {
     bv.perDomain = fs.Int("max.perDomain", 3, "Maximum sources per domain")
     bv.perSourceChars = fs.Int("max.perSourceChars", 12000, "Maximum characters per source extract")
     bv.minSnippetChars = fs.Int("min.snippetChars", 0, "Minimum non-whitespace snippet characters to keep a result (0 disables)")
+    bv.recencyMonths = fs.Int("recency.months", 0, "Prefer sources published within the last N months; older dated results are demoted (0 disables)")
+    bv.recencyExempt = fs.String("recency.exempt", "", "Comma-separated host patterns never treated as stale (default: standards bodies such as rfc-editor.org, w3.org)")
+    bv.trackerParams = fs.String("url.trackerParams", "", "Comma-separated query parameters stripped when normalizing URLs; a trailing * matches a prefix (default: utm_*, gclid, fbclid and other common trackers)")
+    bv.typesMin = fs.String("types.min", "", "Minimum selected sources per source type as <type>=<n>, comma-separated (types: primary, academic, reference, news, vendor, forum, blog, web)")
+    bv.typesMax = fs.String("types.max", "", "Maximum selected sources per source type as <type>=<n>, comma-separated, e.g. forum=2,blog=2")
+    bv.rerankModel = fs.String("rerank.model", getenv("RERANK_MODEL"), "Embeddings model on the LLM endpoint used to rerank search results by relevance to the brief; empty disables reranking")
+    bv.rerankLambda = fs.Float64("rerank.lambda", 0.7, "Relevance/diversity trade-off for reranking (maximal marginal relevance); 1 ranks purely by relevance")
+    bv.credibilityFile = fs.String("credibility.file", getenv("CREDIBILITY_FILE"), "Path to a YAML reputation file (tiers, boosts and penalties per domain) used to score and rank sources (default: built-in reputation)")
+    bv.fetchConcurrency = fs.Int("fetch.concurrency", 8, "Number of sources fetched and extracted in parallel; requests to any one host stay sequential")
+    bv.fetchMaxAttempts = fs.Int("fetch.maxAttempts", 3, "Attempts per source; retries back off exponentially with jitter or wait out the server's Retry-After")
+    bv.fetchHostRPS = fs.Float64("fetch.hostRPS", 2, "Maximum requests per second to any single host (0 disables the per-host rate limit)")
+    bv.fetchHostBurst = fs.Int("fetch.hostBurst", 4, "Requests a host may receive back to back before fetch.hostRPS applies")
+    bv.fetchGlobalRPS = fs.Float64("fetch.globalRPS", 0, "Maximum requests per second across all hosts (0 means no global cap)")
     bv.language = fs.String("lang", "", "Optional language hint, e.g. 'en' or 'fi'")
+    bv.langAllow = fs.String("lang.allow", "", "Comma-separated ISO 639-1 languages to keep, e.g. en,de; sources confidently detected as another language are dropped (default: all languages)")
     bv.dryRun = fs.Bool("dry-run", false, "Plan and select without calling the model")
     bv.verbose = fs.Bool("v", false, "Verbose logging")
     bv.debugVerbose = fs.Bool("debug-verbose", false, "Allow logging raw chain-of-thought (CoT) for debugging Harmony/tool-call interplay")
@@ -266,9 +492,20 @@ func newFlagSet(getenv func(string) string) (*flag.FlagSet, flagMeta) {
     bv.cacheMaxAge = fs.Duration("cache.maxAge", 0, "Max age for cache entries before purge (e.g. 24h, 7d); 0 disables")
     bv.cacheClear = fs.Bool("cache.clear", false, "Clear cache directory before run")
     bv.cacheStrict = fs.Bool("cache.strictPerms", false, "Restrict cache permissions (0700 dirs, 0600 files)")
+    bv.httpCacheOnly = fs.Bool("http.cacheOnly", false, "Serve HTTP bodies only from WARC inputs and the cache; fail on miss without network access")
+    bv.warcInputs = fs.String("warc", getenv("WARC_FILES"), "Comma-separated WARC files whose recorded responses are served before the cache or network")
     bv.sslVerify = fs.Bool("ssl.verify", getenv("SSL_VERIFY") != "false", "Enable SSL certificate verification (set to false for self-signed certs)")
+    bv.sslCAFile = fs.String("ssl.caFile", getenv("SSL_CA_FILE"), "PEM file of extra CA certificates trusted alongside the system roots (e.g. a TLS-inspecting proxy's CA)")
+    bv.proxyURL = fs.String("proxy.url", getenv("PROXY_URL"), "Proxy for all outbound HTTP(S) requests; overrides HTTP_PROXY/HTTPS_PROXY. Private-address checks then cover only hosts that resolve locally")
+    bv.noProxy = fs.String("proxy.noProxy", "", "Comma-separated hosts, domains and CIDRs reached without the proxy; overrides NO_PROXY")
     bv.topicHash = fs.String("cache.topicHash", getenv("TOPIC_HASH"), "Optional topic hash to scope cache; accepted for traceability")
     bv.enablePDF = fs.Bool("enable.pdf", false, "Enable optional PDF ingestion (application/pdf)")
+    bv.fetchMaxBytes = fs.String("fetch.maxBytes", getenv("FETCH_MAX_BYTES"), "Maximum response body size, e.g. 10MiB or none; oversized sources are skipped (default 10MiB)")
+    bv.fetchMaxBytesByType = fs.String("fetch.maxBytesByType", "", "Per-type body size limits as <type>=<size>, comma-separated, e.g. application/pdf=20MiB,text/*=2MiB (default application/pdf=20MiB)")
+    bv.fetchMemoryBudget = fs.String("fetch.memoryBudget", getenv("FETCH_MEMORY_BUDGET"), "Total body bytes buffered by concurrent fetches, e.g. 128MiB or none (default 128MiB)")
+    bv.extractFormat = fs.String("extract.format", getenv("EXTRACT_FORMAT"), "Excerpt format: text (default) or markdown (keeps headings, lists, tables and code; falls back to text when over budget)")
+    bv.extractMode = fs.String("extract.mode", getenv("EXTRACT_MODE"), "HTML extractor: heuristic (prefers <main>/<article>) or readability (scores content blocks; for pages without semantic markup)")
+    bv.fetchAccept = fs.String("fetch.accept", getenv("FETCH_ACCEPT"), "Comma-separated media types fetched besides HTML (patterns like application/*+xml); default text, Markdown, JSON and XML")
     // Prompt overrides
     bv.synthSystemPrompt = fs.String("synth.systemPrompt", getenv("SYNTH_SYSTEM_PROMPT"), "Override synthesis system prompt (inline string)")
     bv.synthSystemPromptFile = fs.String("synth.systemPromptFile", getenv("SYNTH_SYSTEM_PROMPT_FILE"), "Path to file containing synthesis system prompt")
@@ -292,6 +529,7 @@ func newFlagSet(getenv func(string) string) (*flag.FlagSet, flagMeta) {
     // Artifacts bundle
     bv.reportsDir = fs.String("reports.dir", "reports", "Root directory to persist artifacts bundles (reports)")
     bv.reportsTar = fs.Bool("reports.tar", false, "Also produce a tar.gz of the bundle with digests for offline audit")
+    bv.reportsWARC = fs.Bool("reports.warc", false, "Record every fetched request and response, including robots.txt and redirects, to fetch.warc.gz in the bundle")
     // Logging controls
     bv.logLevel = fs.String("log.level", strings.TrimSpace(getenv("LOG_LEVEL")), "Structured log level for file output: trace|debug|info|warn|error|fatal|panic (default info)")
     bv.logFile = fs.String("log.file", strings.TrimSpace(getenv("LOG_FILE")), "Path to write structured JSON logs (default goresearch.log)")
@@ -317,6 +555,9 @@ func renderCLIReferenceMarkdown(fs *flag.FlagSet) string {
     b.WriteString("goresearch [flags]\n")
     b.WriteString("goresearch init\n")
     b.WriteString("goresearch doc\n")
+    b.WriteString("goresearch doctor [flags]\n")
+    b.WriteString("goresearch search [flags] [-format table|json] [-limit N] <query>\n")
+    b.WriteString("goresearch version\n")
     b.WriteString("```\n\n")
 
     // Collect flags for stable ordering by name
@@ -341,6 +582,10 @@ func renderCLIReferenceMarkdown(fs *flag.FlagSet) string {
         {"LLM_API_KEY", "API key"},
         {"SEARX_URL", "SearxNG base URL (or SEARXNG_URL)"},
         {"SEARX_KEY", "SearxNG API key (or SEARXNG_KEY)"},
+        {"SEARCH_FILE", "Path to JSON file for the offline file-based search provider"},
+        {"SEARCH_PROVIDER", "Comma-separated search providers: searxng|wikipedia|file"},
+        {"WIKIPEDIA_LANG", "Wikipedia language edition for the wikipedia provider"},
+        {"WIKIPEDIA_URL", "Wikipedia base URL override (e.g. a mirror)"},
         {"CACHE_DIR", "Cache directory path"},
         {"LANGUAGE", "Language hint"},
         {"SOURCE_CAPS", "Max sources and optional per-domain cap as '<max>' or '<max>,<perDomain>'"},
@@ -350,7 +595,16 @@ func renderCLIReferenceMarkdown(fs *flag.FlagSet) string {
         {"CACHE_CLEAR", "Clear cache before run when truthy"},
         {"CACHE_STRICT_PERMS", "Restrict cache permissions when truthy"},
         {"SSL_VERIFY", "Enable SSL certificate verification (set to 'false' for self-signed certs)"},
+        {"SSL_CA_FILE", "PEM file of extra CA certificates trusted alongside the system roots"},
+        {"PROXY_URL", "Proxy for all outbound HTTP(S) requests; overrides HTTP_PROXY/HTTPS_PROXY"},
+        {"HTTP_PROXY, HTTPS_PROXY, NO_PROXY", "Standard proxy variables, honored by every outbound client"},
         {"HTTP_CACHE_ONLY", "Serve HTTP bodies only from cache; fail on miss"},
+        {"WARC_FILES", "Comma-separated WARC files served before the cache or network"},
+        {"EXTRACT_MODE", "HTML extractor: heuristic or readability"},
+        {"EXTRACT_FORMAT", "Excerpt format: text or markdown"},
+        {"FETCH_ACCEPT", "Comma-separated media types fetched besides HTML"},
+        {"FETCH_MAX_BYTES", "Maximum response body size, e.g. 10MiB or none"},
+        {"FETCH_MEMORY_BUDGET", "Total body bytes buffered by concurrent fetches"},
         {"LLM_CACHE_ONLY", "Serve LLM results only from cache; fail on miss"},
         {"ROBOTS_OVERRIDE_DOMAINS", "Comma-separated allowlist to ignore robots.txt; requires robots.overrideConfirm"},
         {"DOMAINS_ALLOW", "Comma-separated allowlist of hosts/domains"},
@@ -360,6 +614,8 @@ func renderCLIReferenceMarkdown(fs *flag.FlagSet) string {
         {"VERIFY_SYSTEM_PROMPT", "Inline verification system prompt override"},
         {"VERIFY_SYSTEM_PROMPT_FILE", "Path to verification system prompt file"},
         {"TOPIC_HASH", "Optional topic hash to scope cache"},
+        {"CREDIBILITY_FILE", "Path to a YAML reputation file used to score sources"},
+        {"RERANK_MODEL", "Embeddings model used to rerank search results; empty disables reranking"},
         {"VERIFY", "Set to truthy to force enable verification (overrides NO_VERIFY)"},
         {"NO_VERIFY", "Set to truthy to disable verification"},
         {"LOG_LEVEL", "Structured log level for file output (trace|debug|info|warn|error|fatal|panic)"},
@@ -377,6 +633,14 @@ func renderCLIReferenceMarkdown(fs *flag.FlagSet) string {
 // parseConfig parses CLI flags and environment variables into app.Config.
 // It is separated to enable unit testing of flag behavior.
 func parseConfig(args []string, getenv func(string) string) (app.Config, bool, error) {
+    cfg, verbose, _, err := parseConfigWith(args, getenv, nil)
+    return cfg, verbose, err
+}
+
+// parseConfigWith is parseConfig for subcommands: extra may register
+// additional flags on the shared FlagSet before parsing, and the remaining
+// positional arguments are returned.
+func parseConfigWith(args []string, getenv func(string) string, extra func(*flag.FlagSet)) (app.Config, bool, []string, error) {
     fs := flag.NewFlagSet("goresearch", flag.ContinueOnError)
     // Suppress default output during tests; callers can override
     fs.SetOutput(os.Stderr)
@@ -388,6 +652,12 @@ func parseConfig(args []string, getenv func(string) string) (app.Config, bool, e
         searxKey        string
         searxUA         string
         fileSearchPath  string
+        searchProvider  string
+        wikipediaLang   string
+        wikipediaURL    string
+        searchMaxAttempts      int
+        searchBackoff          time.Duration
+        searchCircuitThreshold int
         llmBaseURL      string
         llmModel        string
         llmKey          string
@@ -395,7 +665,21 @@ func parseConfig(args []string, getenv func(string) string) (app.Config, bool, e
         perDomain       int
         perSourceChars  int
         minSnippetChars int
+        recencyMonths   int
+        recencyExempt   string
+        trackerParams   string
+        credibilityFile string
+        rerankModel     string
+        typesMin        string
+        typesMax        string
+        rerankLambda    float64
+        fetchConcurrency int
+        fetchMaxAttempts int
+        fetchHostRPS    float64
+        fetchHostBurst  int
+        fetchGlobalRPS  float64
         language        string
+        langAllow       string
         dryRun          bool
         verbose         bool
         debugVerbose    bool
@@ -403,9 +687,20 @@ func parseConfig(args []string, getenv func(string) string) (app.Config, bool, e
         cacheMaxAge     time.Duration
         cacheClear      bool
         cacheStrict     bool
+        httpCacheOnly   bool
+        warcInputs      string
         sslVerify       bool
+        sslCAFile       string
+        proxyURL        string
+        noProxy         string
         topicHash       string
         enablePDF       bool
+        fetchAccept     string
+        extractMode     string
+        extractFormat   string
+        fetchMaxBytes   string
+        fetchMaxBytesByType string
+        fetchMemoryBudget   string
         synthSystemPrompt     string
         synthSystemPromptFile string
         verifySystemPrompt    string
@@ -425,6 +720,7 @@ func parseConfig(args []string, getenv func(string) string) (app.Config, bool, e
         noVerify           bool
         reportsDir         string
         reportsTar         bool
+        reportsWARC        bool
         // Logging flags
         logLevel           string
         logFile            string
@@ -436,6 +732,12 @@ func parseConfig(args []string, getenv func(string) string) (app.Config, bool, e
     fs.StringVar(&searxKey, "searx.key", getenv("SEARX_KEY"), "SearxNG API key (optional)")
     fs.StringVar(&searxUA, "searx.ua", "goresearch/1.0 (+https://github.com/hyperifyio/goresearch)", "Custom User-Agent for SearxNG requests")
     fs.StringVar(&fileSearchPath, "search.file", getenv("SEARCH_FILE"), "Path to JSON file for offline file-based search provider")
+    fs.StringVar(&searchProvider, "search.provider", getenv("SEARCH_PROVIDER"), "Comma-separated search providers: searxng|wikipedia|file (default: file when -search.file is set, else searxng)")
+    fs.StringVar(&wikipediaLang, "wikipedia.lang", getenv("WIKIPEDIA_LANG"), "Wikipedia language edition for the wikipedia provider (default: -lang, then en)")
+    fs.StringVar(&wikipediaURL, "wikipedia.url", getenv("WIKIPEDIA_URL"), "Override the Wikipedia base URL (e.g. a mirror); takes precedence over -wikipedia.lang")
+    fs.IntVar(&searchMaxAttempts, "search.maxAttempts", 2, "Total tries per query and search provider before the query counts as failed")
+    fs.DurationVar(&searchBackoff, "search.backoff", 500*time.Millisecond, "Delay before the first search retry; doubles per retry")
+    fs.IntVar(&searchCircuitThreshold, "search.circuitThreshold", 3, "Skip a search provider for the rest of the run after this many consecutive failed queries (0 disables)")
     fs.StringVar(&llmBaseURL, "llm.base", getenv("LLM_BASE_URL"), "OpenAI-compatible base URL")
     fs.StringVar(&llmModel, "llm.model", getenv("LLM_MODEL"), "Model name")
     fs.StringVar(&llmKey, "llm.key", getenv("LLM_API_KEY"), "API key for OpenAI-compatible server")
@@ -443,7 +745,21 @@ func parseConfig(args []string, getenv func(string) string) (app.Config, bool, e
     fs.IntVar(&perDomain, "max.perDomain", 3, "Maximum sources per domain")
     fs.IntVar(&perSourceChars, "max.perSourceChars", 12000, "Maximum characters per source extract")
     fs.IntVar(&minSnippetChars, "min.snippetChars", 0, "Minimum non-whitespace snippet characters to keep a result (0 disables)")
+    fs.IntVar(&recencyMonths, "recency.months", 0, "Prefer sources published within the last N months; older dated results are demoted (0 disables)")
+    fs.StringVar(&recencyExempt, "recency.exempt", "", "Comma-separated host patterns never treated as stale (default: standards bodies such as rfc-editor.org, w3.org)")
+    fs.StringVar(&trackerParams, "url.trackerParams", "", "Comma-separated query parameters stripped when normalizing URLs; a trailing * matches a prefix (default: utm_*, gclid, fbclid and other common trackers)")
+    fs.StringVar(&typesMin, "types.min", "", "Minimum selected sources per source type as <type>=<n>, comma-separated (types: primary, academic, reference, news, vendor, forum, blog, web)")
+    fs.StringVar(&typesMax, "types.max", "", "Maximum selected sources per source type as <type>=<n>, comma-separated, e.g. forum=2,blog=2")
+    fs.StringVar(&rerankModel, "rerank.model", getenv("RERANK_MODEL"), "Embeddings model on the LLM endpoint used to rerank search results by relevance to the brief; empty disables reranking")
+    fs.Float64Var(&rerankLambda, "rerank.lambda", 0.7, "Relevance/diversity trade-off for reranking (maximal marginal relevance); 1 ranks purely by relevance")
+    fs.StringVar(&credibilityFile, "credibility.file", getenv("CREDIBILITY_FILE"), "Path to a YAML reputation file (tiers, boosts and penalties per domain) used to score and rank sources (default: built-in reputation)")
+    fs.IntVar(&fetchConcurrency, "fetch.concurrency", 8, "Number of sources fetched and extracted in parallel; requests to any one host stay sequential")
+    fs.IntVar(&fetchMaxAttempts, "fetch.maxAttempts", 3, "Attempts per source; retries back off exponentially with jitter or wait out the server's Retry-After")
+    fs.Float64Var(&fetchHostRPS, "fetch.hostRPS", 2, "Maximum requests per second to any single host (0 disables the per-host rate limit)")
+    fs.IntVar(&fetchHostBurst, "fetch.hostBurst", 4, "Requests a host may receive back to back before fetch.hostRPS applies")
+    fs.Float64Var(&fetchGlobalRPS, "fetch.globalRPS", 0, "Maximum requests per second across all hosts (0 means no global cap)")
     fs.StringVar(&language, "lang", "", "Optional language hint, e.g. 'en' or 'fi'")
+    fs.StringVar(&langAllow, "lang.allow", "", "Comma-separated ISO 639-1 languages to keep, e.g. en,de; sources confidently detected as another language are dropped (default: all languages)")
     fs.BoolVar(&dryRun, "dry-run", false, "Plan and select without calling the model")
     fs.BoolVar(&verbose, "v", false, "Verbose logging")
     fs.BoolVar(&debugVerbose, "debug-verbose", false, "Allow logging raw chain-of-thought (CoT) for debugging Harmony/tool-call interplay")
@@ -451,9 +767,20 @@ func parseConfig(args []string, getenv func(string) string) (app.Config, bool, e
     fs.DurationVar(&cacheMaxAge, "cache.maxAge", 0, "Max age for cache entries before purge (e.g. 24h, 7d); 0 disables")
     fs.BoolVar(&cacheClear, "cache.clear", false, "Clear cache directory before run")
     fs.BoolVar(&cacheStrict, "cache.strictPerms", false, "Restrict cache permissions (0700 dirs, 0600 files)")
+    fs.BoolVar(&httpCacheOnly, "http.cacheOnly", false, "Serve HTTP bodies only from WARC inputs and the cache; fail on miss without network access")
+    fs.StringVar(&warcInputs, "warc", getenv("WARC_FILES"), "Comma-separated WARC files whose recorded responses are served before the cache or network")
     fs.BoolVar(&sslVerify, "ssl.verify", getenv("SSL_VERIFY") != "false", "Enable SSL certificate verification (set to false for self-signed certs)")
+    fs.StringVar(&sslCAFile, "ssl.caFile", getenv("SSL_CA_FILE"), "PEM file of extra CA certificates trusted alongside the system roots (e.g. a TLS-inspecting proxy's CA)")
+    fs.StringVar(&proxyURL, "proxy.url", getenv("PROXY_URL"), "Proxy for all outbound HTTP(S) requests; overrides HTTP_PROXY/HTTPS_PROXY. Private-address checks then cover only hosts that resolve locally")
+    fs.StringVar(&noProxy, "proxy.noProxy", "", "Comma-separated hosts, domains and CIDRs reached without the proxy; overrides NO_PROXY")
     fs.StringVar(&topicHash, "cache.topicHash", getenv("TOPIC_HASH"), "Optional topic hash to scope cache; accepted for traceability")
     fs.BoolVar(&enablePDF, "enable.pdf", false, "Enable optional PDF ingestion (application/pdf)")
+    fs.StringVar(&fetchMaxBytes, "fetch.maxBytes", getenv("FETCH_MAX_BYTES"), "Maximum response body size, e.g. 10MiB or none; oversized sources are skipped (default 10MiB)")
+    fs.StringVar(&fetchMaxBytesByType, "fetch.maxBytesByType", "", "Per-type body size limits as <type>=<size>, comma-separated, e.g. application/pdf=20MiB,text/*=2MiB (default application/pdf=20MiB)")
+    fs.StringVar(&fetchMemoryBudget, "fetch.memoryBudget", getenv("FETCH_MEMORY_BUDGET"), "Total body bytes buffered by concurrent fetches, e.g. 128MiB or none (default 128MiB)")
+    fs.StringVar(&extractFormat, "extract.format", getenv("EXTRACT_FORMAT"), "Excerpt format: text (default) or markdown (keeps headings, lists, tables and code; falls back to text when over budget)")
+    fs.StringVar(&extractMode, "extract.mode", getenv("EXTRACT_MODE"), "HTML extractor: heuristic (prefers <main>/<article>) or readability (scores content blocks; for pages without semantic markup)")
+    fs.StringVar(&fetchAccept, "fetch.accept", getenv("FETCH_ACCEPT"), "Comma-separated media types fetched besides HTML (patterns like application/*+xml); default text, Markdown, JSON and XML")
     // Prompt profile flexibility: allow overriding system prompts via flags/env
     fs.StringVar(&synthSystemPrompt, "synth.systemPrompt", getenv("SYNTH_SYSTEM_PROMPT"), "Override synthesis system prompt (inline string)")
     fs.StringVar(&synthSystemPromptFile, "synth.systemPromptFile", getenv("SYNTH_SYSTEM_PROMPT_FILE"), "Path to file containing synthesis system prompt")
@@ -478,12 +805,16 @@ func parseConfig(args []string, getenv func(string) string) (app.Config, bool, e
     // Artifacts bundle flags
     fs.StringVar(&reportsDir, "reports.dir", "reports", "Root directory to persist artifacts bundles (reports)")
     fs.BoolVar(&reportsTar, "reports.tar", false, "Also produce a tar.gz of the bundle with digests for offline audit")
+    fs.BoolVar(&reportsWARC, "reports.warc", false, "Record every fetched request and response, including robots.txt and redirects, to fetch.warc.gz in the bundle")
     // Logging flags
     fs.StringVar(&logLevel, "log.level", strings.TrimSpace(getenv("LOG_LEVEL")), "Structured log level for file output: trace|debug|info|warn|error|fatal|panic (default info)")
     fs.StringVar(&logFile, "log.file", strings.TrimSpace(getenv("LOG_FILE")), "Path to write structured JSON logs (default goresearch.log)")
 
+    if extra != nil {
+        extra(fs)
+    }
     if err := fs.Parse(args); err != nil {
-        return app.Config{}, false, err
+        return app.Config{}, false, nil, err
     }
     // If file-based prompts are provided, they take precedence over inline strings
     if strings.TrimSpace(synthSystemPromptFile) != "" {
@@ -504,6 +835,12 @@ func parseConfig(args []string, getenv func(string) string) (app.Config, bool, e
         SearxKey:        searxKey,
         SearxUA:         searxUA,
         FileSearchPath:  fileSearchPath,
+        SearchProvider:  searchProvider,
+        WikipediaLanguage: wikipediaLang,
+        WikipediaBaseURL:  wikipediaURL,
+        SearchMaxAttempts: searchMaxAttempts,
+        SearchBackoff:     searchBackoff,
+        SearchCircuitThreshold: searchCircuitThreshold,
         LLMBaseURL:      llmBaseURL,
         LLMModel:        llmModel,
         LLMAPIKey:       llmKey,
@@ -511,6 +848,15 @@ func parseConfig(args []string, getenv func(string) string) (app.Config, bool, e
         PerDomainCap:    perDomain,
         PerSourceChars:  perSourceChars,
         MinSnippetChars: minSnippetChars,
+        RecencyMonths:   recencyMonths,
+        CredibilityFile: credibilityFile,
+        RerankModel:     rerankModel,
+        RerankLambda:    rerankLambda,
+        FetchConcurrency: fetchConcurrency,
+        FetchMaxAttempts: fetchMaxAttempts,
+        FetchHostRPS:    fetchHostRPS,
+        FetchHostBurst:  fetchHostBurst,
+        FetchGlobalRPS:  fetchGlobalRPS,
         LanguageHint:    language,
         DryRun:          dryRun,
         CacheDir:        cacheDir,
@@ -519,9 +865,15 @@ func parseConfig(args []string, getenv func(string) string) (app.Config, bool, e
         CacheMaxAge:     cacheMaxAge,
         CacheClear:      cacheClear,
         CacheStrictPerms: cacheStrict,
+        HTTPCacheOnly:   httpCacheOnly,
         SSLVerify:       sslVerify,
+        CAFile:          sslCAFile,
+        ProxyURL:        proxyURL,
+        NoProxy:         noProxy,
         TopicHash:       topicHash,
         EnablePDF:       enablePDF,
+        ExtractMode:     extractMode,
+        ExtractFormat:   extractFormat,
         SynthSystemPrompt:  synthSystemPrompt,
         VerifySystemPrompt: verifySystemPrompt,
         RobotsOverrideConfirm: robotsOverrideConfirm,
@@ -533,6 +885,7 @@ func parseConfig(args []string, getenv func(string) string) (app.Config, bool, e
         ToolsMode:       toolsMode,
         ReportsDir:      reportsDir,
         ReportsTar:      reportsTar,
+        WARC:            reportsWARC,
         LogLevel:        logLevel,
         LogFilePath:     logFile,
     }
@@ -559,6 +912,71 @@ func parseConfig(args []string, getenv func(string) string) (app.Config, bool, e
         for _, p := range parts { if v := strings.TrimSpace(p); v != "" { list = append(list, v) } }
         cfg.DomainDenylist = list
     }
+    if s := strings.TrimSpace(warcInputs); s != "" {
+        parts := strings.Split(s, ",")
+        list := make([]string, 0, len(parts))
+        for _, p := range parts { if v := strings.TrimSpace(p); v != "" { list = append(list, v) } }
+        cfg.WARCInputs = list
+    }
+    if s := strings.TrimSpace(fetchAccept); s != "" {
+        parts := strings.Split(s, ",")
+        list := make([]string, 0, len(parts))
+        for _, p := range parts { if v := strings.TrimSpace(p); v != "" { list = append(list, v) } }
+        cfg.AcceptTypes = list
+    }
+    if s := strings.TrimSpace(recencyExempt); s != "" {
+        parts := strings.Split(s, ",")
+        list := make([]string, 0, len(parts))
+        for _, p := range parts { if v := strings.TrimSpace(p); v != "" { list = append(list, v) } }
+        cfg.RecencyExemptHosts = list
+    }
+    if s := strings.TrimSpace(trackerParams); s != "" {
+        parts := strings.Split(s, ",")
+        list := make([]string, 0, len(parts))
+        for _, p := range parts { if v := strings.TrimSpace(p); v != "" { list = append(list, v) } }
+        cfg.TrackerParams = list
+    }
+    if s := strings.TrimSpace(langAllow); s != "" {
+        parts := strings.Split(s, ",")
+        list := make([]string, 0, len(parts))
+        for _, p := range parts { if v := strings.ToLower(strings.TrimSpace(p)); v != "" { list = append(list, v) } }
+        cfg.AllowedLanguages = list
+    }
+    if s := strings.TrimSpace(fetchMaxBytes); s != "" {
+        n, err := fetch.ParseSize(s)
+        if err != nil {
+            return app.Config{}, false, nil, fmt.Errorf("fetch.maxBytes: %w", err)
+        }
+        cfg.FetchMaxBodyBytes = n
+    }
+    if s := strings.TrimSpace(fetchMaxBytesByType); s != "" {
+        m, err := fetch.ParseBodyLimits(s)
+        if err != nil {
+            return app.Config{}, false, nil, fmt.Errorf("fetch.maxBytesByType: %w", err)
+        }
+        cfg.FetchBodyLimits = m
+    }
+    if s := strings.TrimSpace(fetchMemoryBudget); s != "" {
+        n, err := fetch.ParseSize(s)
+        if err != nil {
+            return app.Config{}, false, nil, fmt.Errorf("fetch.memoryBudget: %w", err)
+        }
+        cfg.FetchMemoryBudget = n
+    }
+    if s := strings.TrimSpace(typesMin); s != "" {
+        m, err := sourcetype.ParseCounts(s)
+        if err != nil {
+            return app.Config{}, false, nil, fmt.Errorf("types.min: %w", err)
+        }
+        cfg.SourceTypeMin = m
+    }
+    if s := strings.TrimSpace(typesMax); s != "" {
+        m, err := sourcetype.ParseCounts(s)
+        if err != nil {
+            return app.Config{}, false, nil, fmt.Errorf("types.max: %w", err)
+        }
+        cfg.SourceTypeMax = m
+    }
     // Apply verification toggle precedence:
     // - if --no-verify set, disable
     // - else if --verify explicitly false (rare), disable
@@ -568,7 +986,7 @@ func parseConfig(args []string, getenv func(string) string) (app.Config, bool, e
     } else if !verifyEnabled {
         cfg.DisableVerify = true
     }
-    return cfg, verbose, nil
+    return cfg, verbose, fs.Args(), nil
 }
 
 // isNoSubstantiveBody checks whether the error indicates the synthesizer
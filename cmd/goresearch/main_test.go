@@ -1,9 +1,11 @@
 package main
 
 import (
+    "flag"
     "os"
     "path/filepath"
     "reflect"
+    "strings"
     "testing"
     "time"
 
@@ -63,6 +65,31 @@ func TestParseConfig_VerificationFlags(t *testing.T) {
     }
 }
 
+// Test extraction and body size flags, and that bad sizes are rejected
+func TestParseConfig_ExtractAndFetchLimitFlags(t *testing.T) {
+    getenv := func(k string) string {
+        if k == "EXTRACT_MODE" { return "readability" }
+        return ""
+    }
+    cfg, _, err := parseConfig([]string{"-extract.format", "markdown", "-fetch.maxBytes", "2MiB", "-fetch.maxBytesByType", "application/pdf=30MiB,text/*=none", "-fetch.memoryBudget", "64MB", "-fetch.accept", "text/plain, application/json"}, getenv)
+    if err != nil { t.Fatalf("parse: %v", err) }
+    if cfg.ExtractFormat != "markdown" {
+        t.Fatalf("unexpected extract format %q", cfg.ExtractFormat)
+    }
+    if cfg.ExtractMode != "readability" || cfg.FetchMaxBodyBytes != 2<<20 || cfg.FetchMemoryBudget != 64e6 {
+        t.Fatalf("unexpected config: mode=%q max=%d budget=%d", cfg.ExtractMode, cfg.FetchMaxBodyBytes, cfg.FetchMemoryBudget)
+    }
+    if cfg.FetchBodyLimits["application/pdf"] != 30<<20 || cfg.FetchBodyLimits["text/*"] != -1 {
+        t.Fatalf("unexpected per-type limits: %v", cfg.FetchBodyLimits)
+    }
+    if len(cfg.AcceptTypes) != 2 || cfg.AcceptTypes[1] != "application/json" {
+        t.Fatalf("unexpected accept types: %v", cfg.AcceptTypes)
+    }
+    if _, _, err := parseConfig([]string{"-fetch.maxBytes", "lots"}, func(string) string { return "" }); err == nil {
+        t.Fatalf("expected an invalid size to fail")
+    }
+}
+
 // Test flags override for tools orchestration and prompt file override
 func TestParseConfig_ToolsFlagsAndPromptFile(t *testing.T) {
     dir := t.TempDir()
@@ -261,3 +288,32 @@ func TestRenderVersion_IncludesDefaults(t *testing.T) {
         }
     }
 }
+
+func TestParseConfigWith_ExtraFlagsAndPositionalArgs(t *testing.T) {
+    var format string
+    cfg, _, rest, err := parseConfigWith([]string{"-search.provider", "wikipedia", "-format", "json", "what", "is", "go"}, func(string) string { return "" }, func(fs *flag.FlagSet) {
+        fs.StringVar(&format, "format", "table", "")
+    })
+    if err != nil {
+        t.Fatalf("parseConfigWith: %v", err)
+    }
+    if cfg.SearchProvider != "wikipedia" || format != "json" {
+        t.Fatalf("flags not applied: provider=%q format=%q", cfg.SearchProvider, format)
+    }
+    if !reflect.DeepEqual(rest, []string{"what", "is", "go"}) {
+        t.Fatalf("unexpected positional args: %v", rest)
+    }
+}
+
+func TestRenderSearchTable_ShowsStatusAndSnippets(t *testing.T) {
+    rep := apppkg.SearchReport{Query: "q", Provider: "file", Results: []apppkg.SearchReportRow{
+        {Rank: 1, Title: "Kept", URL: "https://a.example/", Snippet: "kept   snippet", Source: "file", Language: "en", Selected: true},
+        {Title: "Blocked", URL: "https://b.example/", Source: "file", Reason: "domain-denylist"},
+    }}
+    out := renderSearchTable(rep)
+    for _, want := range []string{"provider: file", "selected", "dropped: domain-denylist", "kept snippet", "1 of 2 candidates selected"} {
+        if !strings.Contains(out, want) {
+            t.Fatalf("table missing %q:\n%s", want, out)
+        }
+    }
+}
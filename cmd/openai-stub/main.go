@@ -2,7 +2,9 @@ package main
 
 import (
 	"encoding/json"
+	"hash/fnv"
 	"log"
+	"math"
 	"net/http"
 	"os"
 	"strings"
@@ -16,6 +18,38 @@ type chatRequest struct {
 	} `json:"messages"`
 }
 
+type embeddingsRequest struct {
+	Model string          `json:"model"`
+	Input json.RawMessage `json:"input"`
+}
+
+// embeddingDims is the length of vectors returned by /v1/embeddings.
+const embeddingDims = 64
+
+// embed returns a deterministic unit vector for text by hashing lowercase
+// words into buckets, so texts sharing words score as similar.
+func embed(text string) []float32 {
+	v := make([]float32, embeddingDims)
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > 127)
+	}) {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(w))
+		v[h.Sum32()%embeddingDims]++
+	}
+	var norm float64
+	for _, x := range v {
+		norm += float64(x) * float64(x)
+	}
+	if norm > 0 {
+		n := float32(math.Sqrt(norm))
+		for i := range v {
+			v[i] /= n
+		}
+	}
+	return v
+}
+
 func main() {
 	model := os.Getenv("MODEL_ID")
 	if strings.TrimSpace(model) == "" {
@@ -105,6 +139,30 @@ func main() {
 		})
 	})
 
+	mux.HandleFunc("/v1/embeddings", func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		var req embeddingsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		var inputs []string
+		if err := json.Unmarshal(req.Input, &inputs); err != nil {
+			var one string
+			if err := json.Unmarshal(req.Input, &one); err != nil {
+				http.Error(w, "input must be a string or array of strings", http.StatusBadRequest)
+				return
+			}
+			inputs = []string{one}
+		}
+		data := make([]map[string]any, 0, len(inputs))
+		for i, in := range inputs {
+			data = append(data, map[string]any{"object": "embedding", "index": i, "embedding": embed(in)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"object": "list", "model": req.Model, "data": data})
+	})
+
 	log.Printf("openai-stub listening on %s (model=%s)", addr, model)
 	if err := http.ListenAndServe(addr, mux); err != nil {
 		log.Fatal(err)
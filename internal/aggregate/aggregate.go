@@ -1,15 +1,20 @@
 package aggregate
 
 import (
-	"net/url"
-	"strings"
-
 	"github.com/hyperifyio/goresearch/internal/search"
+	"github.com/hyperifyio/goresearch/internal/urlnorm"
 )
 
 // MergeAndNormalize merges results from multiple queries, canonicalizes URLs,
 // trims obvious tracking parameters, and de-duplicates exact URLs.
 func MergeAndNormalize(groups [][]search.Result) []search.Result {
+	return MergeAndNormalizeWith(groups, urlnorm.Options{})
+}
+
+// MergeAndNormalizeWith is MergeAndNormalize with explicit normalization
+// options. Results whose URLs share a urlnorm key (e.g. AMP or mobile
+// variants, trailing-slash differences) are merged, keeping the first.
+func MergeAndNormalizeWith(groups [][]search.Result, opt urlnorm.Options) []search.Result {
 	seen := map[string]struct{}{}
 	out := make([]search.Result, 0, 64)
 	for _, g := range groups {
@@ -17,30 +22,18 @@ func MergeAndNormalize(groups [][]search.Result) []search.Result {
 			if r.URL == "" {
 				continue
 			}
-			u, err := url.Parse(r.URL)
+			clean, err := opt.Clean(r.URL)
 			if err != nil {
 				continue
 			}
-			normalizeURL(u)
-			key := u.String()
+			key := opt.Key(clean)
 			if _, ok := seen[key]; ok {
 				continue
 			}
 			seen[key] = struct{}{}
-			r.URL = key
+			r.URL = clean
 			out = append(out, r)
 		}
 	}
 	return out
 }
-
-func normalizeURL(u *url.URL) {
-	u.Fragment = ""
-	u.Host = strings.ToLower(u.Host)
-	q := u.Query()
-	// Remove common tracking params
-	for _, p := range []string{"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content", "utm_id", "gclid", "fbclid"} {
-		q.Del(p)
-	}
-	u.RawQuery = q.Encode()
-}
@@ -23,3 +23,17 @@ func TestMergeAndNormalize_Dedup_TrimUTM(t *testing.T) {
 		t.Fatalf("unexpected normalized url: %q", out[0].URL)
 	}
 }
+
+func TestMergeAndNormalize_FoldsVariantsKeepsFetchableURL(t *testing.T) {
+	groups := [][]search.Result{
+		{{Title: "AMP", URL: "https://m.example.com:443/story/amp?fbclid=1"}},
+		{{Title: "Desktop", URL: "https://example.com/story/"}},
+	}
+	out := MergeAndNormalize(groups)
+	if len(out) != 1 {
+		t.Fatalf("expected variants to merge, got %+v", out)
+	}
+	if out[0].URL != "https://m.example.com/story/amp" {
+		t.Fatalf("expected cleaned original URL, got %q", out[0].URL)
+	}
+}
@@ -2,9 +2,12 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -13,16 +16,21 @@ import (
 	"github.com/hyperifyio/goresearch/internal/aggregate"
 	"github.com/hyperifyio/goresearch/internal/brief"
 	"github.com/hyperifyio/goresearch/internal/cache"
+	"github.com/hyperifyio/goresearch/internal/dates"
 	"github.com/hyperifyio/goresearch/internal/extract"
 	"github.com/hyperifyio/goresearch/internal/fetch"
+	"github.com/hyperifyio/goresearch/internal/langid"
     "github.com/hyperifyio/goresearch/internal/llm"
+	"github.com/hyperifyio/goresearch/internal/netguard"
 	"github.com/hyperifyio/goresearch/internal/robots"
 	"github.com/hyperifyio/goresearch/internal/planner"
 	"github.com/hyperifyio/goresearch/internal/search"
+	"github.com/hyperifyio/goresearch/internal/sourcetype"
 	sel "github.com/hyperifyio/goresearch/internal/select"
 	"github.com/hyperifyio/goresearch/internal/synth"
 	"github.com/hyperifyio/goresearch/internal/validate"
 	"github.com/hyperifyio/goresearch/internal/verify"
+	"github.com/hyperifyio/goresearch/internal/warc"
 )
 
 // extractRobotsDetails attempts to pull structured details out of robots-related
@@ -90,7 +98,7 @@ func New(ctx context.Context, cfg Config) (*App, error) {
 	}
 
 	// Use a high-throughput HTTP client to avoid client-side throttling
-	transportCfg.HTTPClient = newHighThroughputHTTPClient(cfg.SSLVerify)
+	transportCfg.HTTPClient = newHTTPClient(cfg)
     client := openai.NewClientWithConfig(transportCfg)
     // Wrap in provider adapter implementing the llm.Client interface
     a := &App{cfg: cfg, ai: &llm.OpenAIProvider{Inner: client}}
@@ -162,11 +170,10 @@ func (a *App) Run(ctx context.Context) error {
     // Persist early artifacts so cancel at any point leaves breadcrumbs
     if strings.TrimSpace(a.cfg.ReportsDir) != "" { _ = os.MkdirAll(a.cfg.ReportsDir, 0o755); _ = exportArtifactsBundle(a.cfg, b, plan, nil, nil, "") }
         // Fake search with zero provider if not configured
-    var provider search.Provider
-    if a.cfg.FileSearchPath != "" {
-        provider = &search.FileProvider{Path: a.cfg.FileSearchPath, Policy: search.DomainPolicy{Allowlist: a.cfg.DomainAllowlist, Denylist: a.cfg.DomainDenylist}}
-    } else if a.cfg.SearxURL != "" {
-        provider = &search.SearxNG{BaseURL: a.cfg.SearxURL, APIKey: a.cfg.SearxKey, HTTPClient: newHighThroughputHTTPClient(a.cfg.SSLVerify), UserAgent: "goresearch/1.0 (+https://github.com/hyperifyio/goresearch)", Policy: search.DomainPolicy{Allowlist: a.cfg.DomainAllowlist, Denylist: a.cfg.DomainDenylist}}
+    health := &search.HealthTracker{}
+    provider, err := buildSearchProvider(a.cfg, health)
+    if err != nil {
+        return fmt.Errorf("search provider: %w", err)
     }
 		var selected []search.Result
 		if provider != nil {
@@ -180,8 +187,11 @@ func (a *App) Run(ctx context.Context) error {
 				}
 				groups = append(groups, results)
 			}
-			merged := aggregate.MergeAndNormalize(groups)
-			selected = sel.Select(merged, sel.Options{MaxTotal: a.cfg.MaxSources, PerDomain: a.cfg.PerDomainCap, MinSnippetChars: a.cfg.MinSnippetChars, PreferredLanguage: a.cfg.LanguageHint})
+            logSearchHealth(health)
+			merged := aggregate.MergeAndNormalizeWith(groups, urlnormOptions(a.cfg))
+			opt := selectOptions(a.cfg)
+			merged, opt.Ranked = rerankResults(ctx, a.ai, a.cfg, b, plan.Outline, merged)
+			selected = sel.Select(merged, opt)
             urls := make([]string, 0, len(selected))
             for _, r := range selected { urls = append(urls, r.URL) }
             log.Info().Str("stage", "selection").Int("selected", len(selected)).Strs("urls", urls).Dur("elapsed", time.Since(stageStart)).Msg("search+selection completed")
@@ -249,18 +259,13 @@ func (a *App) Run(ctx context.Context) error {
 
     // 3) Perform searches and aggregate
     stageStart = time.Now()
-    var provider search.Provider
     // Support file-based provider for deterministic/local runs (parity with dry-run)
-    if a.cfg.FileSearchPath != "" {
-        provider = &search.FileProvider{Path: a.cfg.FileSearchPath}
-    } else if a.cfg.SearxURL != "" {
-        ua := a.cfg.SearxUA
-        if strings.TrimSpace(ua) == "" {
-            ua = "goresearch/1.0 (+https://github.com/hyperifyio/goresearch)"
-        }
-        provider = &search.SearxNG{BaseURL: a.cfg.SearxURL, APIKey: a.cfg.SearxKey, HTTPClient: newHighThroughputHTTPClient(a.cfg.SSLVerify), UserAgent: ua, Policy: search.DomainPolicy{Allowlist: a.cfg.DomainAllowlist, Denylist: a.cfg.DomainDenylist}}
+    health := &search.HealthTracker{}
+    provider, err := buildSearchProvider(a.cfg, health)
+    if err != nil {
+        return fmt.Errorf("search provider: %w", err)
     }
-	var selected []search.Result
+	var selected, reserve []search.Result
 	if provider != nil {
 		groups := make([][]search.Result, 0, len(plan.Queries))
 		for _, q := range plan.Queries {
@@ -271,8 +276,11 @@ func (a *App) Run(ctx context.Context) error {
 			}
 			groups = append(groups, results)
 		}
-		merged := aggregate.MergeAndNormalize(groups)
-		selected = sel.Select(merged, sel.Options{MaxTotal: a.cfg.MaxSources, PerDomain: a.cfg.PerDomainCap, MinSnippetChars: a.cfg.MinSnippetChars, PreferredLanguage: a.cfg.LanguageHint})
+		logSearchHealth(health)
+		merged := aggregate.MergeAndNormalizeWith(groups, urlnormOptions(a.cfg))
+		opt := selectOptions(a.cfg)
+		merged, opt.Ranked = rerankResults(ctx, a.ai, a.cfg, b, plan.Outline, merged)
+		selected, reserve = selectWithReserve(merged, opt)
 	}
     // Log selected URLs for traceability
     if len(selected) > 0 {
@@ -295,29 +303,56 @@ func (a *App) Run(ctx context.Context) error {
 
 	// 4) Fetch and extract content for each selected URL with polite settings
     stageStart = time.Now()
-	httpClient := newHighThroughputHTTPClient(a.cfg.SSLVerify)
+	httpClient := newHTTPClient(a.cfg)
+    warnProxiedGuard(a.cfg)
+    var archive *warc.Archive
+    if len(a.cfg.WARCInputs) > 0 {
+        var err error
+        archive, err = warc.Open(a.cfg.WARCInputs...)
+        if err != nil {
+            return fmt.Errorf("open WARC input: %w", err)
+        }
+        log.Info().Strs("files", a.cfg.WARCInputs).Int("urls", archive.Len()).Msg("serving fetches from WARC archives")
+    }
+    recorder := openWARC(a.cfg, b)
     // Configure robots manager for crawl-delay and polite fetching
-    rb := &robots.Manager{HTTPClient: httpClient, Cache: a.httpCache, UserAgent: "goresearch/1.0 (+https://github.com/hyperifyio/goresearch)", EntryExpiry: 30 * time.Minute, AllowPrivateHosts: a.cfg.AllowPrivateHosts, OverrideAllowlist: a.cfg.RobotsOverrideAllowlist, OverrideConfirm: a.cfg.RobotsOverrideConfirm}
+    rb := &robots.Manager{HTTPClient: httpClient, Cache: a.httpCache, UserAgent: "goresearch/1.0 (+https://github.com/hyperifyio/goresearch)", EntryExpiry: 30 * time.Minute, AllowPrivateHosts: a.cfg.AllowPrivateHosts, OverrideAllowlist: a.cfg.RobotsOverrideAllowlist, OverrideConfirm: a.cfg.RobotsOverrideConfirm, WARC: recorder}
     f := &fetchClient{client: &fetch.Client{
 		HTTPClient:        httpClient,
 		UserAgent:         "goresearch/1.0 (+https://github.com/hyperifyio/goresearch)",
-		MaxAttempts:       2,
+		MaxAttempts:       fetchMaxAttempts(a.cfg),
+		HostRPS:           a.cfg.FetchHostRPS,
+		HostBurst:         a.cfg.FetchHostBurst,
+		GlobalRPS:         a.cfg.FetchGlobalRPS,
 		PerRequestTimeout: 15 * time.Second,
 		Cache:             a.httpCache,
 		RedirectMaxHops:   5,
-		MaxConcurrent:     8,
+		MaxConcurrent:     fetchConcurrency(a.cfg),
+		MaxPerHost:        1,
 		BypassCache:       a.cfg.CacheMaxAge == 0 && a.cfg.CacheClear, // bypass when user forces clear
         AllowPrivateHosts: a.cfg.AllowPrivateHosts,
         EnablePDF:         a.cfg.EnablePDF,
+        AcceptTypes:       acceptTypes(a.cfg),
+        MaxBodyBytes:      a.cfg.FetchMaxBodyBytes,
+        BodyLimits:        bodyLimits(a.cfg),
+        MemoryBudget:      fetchMemoryBudget(a.cfg),
         Robots:            rb,
         DomainAllowlist:   a.cfg.DomainAllowlist,
         DomainDenylist:    a.cfg.DomainDenylist,
-    }, cacheOnly: a.cfg.HTTPCacheOnly, httpCache: a.httpCache}
+        WARC:              recorder,
+    }, cacheOnly: a.cfg.HTTPCacheOnly, httpCache: a.httpCache, archive: archive}
+    if archive != nil {
+        // Archived bodies get the live size limits before they are buffered.
+        archive.BodyLimit = f.client.BodyLimit
+    }
     // Use adapter-based extractor to enable swap of readability tactics
-    excerpts, skipped := fetchAndExtract(ctx, f, extract.HeuristicExtractor{}, selected, a.cfg)
+    excerpts, skipped, fetches := fetchAndExtractUnique(ctx, f, htmlExtractor(a.cfg), selected, reserve, a.cfg)
+    if err := recorder.Close(); err != nil {
+        log.Warn().Err(err).Msg("closing WARC file failed")
+    }
 	// Proportionally truncate excerpts to fit global context budget while preserving all sources
 	excerpts = proportionallyTruncateExcerpts(b, plan.Outline, excerpts, a.cfg)
-    log.Info().Str("stage", "extract").Int("excerpts", len(excerpts)).Dur("elapsed", time.Since(stageStart)).Msg("fetch+extract completed")
+    log.Info().Str("stage", "extract").Int("excerpts", len(excerpts)).Int("fetched", len(fetches)).Int("concurrency", fetchConcurrency(a.cfg)).Dur("elapsed", time.Since(stageStart)).Msg("fetch+extract completed")
     // Persist extracts snapshot
     if strings.TrimSpace(a.cfg.ReportsDir) != "" { _ = os.MkdirAll(a.cfg.ReportsDir, 0o755); _ = exportArtifactsBundle(a.cfg, b, plan, selected, excerpts, "") }
     // Graceful cancel: if interrupted after extraction, persist artifacts and exit
@@ -349,8 +384,9 @@ func (a *App) Run(ctx context.Context) error {
 	}
     log.Info().Str("stage", "synth").Int("chars", len(md)).Dur("elapsed", time.Since(stageStart)).Msg("synthesis completed")
 
-    // 5b) Enrich references: stable URLs, DOI links, and access dates
-    md = enrichReferences(md, nil)
+    // 5b) Enrich references: stable URLs, source attribution, DOI links, and access dates
+    md = enrichReferences(md, excerpts, nil)
+    md = annotateReferenceTypes(md, excerpts)
 
 	// 6) Validate structure and citations. If invalid, keep document but append a warning.
     stageStart = time.Now()
@@ -434,6 +470,7 @@ func (a *App) Run(ctx context.Context) error {
     // 9) Append embedded manifest and write a sidecar JSON manifest
     stageStart = time.Now()
 	manEntries := buildManifestEntriesFromSynth(excerpts)
+	annotateCredibility(manEntries, append(append([]search.Result{}, selected...), reserve...), sourceScorer(a.cfg))
 	manMeta := manifestMeta{
 		Model:       a.cfg.LLMModel,
 		LLMBaseURL:  a.cfg.LLMBaseURL,
@@ -441,7 +478,15 @@ func (a *App) Run(ctx context.Context) error {
 		HTTPCache:   a.httpCache != nil,
 		LLMCache:    true,
 		GeneratedAt: time.Now().UTC(),
+		SearchHealth: newSearchHealth(health),
+		Fetches:     fetches,
 	}
+	if recorder != nil {
+		manMeta.WARCFile = filepath.Base(recorder.Path())
+	}
+	manMeta.WARCInputs = a.cfg.WARCInputs
+	manMeta.Extractor = extractorName(a.cfg)
+	manMeta.ExtractFormat = extractFormat(a.cfg)
     // Include a list of skipped URLs due to robots/opt-out decisions in the manifest
     md = appendEmbeddedManifestWithSkipped(md, manMeta, manEntries, skipped)
     // If tools were used this run and a transcript exists, append it
@@ -519,26 +564,21 @@ type fetchClient struct {
 	client *fetch.Client
     cacheOnly bool
     httpCache *cache.HTTPCache
+    // archive, when set, serves recorded responses before the cache or
+    // the network.
+    archive *warc.Archive
 }
 
 func (f *fetchClient) get(ctx context.Context, url string) ([]byte, string, error) {
     if f == nil {
         return nil, "", fmt.Errorf("fetch client not configured")
     }
+    if res, ok, err := f.fromArchive(url); ok {
+        return res.Body, res.ContentType, err
+    }
     if f.cacheOnly {
-        if f.httpCache == nil {
-            return nil, "", fmt.Errorf("http cache-only mode but no cache configured")
-        }
-        // Load without network; fail fast on miss
-        body, err := f.httpCache.LoadBody(ctx, url)
-        if err != nil {
-            return nil, "", fmt.Errorf("http cache-only: not found")
-        }
-        meta, err := f.httpCache.LoadMeta(ctx, url)
-        if err != nil || meta == nil {
-            return nil, "", fmt.Errorf("http cache-only: not found meta")
-        }
-        return body, meta.ContentType, nil
+        res, err := f.fromCache(ctx, url)
+        return res.Body, res.ContentType, err
     }
     if f.client == nil {
         return nil, "", fmt.Errorf("fetch client not configured")
@@ -546,6 +586,86 @@ func (f *fetchClient) get(ctx context.Context, url string) ([]byte, string, erro
     return f.client.Get(ctx, url)
 }
 
+// fetch is get plus the final URL after redirects and, for cached copies,
+// their freshness.
+func (f *fetchClient) fetch(ctx context.Context, url string) (fetch.Result, error) {
+    if f != nil {
+        if res, ok, err := f.fromArchive(url); ok {
+            return res, err
+        }
+        if f.cacheOnly {
+            return f.fromCache(ctx, url)
+        }
+    }
+    if f == nil || f.client == nil {
+        body, ct, err := f.get(ctx, url)
+        return fetch.Result{Body: body, ContentType: ct, FinalURL: url}, err
+    }
+    return f.client.Fetch(ctx, url)
+}
+
+// fromCache serves url from the HTTP cache without network, failing fast on
+// a miss. Expired copies are still served, and reported as stale.
+func (f *fetchClient) fromCache(ctx context.Context, url string) (fetch.Result, error) {
+    if f.httpCache == nil {
+        return fetch.Result{}, fmt.Errorf("http cache-only mode but no cache configured")
+    }
+    body, err := f.httpCache.LoadBody(ctx, url)
+    if err != nil {
+        return fetch.Result{}, fmt.Errorf("http cache-only: not found")
+    }
+    meta, err := f.httpCache.LoadMeta(ctx, url)
+    if err != nil || meta == nil {
+        return fetch.Result{}, fmt.Errorf("http cache-only: not found meta")
+    }
+    // Cached bodies are stored as served; transcode HTML like a live fetch.
+    cs := meta.Charset
+    if fetch.IsTextContentType(meta.ContentType) {
+        body, cs = fetch.DecodeHTML(body, meta.ContentType, meta.Charset)
+    }
+    status := fetch.CacheStale
+    if meta.Fresh(time.Now()) {
+        status = fetch.CacheFresh
+    }
+    return fetch.Result{Body: body, ContentType: meta.ContentType, FinalURL: pickNonEmpty(meta.FinalURL, url), Charset: cs, CacheStatus: status}, nil
+}
+
+// fromArchive serves url from the WARC archive. ok is false when the archive
+// has no record for it, so the caller falls back to the cache or network; a
+// recorded error status is returned as is, reproducing the original run.
+func (f *fetchClient) fromArchive(url string) (fetch.Result, bool, error) {
+    if f.archive == nil {
+        return fetch.Result{}, false, nil
+    }
+    resp, err := f.archive.Fetch(url)
+    if errors.Is(err, warc.ErrNotFound) {
+        return fetch.Result{}, false, nil
+    }
+    var tl warc.TooLargeError
+    if errors.As(err, &tl) {
+        return fetch.Result{}, true, fetch.TooLargeError{URL: url, ContentType: tl.ContentType, Limit: tl.Limit, Declared: tl.Size}
+    }
+    if err != nil {
+        return fetch.Result{}, true, err
+    }
+    if resp.StatusCode < 200 || resp.StatusCode > 299 {
+        return fetch.Result{}, true, fmt.Errorf("warc: recorded HTTP %d for %s", resp.StatusCode, url)
+    }
+    ct := resp.Header.Get("Content-Type")
+    body := resp.Body
+    // Recorded responses obey the same domain policy, reuse opt-outs,
+    // content types and size limits as live ones.
+    if f.client != nil {
+        if err := f.client.CheckRecorded(url, resp.URL, resp.Header, body); err != nil {
+            return fetch.Result{}, true, err
+        }
+    }
+    if fetch.IsTextContentType(ct) {
+        body, _ = fetch.DecodeHTML(body, ct, "")
+    }
+    return fetch.Result{Body: body, ContentType: ct, FinalURL: resp.URL, WARCRecordID: resp.RecordID}, true, nil
+}
+
 func pickNonEmpty(a, b string) string {
 	if strings.TrimSpace(a) != "" {
 		return a
@@ -553,68 +673,266 @@ func pickNonEmpty(a, b string) string {
 	return b
 }
 
-// fetchAndExtract retrieves HTML for each selected URL independently and extracts
-// readable text. Errors are isolated per URL: failures are logged and skipped
-// rather than aborting the whole run. This satisfies the "Per-source failure
-// isolation" checklist item by ensuring one bad source does not stop progress.
 // sourceGetter abstracts the minimal fetch method used for tests.
 type sourceGetter interface {
     get(ctx context.Context, url string) ([]byte, string, error)
 }
 
-func fetchAndExtract(ctx context.Context, f sourceGetter, extractor interface{ Extract([]byte) extract.Document }, selected []search.Result, cfg Config) ([]synth.SourceExcerpt, []skippedEntry) {
-    excerpts := make([]synth.SourceExcerpt, 0, len(selected))
-    skipped := make([]skippedEntry, 0)
+// redirectAwareGetter is implemented by getters that can report the URL a
+// body was finally served from.
+type redirectAwareGetter interface {
+    fetch(ctx context.Context, url string) (fetch.Result, error)
+}
+
+// getSource fetches url through f. When f can report it, the result carries
+// the final URL and the retries and throttling the fetch went through.
+func getSource(ctx context.Context, f sourceGetter, url string) (fetch.Result, error) {
+    if rf, ok := f.(redirectAwareGetter); ok {
+        return rf.fetch(ctx, url)
+    }
+    body, ct, err := f.get(ctx, url)
+    return fetch.Result{Body: body, ContentType: ct, FinalURL: url}, err
+}
+
+// warcFileName is the WARC file written into the artifacts bundle.
+const warcFileName = "fetch.warc.gz"
+
+// openWARC starts WARC recording into the brief's artifacts bundle when
+// enabled. It returns nil, which disables recording, when WARC is off, no
+// bundle directory is configured, fetches are served from cache only, or
+// the file cannot be created.
+func openWARC(cfg Config, b brief.Brief) *warc.Writer {
+    if !cfg.WARC || cfg.HTTPCacheOnly {
+        return nil
+    }
+    if strings.TrimSpace(cfg.ReportsDir) == "" {
+        log.Warn().Msg("WARC recording needs reports.dir; not recording")
+        return nil
+    }
+    w, err := warc.Create(filepath.Join(bundleDir(cfg, b), warcFileName), "goresearch/1.0 (+https://github.com/hyperifyio/goresearch)")
+    if err != nil {
+        log.Warn().Err(err).Msg("WARC recording disabled")
+        return nil
+    }
+    return w
+}
+
+// defaultFetchConcurrency is the number of sources fetched and extracted in
+// parallel when Config.FetchConcurrency is unset.
+const defaultFetchConcurrency = 8
+
+// fetchConcurrency returns the configured number of parallel fetches.
+func fetchConcurrency(cfg Config) int {
+    if cfg.FetchConcurrency <= 0 {
+        return defaultFetchConcurrency
+    }
+    return cfg.FetchConcurrency
+}
+
+// defaultFetchMaxAttempts is how many times a source is tried when
+// Config.FetchMaxAttempts is unset.
+const defaultFetchMaxAttempts = 3
+
+// fetchMaxAttempts returns the configured attempts per source.
+func fetchMaxAttempts(cfg Config) int {
+    if cfg.FetchMaxAttempts <= 0 {
+        return defaultFetchMaxAttempts
+    }
+    return cfg.FetchMaxAttempts
+}
+
+// sourceOutcome is what fetching and extracting one selected result produced:
+// an excerpt, a skip with its reason, the entries of a feed, or nothing when
+// the fetch failed.
+type sourceOutcome struct {
+    excerpt *synth.SourceExcerpt
+    skipped *skippedEntry
+    entries []search.Result
+    record  fetchRecord
+}
+
+func (o sourceOutcome) skip(url, reason string) sourceOutcome {
+    o.skipped = &skippedEntry{URL: url, Reason: reason}
+    o.record.Status, o.record.Reason = fetchStatusSkipped, reason
+    return o
+}
+
+// fetchAndExtract fetches and extracts the selected sources on a bounded
+// worker pool. Politeness is left to the fetcher (per-host limits and
+// crawl-delay scheduling). Outcomes are collected per position and then
+// numbered in selection order, so citation indices do not depend on which
+// fetch finished first. Records holds one fetch record per selected result.
+func fetchAndExtract(ctx context.Context, f sourceGetter, extractor interface{ Extract([]byte) extract.Document }, selected []search.Result, cfg Config) (excerpts []synth.SourceExcerpt, skipped []skippedEntry, records []fetchRecord) {
+    return collectOutcomes(fetchOutcomes(ctx, f, extractor, selected, cfg))
+}
+
+// fetchOutcomes runs fetchAndExtractOne for each selected result on the
+// worker pool and returns the outcomes in selection order.
+func fetchOutcomes(ctx context.Context, f sourceGetter, extractor interface{ Extract([]byte) extract.Document }, selected []search.Result, cfg Config) []sourceOutcome {
 	capChars := cfg.PerSourceChars
 	if capChars <= 0 {
 		capChars = 12_000
 	}
-	nextIndex := 1
-	for _, r := range selected {
-        body, contentType, err := f.get(ctx, r.URL)
-		if err != nil {
-            if reason, denied := fetch.IsReuseDenied(err); denied {
-                log.Info().Str("url", r.URL).Str("reason", reason).Msg("skipping due to robots/opt-out")
-                skipped = append(skipped, skippedEntry{URL: r.URL, Reason: reason})
-                continue
-            }
-            if reason, denied := fetch.IsRobotsDenied(err); denied {
-                // Try to enrich reason with details if available
-                host, agent, directive, pattern := extractRobotsDetails(err)
-                det := reason
-                if directive != "" || agent != "" || pattern != "" {
-                    // Format: reason (host=.. ua=.. dir=.. pattern=..)
-                    det = det + formatRobotsDetails(host, agent, directive, pattern)
-                }
-                log.Info().Str("url", r.URL).Str("reason", det).Msg("skipping due to robots/disallow")
-                skipped = append(skipped, skippedEntry{URL: r.URL, Reason: det})
-                continue
+    outcomes := make([]sourceOutcome, len(selected))
+    jobs := make(chan int)
+    var wg sync.WaitGroup
+    for w := 0; w < min(fetchConcurrency(cfg), len(selected)); w++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for i := range jobs {
+                outcomes[i] = fetchAndExtractOne(ctx, f, extractor, selected[i], cfg, capChars)
             }
-            log.Warn().Err(err).Str("url", r.URL).Msg("fetch failed; skipping source")
-            continue
-		}
-        // Choose extraction strategy based on content type and config
-        var doc extract.Document
-        if cfg.EnablePDF && strings.HasPrefix(strings.ToLower(contentType), "application/pdf") {
-            doc = extract.FromPDF(body)
-        } else {
-            if extractor != nil {
-                doc = extractor.Extract(body)
-            } else {
-                doc = extract.FromHTML(body)
+        }()
+    }
+    for i := range selected {
+        jobs <- i
+    }
+    close(jobs)
+    wg.Wait()
+    return outcomes
+}
+
+// collectOutcomes numbers the excerpts densely in outcome order and gathers
+// the skips and fetch records.
+func collectOutcomes(outcomes []sourceOutcome) (excerpts []synth.SourceExcerpt, skipped []skippedEntry, records []fetchRecord) {
+    excerpts = make([]synth.SourceExcerpt, 0, len(outcomes))
+    skipped = make([]skippedEntry, 0)
+    records = make([]fetchRecord, 0, len(outcomes))
+    for _, o := range outcomes {
+        records = append(records, o.record)
+        if o.skipped != nil {
+            skipped = append(skipped, *o.skipped)
+        }
+        if o.excerpt != nil {
+            o.excerpt.Index = len(excerpts) + 1
+            excerpts = append(excerpts, *o.excerpt)
+        }
+    }
+    return excerpts, skipped, records
+}
+
+// fetchAndExtractOne fetches one selected result and extracts its text.
+// Errors are isolated per URL: failures are logged and reported in the
+// outcome rather than aborting the run.
+func fetchAndExtractOne(ctx context.Context, f sourceGetter, extractor interface{ Extract([]byte) extract.Document }, r search.Result, cfg Config, capChars int) sourceOutcome {
+    // The body stays reserved against the fetch memory budget until it has
+    // been extracted, not just downloaded.
+    ctx, release := fetch.HoldBudget(ctx)
+    defer release()
+    out := sourceOutcome{record: fetchRecord{URL: r.URL}}
+    start := time.Now()
+    res, err := getSource(ctx, f, r.URL)
+    out.record.FetchMillis = time.Since(start).Milliseconds()
+    out.record.Retries = res.Retries
+    out.record.ThrottleMillis = res.Throttled.Milliseconds()
+    out.record.WARCRecordID = res.WARCRecordID
+    out.record.Cache = res.CacheStatus
+    body, contentType, finalURL := res.Body, res.ContentType, res.FinalURL
+	if err != nil {
+        if reason, denied := fetch.IsReuseDenied(err); denied {
+            log.Info().Str("url", r.URL).Str("reason", reason).Msg("skipping due to robots/opt-out")
+            return out.skip(r.URL, reason)
+        }
+        if reason, tooLarge := fetch.IsTooLarge(err); tooLarge {
+            log.Info().Str("url", r.URL).Str("reason", reason).Msg("skipping oversized source")
+            return out.skip(r.URL, reason)
+        }
+        if reason, denied := netguard.IsDenied(err); denied {
+            log.Info().Str("url", r.URL).Str("reason", reason).Msg("skipping source on a non-public address")
+            return out.skip(r.URL, reason)
+        }
+        if reason, denied := fetch.IsRobotsDenied(err); denied {
+            // Try to enrich reason with details if available
+            host, agent, directive, pattern := extractRobotsDetails(err)
+            det := reason
+            if directive != "" || agent != "" || pattern != "" {
+                // Format: reason (host=.. ua=.. dir=.. pattern=..)
+                det = det + formatRobotsDetails(host, agent, directive, pattern)
             }
+            log.Info().Str("url", r.URL).Str("reason", det).Msg("skipping due to robots/disallow")
+            return out.skip(r.URL, det)
         }
-		text := doc.Text
-		if len(text) > capChars {
-			text = text[:capChars]
-		}
-		excerpts = append(excerpts, synth.SourceExcerpt{
-			Index:   nextIndex,
-			Title:   pickNonEmpty(doc.Title, r.Title),
-			URL:     r.URL,
-			Excerpt: text,
-		})
-		nextIndex++
+        log.Warn().Err(err).Str("url", r.URL).Msg("fetch failed; skipping source")
+        out.record.Status, out.record.Reason = fetchStatusFailed, err.Error()
+        return out
+	}
+    out.record.Bytes = len(body)
+    start = time.Now()
+    // A feed stands for the pages it links to; entries reached through a
+    // feed are not expanded again.
+    if r.Source != feedEntrySource {
+        if entries := feedEntries(contentType, body, pickNonEmpty(finalURL, r.URL), cfg); len(entries) > 0 {
+            log.Info().Str("url", r.URL).Int("entries", len(entries)).Msg("expanding feed into its entries")
+            out.record.ExtractMillis = time.Since(start).Milliseconds()
+            out.entries = entries
+            out.record.Status, out.record.Reason = fetchStatusExpanded, fmt.Sprintf("feed expanded into %d entries", len(entries))
+            return out
+        }
+    }
+    doc := extractorsFor(extractor, cfg).Extract(sourceContentType(contentType, r.URL), body)
+	text := doc.Text
+	if len(text) > capChars {
+		text = text[:capChars]
+	}
+    excerpt, plain := text, ""
+    if len(doc.Pages) > 0 {
+        excerpt = pagedText(doc.Pages)
+        if len(excerpt) > capChars {
+            excerpt = excerpt[:capChars]
+        }
+    } else if extractFormat(cfg) == extractFormatMarkdown && doc.Markdown != "" && doc.Markdown != doc.Text {
+        excerpt, plain = doc.Markdown, text
+        if len(excerpt) > capChars {
+            excerpt = excerpt[:capChars]
+        }
+    }
+    // Prefer the date found in the page over the provider's date.
+    published := doc.Meta.Published
+    if published.IsZero() {
+        published = r.Published
+    }
+    // Selection already ranked results the provider dated as stale behind
+    // fresher ones and kept them only when nothing better was left. A page
+    // whose own date turns out stale was picked without knowing, so it is
+    // dropped and its slot refilled from the reserve.
+    if olderThanRecency(doc.Meta.Published, cfg) && !olderThanRecency(r.Published, cfg) && !sel.IsRecencyExempt(r.URL, recencyExemptHosts(cfg)) {
+        reason := fmt.Sprintf("%s (published %s)", recencyReason, dates.Format(doc.Meta.Published))
+        log.Info().Str("url", r.URL).Str("reason", reason).Int("recency_months", cfg.RecencyMonths).Msg("skipping source older than recency window")
+        out.record.ExtractMillis = time.Since(start).Milliseconds()
+        return out.skip(r.URL, reason)
+    }
+    sourceURL, aliases := resolveSourceURL(r.URL, finalURL, doc.Meta.Canonical, urlnormOptions(cfg))
+    lang := langid.Detect(text)
+    if reason, drop := languageSkipReason(lang, cfg.AllowedLanguages); drop {
+        log.Info().Str("url", sourceURL).Str("language", lang.Lang).Float64("confidence", lang.Confidence).Msg("skipping source in a language outside lang.allow")
+        out.record.ExtractMillis = time.Since(start).Milliseconds()
+        return out.skip(sourceURL, reason)
+    }
+    if !lang.Reliable() {
+        // Record no language rather than a guess the manifest would overstate.
+        lang = langid.Result{}
+    }
+    out.record.ExtractMillis = time.Since(start).Milliseconds()
+    out.record.Status = fetchStatusOK
+	out.excerpt = &synth.SourceExcerpt{
+		Title:     pickNonEmpty(doc.Title, r.Title),
+		URL:       sourceURL,
+		Published: dates.Format(published),
+		Excerpt:   excerpt,
+		Plain:     plain,
+		Paged:     len(doc.Pages) > 0,
+		Aliases:   aliases,
+		SourceType: sourcetype.Classify(sourceURL, sourcetype.Hints{SchemaTypes: doc.Meta.SchemaTypes, OGType: doc.Meta.OGType, Scholarly: doc.Meta.Scholarly}),
+		Language:   lang.Lang,
+		LanguageConfidence: lang.Confidence,
+		WARCRecordID: res.WARCRecordID,
+		CacheStatus:  res.CacheStatus,
+		Authors:      doc.Meta.Authors,
+		Publisher:    pickNonEmpty(doc.Meta.Publisher, doc.Meta.SiteName),
+		Modified:     dates.Format(doc.Meta.Modified),
+		DOI:          doc.Meta.DOI,
+		DeclaredLanguage: doc.Meta.Language,
 	}
-    return excerpts, skipped
+    return out
 }
@@ -7,6 +7,7 @@ import (
     "net/http/httptest"
     "strings"
     "testing"
+    "time"
 
     "github.com/hyperifyio/goresearch/internal/search"
 )
@@ -32,7 +33,7 @@ func TestFetchAndExtract_PDFSwitch(t *testing.T) {
 
     selected := []search.Result{{Title: "PDF", URL: pdfSrv.URL}}
     cfg := Config{PerSourceChars: 1000, EnablePDF: true}
-    excerpts, skipped := fetchAndExtract(context.Background(), getter, nil, selected, cfg)
+    excerpts, skipped, _ := fetchAndExtract(context.Background(), getter, nil, selected, cfg)
     if len(excerpts) != 1 || len(skipped) != 0 {
         t.Fatalf("expected 1 excerpt and 0 skipped, got excerpts=%d skipped=%d", len(excerpts), len(skipped))
     }
@@ -41,7 +42,104 @@ func TestFetchAndExtract_PDFSwitch(t *testing.T) {
     }
 }
 
+// Test PDF excerpts mark their pages so the model can cite them.
+func TestFetchAndExtract_PDFPagesAreMarked(t *testing.T) {
+    // No xref table: the reader rebuilds it by scanning for objects.
+    pdf := `%PDF-1.4
+1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj
+2 0 obj << /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 >> endobj
+3 0 obj << /Type /Page /Contents 5 0 R >> endobj
+4 0 obj << /Type /Page /Contents 6 0 R >> endobj
+5 0 obj << /Length 38 >> stream
+BT 72 700 Td (Methods are here.) Tj ET
+endstream endobj
+6 0 obj << /Length 38 >> stream
+BT 72 700 Td (Results are here.) Tj ET
+endstream endobj
+trailer << /Root 1 0 R >>
+%%EOF`
+    getter := sourceGetterFunc(func(ctx context.Context, url string) ([]byte, string, error) {
+        return []byte(pdf), "application/pdf", nil
+    })
+    selected := []search.Result{{Title: "Paper", URL: "https://papers.example/p.pdf"}}
+    excerpts, _, _ := fetchAndExtract(context.Background(), getter, nil, selected, Config{PerSourceChars: 1000, EnablePDF: true})
+    if len(excerpts) != 1 {
+        t.Fatalf("expected 1 excerpt, got %d", len(excerpts))
+    }
+    want := "[Page 1]\nMethods are here.\n\n[Page 2]\nResults are here."
+    if excerpts[0].Excerpt != want || !excerpts[0].Paged {
+        t.Fatalf("expected page-marked excerpt, got paged=%v %q", excerpts[0].Paged, excerpts[0].Excerpt)
+    }
+}
+
 // sourceGetterFunc adapts a function to sourceGetter interface for tests.
 type sourceGetterFunc func(ctx context.Context, url string) ([]byte, string, error)
 
 func (f sourceGetterFunc) get(ctx context.Context, url string) ([]byte, string, error) { return f(ctx, url) }
+
+// Test fetchAndExtract prefers page metadata dates over provider dates.
+func TestFetchAndExtract_PublishedDate(t *testing.T) {
+    pages := map[string]string{
+        "https://a.example/": `<html><head><meta property="article:published_time" content="2021-05-04T10:00:00Z"></head><body><p>Dated page</p></body></html>`,
+        "https://b.example/": `<html><body><p>Undated page</p></body></html>`,
+    }
+    getter := sourceGetterFunc(func(ctx context.Context, url string) ([]byte, string, error) {
+        return []byte(pages[url]), "text/html", nil
+    })
+    selected := []search.Result{
+        {Title: "A", URL: "https://a.example/", Published: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)},
+        {Title: "B", URL: "https://b.example/", Published: time.Date(2020, 2, 3, 0, 0, 0, 0, time.UTC)},
+    }
+    excerpts, _, _ := fetchAndExtract(context.Background(), getter, nil, selected, Config{PerSourceChars: 1000, RecencyMonths: 12})
+    if len(excerpts) != 2 {
+        t.Fatalf("expected 2 excerpts, got %d", len(excerpts))
+    }
+    if excerpts[0].Published != "2021-05-04" {
+        t.Fatalf("expected page date, got %q", excerpts[0].Published)
+    }
+    if excerpts[1].Published != "2020-02-03" {
+        t.Fatalf("expected provider date fallback, got %q", excerpts[1].Published)
+    }
+}
+
+// Test a page whose own date is older than the recency window is skipped
+// and refilled from the reserve, unless selection already knew it was old.
+func TestFetchAndExtractUnique_SkipsPagesOlderThanRecencyWindow(t *testing.T) {
+    old := `<html><head><meta property="article:published_time" content="2015-05-04T10:00:00Z"></head><body><p>Old page</p></body></html>`
+    getter := sourceGetterFunc(func(ctx context.Context, url string) ([]byte, string, error) {
+        if url == "https://fresh.example/" {
+            return []byte(`<html><body><p>Fresh page</p></body></html>`), "text/html", nil
+        }
+        return []byte(old), "text/html", nil
+    })
+    selected := []search.Result{
+        {Title: "Undated", URL: "https://undated.example/"},
+        {Title: "Known old", URL: "https://known.example/", Published: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)},
+    }
+    reserve := []search.Result{{Title: "Fresh", URL: "https://fresh.example/"}}
+    excerpts, skipped, _ := fetchAndExtractUnique(context.Background(), getter, nil, selected, reserve, Config{PerSourceChars: 1000, RecencyMonths: 12})
+    if len(excerpts) != 2 || excerpts[0].URL != "https://known.example/" || excerpts[1].URL != "https://fresh.example/" {
+        t.Fatalf("expected the known-old page kept and the reserve refilled, got %+v", excerpts)
+    }
+    if len(skipped) != 1 || skipped[0].URL != "https://undated.example/" || !strings.HasPrefix(skipped[0].Reason, recencyReason) {
+        t.Fatalf("expected the undated page skipped as old, got %+v", skipped)
+    }
+}
+
+// Test fetchAndExtract carries page attribution metadata into the excerpt.
+func TestFetchAndExtract_SourceMetadata(t *testing.T) {
+    page := `<html lang="en"><head><meta name="author" content="Ann Lee"><meta property="og:site_name" content="Example News">` +
+        `<meta property="article:modified_time" content="2024-02-03"><meta name="citation_doi" content="10.5555/n.1"></head><body><p>Story text</p></body></html>`
+    getter := sourceGetterFunc(func(ctx context.Context, url string) ([]byte, string, error) {
+        return []byte(page), "text/html", nil
+    })
+    selected := []search.Result{{Title: "Story", URL: "https://news.example/story"}}
+    excerpts, _, _ := fetchAndExtract(context.Background(), getter, nil, selected, Config{PerSourceChars: 1000})
+    if len(excerpts) != 1 {
+        t.Fatalf("expected 1 excerpt, got %d", len(excerpts))
+    }
+    e := excerpts[0]
+    if len(e.Authors) != 1 || e.Authors[0] != "Ann Lee" || e.Publisher != "Example News" || e.Modified != "2024-02-03" || e.DOI != "10.5555/n.1" || e.DeclaredLanguage != "en" {
+        t.Fatalf("unexpected source metadata: %+v", e)
+    }
+}
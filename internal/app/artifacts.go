@@ -16,6 +16,7 @@ import (
     "time"
 
     "github.com/hyperifyio/goresearch/internal/brief"
+    "github.com/hyperifyio/goresearch/internal/credibility"
     "github.com/hyperifyio/goresearch/internal/planner"
     "github.com/hyperifyio/goresearch/internal/search"
     "github.com/hyperifyio/goresearch/internal/synth"
@@ -32,7 +33,7 @@ func exportArtifactsBundle(cfg Config, b brief.Brief, plan planner.Plan, selecte
     if topic == "" {
         topic = "topic"
     }
-    dir := filepath.Join(root, slugify(topic))
+    dir := bundleDir(cfg, b)
     if err := os.MkdirAll(dir, 0o755); err != nil {
         return fmt.Errorf("mkdir bundle dir: %w", err)
     }
@@ -42,10 +43,16 @@ func exportArtifactsBundle(cfg Config, b brief.Brief, plan planner.Plan, selecte
         return err
     }
     // 2) selected.json (stable order by URL)
-    type selectedItem struct{ Title, URL, Snippet string }
+    type selectedItem struct {
+        Title, URL, Snippet string
+        Score               float64
+        Credibility         credibility.Breakdown
+    }
+    scorer := sourceScorer(cfg)
     sel := make([]selectedItem, 0, len(selected))
     for _, r := range selected {
-        sel = append(sel, selectedItem{Title: r.Title, URL: r.URL, Snippet: r.Snippet})
+        b := scorer.Score(r)
+        sel = append(sel, selectedItem{Title: r.Title, URL: r.URL, Snippet: r.Snippet, Score: b.Score, Credibility: b})
     }
     sort.Slice(sel, func(i, j int) bool { return strings.Compare(sel[i].URL, sel[j].URL) < 0 })
     if err := writeJSON(filepath.Join(dir, "selected.json"), sel); err != nil { return err }
@@ -80,6 +87,7 @@ func exportArtifactsBundle(cfg Config, b brief.Brief, plan planner.Plan, selecte
             GeneratedAt: time.Now().UTC(),
         }
         entries := buildManifestEntriesFromSynth(excerpts)
+        annotateCredibility(entries, selected, sourceScorer(cfg))
         if data, mErr := marshalManifestJSON(meta, entries); mErr == nil {
             _ = os.WriteFile(filepath.Join(dir, "manifest.json"), data, 0o644)
         }
@@ -108,6 +116,15 @@ func exportArtifactsBundle(cfg Config, b brief.Brief, plan planner.Plan, selecte
     return nil
 }
 
+// bundleDir returns the artifacts bundle directory for the brief's topic.
+func bundleDir(cfg Config, b brief.Brief) string {
+    topic := strings.TrimSpace(b.Topic)
+    if topic == "" {
+        topic = "topic"
+    }
+    return filepath.Join(strings.TrimSpace(cfg.ReportsDir), slugify(topic))
+}
+
 func slugify(s string) string {
     s = strings.ToLower(strings.TrimSpace(s))
     // Replace non-alphanumeric with hyphens
@@ -12,6 +12,27 @@ type Config struct {
 	SearxKey string
     SearxUA  string
     FileSearchPath string
+    // SearchProvider selects the search backend(s) as a comma-separated list
+    // of "searxng", "wikipedia" and "file". Several entries are composed into
+    // one provider. Empty means auto: file when FileSearchPath is set, else
+    // SearxNG when SearxURL is set.
+    SearchProvider string
+    // WikipediaLanguage selects the Wikipedia edition (e.g. "en", "fi") for
+    // the wikipedia provider. Defaults to LanguageHint, then "en".
+    WikipediaLanguage string
+    // WikipediaBaseURL overrides the Wikipedia edition root URL, e.g. for a
+    // mirror. When set, WikipediaLanguage is not used to build URLs.
+    WikipediaBaseURL string
+    // SearchMaxAttempts is the total number of tries per query and provider
+    // before the query counts as failed. Values <= 1 disable retries.
+    SearchMaxAttempts int
+    // SearchBackoff is the delay before the first search retry; it doubles
+    // on each further retry.
+    SearchBackoff time.Duration
+    // SearchCircuitThreshold opens a provider's circuit after this many
+    // consecutive failed queries; the provider is then skipped for the rest
+    // of the run. Zero disables circuit breaking.
+    SearchCircuitThreshold int
 
 	// LLM
 	LLMBaseURL string
@@ -28,6 +49,50 @@ type Config struct {
 	LanguageHint         string
 	MinSnippetChars      int
 	ReservedOutputTokens int
+    // RecencyMonths, when > 0, prefers sources published within the last N
+    // months: providers that support it get a matching time range and
+    // selection demotes older dated results. Zero disables the policy.
+    RecencyMonths int
+    // RecencyExemptHosts lists host patterns never treated as stale, such as
+    // standards bodies. Nil means defaultRecencyExemptHosts.
+    RecencyExemptHosts []string
+    // TrackerParams lists query parameters stripped during URL
+    // normalization; a trailing "*" matches a prefix. Nil means
+    // urlnorm.DefaultTrackerParams.
+    TrackerParams []string
+    // CredibilityFile is a YAML reputation file used to score sources.
+    // Empty uses credibility.DefaultReputation.
+    CredibilityFile string
+    // SourceTypeMin and SourceTypeMax bound how many selected sources may be
+    // of each source type (see package sourcetype), e.g. at least 2 primary
+    // sources and at most 2 forum posts.
+    SourceTypeMin map[string]int
+    SourceTypeMax map[string]int
+    // RerankModel, when non-empty, reranks search results by relevance to
+    // the brief using this embeddings model on the LLM endpoint.
+    RerankModel string
+    // RerankLambda is the MMR relevance/diversity trade-off in (0,1]; zero
+    // means rerank.DefaultLambda.
+    RerankLambda float64
+    // AllowedLanguages, when non-empty, drops sources confidently detected
+    // as a language outside this set of ISO 639-1 codes, both at selection
+    // (title and snippet) and after extraction (page text).
+    AllowedLanguages []string
+    // FetchConcurrency is how many sources are fetched and extracted in
+    // parallel; requests to any one host are still made one at a time.
+    // Zero means defaultFetchConcurrency.
+    FetchConcurrency int
+    // FetchMaxAttempts is how many times a source is tried before giving up;
+    // retries back off exponentially or wait out the server's Retry-After.
+    // Zero means defaultFetchMaxAttempts.
+    FetchMaxAttempts int
+    // FetchHostRPS caps requests per second to any single host with a token
+    // bucket of FetchHostBurst tokens. Zero disables the per-host rate limit.
+    FetchHostRPS   float64
+    FetchHostBurst int
+    // FetchGlobalRPS caps requests per second across all hosts; zero means
+    // no global cap.
+    FetchGlobalRPS float64
 
 	// Behavior
 	DryRun   bool
@@ -37,6 +102,10 @@ type Config struct {
     // on-disk HTTP cache and fails fast on a cache miss. No network requests
     // are attempted. Intended for offline/airgapped operation.
     HTTPCacheOnly bool
+    // WARCInputs lists WARC files whose recorded responses are served
+    // before the HTTP cache or the network. With HTTPCacheOnly they make a
+    // run reproducible offline from an existing crawl.
+    WARCInputs []string
     // LLMCacheOnly, when true, serves planner/synthesizer/verifier results
     // exclusively from the LLM cache and fails fast on a cache miss. No LLM
     // requests are attempted. Intended for offline/airgapped operation.
@@ -55,6 +124,15 @@ type Config struct {
     // requests. Intended for local development with self-signed certificates.
     // Default is true (verification enabled) for security.
     SSLVerify bool
+    // CAFile is an optional PEM bundle added to the system trust store for
+    // every outbound client, e.g. the CA of a TLS-inspecting egress proxy.
+    CAFile string
+    // ProxyURL, when set, routes HTTP and HTTPS traffic through this proxy
+    // instead of the one in HTTP_PROXY/HTTPS_PROXY. NoProxy likewise
+    // overrides NO_PROXY with a comma-separated list of hosts, domains and
+    // CIDRs reached directly.
+    ProxyURL string
+    NoProxy  string
 
     // Cache size limits & eviction
     // If > 0, enforce a max total on-disk size for cache entries (HTTP+LLM)
@@ -68,6 +146,26 @@ type Config struct {
     // application/pdf content types and the extractor will attempt to parse text
     // from PDFs. Default is false to avoid binary parsing risk by default.
     EnablePDF bool
+    // AcceptTypes lists media types fetched besides HTML, as patterns such
+    // as "text/plain" or "application/*+xml". Empty means plain text,
+    // Markdown, JSON and XML, including RSS/Atom feeds.
+    AcceptTypes []string
+    // ExtractMode selects the HTML extractor: "heuristic" (default) trusts
+    // <main>/<article> markup, "readability" scores content blocks and
+    // suits pages without semantic markup.
+    ExtractMode string
+    // ExtractFormat selects the excerpt representation: "text" (default)
+    // or "markdown", which keeps headings, lists, tables and code blocks.
+    // Markdown excerpts fall back to plain text when the budget is tight.
+    ExtractFormat string
+    // FetchMaxBodyBytes caps response bodies; FetchBodyLimits overrides it
+    // per media type pattern. Zero means 10 MiB, with 20 MiB for PDF, and a
+    // negative value means no limit. Oversized sources are skipped.
+    FetchMaxBodyBytes int64
+    FetchBodyLimits   map[string]int64
+    // FetchMemoryBudget bounds the bytes buffered by concurrent downloads.
+    // Zero means 128 MiB and a negative value means no budget.
+    FetchMemoryBudget int64
 
     // When set, also write a PDF copy of the final report with links.
     OutputPDFPath string
@@ -132,6 +230,9 @@ type Config struct {
     // ReportsTar, when true, also produces a tar.gz archive of the bundle and
     // a SHA256SUMS file listing digests for offline audit.
     ReportsTar bool
+    // WARC, when true, records every fetched request and response,
+    // including robots.txt and redirects, to fetch.warc.gz in the bundle.
+    WARC bool
 
     // Logging
     // LogLevel controls the verbosity of structured logs written to the log file.
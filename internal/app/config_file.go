@@ -10,6 +10,10 @@ import (
     "strings"
 
     yaml "gopkg.in/yaml.v3"
+
+    "github.com/hyperifyio/goresearch/internal/credibility"
+    "github.com/hyperifyio/goresearch/internal/extract"
+    "github.com/hyperifyio/goresearch/internal/langid"
 )
 
 // FileConfig represents the single-file configuration schema.
@@ -21,6 +25,7 @@ type FileConfig struct {
     Reports struct {
         Dir string `yaml:"dir" json:"dir"`
         Tar bool   `yaml:"tar" json:"tar"`
+        WARC bool  `yaml:"warc" json:"warc"`
     } `yaml:"reports" json:"reports"`
 
     Logging struct {
@@ -41,9 +46,18 @@ type FileConfig struct {
     } `yaml:"searx" json:"searx"`
 
     Search struct {
-        File string `yaml:"file" json:"file"`
+        File             string        `yaml:"file" json:"file"`
+        Provider         string        `yaml:"provider" json:"provider"`
+        MaxAttempts      int           `yaml:"maxAttempts" json:"maxAttempts"`
+        Backoff          time.Duration `yaml:"backoff" json:"backoff"`
+        CircuitThreshold *int          `yaml:"circuitThreshold" json:"circuitThreshold"`
     } `yaml:"search" json:"search"`
 
+    Wikipedia struct {
+        Lang string `yaml:"lang" json:"lang"`
+        URL  string `yaml:"url" json:"url"`
+    } `yaml:"wikipedia" json:"wikipedia"`
+
     Max struct {
         Sources        int `yaml:"sources" json:"sources"`
         PerDomain      int `yaml:"perDomain" json:"perDomain"`
@@ -54,6 +68,54 @@ type FileConfig struct {
         SnippetChars int `yaml:"snippetChars" json:"snippetChars"`
     } `yaml:"min" json:"min"`
 
+    Recency struct {
+        Months int      `yaml:"months" json:"months"`
+        Exempt []string `yaml:"exempt" json:"exempt"`
+    } `yaml:"recency" json:"recency"`
+
+    Credibility struct {
+        File string `yaml:"file" json:"file"`
+    } `yaml:"credibility" json:"credibility"`
+
+    Types struct {
+        Min map[string]int `yaml:"min" json:"min"`
+        Max map[string]int `yaml:"max" json:"max"`
+    } `yaml:"types" json:"types"`
+
+    Rerank struct {
+        Model  string  `yaml:"model" json:"model"`
+        Lambda float64 `yaml:"lambda" json:"lambda"`
+    } `yaml:"rerank" json:"rerank"`
+
+    Fetch struct {
+        Concurrency int     `yaml:"concurrency" json:"concurrency"`
+        MaxAttempts int     `yaml:"maxAttempts" json:"maxAttempts"`
+        HostRPS     float64 `yaml:"hostRPS" json:"hostRPS"`
+        HostBurst   int     `yaml:"hostBurst" json:"hostBurst"`
+        GlobalRPS   float64 `yaml:"globalRPS" json:"globalRPS"`
+        // Accept lists media types fetched besides HTML; see Config.AcceptTypes.
+        Accept []string `yaml:"accept" json:"accept"`
+        // Body size limits in bytes; see Config.FetchMaxBodyBytes.
+        MaxBodyBytes int64            `yaml:"maxBodyBytes" json:"maxBodyBytes"`
+        BodyLimits   map[string]int64 `yaml:"bodyLimits" json:"bodyLimits"`
+        MemoryBudget int64            `yaml:"memoryBudget" json:"memoryBudget"`
+    } `yaml:"fetch" json:"fetch"`
+
+    Extract struct {
+        // Mode selects the HTML extractor; see Config.ExtractMode.
+        Mode string `yaml:"mode" json:"mode"`
+        // Format selects the excerpt representation; see Config.ExtractFormat.
+        Format string `yaml:"format" json:"format"`
+    } `yaml:"extract" json:"extract"`
+
+    Lang struct {
+        Allow []string `yaml:"allow" json:"allow"`
+    } `yaml:"lang" json:"lang"`
+
+    URL struct {
+        TrackerParams []string `yaml:"trackerParams" json:"trackerParams"`
+    } `yaml:"url" json:"url"`
+
     Language string `yaml:"language" json:"language"`
     DryRun   bool   `yaml:"dryRun" json:"dryRun"`
     Verbose  bool   `yaml:"verbose" json:"verbose"`
@@ -74,6 +136,18 @@ type FileConfig struct {
 
     EnablePDF bool `yaml:"enablePDF" json:"enablePDF"`
 
+    // WARC lists archives served as an offline source; see Config.WARCInputs.
+    WARC []string `yaml:"warc" json:"warc"`
+
+    SSL struct {
+        CAFile string `yaml:"caFile" json:"caFile"`
+    } `yaml:"ssl" json:"ssl"`
+
+    Proxy struct {
+        URL     string `yaml:"url" json:"url"`
+        NoProxy string `yaml:"noProxy" json:"noProxy"`
+    } `yaml:"proxy" json:"proxy"`
+
     Distribution struct {
         Enable   bool   `yaml:"enable" json:"enable"`
         Author   string `yaml:"author" json:"author"`
@@ -152,6 +226,14 @@ func ApplyFileConfig(cfg *Config, fc FileConfig) {
         toolsMaxCallsDefault     = 32
         toolsPerToolTimeoutSecs  = 10
         toolsModeDefault         = "harmony"
+        searchMaxAttemptsDefault = 2
+        searchBackoffDefault     = 500 * time.Millisecond
+        searchCircuitDefault     = 3
+        rerankLambdaDefault      = 0.7
+        fetchConcurrencyDefault  = 8
+        fetchMaxAttemptsDefault  = 3
+        fetchHostRPSDefault      = 2.0
+        fetchHostBurstDefault    = 4
     )
 
     if (cfg.InputPath == "" || cfg.InputPath == inputDefault) && fc.Input != "" { cfg.InputPath = fc.Input }
@@ -159,6 +241,7 @@ func ApplyFileConfig(cfg *Config, fc FileConfig) {
     if fc.OutputPDF != "" { cfg.OutputPDFPath = fc.OutputPDF }
     if cfg.ReportsDir == "" && fc.Reports.Dir != "" { cfg.ReportsDir = fc.Reports.Dir }
     if !cfg.ReportsTar && fc.Reports.Tar { cfg.ReportsTar = true }
+    if !cfg.WARC && fc.Reports.WARC { cfg.WARC = true }
 
     if cfg.LLMBaseURL == "" && fc.LLM.BaseURL != "" { cfg.LLMBaseURL = fc.LLM.BaseURL }
     if cfg.LLMModel == "" && fc.LLM.Model != "" { cfg.LLMModel = fc.LLM.Model }
@@ -168,12 +251,38 @@ func ApplyFileConfig(cfg *Config, fc FileConfig) {
     if cfg.SearxKey == "" && fc.Searx.Key != "" { cfg.SearxKey = fc.Searx.Key }
     if (cfg.SearxUA == "" || cfg.SearxUA == searxUADefault) && fc.Searx.UA != "" { cfg.SearxUA = fc.Searx.UA }
     if cfg.FileSearchPath == "" && fc.Search.File != "" { cfg.FileSearchPath = fc.Search.File }
+    if cfg.SearchProvider == "" && fc.Search.Provider != "" { cfg.SearchProvider = fc.Search.Provider }
+    if cfg.WikipediaLanguage == "" && fc.Wikipedia.Lang != "" { cfg.WikipediaLanguage = fc.Wikipedia.Lang }
+    if cfg.WikipediaBaseURL == "" && fc.Wikipedia.URL != "" { cfg.WikipediaBaseURL = fc.Wikipedia.URL }
+    if (cfg.SearchMaxAttempts == 0 || cfg.SearchMaxAttempts == searchMaxAttemptsDefault) && fc.Search.MaxAttempts > 0 { cfg.SearchMaxAttempts = fc.Search.MaxAttempts }
+    if (cfg.SearchBackoff == 0 || cfg.SearchBackoff == searchBackoffDefault) && fc.Search.Backoff > 0 { cfg.SearchBackoff = fc.Search.Backoff }
+    if (cfg.SearchCircuitThreshold == 0 || cfg.SearchCircuitThreshold == searchCircuitDefault) && fc.Search.CircuitThreshold != nil { cfg.SearchCircuitThreshold = *fc.Search.CircuitThreshold }
 
     if (cfg.MaxSources == 0 || cfg.MaxSources == maxSourcesDefault) && fc.Max.Sources > 0 { cfg.MaxSources = fc.Max.Sources }
     if (cfg.PerDomainCap == 0 || cfg.PerDomainCap == perDomainDefault) && fc.Max.PerDomain > 0 { cfg.PerDomainCap = fc.Max.PerDomain }
     if (cfg.PerSourceChars == 0 || cfg.PerSourceChars == perSourceCharsDefault) && fc.Max.PerSourceChars > 0 { cfg.PerSourceChars = fc.Max.PerSourceChars }
     if (cfg.MinSnippetChars == 0 || cfg.MinSnippetChars == minSnippetCharsDefault) && fc.Min.SnippetChars > 0 { cfg.MinSnippetChars = fc.Min.SnippetChars }
+    if cfg.RecencyMonths == 0 && fc.Recency.Months > 0 { cfg.RecencyMonths = fc.Recency.Months }
+    if cfg.RecencyExemptHosts == nil && fc.Recency.Exempt != nil { cfg.RecencyExemptHosts = fc.Recency.Exempt }
+    if cfg.CredibilityFile == "" && fc.Credibility.File != "" { cfg.CredibilityFile = fc.Credibility.File }
+    if cfg.TrackerParams == nil && fc.URL.TrackerParams != nil { cfg.TrackerParams = fc.URL.TrackerParams }
+    if cfg.SourceTypeMin == nil && fc.Types.Min != nil { cfg.SourceTypeMin = fc.Types.Min }
+    if cfg.SourceTypeMax == nil && fc.Types.Max != nil { cfg.SourceTypeMax = fc.Types.Max }
+    if cfg.RerankModel == "" && fc.Rerank.Model != "" { cfg.RerankModel = fc.Rerank.Model }
+    if (cfg.RerankLambda == 0 || cfg.RerankLambda == rerankLambdaDefault) && fc.Rerank.Lambda > 0 { cfg.RerankLambda = fc.Rerank.Lambda }
     if cfg.LanguageHint == "" && fc.Language != "" { cfg.LanguageHint = fc.Language }
+    if (cfg.FetchConcurrency == 0 || cfg.FetchConcurrency == fetchConcurrencyDefault) && fc.Fetch.Concurrency > 0 { cfg.FetchConcurrency = fc.Fetch.Concurrency }
+    if (cfg.FetchMaxAttempts == 0 || cfg.FetchMaxAttempts == fetchMaxAttemptsDefault) && fc.Fetch.MaxAttempts > 0 { cfg.FetchMaxAttempts = fc.Fetch.MaxAttempts }
+    if (cfg.FetchHostRPS == 0 || cfg.FetchHostRPS == fetchHostRPSDefault) && fc.Fetch.HostRPS > 0 { cfg.FetchHostRPS = fc.Fetch.HostRPS }
+    if (cfg.FetchHostBurst == 0 || cfg.FetchHostBurst == fetchHostBurstDefault) && fc.Fetch.HostBurst > 0 { cfg.FetchHostBurst = fc.Fetch.HostBurst }
+    if cfg.FetchGlobalRPS == 0 && fc.Fetch.GlobalRPS > 0 { cfg.FetchGlobalRPS = fc.Fetch.GlobalRPS }
+    if trim(cfg.ExtractMode) == "" && trim(fc.Extract.Mode) != "" { cfg.ExtractMode = fc.Extract.Mode }
+    if trim(cfg.ExtractFormat) == "" && trim(fc.Extract.Format) != "" { cfg.ExtractFormat = fc.Extract.Format }
+    if len(cfg.AcceptTypes) == 0 && len(fc.Fetch.Accept) > 0 { cfg.AcceptTypes = append([]string{}, fc.Fetch.Accept...) }
+    if cfg.FetchMaxBodyBytes == 0 && fc.Fetch.MaxBodyBytes != 0 { cfg.FetchMaxBodyBytes = fc.Fetch.MaxBodyBytes }
+    if cfg.FetchBodyLimits == nil && fc.Fetch.BodyLimits != nil { cfg.FetchBodyLimits = fc.Fetch.BodyLimits }
+    if cfg.FetchMemoryBudget == 0 && fc.Fetch.MemoryBudget != 0 { cfg.FetchMemoryBudget = fc.Fetch.MemoryBudget }
+    if cfg.AllowedLanguages == nil && fc.Lang.Allow != nil { cfg.AllowedLanguages = fc.Lang.Allow }
     if !cfg.DryRun && fc.DryRun { cfg.DryRun = true }
     if !cfg.Verbose && fc.Verbose { cfg.Verbose = true }
     if !cfg.DebugVerbose && fc.DebugVerbose { cfg.DebugVerbose = true }
@@ -205,6 +314,7 @@ func ApplyFileConfig(cfg *Config, fc FileConfig) {
     if len(cfg.RobotsOverrideAllowlist) == 0 && len(fc.Robots.OverrideDomains) > 0 { cfg.RobotsOverrideAllowlist = append([]string{}, fc.Robots.OverrideDomains...) }
     if !cfg.RobotsOverrideConfirm && fc.Robots.OverrideConfirm { cfg.RobotsOverrideConfirm = true }
 
+    if len(cfg.WARCInputs) == 0 && len(fc.WARC) > 0 { cfg.WARCInputs = append([]string{}, fc.WARC...) }
     if len(cfg.DomainAllowlist) == 0 && len(fc.Domains.Allow) > 0 { cfg.DomainAllowlist = append([]string{}, fc.Domains.Allow...) }
     if len(cfg.DomainDenylist) == 0 && len(fc.Domains.Deny) > 0 { cfg.DomainDenylist = append([]string{}, fc.Domains.Deny...) }
 
@@ -215,6 +325,11 @@ func ApplyFileConfig(cfg *Config, fc FileConfig) {
     if (cfg.ToolsPerToolTimeout == 0 || cfg.ToolsPerToolTimeout == toolsPerToolTimeoutSecs*time.Second) && fc.Tools.PerToolTimeout > 0 { cfg.ToolsPerToolTimeout = fc.Tools.PerToolTimeout }
     if (cfg.ToolsMode == "" || cfg.ToolsMode == toolsModeDefault) && fc.Tools.Mode != "" { cfg.ToolsMode = fc.Tools.Mode }
 
+    // Network
+    if trim(cfg.CAFile) == "" && trim(fc.SSL.CAFile) != "" { cfg.CAFile = fc.SSL.CAFile }
+    if trim(cfg.ProxyURL) == "" && trim(fc.Proxy.URL) != "" { cfg.ProxyURL = fc.Proxy.URL }
+    if trim(cfg.NoProxy) == "" && trim(fc.Proxy.NoProxy) != "" { cfg.NoProxy = fc.Proxy.NoProxy }
+
     // Logging
     if strings.TrimSpace(cfg.LogLevel) == "" && strings.TrimSpace(fc.Logging.Level) != "" {
         cfg.LogLevel = fc.Logging.Level
@@ -238,9 +353,44 @@ func ValidateConfig(cfg Config) error {
             return errors.New("config: llm.model is required (or set LLM_MODEL)")
         }
     }
-    if cfg.MaxSources < 0 || cfg.PerDomainCap < 0 || cfg.PerSourceChars < 0 {
+    if cfg.MaxSources < 0 || cfg.PerDomainCap < 0 || cfg.PerSourceChars < 0 || cfg.RecencyMonths < 0 || cfg.FetchConcurrency < 0 ||
+        cfg.FetchMaxAttempts < 0 || cfg.FetchHostRPS < 0 || cfg.FetchHostBurst < 0 || cfg.FetchGlobalRPS < 0 {
         return errors.New("config: negative limits are not allowed")
     }
+    if cfg.RerankLambda < 0 || cfg.RerankLambda > 1 {
+        return errors.New("config: rerank.lambda must be within [0,1]")
+    }
+    for _, l := range cfg.AllowedLanguages {
+        if !langid.Supported(l) {
+            return fmt.Errorf("config: lang.allow: unsupported language %q (supported: %s)", l, strings.Join(langid.Languages(), ", "))
+        }
+    }
+    if err := sourceTypeQuotas(cfg).Validate(); err != nil {
+        return fmt.Errorf("config: types: %w", err)
+    }
+    if _, err := extract.NewExtractor(cfg.ExtractMode); err != nil {
+        return fmt.Errorf("config: extract.mode: %w", err)
+    }
+    switch extractFormat(cfg) {
+    case extractFormatText, extractFormatMarkdown:
+    default:
+        return fmt.Errorf("config: extract.format: unknown format %q (want %s or %s)", cfg.ExtractFormat, extractFormatText, extractFormatMarkdown)
+    }
+    if trim(cfg.ProxyURL) != "" {
+        if err := validateProxyURL(trim(cfg.ProxyURL)); err != nil {
+            return fmt.Errorf("config: proxy.url: %w", err)
+        }
+    }
+    if trim(cfg.CAFile) != "" {
+        if _, err := loadCABundle(cfg.CAFile); err != nil {
+            return fmt.Errorf("config: ssl.caFile: %w", err)
+        }
+    }
+    if trim(cfg.CredibilityFile) != "" {
+        if _, err := credibility.NewScorer(cfg.CredibilityFile); err != nil {
+            return fmt.Errorf("config: credibility.file: %w", err)
+        }
+    }
     return nil
 }
 
@@ -0,0 +1,219 @@
+package app
+
+import (
+    "net/url"
+    "path"
+    "strconv"
+    "strings"
+
+    "github.com/hyperifyio/goresearch/internal/extract"
+    "github.com/hyperifyio/goresearch/internal/fetch"
+    "github.com/hyperifyio/goresearch/internal/search"
+)
+
+// defaultAcceptTypes are the media types fetched besides HTML when
+// Config.AcceptTypes is empty: plain text such as RFCs, Markdown, JSON and
+// XML, which includes RSS and Atom feeds.
+var defaultAcceptTypes = []string{
+    "text/plain",
+    "text/markdown",
+    "text/x-markdown",
+    "application/json",
+    "application/*+json",
+    "application/xml",
+    "text/xml",
+    "application/*+xml",
+}
+
+// acceptTypes returns the configured extra media types for the fetcher.
+func acceptTypes(cfg Config) []string {
+    if len(cfg.AcceptTypes) == 0 {
+        return defaultAcceptTypes
+    }
+    return cfg.AcceptTypes
+}
+
+// defaultBodyLimits apply when Config.FetchBodyLimits is empty. PDFs are
+// legitimately larger than pages but are the usual cause of huge bodies.
+var defaultBodyLimits = map[string]int64{"application/pdf": 20 << 20}
+
+// defaultFetchMemoryBudget is the memory budget when Config.FetchMemoryBudget
+// is zero.
+const defaultFetchMemoryBudget int64 = 128 << 20
+
+// bodyLimits returns the per-type body limits for the fetcher.
+func bodyLimits(cfg Config) []fetch.BodyLimit {
+    if len(cfg.FetchBodyLimits) == 0 {
+        return fetch.SortBodyLimits(defaultBodyLimits)
+    }
+    return fetch.SortBodyLimits(cfg.FetchBodyLimits)
+}
+
+// fetchMemoryBudget returns the fetcher's memory budget; a negative result
+// disables it.
+func fetchMemoryBudget(cfg Config) int64 {
+    if cfg.FetchMemoryBudget == 0 {
+        return defaultFetchMemoryBudget
+    }
+    return cfg.FetchMemoryBudget
+}
+
+// extractorName returns the configured HTML extractor's name.
+func extractorName(cfg Config) string {
+    if name := strings.ToLower(strings.TrimSpace(cfg.ExtractMode)); name != "" {
+        return name
+    }
+    return extract.ExtractorHeuristic
+}
+
+// htmlExtractor returns the configured HTML extractor. ValidateConfig
+// rejects unknown names, so the heuristic fallback only guards callers that
+// skip validation.
+func htmlExtractor(cfg Config) extract.Extractor {
+    e, err := extract.NewExtractor(cfg.ExtractMode)
+    if err != nil {
+        return extract.HeuristicExtractor{}
+    }
+    return e
+}
+
+// Excerpt formats for Config.ExtractFormat.
+const (
+    extractFormatText     = "text"
+    extractFormatMarkdown = "markdown"
+)
+
+// extractFormat returns the configured excerpt format.
+func extractFormat(cfg Config) string {
+    if f := strings.ToLower(strings.TrimSpace(cfg.ExtractFormat)); f != "" {
+        return f
+    }
+    return extractFormatText
+}
+
+// extractorsFor returns the extract registry for a run. HTML goes through
+// extractor, when given, so readability tactics stay swappable, and PDF is
+// only registered when enabled.
+func extractorsFor(extractor interface{ Extract([]byte) extract.Document }, cfg Config) *extract.Registry {
+    reg := extract.DefaultRegistry()
+    if extractor != nil {
+        reg.Register("text/html", extractor)
+        reg.Register("application/xhtml+xml", extractor)
+    }
+    if cfg.EnablePDF {
+        reg.Register("application/pdf", extract.ExtractorFunc(extract.FromPDF))
+    }
+    return reg
+}
+
+// pagedText renders page texts with a [Page N] line before each page, N
+// being the printed label when the document has one, so the model can
+// cite pages as [n, p. N].
+func pagedText(pages []extract.Page) string {
+    var b strings.Builder
+    for _, p := range pages {
+        label := p.Label
+        if label == "" {
+            label = strconv.Itoa(p.Number)
+        }
+        if b.Len() > 0 {
+            b.WriteString("\n\n")
+        }
+        b.WriteString("[Page " + label + "]\n")
+        b.WriteString(p.Text)
+    }
+    return b.String()
+}
+
+// sourceContentType refines a response's content type with the URL: raw
+// Markdown files are commonly served as text/plain.
+func sourceContentType(contentType, rawURL string) string {
+    if extract.MediaType(contentType) != "text/plain" {
+        return contentType
+    }
+    u, err := url.Parse(rawURL)
+    if err != nil {
+        return contentType
+    }
+    switch strings.ToLower(path.Ext(u.Path)) {
+    case ".md", ".markdown":
+        return "text/markdown"
+    }
+    return contentType
+}
+
+// feedEntrySource marks search results that came from expanding a feed.
+const feedEntrySource = "feed"
+
+// feedEntryLimit caps how many entries one feed contributes and how many
+// sources one domain may reach through feeds: no more than selection lets a
+// single domain have, so a feed cannot crowd out other sources.
+func feedEntryLimit(cfg Config) int {
+    if cfg.PerDomainCap > 0 {
+        return cfg.PerDomainCap
+    }
+    return 3
+}
+
+// admitFeedEntries drops feed entries whose URL is already queued or
+// fetched, and entries beyond what their domain has left of the per-domain
+// cap, so a feed cannot add pages twice or widen a domain's share. domains
+// counts the sources taken per host and queued holds the URL keys seen;
+// both are updated for the entries admitted.
+func admitFeedEntries(entries []search.Result, cfg Config, queued map[string]bool, domains map[string]int) []search.Result {
+    norm := urlnormOptions(cfg)
+    var out []search.Result
+    for _, e := range entries {
+        key, host := norm.Key(e.URL), domainKey(e.URL)
+        if queued[key] || domains[host] >= feedEntryLimit(cfg) {
+            continue
+        }
+        queued[key] = true
+        domains[host]++
+        out = append(out, e)
+    }
+    return out
+}
+
+// domainKey is the host a source counts against for the per-domain cap, as
+// in selection.
+func domainKey(rawURL string) string {
+    u, err := url.Parse(strings.TrimSpace(rawURL))
+    if err != nil {
+        return ""
+    }
+    return strings.ToLower(u.Host)
+}
+
+// feedEntries returns the linked entries of an RSS or Atom body, resolved
+// against the feed's URL, or nil when the body is not a feed. Feeds served
+// as generic XML are recognized by their root element.
+func feedEntries(contentType string, body []byte, feedURL string, cfg Config) []search.Result {
+    switch extract.MediaType(contentType) {
+    case "application/rss+xml", "application/atom+xml", "application/rdf+xml", "application/xml", "text/xml":
+    default:
+        return nil
+    }
+    feed, ok := extract.ParseFeed(body)
+    if !ok {
+        return nil
+    }
+    base, _ := url.Parse(feedURL)
+    var out []search.Result
+    for _, e := range feed.Entries {
+        if len(out) >= feedEntryLimit(cfg) {
+            break
+        }
+        link := strings.TrimSpace(e.URL)
+        if link == "" {
+            continue
+        }
+        if base != nil {
+            if u, err := base.Parse(link); err == nil {
+                link = u.String()
+            }
+        }
+        out = append(out, search.Result{Title: e.Title, URL: link, Snippet: e.Summary, Source: feedEntrySource, Published: e.Published})
+    }
+    return out
+}
@@ -0,0 +1,165 @@
+package app
+
+import (
+    "context"
+    "strings"
+    "sync"
+    "testing"
+
+    "github.com/hyperifyio/goresearch/internal/extract"
+    "github.com/hyperifyio/goresearch/internal/search"
+)
+
+// Test non-HTML sources are dispatched to their type's extractor.
+func TestFetchAndExtract_DispatchesByContentType(t *testing.T) {
+    pages := map[string][2]string{
+        "https://rfc.example/rfc9110.txt":   {"text/plain; charset=us-ascii", "Internet Engineering Task Force\n\n1.  Introduction\n\n   HTTP is a stateless protocol.\n"},
+        "https://raw.example/org/README.md": {"text/plain", "# Project\n\nRead [the guide](https://example.com/guide).\n"},
+        "https://api.example/spec.json":     {"application/json", `{"title":"Users API","paths":{"/users":{"get":{"summary":"List users"}}}}`},
+    }
+    getter := sourceGetterFunc(func(ctx context.Context, url string) ([]byte, string, error) {
+        p := pages[url]
+        return []byte(p[1]), p[0], nil
+    })
+    selected := []search.Result{
+        {Title: "RFC", URL: "https://rfc.example/rfc9110.txt"},
+        {Title: "Readme", URL: "https://raw.example/org/README.md"},
+        {Title: "Spec", URL: "https://api.example/spec.json"},
+    }
+    excerpts, skipped, _ := fetchAndExtract(context.Background(), getter, nil, selected, Config{PerSourceChars: 1000})
+    if len(excerpts) != 3 || len(skipped) != 0 {
+        t.Fatalf("expected 3 excerpts, got %d (skipped %v)", len(excerpts), skipped)
+    }
+    if !strings.Contains(excerpts[0].Excerpt, "HTTP is a stateless protocol.") {
+        t.Fatalf("plain text not kept: %q", excerpts[0].Excerpt)
+    }
+    if !strings.Contains(excerpts[1].Excerpt, "Read the guide.") || strings.Contains(excerpts[1].Excerpt, "](") {
+        t.Fatalf("raw Markdown not extracted as Markdown: %q", excerpts[1].Excerpt)
+    }
+    if !strings.Contains(excerpts[2].Excerpt, "paths./users.get.summary: List users") {
+        t.Fatalf("JSON not flattened: %q", excerpts[2].Excerpt)
+    }
+}
+
+// Test a feed is replaced by the entries it links to, which take the next
+// slots ahead of the reserve.
+func TestFetchAndExtractUnique_ExpandsFeed(t *testing.T) {
+    feed := `<?xml version="1.0"?><rss version="2.0"><channel><title>Blog</title>
+<item><title>First post</title><link>/posts/1</link><description>&lt;p&gt;One&lt;/p&gt;</description><pubDate>Mon, 04 Mar 2024 10:00:00 GMT</pubDate></item>
+<item><title>Second post</title><link>https://blog.example/posts/2</link></item>
+<item><title>No link</title></item>
+</channel></rss>`
+    pages := map[string][2]string{
+        "https://blog.example/feed.xml": {"application/rss+xml", feed},
+        "https://blog.example/posts/1":  {"text/html", "<html><body><p>Body of the first post.</p></body></html>"},
+        "https://blog.example/posts/2":  {"text/html", "<html><body><p>The second post says something else entirely.</p></body></html>"},
+        "https://reserve.example/":      {"text/html", "<html><body><p>Reserve page.</p></body></html>"},
+    }
+    getter := sourceGetterFunc(func(ctx context.Context, url string) ([]byte, string, error) {
+        p := pages[url]
+        return []byte(p[1]), p[0], nil
+    })
+    selected := []search.Result{{Title: "Blog", URL: "https://blog.example/feed.xml"}}
+    reserve := []search.Result{{Title: "Reserve", URL: "https://reserve.example/"}}
+    excerpts, _, records := fetchAndExtractUnique(context.Background(), getter, nil, selected, reserve, Config{PerSourceChars: 1000, PerDomainCap: 2})
+    if len(excerpts) != 1 || excerpts[0].URL != "https://blog.example/posts/1" || excerpts[0].Title != "First post" {
+        t.Fatalf("expected the first feed entry in the freed slot, got %+v", excerpts)
+    }
+    if excerpts[0].Published != "2024-03-04" {
+        t.Fatalf("expected the entry date, got %q", excerpts[0].Published)
+    }
+    if len(records) == 0 || records[0].Status != fetchStatusExpanded {
+        t.Fatalf("expected the feed recorded as expanded, got %+v", records)
+    }
+}
+
+// Test feed entries skip pages already selected and stay within their
+// domain's per-domain cap.
+func TestFetchAndExtractUnique_FeedEntriesRespectQueueAndDomainCap(t *testing.T) {
+    feed := `<?xml version="1.0"?><rss version="2.0"><channel><title>Blog</title>
+<item><title>Second post</title><link>https://blog.example/posts/2</link></item>
+<item><title>First post</title><link>https://blog.example/posts/1</link></item>
+<item><title>Third post</title><link>https://blog.example/posts/3</link></item>
+</channel></rss>`
+    var mu sync.Mutex
+    fetched := map[string]int{}
+    getter := sourceGetterFunc(func(ctx context.Context, url string) ([]byte, string, error) {
+        mu.Lock()
+        fetched[url]++
+        mu.Unlock()
+        if strings.HasSuffix(url, "/feed.xml") {
+            return []byte(feed), "application/rss+xml", nil
+        }
+        return []byte("<html><body><p>Distinct page at " + url + " with its own words.</p></body></html>"), "text/html", nil
+    })
+    selected := []search.Result{
+        {Title: "Blog", URL: "https://blog.example/feed.xml"},
+        {Title: "Second post", URL: "https://blog.example/posts/2"},
+        {Title: "Other", URL: "https://other.example/a"},
+    }
+    excerpts, _, _ := fetchAndExtractUnique(context.Background(), getter, nil, selected, nil, Config{PerSourceChars: 1000, PerDomainCap: 2})
+    var urls []string
+    for _, ex := range excerpts {
+        urls = append(urls, ex.URL)
+    }
+    if strings.Join(urls, " ") != "https://blog.example/posts/2 https://other.example/a https://blog.example/posts/1" {
+        t.Fatalf("unexpected sources %v", urls)
+    }
+    if fetched["https://blog.example/posts/2"] != 1 || fetched["https://blog.example/posts/3"] != 0 {
+        t.Fatalf("expected no refetch and no entry past the domain cap, got %v", fetched)
+    }
+}
+
+func TestValidateConfig_ExtractMode(t *testing.T) {
+    cfg := Config{InputPath: "in.md", OutputPath: "out.md", DryRun: true, ExtractMode: "boilerpipe"}
+    if err := ValidateConfig(cfg); err == nil || !strings.Contains(err.Error(), "extract.mode") {
+        t.Fatalf("expected extract.mode error, got %v", err)
+    }
+    cfg.ExtractMode = "readability"
+    if err := ValidateConfig(cfg); err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+    if _, ok := htmlExtractor(cfg).(extract.ReadabilityExtractor); !ok || extractorName(cfg) != "readability" {
+        t.Fatalf("expected the readability extractor, got %T", htmlExtractor(cfg))
+    }
+}
+
+// Test the markdown format keeps page structure and carries the plain text
+// for budgeting.
+func TestFetchAndExtract_MarkdownFormat(t *testing.T) {
+    page := `<html><body><main><h2>Setup</h2><ol><li>Install</li><li>Run</li></ol>
+<table><tr><th>Flag</th><th>Default</th></tr><tr><td>-v</td><td>off</td></tr></table>
+<pre><code class="language-go">fmt.Println("hi")</code></pre></main></body></html>`
+    getter := sourceGetterFunc(func(ctx context.Context, url string) ([]byte, string, error) {
+        return []byte(page), "text/html", nil
+    })
+    selected := []search.Result{{Title: "Docs", URL: "https://docs.example/setup"}}
+    excerpts, _, _ := fetchAndExtract(context.Background(), getter, nil, selected, Config{PerSourceChars: 1000, ExtractFormat: "markdown"})
+    if len(excerpts) != 1 {
+        t.Fatalf("expected 1 excerpt, got %d", len(excerpts))
+    }
+    md := excerpts[0].Excerpt
+    for _, want := range []string{"## Setup", "1. Install\n2. Run", "| Flag | Default |", "```go\nfmt.Println(\"hi\")\n```"} {
+        if !strings.Contains(md, want) {
+            t.Fatalf("markdown excerpt missing %q:\n%s", want, md)
+        }
+    }
+    if excerpts[0].Plain == "" || strings.Contains(excerpts[0].Plain, "##") {
+        t.Fatalf("expected plain text alongside, got %q", excerpts[0].Plain)
+    }
+    excerpts, _, _ = fetchAndExtract(context.Background(), getter, nil, selected, Config{PerSourceChars: 1000})
+    if strings.Contains(excerpts[0].Excerpt, "## Setup") || excerpts[0].Plain != "" {
+        t.Fatalf("text format should stay plain: %+v", excerpts[0])
+    }
+}
+
+func TestValidateConfig_ExtractFormat(t *testing.T) {
+    cfg := Config{InputPath: "in.md", OutputPath: "out.md", DryRun: true, ExtractFormat: "html"}
+    if err := ValidateConfig(cfg); err == nil || !strings.Contains(err.Error(), "extract.format") {
+        t.Fatalf("expected extract.format error, got %v", err)
+    }
+    cfg.ExtractFormat = "Markdown"
+    if err := ValidateConfig(cfg); err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+    "strings"
+    "sync"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/hyperifyio/goresearch/internal/credibility"
+    "github.com/hyperifyio/goresearch/internal/search"
+)
+
+// scorerCache memoizes loaded reputation files by path so the pipeline,
+// artifacts export and the search subcommand share one parse.
+var scorerCache sync.Map // path -> *credibility.Scorer
+
+// sourceScorer returns the credibility scorer for cfg. A reputation file that
+// fails to load is reported and replaced by the built-in defaults; call
+// ValidateConfig first to surface the error to the user.
+func sourceScorer(cfg Config) *credibility.Scorer {
+    path := strings.TrimSpace(cfg.CredibilityFile)
+    if s, ok := scorerCache.Load(path); ok {
+        return s.(*credibility.Scorer)
+    }
+    s, err := credibility.NewScorer(path)
+    if err != nil {
+        log.Warn().Err(err).Str("file", path).Msg("reputation file invalid; using defaults")
+        s = &credibility.Scorer{}
+    }
+    scorerCache.Store(path, s)
+    return s
+}
+
+// annotateCredibility attaches the score of the search result each manifest
+// entry came from. Entries are matched to candidates by URL or alias; an
+// unmatched entry is scored from its URL alone.
+func annotateCredibility(entries []manifestEntry, candidates []search.Result, scorer *credibility.Scorer) {
+    byURL := make(map[string]search.Result, len(candidates))
+    for _, r := range candidates {
+        byURL[r.URL] = r
+    }
+    for i := range entries {
+        r, ok := byURL[entries[i].URL]
+        for _, a := range entries[i].Aliases {
+            if ok {
+                break
+            }
+            r, ok = byURL[a]
+        }
+        if !ok {
+            r = search.Result{URL: entries[i].URL}
+        }
+        b := scorer.Score(r)
+        entries[i].Credibility = &b
+    }
+}
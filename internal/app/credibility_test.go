@@ -0,0 +1,41 @@
+package app
+
+import (
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "github.com/hyperifyio/goresearch/internal/credibility"
+    "github.com/hyperifyio/goresearch/internal/search"
+    "github.com/hyperifyio/goresearch/internal/synth"
+)
+
+func TestAnnotateCredibility_MatchesByAliasAndWritesManifestLine(t *testing.T) {
+    entries := buildManifestEntriesFromSynth([]synth.SourceExcerpt{
+        {Index: 1, URL: "https://www.w3.org/TR/html/", Excerpt: "spec", Aliases: []string{"https://w3.org/TR/html"}},
+    })
+    candidates := []search.Result{{Title: "HTML", URL: "https://w3.org/TR/html", Snippet: "The HTML standard"}}
+    annotateCredibility(entries, candidates, &credibility.Scorer{})
+    if entries[0].Credibility == nil || entries[0].Credibility.Tier != "primary" {
+        t.Fatalf("expected primary tier breakdown, got %+v", entries[0].Credibility)
+    }
+    if entries[0].Credibility.Snippet == 0 {
+        t.Fatalf("expected snippet quality from the aliased candidate, got %+v", entries[0].Credibility)
+    }
+    out := appendEmbeddedManifest("# Doc\n", manifestMeta{}, entries)
+    if !strings.Contains(out, "; score=") || !strings.Contains(out, "(primary)") {
+        t.Fatalf("expected score in entry line; got:\n%s", out)
+    }
+}
+
+func TestValidateConfig_RejectsInvalidReputationFile(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "rep.yaml")
+    if err := os.WriteFile(path, []byte("tiers:\n  good: 1\ndefault: good\ndomains:\n  - pattern: a.example\n    tier: missing\n"), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    cfg := Config{InputPath: "in.md", OutputPath: "out.md", MaxSources: 1, PerDomainCap: 1, PerSourceChars: 100, DryRun: true, CredibilityFile: path}
+    if err := ValidateConfig(cfg); err == nil || !strings.Contains(err.Error(), "undefined tier") {
+        t.Fatalf("expected undefined tier error, got %v", err)
+    }
+}
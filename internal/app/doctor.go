@@ -0,0 +1,128 @@
+package app
+
+import (
+    "context"
+    "fmt"
+    "sort"
+    "strings"
+    "time"
+
+    openai "github.com/sashabaranov/go-openai"
+
+    "github.com/hyperifyio/goresearch/internal/search"
+)
+
+// doctorProbeQuery is a short, common query that any working search backend
+// should answer.
+const doctorProbeQuery = "wikipedia"
+
+// DoctorCheck is the outcome of a single preflight check.
+type DoctorCheck struct {
+    Name    string
+    OK      bool
+    Detail  string
+    Elapsed time.Duration
+}
+
+// Doctor runs preflight reachability checks for the configured LLM endpoint
+// and every configured search provider. It never fails fast: all checks run
+// so a single invocation reports everything that needs fixing before a long
+// research run.
+func Doctor(ctx context.Context, cfg Config) []DoctorCheck {
+    checks := []DoctorCheck{doctorLLM(ctx, cfg)}
+    return append(checks, doctorSearch(ctx, cfg)...)
+}
+
+// doctorLLM lists models at the configured base URL and confirms the
+// configured model is served.
+func doctorLLM(ctx context.Context, cfg Config) DoctorCheck {
+    c := DoctorCheck{Name: "llm"}
+    if strings.TrimSpace(cfg.LLMBaseURL) == "" {
+        c.Detail = "llm.base is not configured"
+        return c
+    }
+    transportCfg := openai.DefaultConfig(cfg.LLMAPIKey)
+    transportCfg.BaseURL = cfg.LLMBaseURL
+    transportCfg.HTTPClient = newHTTPClient(cfg)
+    client := openai.NewClientWithConfig(transportCfg)
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
+    start := time.Now()
+    models, err := client.ListModels(ctx)
+    c.Elapsed = time.Since(start)
+    if err != nil {
+        c.Detail = fmt.Sprintf("%s: list models failed: %v", cfg.LLMBaseURL, err)
+        return c
+    }
+    ids := make([]string, 0, len(models.Models))
+    for _, m := range models.Models {
+        ids = append(ids, m.ID)
+    }
+    sort.Strings(ids)
+    model := strings.TrimSpace(cfg.LLMModel)
+    switch {
+    case model == "":
+        c.Detail = fmt.Sprintf("%s: %d models; llm.model is not configured", cfg.LLMBaseURL, len(ids))
+    case containsString(ids, model):
+        c.OK = true
+        c.Detail = fmt.Sprintf("%s: %d models; %s available", cfg.LLMBaseURL, len(ids), model)
+    default:
+        c.Detail = fmt.Sprintf("%s: model %s not listed (available: %s)", cfg.LLMBaseURL, model, strings.Join(ids, ", "))
+    }
+    return c
+}
+
+// doctorSearch probes each configured provider with a single query.
+func doctorSearch(ctx context.Context, cfg Config) []DoctorCheck {
+    names := searchProviderNames(cfg)
+    if len(names) == 0 {
+        return []DoctorCheck{{Name: "search", Detail: "no search provider configured (set searx.url, search.file or search.provider)"}}
+    }
+    out := make([]DoctorCheck, 0, len(names))
+    for _, name := range names {
+        c := DoctorCheck{Name: "search:" + name}
+        health := &search.HealthTracker{}
+        p, err := newNamedSearchProvider(name, cfg, health)
+        if err != nil {
+            c.Detail = err.Error()
+            out = append(out, c)
+            continue
+        }
+        pctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+        start := time.Now()
+        results, err := p.Search(pctx, doctorProbeQuery, 3)
+        c.Elapsed = time.Since(start)
+        cancel()
+        if err != nil {
+            c.Detail = fmt.Sprintf("probe query failed: %v", err)
+            out = append(out, c)
+            continue
+        }
+        c.OK = true
+        c.Detail = fmt.Sprintf("%d results for %q", len(results), doctorProbeQuery)
+        if len(results) == 0 {
+            c.OK = false
+            c.Detail = fmt.Sprintf("reachable but no results for %q", doctorProbeQuery)
+        }
+        var down []string
+        for _, e := range health.Engines() {
+            if e.Unresponsive > 0 {
+                down = append(down, fmt.Sprintf("%s (%s)", e.Name, e.LastReason))
+            }
+        }
+        if len(down) > 0 {
+            c.Detail += "; unresponsive engines: " + strings.Join(down, ", ")
+        }
+        out = append(out, c)
+    }
+    return out
+}
+
+func containsString(list []string, v string) bool {
+    for _, s := range list {
+        if s == v {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func TestDoctor_ReportsLLMAndSearchReachability(t *testing.T) {
+    llm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path != "/v1/models" {
+            http.NotFound(w, r)
+            return
+        }
+        w.Header().Set("Content-Type", "application/json")
+        _ = json.NewEncoder(w).Encode(map[string]any{"object": "list", "data": []map[string]any{{"id": "test-model", "object": "model"}}})
+    }))
+    defer llm.Close()
+
+    fixture := filepath.Join(t.TempDir(), "search.json")
+    if err := os.WriteFile(fixture, []byte(`[{"title": "Wikipedia", "url": "https://en.wikipedia.org/", "snippet": "wikipedia"}]`), 0o644); err != nil {
+        t.Fatalf("write fixture: %v", err)
+    }
+
+    checks := Doctor(context.Background(), Config{LLMBaseURL: llm.URL + "/v1", LLMModel: "test-model", FileSearchPath: fixture, SSLVerify: true})
+    if len(checks) != 2 {
+        t.Fatalf("expected llm and search checks, got %+v", checks)
+    }
+    for _, c := range checks {
+        if !c.OK {
+            t.Fatalf("expected check %s to pass: %s", c.Name, c.Detail)
+        }
+    }
+    if checks[1].Name != "search:file" {
+        t.Fatalf("unexpected search check name: %q", checks[1].Name)
+    }
+
+    checks = Doctor(context.Background(), Config{LLMBaseURL: llm.URL + "/v1", LLMModel: "missing-model", SSLVerify: true})
+    if checks[0].OK || !strings.Contains(checks[0].Detail, "missing-model not listed") {
+        t.Fatalf("expected missing model to fail: %+v", checks[0])
+    }
+    if len(checks) != 2 || checks[1].OK || checks[1].Name != "search" {
+        t.Fatalf("expected unconfigured search to fail: %+v", checks)
+    }
+}
@@ -0,0 +1,137 @@
+package app
+
+import (
+    "context"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "net/url"
+    "strings"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "github.com/hyperifyio/goresearch/internal/fetch"
+    "github.com/hyperifyio/goresearch/internal/netguard"
+    "github.com/hyperifyio/goresearch/internal/search"
+)
+
+func TestFetchAndExtract_ParallelKeepsSelectionOrder(t *testing.T) {
+    var inFlight, maxInFlight int32
+    getter := sourceGetterFunc(func(ctx context.Context, url string) ([]byte, string, error) {
+        n := atomic.AddInt32(&inFlight, 1)
+        defer atomic.AddInt32(&inFlight, -1)
+        for {
+            m := atomic.LoadInt32(&maxInFlight)
+            if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+                break
+            }
+        }
+        // Earlier sources finish last, so completion order is reversed.
+        switch url {
+        case "https://a.example/":
+            time.Sleep(60 * time.Millisecond)
+        case "https://b.example/":
+            time.Sleep(30 * time.Millisecond)
+        case "https://fail.example/":
+            return nil, "", errors.New("connection refused")
+        }
+        return []byte("<html><body><p>Text from " + url + "</p></body></html>"), "text/html", nil
+    })
+    selected := []search.Result{
+        {Title: "A", URL: "https://a.example/"},
+        {Title: "Fail", URL: "https://fail.example/"},
+        {Title: "B", URL: "https://b.example/"},
+        {Title: "C", URL: "https://c.example/"},
+    }
+    excerpts, _, records := fetchAndExtract(context.Background(), getter, nil, selected, Config{PerSourceChars: 1000, FetchConcurrency: 4})
+    if maxInFlight < 2 {
+        t.Fatalf("expected parallel fetches, max in flight %d", maxInFlight)
+    }
+    var got []string
+    for i, ex := range excerpts {
+        if ex.Index != i+1 {
+            t.Fatalf("expected dense indices in selection order, got %d at %d", ex.Index, i)
+        }
+        got = append(got, ex.Title)
+    }
+    if strings.Join(got, ",") != "A,B,C" {
+        t.Fatalf("expected selection order A,B,C, got %v", got)
+    }
+    if len(records) != 4 || records[0].Status != fetchStatusOK || records[1].Status != fetchStatusFailed || records[1].Reason == "" {
+        t.Fatalf("unexpected fetch records: %+v", records)
+    }
+    if records[0].FetchMillis < 50 || records[0].Bytes == 0 {
+        t.Fatalf("expected timing and size on record: %+v", records[0])
+    }
+}
+
+func TestFetchSummary(t *testing.T) {
+    got := fetchSummary([]fetchRecord{{Status: fetchStatusOK}, {Status: fetchStatusFailed}, {Status: fetchStatusOK}, {Status: fetchStatusSkipped}})
+    if got != "2 ok, 1 skipped, 1 failed" {
+        t.Fatalf("unexpected summary %q", got)
+    }
+    got = fetchSummary([]fetchRecord{{Status: fetchStatusOK, Retries: []fetch.Retry{{Attempt: 1}, {Attempt: 2}}}})
+    if got != "1 ok, 2 retries" {
+        t.Fatalf("unexpected summary %q", got)
+    }
+    got = fetchSummary([]fetchRecord{{Status: fetchStatusOK, Cache: fetch.CacheStale}, {Status: fetchStatusOK, Cache: fetch.CacheFresh}})
+    if got != "2 ok, 1 served stale" {
+        t.Fatalf("unexpected summary %q", got)
+    }
+    if fetchSummary(nil) != "" {
+        t.Fatal("expected empty summary without fetches")
+    }
+}
+
+func TestFetchAndExtract_RecordsRetries(t *testing.T) {
+    var calls int32
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if atomic.AddInt32(&calls, 1) == 1 {
+            w.WriteHeader(http.StatusBadGateway)
+            return
+        }
+        w.Header().Set("Content-Type", "text/html")
+        _, _ = w.Write([]byte("<html><body><p>Recovered after a retry.</p></body></html>"))
+    }))
+    defer srv.Close()
+    f := &fetchClient{client: &fetch.Client{UserAgent: "goresearch-test", MaxAttempts: 2, Backoff: time.Millisecond, AllowPrivateHosts: true}}
+    _, _, records := fetchAndExtract(context.Background(), f, nil, []search.Result{{Title: "S", URL: srv.URL}}, Config{PerSourceChars: 1000})
+    if len(records) != 1 || records[0].Status != fetchStatusOK {
+        t.Fatalf("unexpected fetch records: %+v", records)
+    }
+    if len(records[0].Retries) != 1 || records[0].Retries[0].Status != http.StatusBadGateway || records[0].Retries[0].Attempt != 1 {
+        t.Fatalf("expected the 502 retry on the record, got %+v", records[0].Retries)
+    }
+}
+
+func TestFetchAndExtract_SkipsNonPublicAddresses(t *testing.T) {
+    getter := sourceGetterFunc(func(ctx context.Context, u string) ([]byte, string, error) {
+        return nil, "", &url.Error{Op: "Get", URL: u, Err: netguard.PolicyError{Host: "internal.example", IP: "10.0.0.7", Reason: "private"}}
+    })
+    _, skipped, records := fetchAndExtract(context.Background(), getter, nil, []search.Result{{Title: "I", URL: "https://internal.example/"}}, Config{})
+    if len(skipped) != 1 || skipped[0].Reason != "private host not allowed: internal.example resolves to 10.0.0.7 (private)" {
+        t.Fatalf("unexpected skipped entries: %+v", skipped)
+    }
+    if records[0].Status != fetchStatusSkipped {
+        t.Fatalf("expected a skipped fetch record, got %+v", records[0])
+    }
+}
+
+func TestFetchAndExtract_SkipsOversizedBodies(t *testing.T) {
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/pdf")
+        _, _ = w.Write([]byte(strings.Repeat("%PDF-1.4 ", 512)))
+    }))
+    defer srv.Close()
+    cfg := Config{PerSourceChars: 1000, EnablePDF: true, FetchBodyLimits: map[string]int64{"application/pdf": 1024}}
+    f := &fetchClient{client: &fetch.Client{UserAgent: "goresearch-test", MaxAttempts: 2, Backoff: time.Millisecond, AllowPrivateHosts: true, EnablePDF: true,
+        BodyLimits: bodyLimits(cfg), MemoryBudget: fetchMemoryBudget(cfg)}}
+    _, skipped, records := fetchAndExtract(context.Background(), f, nil, []search.Result{{Title: "Big", URL: srv.URL}}, cfg)
+    if len(skipped) != 1 || !strings.HasSuffix(skipped[0].Reason, "exceeds the 1024-byte limit for application/pdf") {
+        t.Fatalf("unexpected skipped entries: %+v", skipped)
+    }
+    if records[0].Status != fetchStatusSkipped || len(records[0].Retries) != 0 {
+        t.Fatalf("expected a skipped record without retries, got %+v", records[0])
+    }
+}
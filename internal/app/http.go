@@ -2,9 +2,17 @@ package app
 
 import (
 	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"strings"
 	"time"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/net/http/httpproxy"
 )
 
 // newHighThroughputHTTPClient returns an HTTP client tuned for high parallelism
@@ -38,3 +46,88 @@ func newHighThroughputHTTPClient(sslVerify bool) *http.Client {
 		Timeout:   60 * time.Second,
 	}
 }
+
+// newHTTPClient returns the high-throughput client with the configured
+// outbound proxy and CA bundle applied. Every outbound client (LLM, search,
+// robots and page fetches) is built here so they share one network policy.
+func newHTTPClient(cfg Config) *http.Client {
+	c := newHighThroughputHTTPClient(cfg.SSLVerify)
+	tr := c.Transport.(*http.Transport)
+	tr.Proxy = proxyFunc(cfg)
+	if trim(cfg.CAFile) != "" && cfg.SSLVerify {
+		pool, err := loadCABundle(cfg.CAFile)
+		if err != nil {
+			// ValidateConfig rejects unreadable bundles; keep the system
+			// roots if the file changed since.
+			log.Warn().Err(err).Str("file", cfg.CAFile).Msg("CA bundle not loaded; using system roots")
+		} else {
+			tr.TLSClientConfig = &tls.Config{RootCAs: pool}
+		}
+	}
+	return c
+}
+
+// proxyFunc resolves the proxy for a request from HTTP_PROXY, HTTPS_PROXY
+// and NO_PROXY, with Config.ProxyURL and Config.NoProxy taking precedence
+// over the environment.
+func proxyFunc(cfg Config) func(*http.Request) (*url.URL, error) {
+	pc := httpproxy.FromEnvironment()
+	if p := trim(cfg.ProxyURL); p != "" {
+		pc.HTTPProxy, pc.HTTPSProxy = p, p
+	}
+	if np := trim(cfg.NoProxy); np != "" {
+		pc.NoProxy = np
+	}
+	resolve := pc.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return resolve(req.URL)
+	}
+}
+
+// warnProxiedGuard notes that the public-web guard is weaker behind a
+// proxy: the proxy connects to the destination, so hosts can only be
+// checked by resolving them locally before the request is handed over.
+func warnProxiedGuard(cfg Config) {
+    if cfg.AllowPrivateHosts {
+        return
+    }
+    u, err := proxyFunc(cfg)(&http.Request{URL: &url.URL{Scheme: "https", Host: "example.com"}})
+    if err != nil || u == nil {
+        return
+    }
+    log.Warn().Str("proxy", u.Redacted()).Msg("outbound proxy in use: private-address checks rely on local DNS; names only the proxy resolves are not checked")
+}
+
+// loadCABundle returns the system roots with the PEM certificates in path
+// added, so a TLS-inspecting proxy's CA is trusted alongside public CAs.
+func loadCABundle(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM certificates in %s", path)
+	}
+	return pool, nil
+}
+
+// validateProxyURL checks an explicit proxy setting.
+func validateProxyURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "socks5":
+	default:
+		return fmt.Errorf("unsupported proxy scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("missing proxy host in %q", raw)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewHTTPClient_RoutesThroughConfiguredProxy(t *testing.T) {
+	var seen string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = r.URL.String()
+		_, _ = io.WriteString(w, "via proxy")
+	}))
+	defer proxy.Close()
+
+	c := newHTTPClient(Config{SSLVerify: true, ProxyURL: proxy.URL, NoProxy: "direct.example"})
+	resp, err := c.Get("http://docs.example/page")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	resp.Body.Close()
+	if seen != "http://docs.example/page" {
+		t.Fatalf("expected the proxy to receive the absolute URL, got %q", seen)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.direct.example/", nil)
+	if u, err := proxyFunc(Config{ProxyURL: proxy.URL, NoProxy: "direct.example"})(req); err != nil || u != nil {
+		t.Fatalf("expected NoProxy host to bypass the proxy, got %v (%v)", u, err)
+	}
+}
+
+func TestNewHTTPClient_TrustsCABundle(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caFile, pemBytes, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := newHTTPClient(Config{SSLVerify: true}).Get(srv.URL); err == nil {
+		t.Fatal("expected an unknown authority error without the bundle")
+	}
+	resp, err := newHTTPClient(Config{SSLVerify: true, CAFile: caFile}).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("expected the bundle to be trusted: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestValidateConfig_ProxyAndCAFile(t *testing.T) {
+	base := Config{InputPath: "in.md", OutputPath: "out.md", DryRun: true}
+
+	cfg := base
+	cfg.ProxyURL = "ftp://proxy.corp:21"
+	if err := ValidateConfig(cfg); err == nil || !strings.Contains(err.Error(), "proxy.url") {
+		t.Fatalf("expected proxy.url error, got %v", err)
+	}
+
+	cfg = base
+	cfg.CAFile = filepath.Join(t.TempDir(), "missing.pem")
+	if err := ValidateConfig(cfg); err == nil || !strings.Contains(err.Error(), "ssl.caFile") {
+		t.Fatalf("expected ssl.caFile error, got %v", err)
+	}
+
+	cfg = base
+	cfg.ProxyURL = "http://proxy.corp:3128"
+	if err := ValidateConfig(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -61,7 +61,7 @@ func TestFetchAndExtract_PerSourceIsolation(t *testing.T) {
 	for _, s := range selected {
 		results = append(results, search.Result{Title: s.title, URL: s.url})
 	}
-    out, skipped := fetchAndExtract(context.Background(), fg, extract.HeuristicExtractor{}, results, Config{PerSourceChars: 1000, AllowPrivateHosts: true})
+    out, skipped, _ := fetchAndExtract(context.Background(), fg, extract.HeuristicExtractor{}, results, Config{PerSourceChars: 1000, AllowPrivateHosts: true})
     if len(out) != 1 || len(skipped) != 0 {
         t.Fatalf("expected 1 excerpt and 0 skipped (non-robots failure), got excerpts=%d skipped=%d", len(out), len(skipped))
 	}
@@ -0,0 +1,71 @@
+package app
+
+import (
+    "net/url"
+    "strings"
+
+    "golang.org/x/net/publicsuffix"
+
+    "github.com/hyperifyio/goresearch/internal/urlnorm"
+)
+
+// resolveSourceURL picks the identity of a fetched source: the page's
+// declared canonical URL when usable, else the final URL after redirects,
+// else the searched URL. Other distinct URLs are returned as aliases, led by
+// the searched URL. A canonical that points at the site root from a deeper
+// page is a common CMS misconfiguration and is ignored. A canonical on
+// another site is only recorded as an alias, so a mirror cannot borrow the
+// identity, type or reputation of the site it claims to copy.
+func resolveSourceURL(searched, final, canonical string, opt urlnorm.Options) (string, []string) {
+    identity := strings.TrimSpace(final)
+    if identity == "" {
+        identity = searched
+    }
+    var foreign string
+    if c := strings.TrimSpace(canonical); c != "" {
+        if base, err := url.Parse(identity); err == nil {
+            if ref, err := base.Parse(c); err == nil && (ref.Scheme == "http" || ref.Scheme == "https") && ref.Host != "" {
+                switch {
+                case strings.Trim(ref.Path, "/") == "" && strings.Trim(base.Path, "/") != "":
+                    // Root canonical from a deeper page: ignored.
+                case !sameSite(base.Hostname(), ref.Hostname()):
+                    foreign = ref.String()
+                default:
+                    identity = ref.String()
+                }
+            }
+        }
+    }
+    if clean, err := opt.Clean(identity); err == nil {
+        identity = clean
+    }
+    seen := map[string]struct{}{identity: {}}
+    var aliases []string
+    for _, u := range []string{searched, final, foreign} {
+        u = strings.TrimSpace(u)
+        if u == "" {
+            continue
+        }
+        if clean, err := opt.Clean(u); err == nil {
+            u = clean
+        }
+        if _, dup := seen[u]; dup {
+            continue
+        }
+        seen[u] = struct{}{}
+        aliases = append(aliases, u)
+    }
+    return identity, aliases
+}
+
+// sameSite reports whether two hosts share a registrable domain, such as
+// m.example.com and example.com.
+func sameSite(a, b string) bool {
+    a, b = strings.ToLower(a), strings.ToLower(b)
+    if a == b {
+        return true
+    }
+    da, errA := publicsuffix.EffectiveTLDPlusOne(a)
+    db, errB := publicsuffix.EffectiveTLDPlusOne(b)
+    return errA == nil && errB == nil && da == db
+}
@@ -0,0 +1,31 @@
+package app
+
+import (
+    "reflect"
+    "testing"
+
+    "github.com/hyperifyio/goresearch/internal/urlnorm"
+)
+
+func TestResolveSourceURL(t *testing.T) {
+    cases := []struct {
+        name                      string
+        searched, final, canonical string
+        want                      string
+        aliases                   []string
+    }{
+        {"canonical wins", "https://m.example.com/a?utm_source=x", "https://m.example.com/a", "https://example.com/a", "https://example.com/a", []string{"https://m.example.com/a"}},
+        {"relative canonical", "https://example.com/a/amp", "https://example.com/a/amp", "/a", "https://example.com/a", []string{"https://example.com/a/amp"}},
+        {"redirect target", "http://example.com/old", "https://example.com/new", "", "https://example.com/new", []string{"http://example.com/old"}},
+        {"root canonical ignored", "https://example.com/post/1", "https://example.com/post/1", "https://example.com/", "https://example.com/post/1", nil},
+        {"cross-site canonical is an alias", "https://mirror.example.net/rfc9110", "https://mirror.example.net/rfc9110", "https://www.rfc-editor.org/rfc/rfc9110", "https://mirror.example.net/rfc9110", []string{"https://www.rfc-editor.org/rfc/rfc9110"}},
+        {"sibling subdomain canonical", "https://docs.example.co.uk/a", "https://docs.example.co.uk/a", "https://www.example.co.uk/a", "https://www.example.co.uk/a", []string{"https://docs.example.co.uk/a"}},
+        {"non-http canonical ignored", "https://example.com/a", "", "ftp://example.com/a", "https://example.com/a", nil},
+    }
+    for _, c := range cases {
+        got, aliases := resolveSourceURL(c.searched, c.final, c.canonical, urlnorm.Options{})
+        if got != c.want || !reflect.DeepEqual(aliases, c.aliases) {
+            t.Fatalf("%s: got %q %v, want %q %v", c.name, got, aliases, c.want, c.aliases)
+        }
+    }
+}
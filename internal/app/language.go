@@ -0,0 +1,28 @@
+package app
+
+import (
+    "strconv"
+    "strings"
+
+    "github.com/hyperifyio/goresearch/internal/langid"
+    sel "github.com/hyperifyio/goresearch/internal/select"
+)
+
+// languageReasonPrefix starts the skipped reason for sources whose extracted
+// text is in a language outside lang.allow, e.g. "language de (0.97) not allowed".
+const languageReasonPrefix = "language "
+
+// isLanguageReason reports whether a skipped reason marks a language mismatch.
+func isLanguageReason(reason string) bool {
+    return strings.HasPrefix(reason, languageReasonPrefix)
+}
+
+// languageSkipReason decides whether a source detected as det must be
+// dropped under the allowed languages. Only reliable detections are acted
+// on, so short or mixed pages are kept rather than guessed at.
+func languageSkipReason(det langid.Result, allowed []string) (string, bool) {
+    if len(allowed) == 0 || !det.Reliable() || sel.LanguageAllowed(det.Lang, allowed) {
+        return "", false
+    }
+    return languageReasonPrefix + det.Lang + " (" + strconv.FormatFloat(det.Confidence, 'f', 2, 64) + ") not allowed", true
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+    "context"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/hyperifyio/goresearch/internal/search"
+)
+
+func TestFetchAndExtractUnique_DropsDisallowedLanguageAndRefills(t *testing.T) {
+    pages := map[string]string{
+        "https://en.example/": "<html><body><p>" + strings.Repeat("Certificate transparency logs record every issued certificate so that domain owners can audit issuance. ", 3) + "</p></body></html>",
+        "https://de.example/": "<html><body><p>" + strings.Repeat("Die Protokolle speichern jedes ausgestellte Zertifikat, damit die Inhaber der Domain die Ausstellung prüfen können. ", 3) + "</p></body></html>",
+        "https://en2.example/": "<html><body><p>" + strings.Repeat("Key pinning binds a host to a set of public keys and was deprecated by browsers over operational risk. ", 3) + "</p></body></html>",
+    }
+    getter := sourceGetterFunc(func(ctx context.Context, url string) ([]byte, string, error) {
+        return []byte(pages[url]), "text/html", nil
+    })
+    selected := []search.Result{{Title: "EN", URL: "https://en.example/"}, {Title: "DE", URL: "https://de.example/"}}
+    reserve := []search.Result{{Title: "EN2", URL: "https://en2.example/"}}
+    excerpts, skipped, _ := fetchAndExtractUnique(context.Background(), getter, nil, selected, reserve, Config{PerSourceChars: 5000, AllowedLanguages: []string{"en"}})
+    if len(excerpts) != 2 || excerpts[1].URL != "https://en2.example/" {
+        t.Fatalf("expected German source replaced from reserve, got %+v", excerpts)
+    }
+    if excerpts[0].Language != "en" || excerpts[0].LanguageConfidence < 0.8 {
+        t.Fatalf("expected detected language on excerpt, got %q (%.2f)", excerpts[0].Language, excerpts[0].LanguageConfidence)
+    }
+    if len(skipped) != 1 || skipped[0].URL != "https://de.example/" || !strings.HasPrefix(skipped[0].Reason, "language de (") {
+        t.Fatalf("unexpected skipped entries: %+v", skipped)
+    }
+
+    // Without an allow-list every language is kept.
+    excerpts, _, _ = fetchAndExtractUnique(context.Background(), getter, nil, selected, nil, Config{PerSourceChars: 5000})
+    if len(excerpts) != 2 || excerpts[1].Language != "de" {
+        t.Fatalf("expected German source kept and labeled, got %+v", excerpts)
+    }
+}
+
+func TestManifest_ListsLanguageAndLanguageSkips(t *testing.T) {
+    entries := []manifestEntry{{Index: 1, URL: "https://en.example/", SHA256: "x", Chars: 10, Language: "en", LanguageConfidence: 0.974}}
+    skipped := []skippedEntry{{URL: "https://de.example/", Reason: "language de (0.99) not allowed"}, {URL: "https://r.example/", Reason: "robots"}}
+    md := appendEmbeddedManifestWithSkipped("# R", manifestMeta{GeneratedAt: time.Unix(0, 0)}, entries, skipped)
+    if !strings.Contains(md, "; lang=en (0.97)") {
+        t.Fatalf("expected language on manifest line:\n%s", md)
+    }
+    lang := md[strings.Index(md, "### Skipped for language"):]
+    if !strings.Contains(lang, "https://de.example/") || strings.Contains(lang, "https://r.example/") {
+        t.Fatalf("expected only the language skip in its section:\n%s", md)
+    }
+}
@@ -8,6 +8,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hyperifyio/goresearch/internal/credibility"
+	"github.com/hyperifyio/goresearch/internal/fetch"
+	"github.com/hyperifyio/goresearch/internal/search"
+	"github.com/hyperifyio/goresearch/internal/sourcetype"
 	"github.com/hyperifyio/goresearch/internal/synth"
     "github.com/hyperifyio/goresearch/internal/llmtools"
 )
@@ -19,6 +23,32 @@ type manifestEntry struct {
 	Title  string `json:"title"`
 	SHA256 string `json:"sha256"`
 	Chars  int    `json:"chars"`
+	// Published is the source's publication date (YYYY-MM-DD) when known.
+	Published string `json:"published,omitempty"`
+	// Aliases are other URLs that served the same source, such as the
+	// searched URL before redirects and rel=canonical resolution.
+	Aliases []string `json:"aliases,omitempty"`
+	// SourceType is the kind of source (see package sourcetype).
+	SourceType string `json:"source_type,omitempty"`
+	// Language is the detected language of the source text with the
+	// detector's confidence.
+	Language           string  `json:"language,omitempty"`
+	LanguageConfidence float64 `json:"language_confidence,omitempty"`
+	// WARCRecordID is the WARC-Record-ID of the response the text came
+	// from, in the bundle's WARC file.
+	WARCRecordID string `json:"warc_record_id,omitempty"`
+	// Cache says whether the source was served from the HTTP cache as a
+	// fresh, revalidated or stale copy; omitted for origin fetches.
+	Cache string `json:"cache,omitempty"`
+	// Credibility explains how the source scored during selection.
+	Credibility *credibility.Breakdown `json:"credibility,omitempty"`
+	// Authors, Publisher, Modified, DOI and DeclaredLanguage are what the
+	// source's own metadata declares; see synth.SourceExcerpt.
+	Authors          []string `json:"authors,omitempty"`
+	Publisher        string   `json:"publisher,omitempty"`
+	Modified         string   `json:"modified,omitempty"`
+	DOI              string   `json:"doi,omitempty"`
+	DeclaredLanguage string   `json:"declared_language,omitempty"`
 }
 
 // manifestMeta captures high-level run details that aid reproducibility.
@@ -29,15 +59,95 @@ type manifestMeta struct {
 	HTTPCache   bool      `json:"http_cache"`
 	LLMCache    bool      `json:"llm_cache"`
 	GeneratedAt time.Time `json:"generated_at"`
+	// SearchHealth summarizes provider and engine reliability during the
+	// search stage; omitted when no search ran.
+	SearchHealth *searchHealth `json:"search_health,omitempty"`
+	// Fetches records status and timing for every source fetched, in the
+	// order they were attempted.
+	Fetches []fetchRecord `json:"fetches,omitempty"`
+	// WARCFile names the WARC file in the artifacts bundle that holds the
+	// raw requests and responses, when recording was enabled.
+	WARCFile string `json:"warc_file,omitempty"`
+	// WARCInputs lists the archives fetches were served from, whose record
+	// IDs then appear in warc_record_id.
+	WARCInputs []string `json:"warc_inputs,omitempty"`
+	// Extractor names the HTML extractor the source texts came from.
+	Extractor string `json:"extractor,omitempty"`
+	// ExtractFormat is the excerpt format, text or markdown.
+	ExtractFormat string `json:"extract_format,omitempty"`
+}
+
+// searchHealth is the manifest view of a search.HealthTracker.
+type searchHealth struct {
+    Providers []search.ProviderHealth `json:"providers"`
+    Engines   []search.EngineHealth   `json:"engines,omitempty"`
+}
+
+// newSearchHealth snapshots the tracker, returning nil when nothing was recorded.
+func newSearchHealth(t *search.HealthTracker) *searchHealth {
+    providers := t.Providers()
+    if len(providers) == 0 {
+        return nil
+    }
+    return &searchHealth{Providers: providers, Engines: t.Engines()}
+}
+
+// degraded reports whether any provider failed or any engine was unresponsive.
+func (h *searchHealth) degraded() bool {
+    if h == nil {
+        return false
+    }
+    for _, p := range h.Providers {
+        if p.Failures > 0 || p.CircuitOpen {
+            return true
+        }
+    }
+    for _, e := range h.Engines {
+        if e.Unresponsive > 0 {
+            return true
+        }
+    }
+    return false
 }
 
 // skippedEntry records a URL that was intentionally skipped due to robots or
-// opt-out policy (e.g., X-Robots-Tag: noai/notrain).
+// opt-out policy (e.g., X-Robots-Tag: noai/notrain), or because its content
+// duplicated a source already kept.
 type skippedEntry struct {
     URL    string `json:"url"`
     Reason string `json:"reason"`
 }
 
+// Fetch statuses recorded per attempted source.
+const (
+    fetchStatusOK      = "ok"
+    fetchStatusSkipped = "skipped"
+    fetchStatusFailed  = "failed"
+    // fetchStatusExpanded is a feed replaced by the entries it links to.
+    fetchStatusExpanded = "expanded"
+)
+
+// fetchRecord is the manifest's account of fetching one source: how it ended
+// and how long the fetch and the extraction took. Records are listed in the
+// order sources were attempted, including failures and skips.
+type fetchRecord struct {
+    URL    string `json:"url"`
+    Status string `json:"status"`
+    // Reason explains a skip or failure.
+    Reason        string `json:"reason,omitempty"`
+    Bytes         int    `json:"bytes,omitempty"`
+    FetchMillis   int64  `json:"fetch_ms"`
+    ExtractMillis int64  `json:"extract_ms,omitempty"`
+    // Retries lists each failed attempt that was retried and the wait chosen.
+    Retries []fetch.Retry `json:"retries,omitempty"`
+    // ThrottleMillis is time spent waiting on per-host and global rate limits.
+    ThrottleMillis int64 `json:"throttle_ms,omitempty"`
+    // WARCRecordID links the fetch to its response record in the WARC file.
+    WARCRecordID string `json:"warc_record_id,omitempty"`
+    // Cache is the fetch's cache status, as on manifestEntry.
+    Cache string `json:"cache,omitempty"`
+}
+
 // computeSHA256Hex returns a lowercase hex-encoded SHA-256 of the given text.
 func computeSHA256Hex(text string) string {
 	h := sha256.Sum256([]byte(text))
@@ -55,6 +165,18 @@ func buildManifestEntriesFromSynth(excerpts []synth.SourceExcerpt) []manifestEnt
 			Title:  strings.TrimSpace(e.Title),
 			SHA256: computeSHA256Hex(content),
 			Chars:  len(content),
+			Published: e.Published,
+			Aliases:   e.Aliases,
+			SourceType: e.SourceType,
+			Language:   e.Language,
+			LanguageConfidence: e.LanguageConfidence,
+			WARCRecordID:       e.WARCRecordID,
+			Cache:              e.CacheStatus,
+			Authors:            e.Authors,
+			Publisher:          e.Publisher,
+			Modified:           e.Modified,
+			DOI:                e.DOI,
+			DeclaredLanguage:   e.DeclaredLanguage,
 		})
 	}
 	return out
@@ -73,6 +195,14 @@ func appendEmbeddedManifest(markdown string, meta manifestMeta, entries []manife
 	b.WriteString(strings.TrimSpace(meta.LLMBaseURL))
 	b.WriteString("\n- Sources: ")
 	b.WriteString(strconv.Itoa(meta.SourceCount))
+	if mix := sourceTypeMix(entries); mix != "" {
+		b.WriteString("\n- Source types: ")
+		b.WriteString(mix)
+	}
+	if summary := fetchSummary(meta.Fetches); summary != "" {
+		b.WriteString("\n- Fetches: ")
+		b.WriteString(summary)
+	}
 	b.WriteString("\n- HTTP cache: ")
 	b.WriteString(boolToString(meta.HTTPCache))
 	b.WriteString("\n- LLM cache: ")
@@ -90,21 +220,115 @@ func appendEmbeddedManifest(markdown string, meta manifestMeta, entries []manife
 		b.WriteString(e.SHA256)
 		b.WriteString("; chars=")
 		b.WriteString(strconv.Itoa(e.Chars))
+		if e.Published != "" {
+			b.WriteString("; published=")
+			b.WriteString(e.Published)
+		}
+		if e.DOI != "" {
+			b.WriteString("; doi=")
+			b.WriteString(e.DOI)
+		}
+		if e.SourceType != "" {
+			b.WriteString("; type=")
+			b.WriteString(e.SourceType)
+		}
+		if e.Language != "" {
+			b.WriteString("; lang=")
+			b.WriteString(e.Language)
+			b.WriteString(" (")
+			b.WriteString(strconv.FormatFloat(e.LanguageConfidence, 'f', 2, 64))
+			b.WriteString(")")
+		}
+		if e.Cache != "" {
+			b.WriteString("; cache=")
+			b.WriteString(e.Cache)
+		}
+		if e.Credibility != nil {
+			b.WriteString("; score=")
+			b.WriteString(strconv.FormatFloat(e.Credibility.Score, 'f', 2, 64))
+			b.WriteString(" (")
+			b.WriteString(e.Credibility.Tier)
+			b.WriteString(")")
+		}
+		if len(e.Aliases) > 0 {
+			b.WriteString("; aliases=")
+			b.WriteString(strings.Join(e.Aliases, " "))
+		}
 		b.WriteString("\n")
 	}
+	// Only surface search health in the report when something went wrong;
+	// the sidecar JSON always carries the full snapshot.
+	if meta.SearchHealth.degraded() {
+		b.WriteString("\n### Search health\n\n")
+		for _, p := range meta.SearchHealth.Providers {
+			b.WriteString("- ")
+			b.WriteString(p.Name)
+			b.WriteString(": requests=")
+			b.WriteString(strconv.Itoa(p.Requests))
+			b.WriteString("; failures=")
+			b.WriteString(strconv.Itoa(p.Failures))
+			b.WriteString("; retries=")
+			b.WriteString(strconv.Itoa(p.Retries))
+			if p.CircuitOpen {
+				b.WriteString("; circuit=open; skipped=")
+				b.WriteString(strconv.Itoa(p.Skipped))
+			}
+			b.WriteString("\n")
+		}
+		for _, e := range meta.SearchHealth.Engines {
+			if e.Unresponsive == 0 {
+				continue
+			}
+			b.WriteString("- engine ")
+			b.WriteString(e.Name)
+			b.WriteString(": unresponsive=")
+			b.WriteString(strconv.Itoa(e.Unresponsive))
+			if strings.TrimSpace(e.LastReason) != "" {
+				b.WriteString(" (")
+				b.WriteString(strings.TrimSpace(e.LastReason))
+				b.WriteString(")")
+			}
+			b.WriteString("\n")
+		}
+	}
 	return b.String()
 }
 
 // appendEmbeddedManifestWithSkipped appends the manifest and, when provided,
-// a section enumerating URLs skipped due to robots/opt-out decisions.
+// sections enumerating URLs skipped due to robots/opt-out decisions, sources
+// dropped as duplicates or near-duplicates of a kept source, and sources
+// outside the allowed languages.
 func appendEmbeddedManifestWithSkipped(markdown string, meta manifestMeta, entries []manifestEntry, skipped []skippedEntry) string {
     out := appendEmbeddedManifest(markdown, meta, entries)
     if len(skipped) == 0 {
         return out
     }
+    var policy, dups, langs []skippedEntry
+    for _, s := range skipped {
+        switch {
+        case isDuplicateReason(s.Reason):
+            dups = append(dups, s)
+        case isLanguageReason(s.Reason):
+            langs = append(langs, s)
+        default:
+            policy = append(policy, s)
+        }
+    }
     var b strings.Builder
     b.WriteString(out)
-    b.WriteString("\n### Skipped due to robots/opt-out\n\n")
+    writeSkippedSection(&b, "Skipped due to robots/opt-out", policy)
+    writeSkippedSection(&b, "Skipped as duplicates", dups)
+    writeSkippedSection(&b, "Skipped for language", langs)
+    return b.String()
+}
+
+func writeSkippedSection(b *strings.Builder, title string, skipped []skippedEntry) {
+    if len(skipped) == 0 {
+        return
+    }
+    b.WriteString("\n### ")
+    b.WriteString(title)
+    b.WriteString("\n\n")
     for _, s := range skipped {
         b.WriteString("- ")
         b.WriteString(strings.TrimSpace(s.URL))
@@ -114,7 +338,6 @@ func appendEmbeddedManifestWithSkipped(markdown string, meta manifestMeta, entri
         }
         b.WriteString("\n")
     }
-    return b.String()
 }
 
 // appendToolTranscript appends a transcript of tool calls if provided.
@@ -167,3 +390,54 @@ func boolToString(bv bool) string {
 	}
 	return "false"
 }
+
+// sourceTypeMix summarizes how many sources of each type were used, e.g.
+// "primary 2, academic 1, blog 1", in sourcetype.All order.
+func sourceTypeMix(entries []manifestEntry) string {
+	counts := map[string]int{}
+	for _, e := range entries {
+		if e.SourceType != "" {
+			counts[e.SourceType]++
+		}
+	}
+	parts := make([]string, 0, len(counts))
+	for _, t := range sourcetype.All {
+		if n := counts[t]; n > 0 {
+			parts = append(parts, t+" "+strconv.Itoa(n))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// fetchSummary counts fetch records by status, e.g. "10 ok, 1 skipped,
+// 1 failed, 2 retries", or returns "" when nothing was fetched.
+func fetchSummary(records []fetchRecord) string {
+	if len(records) == 0 {
+		return ""
+	}
+	counts := map[string]int{}
+	retries, stale := 0, 0
+	for _, r := range records {
+		counts[r.Status]++
+		retries += len(r.Retries)
+		if r.Cache == fetch.CacheStale {
+			stale++
+		}
+	}
+	parts := make([]string, 0, 4)
+	for _, st := range []string{fetchStatusOK, fetchStatusExpanded, fetchStatusSkipped, fetchStatusFailed} {
+		if counts[st] > 0 {
+			parts = append(parts, strconv.Itoa(counts[st])+" "+st)
+		}
+	}
+	switch {
+	case retries == 1:
+		parts = append(parts, "1 retry")
+	case retries > 1:
+		parts = append(parts, strconv.Itoa(retries)+" retries")
+	}
+	if stale > 0 {
+		parts = append(parts, strconv.Itoa(stale)+" served stale")
+	}
+	return strings.Join(parts, ", ")
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hyperifyio/goresearch/internal/search"
 	"github.com/hyperifyio/goresearch/internal/synth"
     "github.com/hyperifyio/goresearch/internal/llmtools"
 )
@@ -44,11 +45,41 @@ func TestAppendEmbeddedManifest_AppendsReadableSection(t *testing.T) {
 	if !strings.Contains(out, "gpt-local") || !strings.Contains(out, "http://localhost:11434/v1") {
 		t.Fatalf("expected header fields present")
 	}
-	if !strings.Contains(out, "1. https://example.com/a — sha256=abcd; chars=5") {
+	if !strings.Contains(out, "1. https://example.com/a — sha256=abcd; chars=5\n") {
 		t.Fatalf("expected entry line; got:\n%s", out)
 	}
 }
 
+func TestManifest_RecordsPublishedDate(t *testing.T) {
+	entries := buildManifestEntriesFromSynth([]synth.SourceExcerpt{{Index: 1, URL: "https://example.com/a", Title: "A", Excerpt: "hello", Published: "2023-06-01"}})
+	if entries[0].Published != "2023-06-01" {
+		t.Fatalf("expected published date carried over, got %+v", entries[0])
+	}
+	out := appendEmbeddedManifest("# Doc\n", manifestMeta{GeneratedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}, entries)
+	if !strings.Contains(out, "; chars=5; published=2023-06-01\n") {
+		t.Fatalf("expected published date in entry line; got:\n%s", out)
+	}
+	b, err := marshalManifestJSON(manifestMeta{}, entries)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(b), `"published": "2023-06-01"`) {
+		t.Fatalf("expected published in JSON manifest: %s", b)
+	}
+}
+
+func TestManifest_RecordsAliases(t *testing.T) {
+	entries := buildManifestEntriesFromSynth([]synth.SourceExcerpt{{Index: 1, URL: "https://example.com/a", Excerpt: "hello", Aliases: []string{"https://m.example.com/a"}}})
+	out := appendEmbeddedManifest("# Doc\n", manifestMeta{}, entries)
+	if !strings.Contains(out, "; chars=5; aliases=https://m.example.com/a\n") {
+		t.Fatalf("expected aliases in entry line; got:\n%s", out)
+	}
+	b, _ := marshalManifestJSON(manifestMeta{}, entries)
+	if !strings.Contains(string(b), `"aliases": [`) {
+		t.Fatalf("expected aliases in JSON manifest: %s", b)
+	}
+}
+
 func TestAppendEmbeddedManifestWithSkipped_AppendsSkippedSection(t *testing.T) {
     base := "# Doc\n\nBody\n"
     meta := manifestMeta{Model: "gpt-local", LLMBaseURL: "http://localhost:11434/v1", SourceCount: 1, HTTPCache: true, LLMCache: true, GeneratedAt: time.Date(2024,1,1,0,0,0,0,time.UTC)}
@@ -63,6 +94,18 @@ func TestAppendEmbeddedManifestWithSkipped_AppendsSkippedSection(t *testing.T) {
     }
 }
 
+func TestAppendEmbeddedManifestWithSkipped_SeparatesNearDuplicates(t *testing.T) {
+    meta := manifestMeta{GeneratedAt: time.Date(2024,1,1,0,0,0,0,time.UTC)}
+    skipped := []skippedEntry{{URL: "https://mirror.example/a", Reason: "near-duplicate of [1]"}}
+    out := appendEmbeddedManifestWithSkipped("# Doc\n", meta, nil, skipped)
+    if strings.Contains(out, "### Skipped due to robots/opt-out") {
+        t.Fatalf("did not expect robots section without robots skips:\n%s", out)
+    }
+    if !strings.Contains(out, "### Skipped as duplicates\n\n- https://mirror.example/a — near-duplicate of [1]\n") {
+        t.Fatalf("expected near-duplicate section; got:\n%s", out)
+    }
+}
+
 func TestAppendToolTranscript_AppendsEntries(t *testing.T) {
     base := "# Doc\n\nBody\n"
     transcript := []llmtools.ToolCallRecord{
@@ -93,3 +136,46 @@ func TestFormatRobotsDetails_IncludesAvailableFields(t *testing.T) {
         t.Fatalf("unexpected formatted details: %q", s)
     }
 }
+
+func TestAppendEmbeddedManifest_SearchHealthOnlyWhenDegraded(t *testing.T) {
+    meta := manifestMeta{Model: "m", GeneratedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+    meta.SearchHealth = &searchHealth{Providers: []search.ProviderHealth{{Name: "file", Requests: 3}}}
+    if out := appendEmbeddedManifest("# Doc\n", meta, nil); strings.Contains(out, "### Search health") {
+        t.Fatalf("healthy search should not add a section:\n%s", out)
+    }
+    meta.SearchHealth = &searchHealth{
+        Providers: []search.ProviderHealth{{Name: "searxng", Requests: 6, Failures: 6, Retries: 3, Skipped: 4, CircuitOpen: true}},
+        Engines:   []search.EngineHealth{{Name: "duckduckgo_html", Unresponsive: 2, LastReason: "CAPTCHA"}},
+    }
+    out := appendEmbeddedManifest("# Doc\n", meta, nil)
+    if !strings.Contains(out, "- searxng: requests=6; failures=6; retries=3; circuit=open; skipped=4") {
+        t.Fatalf("expected provider health line:\n%s", out)
+    }
+    if !strings.Contains(out, "- engine duckduckgo_html: unresponsive=2 (CAPTCHA)") {
+        t.Fatalf("expected engine health line:\n%s", out)
+    }
+    data, err := marshalManifestJSON(meta, nil)
+    if err != nil || !strings.Contains(string(data), `"search_health"`) || !strings.Contains(string(data), `"circuit_open": true`) {
+        t.Fatalf("expected search_health in JSON manifest: %v\n%s", err, data)
+    }
+}
+
+func TestManifest_RecordsSourceMetadata(t *testing.T) {
+	entries := buildManifestEntriesFromSynth([]synth.SourceExcerpt{{
+		Index: 1, URL: "https://example.com/a", Excerpt: "hello",
+		Authors: []string{"Ann Lee"}, Publisher: "IETF", Modified: "2023-06-01", DOI: "10.5555/x", DeclaredLanguage: "en-US",
+	}})
+	out := appendEmbeddedManifest("# Doc\n", manifestMeta{}, entries)
+	if !strings.Contains(out, "; chars=5; doi=10.5555/x\n") {
+		t.Fatalf("expected DOI in entry line; got:\n%s", out)
+	}
+	b, err := marshalManifestJSON(manifestMeta{}, entries)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	for _, want := range []string{`"authors": [`, `"publisher": "IETF"`, `"modified": "2023-06-01"`, `"doi": "10.5555/x"`, `"declared_language": "en-US"`} {
+		if !strings.Contains(string(b), want) {
+			t.Fatalf("expected %s in JSON manifest: %s", want, b)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package app
+
+import (
+    "context"
+    "fmt"
+    "strings"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/hyperifyio/goresearch/internal/dedupe"
+    "github.com/hyperifyio/goresearch/internal/extract"
+    "github.com/hyperifyio/goresearch/internal/search"
+    sel "github.com/hyperifyio/goresearch/internal/select"
+    "github.com/hyperifyio/goresearch/internal/synth"
+)
+
+// Skipped reasons for sources dropped as copies of a kept source, e.g.
+// "near-duplicate of [2]". Duplicates share a resolved URL identity;
+// near-duplicates share most of their text.
+const (
+    duplicateReasonPrefix     = "duplicate of "
+    nearDuplicateReasonPrefix = "near-duplicate of "
+)
+
+// isDuplicateReason reports whether a skipped reason marks a copy.
+func isDuplicateReason(reason string) bool {
+    return strings.HasPrefix(reason, duplicateReasonPrefix) || strings.HasPrefix(reason, nearDuplicateReasonPrefix)
+}
+
+// selectWithReserve selects the sources to fetch and a ranked reserve of as
+// many extra candidates, used to refill slots lost during fetch and
+// extraction. The sources are selected on their own so type minimums are
+// met within the budget rather than spilling into the reserve.
+func selectWithReserve(results []search.Result, opt sel.Options) (selected, reserve []search.Result) {
+    limit := opt.MaxTotal
+    if limit <= 0 {
+        limit = 10
+    }
+    opt.MaxTotal = limit
+    selected = sel.Select(results, opt)
+    taken := make(map[string]struct{}, len(selected))
+    for _, r := range selected {
+        taken[r.URL] = struct{}{}
+    }
+    opt.MaxTotal = 2 * limit
+    for _, r := range sel.Select(results, opt) {
+        if len(reserve) >= limit {
+            break
+        }
+        if _, ok := taken[r.URL]; !ok {
+            reserve = append(reserve, r)
+        }
+    }
+    return selected, reserve
+}
+
+// fetchAndExtractUnique fetches the selected sources like fetchAndExtract and
+// drops any that resolve to the same URL identity as, or whose extracted text
+// is a near-duplicate of, an earlier, higher ranked source. Selection order
+// already reflects authority, so the first copy is the one kept. Slots freed
+// by duplicates or failed fetches are refilled from reserve in order, and
+// the excerpts are numbered densely.
+func fetchAndExtractUnique(ctx context.Context, f sourceGetter, extractor interface{ Extract([]byte) extract.Document }, selected, reserve []search.Result, cfg Config) ([]synth.SourceExcerpt, []skippedEntry, []fetchRecord) {
+    target := len(selected)
+    queue := append(append([]search.Result{}, selected...), reserve...)
+    excerpts := make([]synth.SourceExcerpt, 0, target)
+    skipped := make([]skippedEntry, 0)
+    var records []fetchRecord
+    var index dedupe.Index
+    norm := urlnormOptions(cfg)
+    identities := map[string]int{}
+    // queued and domains let feed entries skip pages already queued and
+    // stay within the per-domain cap.
+    queued := make(map[string]bool, len(queue))
+    for _, r := range queue {
+        queued[norm.Key(r.URL)] = true
+    }
+    domains := map[string]int{}
+    for len(excerpts) < target && len(queue) > 0 && ctx.Err() == nil {
+        n := target - len(excerpts)
+        if n > len(queue) {
+            n = len(queue)
+        }
+        batch := queue[:n]
+        queue = queue[n:]
+        for _, r := range batch {
+            if r.Source != feedEntrySource {
+                domains[domainKey(r.URL)]++
+            }
+        }
+        outcomes := fetchOutcomes(ctx, f, extractor, batch, cfg)
+        // Feed entries take the next slots, ahead of the reserve. An
+        // expanded feed is not a source, so it gives its domain slot back.
+        var entries []search.Result
+        for i, o := range outcomes {
+            if o.record.Status == fetchStatusExpanded {
+                domains[domainKey(batch[i].URL)]--
+            }
+            entries = append(entries, admitFeedEntries(o.entries, cfg, queued, domains)...)
+        }
+        queue = append(entries, queue...)
+        got, sk, rec := collectOutcomes(outcomes)
+        skipped = append(skipped, sk...)
+        records = append(records, rec...)
+        for _, ex := range got {
+            ex.Index = len(excerpts) + 1
+            key := norm.Key(ex.URL)
+            if of, dup := identities[key]; dup {
+                reason := fmt.Sprintf("%s[%d]", duplicateReasonPrefix, of)
+                log.Info().Str("url", ex.URL).Strs("aliases", ex.Aliases).Str("reason", reason).Msg("skipping duplicate source")
+                skipped = append(skipped, skippedEntry{URL: pickNonEmpty(firstOf(ex.Aliases), ex.URL), Reason: reason})
+                continue
+            }
+            sig, ok := dedupe.Fingerprint(ex.Excerpt)
+            if ok {
+                if of, dup := index.Match(sig); dup {
+                    reason := fmt.Sprintf("%s[%d]", nearDuplicateReasonPrefix, of)
+                    log.Info().Str("url", ex.URL).Str("reason", reason).Msg("skipping near-duplicate source")
+                    skipped = append(skipped, skippedEntry{URL: ex.URL, Reason: reason})
+                    continue
+                }
+                index.Add(ex.Index, sig)
+            }
+            identities[key] = ex.Index
+            excerpts = append(excerpts, ex)
+        }
+    }
+    return excerpts, skipped, records
+}
+
+func firstOf(list []string) string {
+    if len(list) == 0 {
+        return ""
+    }
+    return list[0]
+}
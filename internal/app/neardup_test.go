@@ -0,0 +1,66 @@
+package app
+
+import (
+    "context"
+    "strings"
+    "testing"
+
+    "github.com/hyperifyio/goresearch/internal/search"
+    sel "github.com/hyperifyio/goresearch/internal/select"
+)
+
+func TestSelectWithReserve_SplitsAtBudget(t *testing.T) {
+    var results []search.Result
+    for _, host := range []string{"a", "b", "c", "d", "e"} {
+        results = append(results, search.Result{Title: host, URL: "https://" + host + ".example/", Snippet: "snippet " + host})
+    }
+    selected, reserve := selectWithReserve(results, sel.Options{MaxTotal: 2, PerDomain: 1})
+    if len(selected) != 2 || len(reserve) != 2 {
+        t.Fatalf("expected 2 selected and 2 reserve, got %d and %d", len(selected), len(reserve))
+    }
+}
+
+func TestFetchAndExtractUnique_DropsNearDuplicatesAndRefills(t *testing.T) {
+    body := strings.Repeat("Certificate transparency logs record every issued certificate so domain owners can audit issuance. ", 6)
+    pages := map[string]string{
+        "https://origin.example/":  "<html><body><p>" + body + "</p></body></html>",
+        "https://mirror.example/":  "<html><body><p>Syndicated copy. " + body + "</p></body></html>",
+        "https://other.example/":   "<html><body><p>" + strings.Repeat("Key pinning binds a host to a set of public keys and was deprecated by browsers over operational risk. ", 6) + "</p></body></html>",
+    }
+    getter := sourceGetterFunc(func(ctx context.Context, url string) ([]byte, string, error) {
+        return []byte(pages[url]), "text/html", nil
+    })
+    selected := []search.Result{{Title: "Origin", URL: "https://origin.example/"}, {Title: "Mirror", URL: "https://mirror.example/"}}
+    reserve := []search.Result{{Title: "Other", URL: "https://other.example/"}}
+    excerpts, skipped, _ := fetchAndExtractUnique(context.Background(), getter, nil, selected, reserve, Config{PerSourceChars: 5000})
+    if len(excerpts) != 2 {
+        t.Fatalf("expected 2 excerpts after refill, got %d", len(excerpts))
+    }
+    if excerpts[0].URL != "https://origin.example/" || excerpts[1].URL != "https://other.example/" {
+        t.Fatalf("unexpected sources: %s, %s", excerpts[0].URL, excerpts[1].URL)
+    }
+    if excerpts[1].Index != 2 {
+        t.Fatalf("expected dense numbering, got index %d", excerpts[1].Index)
+    }
+    if len(skipped) != 1 || skipped[0].URL != "https://mirror.example/" || skipped[0].Reason != "near-duplicate of [1]" {
+        t.Fatalf("unexpected skipped entries: %+v", skipped)
+    }
+}
+
+func TestFetchAndExtractUnique_DropsSameCanonicalIdentity(t *testing.T) {
+    pages := map[string]string{
+        "https://example.com/story":     `<html><head><title>Story</title></head><body><p>Short story text.</p></body></html>`,
+        "https://example.com/story/amp": `<html><head><link rel="canonical" href="https://example.com/story"></head><body><p>AMP story text.</p></body></html>`,
+    }
+    getter := sourceGetterFunc(func(ctx context.Context, url string) ([]byte, string, error) {
+        return []byte(pages[url]), "text/html", nil
+    })
+    selected := []search.Result{{Title: "Story", URL: "https://example.com/story"}, {Title: "AMP", URL: "https://example.com/story/amp"}}
+    excerpts, skipped, _ := fetchAndExtractUnique(context.Background(), getter, nil, selected, nil, Config{PerSourceChars: 5000})
+    if len(excerpts) != 1 {
+        t.Fatalf("expected 1 excerpt, got %d", len(excerpts))
+    }
+    if len(skipped) != 1 || skipped[0].URL != "https://example.com/story/amp" || skipped[0].Reason != "duplicate of [1]" {
+        t.Fatalf("unexpected skipped entries: %+v", skipped)
+    }
+}
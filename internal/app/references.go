@@ -2,19 +2,30 @@ package app
 
 import (
     "regexp"
+    "strconv"
     "strings"
     "time"
+
+    "github.com/hyperifyio/goresearch/internal/sourcetype"
+    "github.com/hyperifyio/goresearch/internal/synth"
 )
 
 // enrichReferences scans the Markdown references section and applies deterministic
 // enrichments:
 // - Convert certain URLs to stable/canonical forms (arXiv abs, RFC Editor)
+// - Append the authors, publisher and publication date the source's metadata
+//   declares, and its DOI, when the line lacks them
 // - If a DOI is detectable in the line, ensure a canonical DOI URL is present
 // - Append "(Accessed on YYYY-MM-DD)" for web sources lacking an access date
+// Lines are matched to sources as in annotateReferenceTypes; sources may be nil.
 // The function is conservative: it only rewrites within the References block and
 // keeps other content unchanged. It requires no network access.
-func enrichReferences(markdown string, now func() time.Time) string {
+func enrichReferences(markdown string, sources []synth.SourceExcerpt, now func() time.Time) string {
     if now == nil { now = time.Now }
+    byIndex := make(map[int]synth.SourceExcerpt, len(sources))
+    for _, s := range sources {
+        byIndex[s.Index] = s
+    }
     lines := strings.Split(markdown, "\n")
     inRefs := false
     order := 0
@@ -48,6 +59,16 @@ func enrichReferences(markdown string, now func() time.Time) string {
             order++
             content := strings.TrimSpace(m[2])
 
+            // Attribute the source from its own metadata
+            src, ok := sourceOnLine(s, sources)
+            if !ok {
+                n, _ := strconv.Atoi(m[1])
+                src, ok = byIndex[n]
+            }
+            if ok {
+                content = appendAttribution(content, src)
+            }
+
             // Identify first URL
             loc := urlRe.FindStringIndex(content)
             if loc != nil {
@@ -85,6 +106,42 @@ func enrichReferences(markdown string, now func() time.Time) string {
     return strings.Join(lines, "\n")
 }
 
+// appendAttribution appends " — authors. Publisher. Published YYYY-MM-DD."
+// with the parts the reference line does not already mention, and the
+// source's DOI when the line has none, so repeated runs change nothing.
+func appendAttribution(content string, src synth.SourceExcerpt) string {
+    // Keep an access date the model wrote at the end of the line.
+    tail := ""
+    if i := strings.LastIndex(strings.ToLower(content), " (accessed on "); i >= 0 {
+        content, tail = content[:i], content[i:]
+    }
+    lower := strings.ToLower(content)
+    var parts []string
+    mentioned := false
+    for _, a := range src.Authors {
+        if strings.Contains(lower, strings.ToLower(a)) {
+            mentioned = true
+            break
+        }
+    }
+    if len(src.Authors) > 0 && !mentioned {
+        parts = append(parts, strings.Join(src.Authors, "; "))
+    }
+    if p := strings.TrimSpace(src.Publisher); p != "" && !strings.Contains(lower, strings.ToLower(p)) {
+        parts = append(parts, p)
+    }
+    if d := strings.TrimSpace(src.Published); d != "" && !strings.Contains(content, d) {
+        parts = append(parts, "Published "+d)
+    }
+    if len(parts) > 0 {
+        content = strings.TrimSuffix(content, ".") + " — " + strings.Join(parts, ". ") + "."
+    }
+    if doi := strings.TrimSpace(src.DOI); doi != "" && !strings.Contains(strings.ToLower(content), strings.ToLower(doi)) {
+        content = strings.TrimSuffix(content, ".") + " DOI: https://doi.org/" + doi
+    }
+    return content + tail
+}
+
 // toStableURL converts certain known URLs to stable forms without network access.
 // - arXiv PDF -> arXiv abs
 // - IETF datatracker RFC -> rfc-editor canonical RFC URL
@@ -107,3 +164,64 @@ func toStableURL(u string) string {
     }
     return u
 }
+
+// annotateReferenceTypes appends the source type, e.g. "(Type: academic)", to
+// each numbered item in the References section so readers can judge the
+// evidence base at a glance. An item is matched to the source whose URL or
+// alias appears on the line, else to the source with the same number. Lines
+// already carrying a type are left alone.
+func annotateReferenceTypes(markdown string, sources []synth.SourceExcerpt) string {
+    if len(sources) == 0 {
+        return markdown
+    }
+    byIndex := make(map[int]synth.SourceExcerpt, len(sources))
+    for _, s := range sources {
+        byIndex[s.Index] = s
+    }
+    headingRe := regexp.MustCompile(`^#{1,6}\s+References\s*$`)
+    numItemRe := regexp.MustCompile(`^(\d+)\.\s+(.+)$`)
+    lines := strings.Split(markdown, "\n")
+    inRefs := false
+    for i := range lines {
+        s := strings.TrimSpace(lines[i])
+        if s == "" { continue }
+        if headingRe.MatchString(s) {
+            inRefs = true
+            continue
+        }
+        if !inRefs { continue }
+        if strings.HasPrefix(s, "#") {
+            inRefs = false
+            continue
+        }
+        m := numItemRe.FindStringSubmatch(s)
+        if m == nil || strings.Contains(s, "(Type: ") { continue }
+        src, ok := sourceOnLine(s, sources)
+        if !ok {
+            n, _ := strconv.Atoi(m[1])
+            src, ok = byIndex[n]
+        }
+        if !ok || src.SourceType == "" { continue }
+        lines[i] = s + " (Type: " + sourcetype.Label(src.SourceType) + ")"
+    }
+    return strings.Join(lines, "\n")
+}
+
+// sourceURLRe finds the links sourceOnLine compares against source URLs.
+var sourceURLRe = regexp.MustCompile(`https?://[^\s)>\]]+`)
+
+// sourceOnLine returns the source whose URL or one of its aliases is linked
+// on line.
+func sourceOnLine(line string, sources []synth.SourceExcerpt) (synth.SourceExcerpt, bool) {
+    for _, found := range sourceURLRe.FindAllString(line, -1) {
+        found = strings.TrimRight(found, ".,;")
+        for _, s := range sources {
+            for _, u := range append([]string{s.URL}, s.Aliases...) {
+                if u != "" && u == found {
+                    return s, true
+                }
+            }
+        }
+    }
+    return synth.SourceExcerpt{}, false
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/hyperifyio/goresearch/internal/sourcetype"
+    "github.com/hyperifyio/goresearch/internal/synth"
+)
+
+func TestAnnotateReferenceTypes(t *testing.T) {
+    md := "# R\n\nText [1][2].\n\n## References\n1. Spec — https://www.w3.org/TR/html/ (Accessed on 2025-01-01)\n2. Post — https://blog.example.com/p\n3. Other — https://unknown.example/\n\n## Appendix\n1. Not a reference — https://www.w3.org/TR/html/\n"
+    sources := []synth.SourceExcerpt{
+        {Index: 1, URL: "https://blog.example.com/p", SourceType: sourcetype.Blog},
+        {Index: 2, URL: "https://w3.org/TR/html", Aliases: []string{"https://www.w3.org/TR/html/"}, SourceType: sourcetype.Primary},
+        {Index: 3, URL: "https://elsewhere.example/", SourceType: sourcetype.News},
+    }
+    out := annotateReferenceTypes(md, sources)
+    for _, want := range []string{
+        "1. Spec — https://www.w3.org/TR/html/ (Accessed on 2025-01-01) (Type: official docs/standard)",
+        "2. Post — https://blog.example.com/p (Type: blog)",
+        // No URL match: falls back to the source with the same number.
+        "3. Other — https://unknown.example/ (Type: news)",
+        "1. Not a reference — https://www.w3.org/TR/html/\n",
+    } {
+        if !strings.Contains(out, want) {
+            t.Fatalf("missing %q in:\n%s", want, out)
+        }
+    }
+    if again := annotateReferenceTypes(out, sources); again != out {
+        t.Fatalf("expected idempotent annotation, got:\n%s", again)
+    }
+}
+
+func TestEnrichReferences_AttributesSourceMetadata(t *testing.T) {
+    now := func() time.Time { return time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC) }
+    md := "# R\n\n## References\n1. HTTP Semantics — https://www.rfc-editor.org/rfc/rfc9110\n2. Paper by Lee, IETF — https://papers.example/p (Accessed on 2025-01-01)\n3. Unknown — https://unknown.example/\n"
+    sources := []synth.SourceExcerpt{
+        {Index: 1, URL: "https://www.rfc-editor.org/rfc/rfc9110", Authors: []string{"R. Fielding", "M. Nottingham"}, Publisher: "IETF", Published: "2022-06-01"},
+        {Index: 2, URL: "https://papers.example/p", Authors: []string{"Lee"}, Publisher: "IETF", Published: "2023-06-01", DOI: "10.5555/x.1"},
+    }
+    out := enrichReferences(md, sources, now)
+    for _, want := range []string{
+        "1. HTTP Semantics — https://www.rfc-editor.org/rfc/rfc9110 — R. Fielding; M. Nottingham. IETF. Published 2022-06-01. (Accessed on 2025-02-03)",
+        "2. Paper by Lee, IETF — https://papers.example/p — Published 2023-06-01 DOI: https://doi.org/10.5555/x.1 (Accessed on 2025-01-01)",
+        "3. Unknown — https://unknown.example/ (Accessed on 2025-02-03)",
+    } {
+        if !strings.Contains(out, want) {
+            t.Fatalf("missing %q in:\n%s", want, out)
+        }
+    }
+    if again := enrichReferences(out, sources, now); again != out {
+        t.Fatalf("expected idempotent enrichment, got:\n%s", again)
+    }
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+    "context"
+    "strings"
+    "time"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/hyperifyio/goresearch/internal/brief"
+    "github.com/hyperifyio/goresearch/internal/cache"
+    "github.com/hyperifyio/goresearch/internal/llm"
+    "github.com/hyperifyio/goresearch/internal/rerank"
+    "github.com/hyperifyio/goresearch/internal/search"
+)
+
+// rerankQueryMaxChars bounds how much of the raw brief is embedded as the
+// reranking query.
+const rerankQueryMaxChars = 2000
+
+// rerankResults orders merged search results by embedding relevance to the
+// brief and outline when a rerank model is configured. It reports whether
+// the returned slice is relevance-ranked; on any failure the input order is
+// kept so reranking never fails the run.
+func rerankResults(ctx context.Context, client llm.Client, cfg Config, b brief.Brief, outline []string, results []search.Result) ([]search.Result, bool) {
+    model := strings.TrimSpace(cfg.RerankModel)
+    if model == "" || len(results) < 2 {
+        return results, false
+    }
+    emb, ok := client.(llm.Embedder)
+    if !ok {
+        log.Warn().Msg("rerank: LLM provider does not support embeddings; keeping search order")
+        return results, false
+    }
+    start := time.Now()
+    r := &rerank.Reranker{Client: emb, Model: model, Lambda: cfg.RerankLambda, CacheOnly: cfg.LLMCacheOnly}
+    if strings.TrimSpace(cfg.CacheDir) != "" {
+        r.Cache = &cache.LLMCache{Dir: cfg.CacheDir, StrictPerms: cfg.CacheStrictPerms}
+    }
+    out, scores, err := r.Rerank(ctx, rerankQuery(b, outline), results)
+    if err != nil {
+        log.Warn().Err(err).Str("model", model).Msg("rerank failed; keeping search order")
+        return results, false
+    }
+    for i, s := range scores {
+        log.Debug().Str("stage", "rerank").Int("rank", i+1).Str("url", s.URL).Float64("relevance", s.Relevance).Float64("mmr", s.MMR).Msg("reranked result")
+    }
+    log.Info().Str("stage", "rerank").Str("model", model).Int("results", len(out)).Dur("elapsed", time.Since(start)).Msg("rerank completed")
+    return out, true
+}
+
+// rerankQuery builds the text results are compared against: the brief
+// (bounded in length) followed by the planned section headings.
+func rerankQuery(b brief.Brief, outline []string) string {
+    text := strings.TrimSpace(b.Raw)
+    if text == "" {
+        text = b.Topic
+    }
+    if r := []rune(text); len(r) > rerankQueryMaxChars {
+        text = string(r[:rerankQueryMaxChars])
+    }
+    if len(outline) > 0 {
+        text += "\n" + strings.Join(outline, "\n")
+    }
+    return text
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    openai "github.com/sashabaranov/go-openai"
+
+    "github.com/hyperifyio/goresearch/internal/brief"
+    "github.com/hyperifyio/goresearch/internal/llm"
+    "github.com/hyperifyio/goresearch/internal/search"
+)
+
+// stubEmbeddings serves /v1/embeddings with one dimension per known word.
+func stubEmbeddings(t *testing.T, vocab []string) *httptest.Server {
+    t.Helper()
+    return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path != "/v1/embeddings" {
+            http.NotFound(w, r)
+            return
+        }
+        var req struct {
+            Input []string `json:"input"`
+        }
+        _ = json.NewDecoder(r.Body).Decode(&req)
+        data := make([]map[string]any, 0, len(req.Input))
+        for i, in := range req.Input {
+            v := make([]float32, len(vocab))
+            for j, word := range vocab {
+                v[j] = float32(strings.Count(strings.ToLower(in), word))
+            }
+            data = append(data, map[string]any{"object": "embedding", "index": i, "embedding": v})
+        }
+        w.Header().Set("Content-Type", "application/json")
+        _ = json.NewEncoder(w).Encode(map[string]any{"object": "list", "data": data})
+    }))
+}
+
+func TestRerankResults_OrdersByRelevanceAndCaches(t *testing.T) {
+    srv := stubEmbeddings(t, []string{"kubernetes", "scheduler", "pasta"})
+    oc := openai.DefaultConfig("")
+    oc.BaseURL = srv.URL + "/v1"
+    client := &llm.OpenAIProvider{Inner: openai.NewClientWithConfig(oc)}
+    results := []search.Result{
+        {Title: "Pasta", URL: "https://food.example/", Snippet: "pasta pasta"},
+        {Title: "Kubernetes scheduler", URL: "https://k8s.example/", Snippet: "how the kubernetes scheduler works"},
+    }
+    cfg := Config{RerankModel: "embed", CacheDir: t.TempDir()}
+    b := brief.Brief{Topic: "Kubernetes scheduler internals"}
+    out, ranked := rerankResults(context.Background(), client, cfg, b, nil, results)
+    if !ranked || out[0].URL != "https://k8s.example/" {
+        t.Fatalf("expected relevance order, ranked=%v got %v", ranked, out)
+    }
+    // Vectors come from the cache once the endpoint is gone.
+    srv.Close()
+    cfg.LLMCacheOnly = true
+    out, ranked = rerankResults(context.Background(), client, cfg, b, nil, results)
+    if !ranked || out[0].URL != "https://k8s.example/" {
+        t.Fatalf("expected cached relevance order, ranked=%v got %v", ranked, out)
+    }
+}
+
+func TestRerankResults_KeepsOrderWhenUnavailable(t *testing.T) {
+    results := []search.Result{{URL: "https://a.example/"}, {URL: "https://b.example/"}}
+    out, ranked := rerankResults(context.Background(), chatOnlyClient{}, Config{RerankModel: "embed"}, brief.Brief{Topic: "x"}, nil, results)
+    if ranked || out[0].URL != "https://a.example/" {
+        t.Fatalf("expected input order without embeddings support, ranked=%v got %v", ranked, out)
+    }
+    out, ranked = rerankResults(context.Background(), chatOnlyClient{}, Config{}, brief.Brief{Topic: "x"}, nil, results)
+    if ranked || len(out) != 2 {
+        t.Fatalf("expected reranking disabled without a model")
+    }
+}
+
+func TestRerankQuery_IncludesBriefAndOutline(t *testing.T) {
+    q := rerankQuery(brief.Brief{Topic: "T", Raw: "# T\nDetails"}, []string{"Background", "Risks"})
+    if !strings.Contains(q, "Details") || !strings.HasSuffix(q, "Background\nRisks") {
+        t.Fatalf("unexpected query: %q", q)
+    }
+}
+
+// chatOnlyClient is an llm.Client without the Embedder capability.
+type chatOnlyClient struct{}
+
+func (chatOnlyClient) CreateChatCompletion(context.Context, openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+    return openai.ChatCompletionResponse{}, nil
+}
@@ -0,0 +1,152 @@
+package app
+
+import (
+    "fmt"
+    "strings"
+    "time"
+
+    "github.com/rs/zerolog/log"
+
+    "github.com/hyperifyio/goresearch/internal/search"
+)
+
+const defaultUserAgent = "goresearch/1.0 (+https://github.com/hyperifyio/goresearch)"
+
+// defaultRecencyExemptHosts are standards bodies and registries whose
+// documents stay authoritative long after publication.
+var defaultRecencyExemptHosts = []string{"rfc-editor.org", "ietf.org", "w3.org", "whatwg.org", "iso.org", "nist.gov", "ecma-international.org"}
+
+// recencyExemptHosts returns the configured exemptions or the defaults.
+func recencyExemptHosts(cfg Config) []string {
+    if cfg.RecencyExemptHosts != nil {
+        return cfg.RecencyExemptHosts
+    }
+    return defaultRecencyExemptHosts
+}
+
+// recencyReason is the skipped reason for a page whose own date is older
+// than the recency window.
+const recencyReason = "older than recency window"
+
+// olderThanRecency reports whether t is a known date before the recency
+// window; it is always false when no window is configured.
+func olderThanRecency(t time.Time, cfg Config) bool {
+    return cfg.RecencyMonths > 0 && !t.IsZero() && t.Before(time.Now().AddDate(0, -cfg.RecencyMonths, 0))
+}
+
+// searxTimeRange maps the recency window onto SearxNG's coarse time_range
+// values. Windows longer than a year are left to selection alone.
+func searxTimeRange(months int) string {
+    switch {
+    case months <= 0:
+        return ""
+    case months <= 1:
+        return "month"
+    case months <= 12:
+        return "year"
+    default:
+        return ""
+    }
+}
+
+// buildSearchProvider constructs the configured search provider. It returns
+// nil when no provider is configured so callers can continue without search.
+// An explicit SearchProvider list is honored in order; otherwise the file
+// provider wins over SearxNG to keep offline runs deterministic. When health
+// is non-nil each provider is wrapped with retries and a circuit breaker that
+// report into it.
+func buildSearchProvider(cfg Config, health *search.HealthTracker) (search.Provider, error) {
+    names := searchProviderNames(cfg)
+    providers := make([]search.Provider, 0, len(names))
+    for _, name := range names {
+        p, err := newNamedSearchProvider(name, cfg, health)
+        if err != nil {
+            return nil, err
+        }
+        if health != nil {
+            p = &search.Guarded{Provider: p, Health: health, MaxAttempts: cfg.SearchMaxAttempts, Backoff: cfg.SearchBackoff, FailureThreshold: cfg.SearchCircuitThreshold}
+        }
+        providers = append(providers, p)
+    }
+    switch len(providers) {
+    case 0:
+        return nil, nil
+    case 1:
+        return providers[0], nil
+    default:
+        return &search.Multi{Providers: providers}, nil
+    }
+}
+
+// searchProviderNames resolves the provider list, applying the automatic
+// choice when SearchProvider is empty.
+func searchProviderNames(cfg Config) []string {
+    names := splitProviderList(cfg.SearchProvider)
+    if len(names) == 0 {
+        if strings.TrimSpace(cfg.FileSearchPath) != "" {
+            names = []string{"file"}
+        } else if strings.TrimSpace(cfg.SearxURL) != "" {
+            names = []string{"searxng"}
+        }
+    }
+    return names
+}
+
+// newNamedSearchProvider builds a single provider by its configuration name.
+func newNamedSearchProvider(name string, cfg Config, health *search.HealthTracker) (search.Provider, error) {
+    policy := search.DomainPolicy{Allowlist: cfg.DomainAllowlist, Denylist: cfg.DomainDenylist}
+    ua := strings.TrimSpace(cfg.SearxUA)
+    if ua == "" {
+        ua = defaultUserAgent
+    }
+    switch name {
+    case "file":
+        if strings.TrimSpace(cfg.FileSearchPath) == "" {
+            return nil, fmt.Errorf("search provider %q requires search.file", name)
+        }
+        return &search.FileProvider{Path: cfg.FileSearchPath, Policy: policy}, nil
+    case "searxng", "searx":
+        if strings.TrimSpace(cfg.SearxURL) == "" {
+            return nil, fmt.Errorf("search provider %q requires searx.url", name)
+        }
+        return &search.SearxNG{BaseURL: cfg.SearxURL, APIKey: cfg.SearxKey, HTTPClient: newHTTPClient(cfg), UserAgent: ua, Policy: policy, Health: health, TimeRange: searxTimeRange(cfg.RecencyMonths), AllowPrivateHosts: cfg.AllowPrivateHosts}, nil
+    case "wikipedia":
+        lang := strings.TrimSpace(cfg.WikipediaLanguage)
+        if lang == "" {
+            lang = strings.TrimSpace(cfg.LanguageHint)
+        }
+        return &search.Wikipedia{Language: lang, BaseURL: cfg.WikipediaBaseURL, HTTPClient: newHTTPClient(cfg), UserAgent: ua, Policy: policy}, nil
+    default:
+        return nil, fmt.Errorf("unknown search provider %q", name)
+    }
+}
+
+// splitProviderList parses a comma-separated provider list, lower-casing and
+// dropping empty entries.
+func splitProviderList(s string) []string {
+    parts := strings.Split(s, ",")
+    out := make([]string, 0, len(parts))
+    for _, p := range parts {
+        if v := strings.ToLower(strings.TrimSpace(p)); v != "" {
+            out = append(out, v)
+        }
+    }
+    return out
+}
+
+// logSearchHealth reports degraded providers and engines after the search
+// stage so operators can tell an empty result set from an outage.
+func logSearchHealth(health *search.HealthTracker) {
+    for _, p := range health.Providers() {
+        if p.Failures == 0 && !p.CircuitOpen {
+            continue
+        }
+        log.Warn().Str("provider", p.Name).Int("requests", p.Requests).Int("failures", p.Failures).Int("retries", p.Retries).Int("skipped", p.Skipped).Bool("circuit_open", p.CircuitOpen).Str("last_error", p.LastError).Msg("search provider degraded")
+    }
+    for _, e := range health.Engines() {
+        if e.Unresponsive == 0 {
+            continue
+        }
+        log.Warn().Str("engine", e.Name).Int("ok", e.OK).Int("unresponsive", e.Unresponsive).Str("reason", e.LastReason).Msg("search engine unresponsive")
+    }
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+    "context"
+    "errors"
+    "strings"
+
+    "github.com/hyperifyio/goresearch/internal/aggregate"
+    "github.com/hyperifyio/goresearch/internal/credibility"
+    "github.com/hyperifyio/goresearch/internal/dates"
+    "github.com/hyperifyio/goresearch/internal/search"
+    "github.com/hyperifyio/goresearch/internal/sourcetype"
+    sel "github.com/hyperifyio/goresearch/internal/select"
+    "github.com/hyperifyio/goresearch/internal/urlnorm"
+)
+
+// SearchReport is the outcome of a standalone search and selection pass for a
+// single query, as printed by `goresearch search`.
+type SearchReport struct {
+    Query    string            `json:"query"`
+    Provider string            `json:"provider"`
+    Results  []SearchReportRow `json:"results"`
+    Health   *searchHealth     `json:"search_health,omitempty"`
+}
+
+// SearchReportRow describes one candidate and what selection did with it.
+// Rank is the 1-based selection order; candidates removed by domain policy
+// never reach ranking and have Rank 0.
+type SearchReportRow struct {
+    Rank     int    `json:"rank"`
+    Title    string `json:"title"`
+    URL      string `json:"url"`
+    Snippet  string `json:"snippet"`
+    Source   string `json:"source"`
+    Language string `json:"language,omitempty"`
+    // LanguageConfidence is the detector's confidence in Language.
+    LanguageConfidence float64 `json:"language_confidence,omitempty"`
+    // Published is the provider-reported publication date (YYYY-MM-DD).
+    Published string `json:"published,omitempty"`
+    // Stale marks candidates demoted by the recency policy.
+    Stale bool `json:"stale,omitempty"`
+    // SourceType is the URL-based source type used for quotas.
+    SourceType string `json:"source_type,omitempty"`
+    // Credibility is the score breakdown used for ranking.
+    Credibility *credibility.Breakdown `json:"credibility,omitempty"`
+    Selected bool   `json:"selected"`
+    Reason   string `json:"reason,omitempty"`
+}
+
+// selectOptions maps configuration onto selection options so the pipeline
+// and the search subcommand select identically.
+func selectOptions(cfg Config) sel.Options {
+    return sel.Options{MaxTotal: cfg.MaxSources, PerDomain: cfg.PerDomainCap, MinSnippetChars: cfg.MinSnippetChars, PreferredLanguage: cfg.LanguageHint, RecencyMonths: cfg.RecencyMonths, RecencyExemptHostPatterns: recencyExemptHosts(cfg), URLNorm: urlnormOptions(cfg), Scorer: sourceScorer(cfg), Quotas: sourceTypeQuotas(cfg), AllowedLanguages: cfg.AllowedLanguages}
+}
+
+// sourceTypeQuotas maps configuration onto selection source-type quotas.
+func sourceTypeQuotas(cfg Config) sourcetype.Quotas {
+    return sourcetype.Quotas{Min: cfg.SourceTypeMin, Max: cfg.SourceTypeMax}
+}
+
+// urlnormOptions maps configuration onto URL normalization options.
+func urlnormOptions(cfg Config) urlnorm.Options {
+    return urlnorm.Options{TrackerParams: cfg.TrackerParams}
+}
+
+// RunSearch queries the configured provider(s) for one query and runs the
+// same normalization and selection as the main pipeline, reporting every
+// candidate. Providers are built without the domain policy, which is applied
+// here instead so that policy drops show up in the report with a reason.
+func RunSearch(ctx context.Context, cfg Config, query string, limit int) (SearchReport, error) {
+    rep := SearchReport{Query: query}
+    if strings.TrimSpace(query) == "" {
+        return rep, errors.New("empty query")
+    }
+    if limit <= 0 {
+        limit = 10
+    }
+    policy := search.DomainPolicy{Allowlist: cfg.DomainAllowlist, Denylist: cfg.DomainDenylist}
+    pcfg := cfg
+    pcfg.DomainAllowlist, pcfg.DomainDenylist = nil, nil
+    health := &search.HealthTracker{}
+    provider, err := buildSearchProvider(pcfg, health)
+    if err != nil {
+        return rep, err
+    }
+    if provider == nil {
+        return rep, errors.New("no search provider configured (set searx.url, search.file or search.provider)")
+    }
+    rep.Provider = provider.Name()
+    results, err := provider.Search(ctx, query, limit)
+    rep.Health = newSearchHealth(health)
+    if err != nil {
+        return rep, err
+    }
+    merged := aggregate.MergeAndNormalizeWith([][]search.Result{results}, urlnormOptions(cfg))
+
+    allowed := make([]search.Result, 0, len(merged))
+    var blocked []SearchReportRow
+    for _, r := range merged {
+        if isBlocked, why := policy.Blocked(r.URL); isBlocked {
+            blocked = append(blocked, SearchReportRow{Title: r.Title, URL: r.URL, Snippet: r.Snippet, Source: r.Source, Reason: "domain-" + why})
+            continue
+        }
+        allowed = append(allowed, r)
+    }
+    _, decisions := sel.SelectWithReport(allowed, selectOptions(cfg))
+    rep.Results = make([]SearchReportRow, 0, len(decisions)+len(blocked))
+    for _, d := range decisions {
+        rep.Results = append(rep.Results, SearchReportRow{
+            Rank:     d.Rank,
+            Title:    d.Result.Title,
+            URL:      d.Result.URL,
+            Snippet:  d.Result.Snippet,
+            Source:   d.Result.Source,
+            Language: d.Language,
+            LanguageConfidence: d.LanguageConfidence,
+            Published: dates.Format(d.Result.Published),
+            Stale:    d.Stale,
+            SourceType: d.SourceType,
+            Credibility: d.Credibility,
+            Selected: d.Selected,
+            Reason:   d.Reason,
+        })
+    }
+    rep.Results = append(rep.Results, blocked...)
+    return rep, nil
+}
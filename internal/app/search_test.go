@@ -0,0 +1,86 @@
+package app
+
+import (
+    "context"
+    "os"
+    "path/filepath"
+    "testing"
+
+    "github.com/hyperifyio/goresearch/internal/search"
+)
+
+func TestBuildSearchProvider_Selection(t *testing.T) {
+    p, err := buildSearchProvider(Config{}, nil)
+    if err != nil || p != nil {
+        t.Fatalf("expected no provider when nothing configured, got %v, %v", p, err)
+    }
+
+    p, err = buildSearchProvider(Config{FileSearchPath: "results.json", SearxURL: "http://searx"}, nil)
+    if err != nil {
+        t.Fatalf("build: %v", err)
+    }
+    if _, ok := p.(*search.FileProvider); !ok {
+        t.Fatalf("expected file provider to win by default, got %T", p)
+    }
+
+    p, err = buildSearchProvider(Config{SearchProvider: "wikipedia", LanguageHint: "fi"}, nil)
+    if err != nil {
+        t.Fatalf("build: %v", err)
+    }
+    wp, ok := p.(*search.Wikipedia)
+    if !ok || wp.Language != "fi" {
+        t.Fatalf("expected wikipedia provider with language hint, got %#v", p)
+    }
+
+    p, err = buildSearchProvider(Config{SearchProvider: "searxng, wikipedia", SearxURL: "http://searx"}, nil)
+    if err != nil {
+        t.Fatalf("build: %v", err)
+    }
+    if m, ok := p.(*search.Multi); !ok || m.Name() != "searxng+wikipedia" {
+        t.Fatalf("expected composed provider, got %#v", p)
+    }
+
+    if _, err := buildSearchProvider(Config{SearchProvider: "searxng"}, nil); err == nil {
+        t.Fatalf("expected error when searxng selected without a URL")
+    }
+    if _, err := buildSearchProvider(Config{SearchProvider: "bing"}, nil); err == nil {
+        t.Fatalf("expected error for unknown provider")
+    }
+}
+
+func TestRunSearch_ReportsSelectionAndPolicyDrops(t *testing.T) {
+    fixture := filepath.Join(t.TempDir(), "search.json")
+    data := `{"queries": [{"query": "topic", "results": [
+        {"title": "Docs", "url": "https://docs.example.com/a", "snippet": "the reference documentation for the topic"},
+        {"title": "Blocked", "url": "https://spam.example.org/b", "snippet": "the spam page about the topic"},
+        {"title": "Tiny", "url": "https://other.example.net/c", "snippet": "short"}
+    ]}]}`
+    if err := os.WriteFile(fixture, []byte(data), 0o644); err != nil {
+        t.Fatalf("write fixture: %v", err)
+    }
+    cfg := Config{FileSearchPath: fixture, MaxSources: 5, PerDomainCap: 2, MinSnippetChars: 10, DomainDenylist: []string{"spam.example.org"}}
+    rep, err := RunSearch(context.Background(), cfg, "topic", 10)
+    if err != nil {
+        t.Fatalf("run search: %v", err)
+    }
+    if rep.Provider != "file" || len(rep.Results) != 3 {
+        t.Fatalf("unexpected report: %+v", rep)
+    }
+    byTitle := map[string]SearchReportRow{}
+    for _, r := range rep.Results {
+        byTitle[r.Title] = r
+    }
+    if r := byTitle["Docs"]; !r.Selected || r.Rank != 1 || r.Language != "en" || r.Source != "file" {
+        t.Fatalf("unexpected selected row: %+v", r)
+    }
+    if r := byTitle["Blocked"]; r.Selected || r.Reason != "domain-denylist" || r.Rank != 0 {
+        t.Fatalf("expected policy drop with reason, got %+v", r)
+    }
+    if r := byTitle["Tiny"]; r.Selected || r.Reason != "min-snippet" {
+        t.Fatalf("expected min-snippet drop, got %+v", r)
+    }
+
+    if _, err := RunSearch(context.Background(), Config{}, "topic", 10); err == nil {
+        t.Fatalf("expected error without a configured provider")
+    }
+}
@@ -3,6 +3,7 @@ package app
 import (
     "fmt"
     "math"
+    "sort"
     "strings"
 
     "github.com/hyperifyio/goresearch/internal/brief"
@@ -59,7 +60,7 @@ func proportionallyTruncateExcerpts(b brief.Brief, outline []string, in []synth.
     userBuilder.WriteString("\n\nSources (use only these; cite with [n]):\n")
     for _, src := range in {
         // Header lines per source without excerpt body
-        userBuilder.WriteString(fmt.Sprintf("%d. %s — %s\n", src.Index, src.Title, src.URL))
+        userBuilder.WriteString(synth.SourceHeader(src))
         // We will include the literal word "Excerpt:" label in baseline since
         // it appears even when bodies are empty in our prompt contract.
         userBuilder.WriteString("Excerpt:\n\n")
@@ -78,7 +79,8 @@ func proportionallyTruncateExcerpts(b brief.Brief, outline []string, in []synth.
         // No room for excerpts; keep headers only.
         out := make([]synth.SourceExcerpt, 0, len(in))
         for _, src := range in {
-            out = append(out, synth.SourceExcerpt{Index: src.Index, Title: src.Title, URL: src.URL, Excerpt: ""})
+            src.Excerpt = ""
+            out = append(out, src)
         }
         return out
     }
@@ -93,6 +95,13 @@ func proportionallyTruncateExcerpts(b brief.Brief, outline []string, in []synth.
         return in
     }
 
+    // 3b) Markdown excerpts carry structure at a token cost. Swap them for
+    // their plain text, biggest savings first, before cutting any content.
+    in, currentExcerptTokens = preferPlainExcerpts(in, currentExcerptTokens, availableForExcerptsTokens)
+    if currentExcerptTokens <= availableForExcerptsTokens {
+        return in
+    }
+
     // 4) Scale each excerpt proportionally based on token budget.
     scale := float64(availableForExcerptsTokens) / float64(currentExcerptTokens)
     if scale < 0 {
@@ -101,7 +110,8 @@ func proportionallyTruncateExcerpts(b brief.Brief, outline []string, in []synth.
     out := make([]synth.SourceExcerpt, 0, len(in))
     for _, src := range in {
         if strings.TrimSpace(src.Excerpt) == "" || scale == 0 {
-            out = append(out, synth.SourceExcerpt{Index: src.Index, Title: src.Title, URL: src.URL, Excerpt: ""})
+            src.Excerpt = ""
+            out = append(out, src)
             continue
         }
         // Target bytes proportional to original length. Use floor for safety.
@@ -111,12 +121,42 @@ func proportionallyTruncateExcerpts(b brief.Brief, outline []string, in []synth.
             targetBytes = 0
         }
         truncated := trimByByteLimitPreservingRunes(src.Excerpt, targetBytes)
-        out = append(out, synth.SourceExcerpt{Index: src.Index, Title: src.Title, URL: src.URL, Excerpt: truncated})
+        src.Excerpt = truncated
+        out = append(out, src)
     }
 
     return out
 }
 
+// preferPlainExcerpts replaces Markdown excerpts with their plain text until
+// the excerpts fit in available tokens, starting with the source that saves
+// the most. It returns a copy and the new token total.
+func preferPlainExcerpts(in []synth.SourceExcerpt, total, available int) ([]synth.SourceExcerpt, int) {
+    type saving struct{ i, tokens int }
+    var savings []saving
+    for i, src := range in {
+        if src.Plain == "" {
+            continue
+        }
+        if d := budget.EstimateTokens(src.Excerpt) - budget.EstimateTokens(src.Plain); d > 0 {
+            savings = append(savings, saving{i, d})
+        }
+    }
+    if len(savings) == 0 {
+        return in, total
+    }
+    sort.SliceStable(savings, func(a, b int) bool { return savings[a].tokens > savings[b].tokens })
+    out := append([]synth.SourceExcerpt(nil), in...)
+    for _, s := range savings {
+        if total <= available {
+            break
+        }
+        out[s.i].Excerpt, out[s.i].Plain = out[s.i].Plain, ""
+        total -= s.tokens
+    }
+    return out, total
+}
+
 // trimByByteLimitPreservingRunes returns a prefix of s whose byte length is
 // <= maxBytes, never splitting a UTF-8 rune. If maxBytes >= len(s) it returns s.
 func trimByByteLimitPreservingRunes(s string, maxBytes int) string {
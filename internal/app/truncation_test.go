@@ -4,6 +4,7 @@ import (
     "testing"
 
     "github.com/hyperifyio/goresearch/internal/brief"
+    "github.com/hyperifyio/goresearch/internal/budget"
     "github.com/hyperifyio/goresearch/internal/synth"
 )
 
@@ -61,6 +62,28 @@ func TestProportionalTruncation_NoRoomKeepsHeadersOnly(t *testing.T) {
     }
 }
 
+func TestProportionalTruncation_PrefersPlainOverMarkdown(t *testing.T) {
+    b := brief.Brief{Topic: "t"}
+    cfg := Config{LLMModel: "gpt-4o", ReservedOutputTokens: 1000}
+    // Room for the excerpts only once the larger Markdown body goes plain.
+    avail := budget.ModelContextTokens(cfg.LLMModel) - budget.HeadroomTokens(cfg.LLMModel) - cfg.ReservedOutputTokens
+    plain := repeat("p", avail*2)
+    in := []synth.SourceExcerpt{
+        {Index: 1, Title: "a", URL: "u1", Excerpt: "| a |\n| --- |\n" + repeat("m", avail*5), Plain: plain},
+        {Index: 2, Title: "b", URL: "u2", Excerpt: "## small", Plain: "small"},
+    }
+    out := proportionallyTruncateExcerpts(b, nil, in, cfg)
+    if out[0].Excerpt != plain || out[0].Plain != "" {
+        t.Fatalf("expected the large source to fall back to its plain text")
+    }
+    if out[1].Excerpt != "## small" {
+        t.Fatalf("expected the small Markdown excerpt kept, got %q", out[1].Excerpt)
+    }
+    if in[0].Plain != plain {
+        t.Fatalf("input must not be modified")
+    }
+}
+
 func repeat(s string, n int) string {
     b := make([]byte, 0, len(s)*n)
     for i := 0; i < n; i++ {
@@ -0,0 +1,123 @@
+package app
+
+import (
+    "context"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "github.com/hyperifyio/goresearch/internal/fetch"
+    "github.com/hyperifyio/goresearch/internal/search"
+    "github.com/hyperifyio/goresearch/internal/warc"
+)
+
+func TestFetchAndExtract_ServesFromWARCOffline(t *testing.T) {
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path == "/old" {
+            http.Redirect(w, r, "/article", http.StatusMovedPermanently)
+            return
+        }
+        w.Header().Set("Content-Type", "text/html; charset=utf-8")
+        _, _ = w.Write([]byte("<html><head><title>Archived</title></head><body><p>Text captured in an earlier crawl.</p></body></html>"))
+    }))
+    path := filepath.Join(t.TempDir(), "crawl.warc.gz")
+    w, err := warc.Create(path, "goresearch-test")
+    if err != nil {
+        t.Fatalf("create: %v", err)
+    }
+    resp, err := warc.Client(srv.Client(), w).Get(srv.URL + "/old")
+    if err != nil {
+        t.Fatalf("record: %v", err)
+    }
+    _, _ = io.ReadAll(resp.Body)
+    resp.Body.Close()
+    _ = w.Close()
+    base := srv.URL
+    // Nothing may reach the network from here on.
+    srv.Close()
+
+    archive, err := warc.Open(path)
+    if err != nil {
+        t.Fatalf("open: %v", err)
+    }
+    f := &fetchClient{cacheOnly: true, archive: archive}
+    excerpts, _, records := fetchAndExtract(context.Background(), f, nil, []search.Result{
+        {Title: "Old", URL: base + "/old"},
+        {Title: "Missing", URL: base + "/missing"},
+    }, Config{PerSourceChars: 1000})
+    if len(excerpts) != 1 || excerpts[0].URL != base+"/article" {
+        t.Fatalf("expected the archived page under its final URL, got %+v", excerpts)
+    }
+    if excerpts[0].WARCRecordID == "" || records[0].WARCRecordID != excerpts[0].WARCRecordID {
+        t.Fatalf("expected the archive record id on excerpt and fetch record: %+v %+v", excerpts[0], records[0])
+    }
+    if records[1].Status != fetchStatusFailed {
+        t.Fatalf("expected a cache-only miss to fail, got %+v", records[1])
+    }
+}
+
+// Test archived responses go through the same domain policy, opt-out and
+// content-type checks as live fetches.
+func TestFetchAndExtract_WARCAppliesFetchPolicy(t *testing.T) {
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/noai":
+            w.Header().Set("X-Robots-Tag", "noai")
+            w.Header().Set("Content-Type", "text/html")
+        case "/image":
+            w.Header().Set("Content-Type", "image/png")
+        default:
+            w.Header().Set("Content-Type", "text/html")
+        }
+        _, _ = w.Write([]byte("<html><body><p>Archived text that is long enough to keep.</p></body></html>"))
+    }))
+    path := filepath.Join(t.TempDir(), "crawl.warc.gz")
+    w, err := warc.Create(path, "goresearch-test")
+    if err != nil {
+        t.Fatalf("create: %v", err)
+    }
+    for _, p := range []string{"/ok", "/noai", "/image"} {
+        resp, err := warc.Client(srv.Client(), w).Get(srv.URL + p)
+        if err != nil {
+            t.Fatalf("record: %v", err)
+        }
+        _, _ = io.ReadAll(resp.Body)
+        resp.Body.Close()
+    }
+    _ = w.Close()
+    base := srv.URL
+    srv.Close()
+    archive, err := warc.Open(path)
+    if err != nil {
+        t.Fatalf("open: %v", err)
+    }
+    selected := []search.Result{{Title: "OK", URL: base + "/ok"}, {Title: "NoAI", URL: base + "/noai"}, {Title: "Image", URL: base + "/image"}}
+
+    f := &fetchClient{cacheOnly: true, archive: archive, client: &fetch.Client{UserAgent: "goresearch-test", AllowPrivateHosts: true}}
+    excerpts, skipped, records := fetchAndExtract(context.Background(), f, nil, selected, Config{PerSourceChars: 1000})
+    if len(excerpts) != 1 || excerpts[0].URL != base+"/ok" {
+        t.Fatalf("expected only the allowed page, got %+v", excerpts)
+    }
+    if len(skipped) != 1 || skipped[0].URL != base+"/noai" {
+        t.Fatalf("expected the noai page skipped, got %+v", skipped)
+    }
+    if records[2].Status != fetchStatusFailed || !strings.Contains(records[2].Reason, "unsupported content type") {
+        t.Fatalf("expected the image refused, got %+v", records[2])
+    }
+
+    // Size limits are applied while the record is read.
+    f.client.MaxBodyBytes = 16
+    archive.BodyLimit = f.client.BodyLimit
+    if _, skipped, _ := fetchAndExtract(context.Background(), f, nil, selected[:1], Config{PerSourceChars: 1000}); len(skipped) != 1 || !strings.Contains(skipped[0].Reason, "16-byte limit") {
+        t.Fatalf("expected the oversized record skipped as too large, got %+v", skipped)
+    }
+    f.client.MaxBodyBytes = 0
+
+    f.client.DomainDenylist = []string{"127.0.0.1"}
+    if excerpts, _, _ := fetchAndExtract(context.Background(), f, nil, selected[:1], Config{PerSourceChars: 1000}); len(excerpts) != 0 {
+        t.Fatalf("expected the denylisted host refused, got %+v", excerpts)
+    }
+}
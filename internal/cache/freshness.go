@@ -0,0 +1,186 @@
+package cache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheControl holds the response directives of a Cache-Control header that
+// a private cache acts on.
+type CacheControl struct {
+	NoStore        bool
+	NoCache        bool
+	MustRevalidate bool
+	// MaxAge is valid when HasMaxAge is set.
+	MaxAge    time.Duration
+	HasMaxAge bool
+	// StaleIfError bounds how stale a copy may be served when the origin
+	// fails (RFC 5861); valid when HasStaleIfError is set.
+	StaleIfError    time.Duration
+	HasStaleIfError bool
+}
+
+// ParseCacheControl parses a Cache-Control header value. Unknown directives
+// are ignored; a malformed delta-seconds makes the response stale, as RFC
+// 9111 requires.
+func ParseCacheControl(v string) CacheControl {
+	var cc CacheControl
+	for _, part := range strings.Split(v, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(part), "=")
+		arg = strings.Trim(strings.TrimSpace(arg), `"`)
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "no-store":
+			cc.NoStore = true
+		case "no-cache":
+			cc.NoCache = true
+		case "must-revalidate":
+			cc.MustRevalidate = true
+		case "max-age":
+			cc.MaxAge, cc.HasMaxAge = parseDeltaSeconds(arg), true
+		case "stale-if-error":
+			cc.StaleIfError, cc.HasStaleIfError = parseDeltaSeconds(arg), true
+		}
+	}
+	return cc
+}
+
+func parseDeltaSeconds(s string) time.Duration {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0
+	}
+	// Clamp huge values instead of overflowing the Duration.
+	if n > int64(1<<31) {
+		n = 1 << 31
+	}
+	return time.Duration(n) * time.Second
+}
+
+// Storable reports whether a response with these headers may be persisted.
+func Storable(h http.Header) bool {
+	return !ParseCacheControl(strings.Join(h.Values("Cache-Control"), ",")).NoStore
+}
+
+// NewHTTPEntry builds cache metadata for a response to a request sent at
+// requestTime with headers reqHeader and received at responseTime.
+func NewHTTPEntry(url string, resp http.Header, reqHeader http.Header, requestTime, responseTime time.Time) HTTPEntry {
+	e := HTTPEntry{
+		URL:          url,
+		ContentType:  resp.Get("Content-Type"),
+		ETag:         resp.Get("ETag"),
+		LastModified: resp.Get("Last-Modified"),
+	}
+	e.Refresh(resp, requestTime, responseTime)
+	if vary := resp.Values("Vary"); len(vary) > 0 {
+		e.Vary = map[string]string{}
+		for _, v := range vary {
+			for _, name := range strings.Split(v, ",") {
+				name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+				if name != "" {
+					e.Vary[name] = reqHeader.Get(name)
+				}
+			}
+		}
+	}
+	return e
+}
+
+// Refresh updates the freshness fields from a response, such as a 304 that
+// revalidated the entry. Stored fields, validators included, are replaced
+// only when the response sends them (RFC 9111 section 4.3.4), so a 304
+// without Cache-Control keeps the stored directives.
+func (e *HTTPEntry) Refresh(resp http.Header, requestTime, responseTime time.Time) {
+	date, err := http.ParseTime(resp.Get("Date"))
+	if err != nil {
+		date = responseTime
+	}
+	e.Date = date.UTC()
+	if v := resp.Values("Cache-Control"); len(v) > 0 {
+		e.CacheControl = strings.Join(v, ", ")
+	}
+	if v := resp.Get("Expires"); v != "" {
+		e.Expires = v
+	}
+	if v := resp.Get("ETag"); v != "" {
+		e.ETag = v
+	}
+	if v := resp.Get("Last-Modified"); v != "" {
+		e.LastModified = v
+	}
+	// Corrected initial age, RFC 9111 section 4.2.3.
+	apparent := responseTime.Sub(date)
+	if apparent < 0 {
+		apparent = 0
+	}
+	corrected := parseDeltaSeconds(strings.TrimSpace(resp.Get("Age"))) + responseTime.Sub(requestTime)
+	if corrected < apparent {
+		corrected = apparent
+	}
+	e.AgeSeconds = int64(corrected / time.Second)
+}
+
+// FreshnessLifetime is how long the entry may be served without
+// revalidation. Responses without max-age or Expires get no heuristic
+// lifetime, so they are revalidated on every use.
+func (e *HTTPEntry) FreshnessLifetime() time.Duration {
+	cc := ParseCacheControl(e.CacheControl)
+	if cc.NoCache {
+		return 0
+	}
+	if cc.HasMaxAge {
+		return cc.MaxAge
+	}
+	if e.Expires != "" {
+		exp, err := http.ParseTime(e.Expires)
+		if err != nil || e.Date.IsZero() {
+			// Invalid dates such as "0" mean already expired.
+			return 0
+		}
+		if d := exp.Sub(e.Date); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// CurrentAge estimates the entry's age at now.
+func (e *HTTPEntry) CurrentAge(now time.Time) time.Duration {
+	resident := now.Sub(e.SavedAt)
+	if resident < 0 {
+		resident = 0
+	}
+	return time.Duration(e.AgeSeconds)*time.Second + resident
+}
+
+// Fresh reports whether the entry may be served at now without contacting
+// the origin.
+func (e *HTTPEntry) Fresh(now time.Time) bool {
+	return e.FreshnessLifetime() > e.CurrentAge(now)
+}
+
+// MatchesVary reports whether a request with headers reqHeader selects this
+// entry. "Vary: *" never matches.
+func (e *HTTPEntry) MatchesVary(reqHeader http.Header) bool {
+	for name, v := range e.Vary {
+		if name == "*" || reqHeader.Get(name) != v {
+			return false
+		}
+	}
+	return true
+}
+
+// UsableOnError reports whether the entry may stand in for the origin when
+// it fails: must-revalidate and no-cache forbid it, and stale-if-error, when
+// given, bounds how stale the copy may be.
+func (e *HTTPEntry) UsableOnError(now time.Time) bool {
+	cc := ParseCacheControl(e.CacheControl)
+	if cc.MustRevalidate || cc.NoCache {
+		return false
+	}
+	if cc.HasStaleIfError {
+		return e.CurrentAge(now)-e.FreshnessLifetime() <= cc.StaleIfError
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package cache
+
+import (
+    "net/http"
+    "testing"
+    "time"
+)
+
+func TestParseCacheControl(t *testing.T) {
+    cc := ParseCacheControl(`public, Max-Age="60", must-revalidate, stale-if-error=300, no-cache`)
+    if !cc.HasMaxAge || cc.MaxAge != time.Minute || !cc.MustRevalidate || !cc.NoCache || cc.NoStore {
+        t.Fatalf("unexpected directives: %+v", cc)
+    }
+    if !cc.HasStaleIfError || cc.StaleIfError != 5*time.Minute {
+        t.Fatalf("unexpected stale-if-error: %+v", cc)
+    }
+    if cc := ParseCacheControl("max-age=abc"); !cc.HasMaxAge || cc.MaxAge != 0 {
+        t.Fatalf("malformed max-age should be stale: %+v", cc)
+    }
+    if Storable(http.Header{"Cache-Control": {"private", "no-store"}}) {
+        t.Fatalf("no-store must not be storable")
+    }
+}
+
+func TestHTTPEntry_Freshness(t *testing.T) {
+    now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+    date := now.Format(http.TimeFormat)
+    cases := []struct {
+        name   string
+        header http.Header
+        after  time.Duration
+        fresh  bool
+    }{
+        {"max-age within", http.Header{"Date": {date}, "Cache-Control": {"max-age=600"}}, 5 * time.Minute, true},
+        {"max-age elapsed", http.Header{"Date": {date}, "Cache-Control": {"max-age=600"}}, 11 * time.Minute, false},
+        {"age header counts", http.Header{"Date": {date}, "Cache-Control": {"max-age=600"}, "Age": {"500"}}, 2 * time.Minute, false},
+        {"max-age beats expires", http.Header{"Date": {date}, "Cache-Control": {"max-age=0"}, "Expires": {now.Add(time.Hour).Format(http.TimeFormat)}}, time.Second, false},
+        {"expires", http.Header{"Date": {date}, "Expires": {now.Add(time.Hour).Format(http.TimeFormat)}}, 30 * time.Minute, true},
+        {"invalid expires", http.Header{"Date": {date}, "Expires": {"0"}}, 0, false},
+        {"no-cache", http.Header{"Date": {date}, "Cache-Control": {"no-cache, max-age=600"}}, 0, false},
+        {"no explicit lifetime", http.Header{"Date": {date}, "Last-Modified": {now.Add(-24 * time.Hour).Format(http.TimeFormat)}}, 0, false},
+    }
+    for _, tc := range cases {
+        e := NewHTTPEntry("https://example.com/", tc.header, nil, now, now)
+        e.SavedAt = now
+        if got := e.Fresh(now.Add(tc.after)); got != tc.fresh {
+            t.Errorf("%s: fresh=%v, want %v (lifetime %s, age %s)", tc.name, got, tc.fresh, e.FreshnessLifetime(), e.CurrentAge(now.Add(tc.after)))
+        }
+    }
+}
+
+func TestHTTPEntry_Vary(t *testing.T) {
+    now := time.Now()
+    req := http.Header{"User-Agent": {"goresearch"}}
+    e := NewHTTPEntry("https://example.com/", http.Header{"Vary": {"user-agent, Accept-Language"}}, req, now, now)
+    if !e.MatchesVary(http.Header{"User-Agent": {"goresearch"}}) {
+        t.Fatalf("same selecting headers should match: %+v", e.Vary)
+    }
+    if e.MatchesVary(http.Header{"User-Agent": {"other"}}) {
+        t.Fatalf("different User-Agent should not match")
+    }
+    star := NewHTTPEntry("https://example.com/", http.Header{"Vary": {"*"}}, req, now, now)
+    if star.MatchesVary(req) {
+        t.Fatalf("Vary: * must never match")
+    }
+}
+
+func TestHTTPEntry_UsableOnError(t *testing.T) {
+    now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+    mk := func(cc string) HTTPEntry {
+        e := NewHTTPEntry("https://example.com/", http.Header{"Date": {now.Format(http.TimeFormat)}, "Cache-Control": {cc}}, nil, now, now)
+        e.SavedAt = now
+        return e
+    }
+    later := now.Add(time.Hour)
+    if e := mk("max-age=60"); !e.UsableOnError(later) {
+        t.Fatalf("plain stale entry should be usable on error")
+    }
+    if e := mk("max-age=60, must-revalidate"); e.UsableOnError(later) {
+        t.Fatalf("must-revalidate forbids stale use")
+    }
+    if e := mk("max-age=60, stale-if-error=600"); e.UsableOnError(later) {
+        t.Fatalf("stale beyond stale-if-error window should not be usable")
+    }
+    if e := mk("max-age=60, stale-if-error=7200"); !e.UsableOnError(later) {
+        t.Fatalf("stale within stale-if-error window should be usable")
+    }
+}
+
+func TestHTTPEntry_RefreshKeepsUnsentFields(t *testing.T) {
+    now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+    e := NewHTTPEntry("https://example.com/", http.Header{"Date": {now.Format(http.TimeFormat)}, "Cache-Control": {"max-age=60, must-revalidate"}, "Etag": {`"v1"`}}, nil, now, now)
+    later := now.Add(time.Hour)
+    // A 304 carrying only a Date must not erase the stored directives.
+    e.Refresh(http.Header{"Date": {later.Format(http.TimeFormat)}}, later, later)
+    e.SavedAt = later
+    if e.CacheControl != "max-age=60, must-revalidate" || e.ETag != `"v1"` {
+        t.Fatalf("stored fields lost: %+v", e)
+    }
+    if !e.Fresh(later.Add(30 * time.Second)) {
+        t.Fatalf("max-age should still apply after the 304")
+    }
+    if e.UsableOnError(later.Add(2 * time.Hour)) {
+        t.Fatalf("must-revalidate should still forbid stale use after the 304")
+    }
+    e.Refresh(http.Header{"Cache-Control": {"max-age=5"}}, later, later)
+    if e.CacheControl != "max-age=5" {
+        t.Fatalf("a sent Cache-Control should replace the stored one, got %q", e.CacheControl)
+    }
+}
@@ -20,7 +20,22 @@ type HTTPEntry struct {
 	ContentType  string    `json:"content_type"`
 	ETag         string    `json:"etag"`
 	LastModified string    `json:"last_modified"`
-	SavedAt      time.Time `json:"saved_at"`
+	// Charset is the detected character encoding of an HTML body, which is
+	// stored as served; readers transcode it to UTF-8 with this charset.
+	Charset string    `json:"charset,omitempty"`
+	SavedAt time.Time `json:"saved_at"`
+	// FinalURL is where redirects ended, so fresh hits can report it
+	// without a request.
+	FinalURL string `json:"final_url,omitempty"`
+	// Freshness inputs (RFC 9111): the origin's Date, the response's
+	// Cache-Control and Expires, and its corrected age when saved.
+	Date         time.Time `json:"date,omitempty"`
+	CacheControl string    `json:"cache_control,omitempty"`
+	Expires      string    `json:"expires,omitempty"`
+	AgeSeconds   int64     `json:"age_seconds,omitempty"`
+	// Vary maps each header named by the response's Vary to the value the
+	// request carried; the entry serves only requests with the same values.
+	Vary map[string]string `json:"vary,omitempty"`
 }
 
 // HTTPCache stores responses on disk as <key>.meta.json and <key>.body where
@@ -103,11 +118,16 @@ func (c *HTTPCache) LoadBody(_ context.Context, url string) ([]byte, error) {
 }
 
 // Save stores a new cache entry to disk.
-func (c *HTTPCache) Save(_ context.Context, url string, contentType string, etag string, lastModified string, body []byte) error {
+func (c *HTTPCache) Save(ctx context.Context, url string, contentType string, etag string, lastModified string, body []byte) error {
+	return c.SaveEntry(ctx, HTTPEntry{URL: url, ContentType: contentType, ETag: etag, LastModified: lastModified}, body)
+}
+
+// SaveEntry stores body with the given metadata. SavedAt is set to now.
+func (c *HTTPCache) SaveEntry(ctx context.Context, meta HTTPEntry, body []byte) error {
 	if err := c.ensureDir(); err != nil {
 		return err
 	}
-	key := c.key(url)
+	key := c.key(meta.URL)
 	// Write body first
     bodyMode := os.FileMode(0o644)
     if c.StrictPerms {
@@ -116,13 +136,17 @@ func (c *HTTPCache) Save(_ context.Context, url string, contentType string, etag
     if err := os.WriteFile(c.bodyPath(key), body, bodyMode); err != nil {
 		return fmt.Errorf("write body: %w", err)
 	}
-	meta := HTTPEntry{
-		URL:          url,
-		ContentType:  contentType,
-		ETag:         etag,
-		LastModified: lastModified,
-		SavedAt:      time.Now().UTC(),
+	return c.SaveMeta(ctx, meta)
+}
+
+// SaveMeta replaces the metadata of an entry, keeping its body, as after a
+// 304 revalidation. SavedAt is set to now.
+func (c *HTTPCache) SaveMeta(_ context.Context, meta HTTPEntry) error {
+	if err := c.ensureDir(); err != nil {
+		return err
 	}
+	key := c.key(meta.URL)
+	meta.SavedAt = time.Now().UTC()
 	tmp := c.metaPath(key) + ".tmp"
     f, err := os.Create(tmp)
 	if err != nil {
@@ -145,6 +169,20 @@ func (c *HTTPCache) Save(_ context.Context, url string, contentType string, etag
     return nil
 }
 
+// Delete removes the entry for url, if any.
+func (c *HTTPCache) Delete(_ context.Context, url string) error {
+	if err := c.ensureDir(); err != nil {
+		return err
+	}
+	key := c.key(url)
+	for _, p := range []string{c.metaPath(key), c.bodyPath(key)} {
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
 // CopyMeta copies meta from an io.Reader (useful for tests)
 func (c *HTTPCache) CopyMeta(_ context.Context, url string, r io.Reader) error {
 	if err := c.ensureDir(); err != nil {
@@ -0,0 +1,271 @@
+// Package credibility scores search results by how much a report should
+// trust them. A score combines domain reputation from a user-editable YAML
+// file, the kind of source the URL points at, and snippet quality, and keeps
+// the breakdown so selection decisions can be explained.
+package credibility
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "net/url"
+    "os"
+    "strings"
+
+    yaml "gopkg.in/yaml.v3"
+
+    "github.com/hyperifyio/goresearch/internal/search"
+    "github.com/hyperifyio/goresearch/internal/sourcetype"
+)
+
+// Reputation is the schema of a reputation file:
+//
+//  tiers:            # tier name -> base score in [0,1]
+//    primary: 1.0
+//    low: 0.1
+//  default: unknown  # required; tier for hosts matching no rule
+//  domains:
+//    - pattern: w3.org       # host or parent domain; "*.gov" matches the TLD
+//      tier: primary
+//    - pattern: medium.com
+//      penalty: 0.2          # subtracted after the tier base
+//    - pattern: example.edu
+//      boost: 0.1            # added after the tier base
+//
+// The most specific matching pattern wins.
+type Reputation struct {
+    Tiers   map[string]float64 `yaml:"tiers" json:"tiers"`
+    Default string             `yaml:"default" json:"default"`
+    Domains []Rule             `yaml:"domains" json:"domains"`
+}
+
+// Rule assigns a tier and/or adjustment to hosts matching Pattern.
+type Rule struct {
+    Pattern string  `yaml:"pattern" json:"pattern"`
+    Tier    string  `yaml:"tier" json:"tier"`
+    Boost   float64 `yaml:"boost" json:"boost"`
+    Penalty float64 `yaml:"penalty" json:"penalty"`
+}
+
+// DefaultReputation is used when no reputation file is configured. It
+// encodes the same preference for standards bodies and vendor docs that the
+// selector historically hardcoded, plus common academic and low-signal hosts.
+func DefaultReputation() Reputation {
+    return Reputation{
+        Tiers:   map[string]float64{"primary": 1.0, "reputable": 0.75, "unknown": 0.45, "low": 0.15},
+        Default: "unknown",
+        Domains: []Rule{
+            {Pattern: "rfc-editor.org", Tier: "primary"},
+            {Pattern: "ietf.org", Tier: "primary"},
+            {Pattern: "w3.org", Tier: "primary"},
+            {Pattern: "whatwg.org", Tier: "primary"},
+            {Pattern: "iso.org", Tier: "primary"},
+            {Pattern: "nist.gov", Tier: "primary"},
+            {Pattern: "developer.mozilla.org", Tier: "primary"},
+            {Pattern: "apache.org", Tier: "primary"},
+            {Pattern: "*.gov", Tier: "reputable"},
+            {Pattern: "*.edu", Tier: "reputable"},
+            {Pattern: "arxiv.org", Tier: "reputable"},
+            {Pattern: "doi.org", Tier: "reputable"},
+            {Pattern: "acm.org", Tier: "reputable"},
+            {Pattern: "ieee.org", Tier: "reputable"},
+            {Pattern: "nature.com", Tier: "reputable"},
+            {Pattern: "wikipedia.org", Tier: "reputable"},
+            {Pattern: "github.com", Tier: "unknown", Boost: 0.1},
+            {Pattern: "stackoverflow.com", Tier: "unknown"},
+            {Pattern: "medium.com", Tier: "low"},
+            {Pattern: "pinterest.com", Tier: "low"},
+            {Pattern: "quora.com", Tier: "low"},
+        },
+    }
+}
+
+// LoadReputation reads a YAML reputation file and validates it.
+func LoadReputation(path string) (Reputation, error) {
+    var rep Reputation
+    b, err := os.ReadFile(path)
+    if err != nil {
+        return rep, err
+    }
+    if err := yaml.Unmarshal(b, &rep); err != nil {
+        return rep, fmt.Errorf("parse reputation: %w", err)
+    }
+    if err := rep.validate(); err != nil {
+        return rep, fmt.Errorf("%s: %w", path, err)
+    }
+    return rep, nil
+}
+
+func (r Reputation) validate() error {
+    if len(r.Tiers) == 0 {
+        return errors.New("reputation: at least one tier is required")
+    }
+    for name, v := range r.Tiers {
+        if v < 0 || v > 1 {
+            return fmt.Errorf("reputation: tier %q score %.2f outside [0,1]", name, v)
+        }
+    }
+    // Hosts matching no rule, and rules without a tier, score from the
+    // default tier, so it must exist.
+    if r.Default == "" {
+        return errors.New("reputation: a default tier is required")
+    }
+    if _, ok := r.Tiers[r.Default]; !ok {
+        return fmt.Errorf("reputation: default tier %q is not defined", r.Default)
+    }
+    for i, d := range r.Domains {
+        if strings.TrimSpace(d.Pattern) == "" {
+            return fmt.Errorf("reputation: domain rule %d has no pattern", i)
+        }
+        if d.Tier != "" {
+            if _, ok := r.Tiers[d.Tier]; !ok {
+                return fmt.Errorf("reputation: domain %q uses undefined tier %q", d.Pattern, d.Tier)
+            }
+        }
+    }
+    return nil
+}
+
+// match returns the most specific rule for host, or nil.
+func (r Reputation) match(host string) *Rule {
+    var best *Rule
+    bestLen := -1
+    for i := range r.Domains {
+        p := strings.ToLower(strings.TrimSpace(r.Domains[i].Pattern))
+        suffix := strings.TrimPrefix(p, "*")
+        ok := false
+        if strings.HasPrefix(suffix, ".") {
+            ok = strings.HasSuffix(host, suffix)
+        } else {
+            ok = host == suffix || strings.HasSuffix(host, "."+suffix)
+        }
+        if ok && len(suffix) > bestLen {
+            best, bestLen = &r.Domains[i], len(suffix)
+        }
+    }
+    return best
+}
+
+// Weights balances the score components; they need not sum to one.
+type Weights struct {
+    Reputation float64
+    SourceType float64
+    Snippet    float64
+}
+
+// DefaultWeights favors reputation, then source type, then snippet quality.
+var DefaultWeights = Weights{Reputation: 0.6, SourceType: 0.25, Snippet: 0.15}
+
+// Breakdown explains a score. Component scores are in [0,1]; Score is their
+// weighted mean.
+type Breakdown struct {
+    Score      float64 `json:"score"`
+    Tier       string  `json:"tier"`
+    Rule       string  `json:"rule,omitempty"`
+    Reputation float64 `json:"reputation"`
+    SourceType string  `json:"source_type"`
+    TypeScore  float64 `json:"type_score"`
+    Snippet    float64 `json:"snippet"`
+}
+
+// defaultReputation is the shared, read-only DefaultReputation used by
+// scorers without a reputation file.
+var defaultReputation = DefaultReputation()
+
+// Scorer computes credibility scores. The zero value uses DefaultReputation
+// and DefaultWeights.
+type Scorer struct {
+    Reputation *Reputation
+    Weights    *Weights
+}
+
+// NewScorer returns a scorer for the reputation file at path, or the
+// defaults when path is empty.
+func NewScorer(path string) (*Scorer, error) {
+    if strings.TrimSpace(path) == "" {
+        return &Scorer{}, nil
+    }
+    rep, err := LoadReputation(path)
+    if err != nil {
+        return nil, err
+    }
+    return &Scorer{Reputation: &rep}, nil
+}
+
+// Score rates a single result.
+func (s *Scorer) Score(r search.Result) Breakdown {
+    rep := &defaultReputation
+    if s != nil && s.Reputation != nil {
+        rep = s.Reputation
+    }
+    w := DefaultWeights
+    if s != nil && s.Weights != nil {
+        w = *s.Weights
+    }
+    var b Breakdown
+    host := ""
+    if u, err := url.Parse(strings.TrimSpace(r.URL)); err == nil {
+        host = strings.ToLower(u.Hostname())
+    }
+    b.Tier = rep.Default
+    adjust := 0.0
+    if rule := rep.match(host); rule != nil {
+        b.Rule = rule.Pattern
+        if rule.Tier != "" {
+            b.Tier = rule.Tier
+        }
+        adjust = rule.Boost - rule.Penalty
+    }
+    b.Reputation = clamp01(rep.Tiers[b.Tier] + adjust)
+    b.SourceType = sourcetype.Classify(r.URL, sourcetype.Hints{})
+    b.TypeScore = typeScores[b.SourceType]
+    b.Snippet = snippetQuality(r.Snippet)
+    total := w.Reputation + w.SourceType + w.Snippet
+    if total > 0 {
+        b.Score = (w.Reputation*b.Reputation + w.SourceType*b.TypeScore + w.Snippet*b.Snippet) / total
+    }
+    b.Score = math.Round(b.Score*1000) / 1000
+    return b
+}
+
+// typeScores rates how much each source type is trusted on its own.
+var typeScores = map[string]float64{
+    sourcetype.Primary:   1.0,
+    sourcetype.Academic:  0.85,
+    sourcetype.Reference: 0.7,
+    sourcetype.News:      0.6,
+    sourcetype.Web:       0.5,
+    sourcetype.Forum:     0.4,
+    sourcetype.Vendor:    0.35,
+    sourcetype.Blog:      0.35,
+}
+
+// snippetQuality rewards informative snippets: length up to ~200 characters,
+// with a penalty for truncation ellipses and all-caps shouting.
+func snippetQuality(snippet string) float64 {
+    s := strings.TrimSpace(snippet)
+    if s == "" {
+        return 0
+    }
+    q := math.Min(float64(len([]rune(s)))/200, 1)
+    if strings.HasSuffix(s, "...") || strings.HasSuffix(s, "…") {
+        q -= 0.1
+    }
+    letters, upper := 0, 0
+    for _, r := range s {
+        if r >= 'a' && r <= 'z' {
+            letters++
+        } else if r >= 'A' && r <= 'Z' {
+            letters++
+            upper++
+        }
+    }
+    if letters > 20 && float64(upper)/float64(letters) > 0.5 {
+        q -= 0.3
+    }
+    return clamp01(q)
+}
+
+func clamp01(v float64) float64 {
+    return math.Max(0, math.Min(1, v))
+}
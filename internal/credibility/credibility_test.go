@@ -0,0 +1,90 @@
+package credibility
+
+import (
+    "math"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "github.com/hyperifyio/goresearch/internal/search"
+    "github.com/hyperifyio/goresearch/internal/sourcetype"
+)
+
+func TestScorer_DefaultsRankStandardsAboveBlogs(t *testing.T) {
+    var s Scorer
+    snippet := strings.Repeat("informative snippet text ", 10)
+    rfc := s.Score(search.Result{URL: "https://www.rfc-editor.org/rfc/rfc9110", Snippet: snippet})
+    blog := s.Score(search.Result{URL: "https://someone.medium.com/http-semantics", Snippet: snippet})
+    if rfc.Score <= blog.Score {
+        t.Fatalf("expected rfc (%v) above blog (%v)", rfc, blog)
+    }
+    if rfc.Tier != "primary" || rfc.Rule != "rfc-editor.org" || rfc.SourceType != sourcetype.Primary {
+        t.Fatalf("unexpected rfc breakdown: %+v", rfc)
+    }
+    if blog.Tier != "low" || blog.SourceType != sourcetype.Blog {
+        t.Fatalf("unexpected blog breakdown: %+v", blog)
+    }
+}
+
+func TestLoadReputation_TiersBoostsAndSpecificity(t *testing.T) {
+    dir := t.TempDir()
+    path := filepath.Join(dir, "rep.yaml")
+    yml := `tiers:
+  trusted: 0.9
+  meh: 0.4
+default: meh
+domains:
+  - pattern: "*.example"
+    tier: trusted
+  - pattern: bad.example
+    tier: trusted
+    penalty: 0.5
+  - pattern: good.test
+    boost: 0.2
+`
+    if err := os.WriteFile(path, []byte(yml), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    s, err := NewScorer(path)
+    if err != nil {
+        t.Fatalf("load: %v", err)
+    }
+    if b := s.Score(search.Result{URL: "https://www.site.example/"}); b.Tier != "trusted" || b.Reputation != 0.9 {
+        t.Fatalf("unexpected wildcard match: %+v", b)
+    }
+    if b := s.Score(search.Result{URL: "https://bad.example/"}); b.Rule != "bad.example" || b.Reputation != 0.4 {
+        t.Fatalf("expected the more specific rule with penalty: %+v", b)
+    }
+    if b := s.Score(search.Result{URL: "https://good.test/"}); b.Tier != "meh" || math.Abs(b.Reputation-0.6) > 1e-9 {
+        t.Fatalf("expected default tier plus boost: %+v", b)
+    }
+}
+
+func TestLoadReputation_RejectsUndefinedTier(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "rep.yaml")
+    _ = os.WriteFile(path, []byte("tiers: {a: 0.5}\ndefault: a\ndomains:\n  - pattern: x.org\n    tier: b\n"), 0o644)
+    if _, err := NewScorer(path); err == nil || !strings.Contains(err.Error(), `undefined tier "b"`) {
+        t.Fatalf("expected undefined tier error, got %v", err)
+    }
+}
+
+func TestLoadReputation_RequiresDefaultTier(t *testing.T) {
+    // Without a default, unmatched hosts and tierless rules would score 0.
+    for name, yml := range map[string]string{
+        "unmatched hosts": "tiers: {a: 0.5}\ndomains:\n  - pattern: x.org\n    tier: a\n",
+        "boost-only rule": "tiers: {a: 0.5}\ndomains:\n  - pattern: x.org\n    boost: 0.1\n",
+    } {
+        path := filepath.Join(t.TempDir(), "rep.yaml")
+        _ = os.WriteFile(path, []byte(yml), 0o644)
+        if _, err := NewScorer(path); err == nil || !strings.Contains(err.Error(), "default tier is required") {
+            t.Errorf("%s: expected a missing default error, got %v", name, err)
+        }
+    }
+}
+
+func TestLoadReputation_ExampleFileIsValid(t *testing.T) {
+    if _, err := LoadReputation(filepath.Join("..", "..", "docs", "reputation.example.yaml")); err != nil {
+        t.Fatalf("example reputation file: %v", err)
+    }
+}
@@ -0,0 +1,58 @@
+// Package dates parses the loosely formatted publication dates found in
+// search APIs, HTML metadata and fixtures.
+package dates
+
+import (
+    "strings"
+    "time"
+)
+
+// layouts are tried in order; the first successful parse wins.
+var layouts = []string{
+    time.RFC3339Nano,
+    time.RFC3339,
+    "2006-01-02T15:04:05",
+    "2006-01-02T15:04",
+    "2006-01-02 15:04:05",
+    "2006-01-02T15:04:05Z0700",
+    "2006-01-02",
+    "2006/01/02",
+    "20060102",
+    time.RFC1123Z,
+    time.RFC1123,
+    time.RFC850,
+    time.ANSIC,
+    "Mon, 2 Jan 2006 15:04:05 -0700",
+    "January 2, 2006",
+    "Jan 2, 2006",
+    "2 January 2006",
+    "2 Jan 2006",
+    "2006-01",
+}
+
+// Parse returns the time described by s in UTC. Dates without a time of day
+// are interpreted as midnight UTC. It reports false when s matches none of
+// the supported layouts or lies implausibly far in the past.
+func Parse(s string) (time.Time, bool) {
+    s = strings.TrimSpace(s)
+    if s == "" {
+        return time.Time{}, false
+    }
+    for _, layout := range layouts {
+        if t, err := time.Parse(layout, s); err == nil {
+            if t.Year() < 1970 {
+                return time.Time{}, false
+            }
+            return t.UTC(), true
+        }
+    }
+    return time.Time{}, false
+}
+
+// Format renders t as an ISO date (YYYY-MM-DD), or "" for the zero time.
+func Format(t time.Time) string {
+    if t.IsZero() {
+        return ""
+    }
+    return t.UTC().Format("2006-01-02")
+}
@@ -0,0 +1,33 @@
+package dates
+
+import (
+    "testing"
+    "time"
+)
+
+func TestParse_CommonLayouts(t *testing.T) {
+    want := time.Date(2023, 5, 12, 0, 0, 0, 0, time.UTC)
+    for _, in := range []string{
+        "2023-05-12",
+        "2023-05-12T00:00:00",
+        "2023-05-12T00:00:00Z",
+        "2023-05-12T02:00:00+02:00",
+        "Fri, 12 May 2023 00:00:00 GMT",
+        "May 12, 2023",
+        "12 May 2023",
+        " 2023/05/12 ",
+    } {
+        got, ok := Parse(in)
+        if !ok || !got.Equal(want) {
+            t.Fatalf("Parse(%q) = %v, %v; want %v", in, got, ok, want)
+        }
+    }
+    for _, in := range []string{"", "yesterday", "0001-01-01T00:00:00Z", "12/05/2023"} {
+        if _, ok := Parse(in); ok {
+            t.Fatalf("Parse(%q) should fail", in)
+        }
+    }
+    if Format(want) != "2023-05-12" || Format(time.Time{}) != "" {
+        t.Fatalf("unexpected Format output")
+    }
+}
@@ -0,0 +1,120 @@
+// Package dedupe detects near-duplicate documents by content so syndicated
+// copies, mirrors and versioned docs do not take several citation slots.
+//
+// Documents are fingerprinted with MinHash over word shingles; the fraction
+// of matching signature slots estimates the Jaccard similarity of the two
+// shingle sets. Unlike SimHash this stays reliable for the few hundred words
+// a typical excerpt holds.
+package dedupe
+
+import (
+    "hash/fnv"
+    "strings"
+    "unicode"
+)
+
+// DefaultMinSimilarity is the estimated Jaccard similarity at or above which
+// two documents count as near-duplicates.
+const DefaultMinSimilarity = 0.7
+
+// MinTokens is the minimum number of words a text needs before it is
+// fingerprinted. Very short texts collide too easily to compare reliably.
+const MinTokens = 40
+
+const (
+    // shingleSize is the number of consecutive words hashed together.
+    shingleSize = 3
+    // signatureSize is the number of MinHash slots; the similarity estimate
+    // has a standard error of about 1/sqrt(signatureSize).
+    signatureSize = 128
+)
+
+// Signature is a MinHash fingerprint of a document.
+type Signature [signatureSize]uint64
+
+// Fingerprint returns the MinHash signature of text computed over word
+// shingles. The second result is false when the text is too short to
+// fingerprint.
+func Fingerprint(text string) (Signature, bool) {
+    var sig Signature
+    words := tokenize(text)
+    if len(words) < MinTokens {
+        return sig, false
+    }
+    for i := range sig {
+        sig[i] = ^uint64(0)
+    }
+    for i := 0; i+shingleSize <= len(words); i++ {
+        h := fnv.New64a()
+        _, _ = h.Write([]byte(strings.Join(words[i:i+shingleSize], " ")))
+        base := h.Sum64()
+        for k := range sig {
+            if v := mix(base ^ uint64(k)*0x9e3779b97f4a7c15); v < sig[k] {
+                sig[k] = v
+            }
+        }
+    }
+    return sig, true
+}
+
+// Similarity estimates the Jaccard similarity of the documents behind two
+// signatures, in [0, 1].
+func Similarity(a, b Signature) float64 {
+    same := 0
+    for i := range a {
+        if a[i] == b[i] {
+            same++
+        }
+    }
+    return float64(same) / float64(signatureSize)
+}
+
+// Index accumulates signatures of kept documents and reports which one a new
+// document duplicates. The zero value uses DefaultMinSimilarity.
+type Index struct {
+    // MinSimilarity overrides DefaultMinSimilarity when > 0.
+    MinSimilarity float64
+
+    ids  []int
+    sigs []Signature
+}
+
+// Match returns the id of the most similar added document when it reaches
+// the similarity threshold.
+func (x *Index) Match(sig Signature) (int, bool) {
+    min := x.MinSimilarity
+    if min <= 0 {
+        min = DefaultMinSimilarity
+    }
+    best, bestID := 0.0, 0
+    for i := range x.sigs {
+        if s := Similarity(sig, x.sigs[i]); s > best {
+            best, bestID = s, x.ids[i]
+        }
+    }
+    if best >= min {
+        return bestID, true
+    }
+    return 0, false
+}
+
+// Add records a kept document under id.
+func (x *Index) Add(id int, sig Signature) {
+    x.ids = append(x.ids, id)
+    x.sigs = append(x.sigs, sig)
+}
+
+// mix is the splitmix64 finalizer, used to derive independent hash functions
+// from one base hash.
+func mix(z uint64) uint64 {
+    z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+    z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+    return z ^ (z >> 31)
+}
+
+// tokenize lower-cases text and splits it into letter/digit words.
+func tokenize(text string) []string {
+    return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+        return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+    })
+}
@@ -0,0 +1,61 @@
+package dedupe
+
+import (
+    "strings"
+    "testing"
+)
+
+const article = `Transport Layer Security is a cryptographic protocol designed to provide
+communications security over a computer network. The protocol is widely used in
+applications such as email, instant messaging and voice over IP, but its use in
+securing HTTPS remains the most publicly visible. The TLS protocol aims primarily
+to provide security, including privacy, integrity and authenticity through the use
+of cryptography such as certificates between two or more communicating applications.
+It runs in the presentation layer and is itself composed of two layers: the TLS
+record and the TLS handshake protocols. The closely related Datagram Transport
+Layer Security protocol provides security to datagram-based applications by
+allowing them to communicate in a way designed to prevent eavesdropping,
+tampering, or message forgery. TLS is a proposed Internet Engineering Task Force
+standard, first defined in 1999, and the current version is TLS 1.3, defined in
+August 2018. TLS builds on the now-deprecated SSL specifications developed
+between 1994 and 1996 by Netscape Communications for adding the HTTPS protocol to
+their web browser. Client-server applications use the TLS protocol to communicate
+across a network in a way designed to prevent eavesdropping and tampering. Since
+applications can communicate either with or without TLS, it is necessary for the
+client to request that the server set up a TLS connection.`
+
+func TestFingerprint_NearDuplicatesAreClose(t *testing.T) {
+    a, ok := Fingerprint(article)
+    if !ok {
+        t.Fatalf("expected article to be fingerprinted")
+    }
+    // A syndicated copy with boilerplate around the same body.
+    b, _ := Fingerprint("Reposted from partner site. " + article + " Share this post.")
+    if s := Similarity(a, b); s < DefaultMinSimilarity {
+        t.Fatalf("expected near-duplicate similarity >= %.2f, got %.2f", DefaultMinSimilarity, s)
+    }
+    other := strings.Repeat("Goroutines are lightweight threads managed by the Go runtime and channels let them communicate safely. ", 4)
+    c, _ := Fingerprint(other)
+    if s := Similarity(a, c); s >= DefaultMinSimilarity {
+        t.Fatalf("expected unrelated text to differ, got similarity %.2f", s)
+    }
+}
+
+func TestFingerprint_ShortTextIsSkipped(t *testing.T) {
+    if _, ok := Fingerprint("too short to compare"); ok {
+        t.Fatalf("expected short text not to be fingerprinted")
+    }
+}
+
+func TestIndex_Match(t *testing.T) {
+    var x Index
+    a, _ := Fingerprint(article)
+    if _, ok := x.Match(a); ok {
+        t.Fatalf("empty index must not match")
+    }
+    x.Add(1, a)
+    b, _ := Fingerprint(strings.Replace(article, "widely used", "commonly used", 1))
+    if id, ok := x.Match(b); !ok || id != 1 {
+        t.Fatalf("expected match with id 1, got %d %v", id, ok)
+    }
+}
@@ -12,60 +12,15 @@ import (
 type Document struct {
     Title string
     Text  string
-}
-
-// FromPDF performs a minimal best-effort text extraction from PDF bytes.
-// It is intentionally conservative: it scans for common string literal
-// patterns and decodes simple escape sequences. This is not a full PDF parser
-// but suffices for quick prototyping behind an off-by-default flag.
-func FromPDF(input []byte) Document {
-    if len(input) == 0 {
-        return Document{}
-    }
-    // Very lightweight heuristic: collect printable runs and parentheses-delimited strings.
-    // Avoid heavy dependencies while we keep PDF optional.
-    var b strings.Builder
-    // 1) Extract text inside parentheses which often contain string operands
-    // in content streams. This is simplistic but yields readable snippets.
-    depth := 0
-    for i := 0; i < len(input); i++ {
-        c := input[i]
-        if c == '(' {
-            depth++
-            // skip writing the parenthesis
-            continue
-        }
-        if c == ')' && depth > 0 {
-            depth--
-            b.WriteString("\n")
-            continue
-        }
-        if depth > 0 {
-            // handle simple escapes \n \r \t and \\
-            if c == '\\' && i+1 < len(input) {
-                i++
-                switch input[i] {
-                case 'n':
-                    b.WriteByte('\n')
-                case 'r':
-                    b.WriteByte('\n')
-                case 't':
-                    b.WriteByte(' ')
-                case '\\':
-                    b.WriteByte('\\')
-                default:
-                    b.WriteByte(input[i])
-                }
-                continue
-            }
-            // write byte if likely printable ASCII
-            if c >= 32 && c <= 126 {
-                b.WriteByte(c)
-            }
-        }
-    }
-    text := normalizeText(b.String())
-    return Document{Title: "", Text: text}
+    // Markdown is the same content with its structure kept: headings,
+    // lists, pipe tables, fenced code and blockquotes. Empty for formats
+    // without structure to keep, such as plain text and JSON.
+    Markdown string
+    // Pages holds the text of each page of paged formats such as PDF, in
+    // order; pages without text are omitted. Empty for other formats.
+    Pages []Page
+    // Meta carries metadata read from markup (e.g. publication date).
+    Meta Metadata
 }
 
 // FromHTML extracts readable text from HTML, preferring <main> or <article>,
@@ -88,13 +43,15 @@ func FromHTML(input []byte) Document {
         content = findFirst(node, "body")
     }
     var b strings.Builder
+    var md string
     if content != nil {
         // Walk and collect text with simple heuristics
         collectText(&b, content, false)
+        md = toMarkdown(content)
     }
     // post-process: Unicode normalization, whitespace normalization, and line de-duplication
     text := normalizeText(b.String())
-    return Document{Title: title, Text: text}
+    return Document{Title: title, Text: text, Markdown: md, Meta: extractMetadata(node)}
 }
 
 func findTitle(n *html.Node) string {
@@ -31,6 +31,33 @@ func BenchmarkFromHTML(b *testing.B) {
 	})
 }
 
+// Benchmark both HTML extractors on the same fixtures, plus a page without
+// semantic markup, so their cost can be compared.
+func BenchmarkExtractors(b *testing.B) {
+	fixtures := []struct {
+		name  string
+		input []byte
+	}{
+		{"small", []byte("<html><head><title>t</title></head><body><main><p>a</p></main></body></html>")},
+		{"medium", makeHTML(50, 60)},
+		{"large", makeHTML(200, 200)},
+		{"unsemantic", []byte(unsemanticPage)},
+	}
+	for _, e := range []struct {
+		name string
+		ex   Extractor
+	}{{ExtractorHeuristic, HeuristicExtractor{}}, {ExtractorReadability, ReadabilityExtractor{}}} {
+		for _, f := range fixtures {
+			b.Run(e.name+"/"+f.name, func(b *testing.B) {
+				b.SetBytes(int64(len(f.input)))
+				for i := 0; i < b.N; i++ {
+					_ = e.ex.Extract(f.input)
+				}
+			})
+		}
+	}
+}
+
 func makeHTML(paras int, itemsPerList int) []byte {
     builder := new(strings.Builder)
 	builder.WriteString("<html><head><title>demo</title></head><body><main>")
@@ -1,5 +1,10 @@
 package extract
 
+import (
+    "fmt"
+    "strings"
+)
+
 // Extractor defines a minimal interface for content extraction strategies.
 // Implementations can swap readability tactics without changing callers.
 type Extractor interface {
@@ -15,3 +20,21 @@ type HeuristicExtractor struct{}
 func (HeuristicExtractor) Extract(input []byte) Document {
     return FromHTML(input)
 }
+
+// Extractor names accepted by NewExtractor.
+const (
+    ExtractorHeuristic   = "heuristic"
+    ExtractorReadability = "readability"
+)
+
+// NewExtractor returns the HTML extractor with the given name; empty means
+// heuristic.
+func NewExtractor(name string) (Extractor, error) {
+    switch strings.ToLower(strings.TrimSpace(name)) {
+    case "", ExtractorHeuristic:
+        return HeuristicExtractor{}, nil
+    case ExtractorReadability:
+        return ReadabilityExtractor{}, nil
+    }
+    return nil, fmt.Errorf("unknown extractor %q (want %s or %s)", name, ExtractorHeuristic, ExtractorReadability)
+}
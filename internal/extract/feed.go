@@ -0,0 +1,141 @@
+package extract
+
+import (
+    "bytes"
+    "encoding/xml"
+    "io"
+    "strings"
+    "time"
+
+    "github.com/hyperifyio/goresearch/internal/dates"
+)
+
+// Feed is a parsed RSS or Atom feed.
+type Feed struct {
+    Title   string
+    Entries []FeedEntry
+}
+
+// FeedEntry is one item of a feed with the page it links to.
+type FeedEntry struct {
+    Title     string
+    URL       string
+    Summary   string
+    Published time.Time
+}
+
+// feedXML covers RSS 2.0, RSS 1.0 (RDF) and Atom; elements are matched by
+// local name, so namespaces do not matter.
+type feedXML struct {
+    XMLName xml.Name
+    // RSS 2.0 nests items in a channel; RSS 1.0 puts them beside it.
+    Channel struct {
+        Title string    `xml:"title"`
+        Items []rssItem `xml:"item"`
+    } `xml:"channel"`
+    Items []rssItem `xml:"item"`
+    // Atom
+    Title   string      `xml:"title"`
+    Entries []atomEntry `xml:"entry"`
+}
+
+type rssItem struct {
+    Title       string `xml:"title"`
+    Link        string `xml:"link"`
+    GUID        string `xml:"guid"`
+    Description string `xml:"description"`
+    PubDate     string `xml:"pubDate"`
+    Date        string `xml:"date"`
+}
+
+type atomEntry struct {
+    Title string `xml:"title"`
+    Links []struct {
+        Href string `xml:"href,attr"`
+        Rel  string `xml:"rel,attr"`
+    } `xml:"link"`
+    Summary   string `xml:"summary"`
+    Content   string `xml:"content"`
+    Published string `xml:"published"`
+    Updated   string `xml:"updated"`
+}
+
+// ParseFeed parses input as an RSS or Atom feed. It reports false when the
+// document's root element is not rss, RDF or feed, so generic XML can be
+// told apart from feeds served as text/xml.
+func ParseFeed(input []byte) (Feed, bool) {
+    var f feedXML
+    dec := xml.NewDecoder(bytes.NewReader(input))
+    dec.Strict = false
+    dec.CharsetReader = func(_ string, r io.Reader) (io.Reader, error) { return r, nil }
+    if err := dec.Decode(&f); err != nil {
+        return Feed{}, false
+    }
+    var out Feed
+    switch strings.ToLower(f.XMLName.Local) {
+    case "rss", "rdf":
+        out.Title = strings.TrimSpace(f.Channel.Title)
+        for _, it := range append(f.Channel.Items, f.Items...) {
+            link := strings.TrimSpace(it.Link)
+            if link == "" && strings.HasPrefix(strings.TrimSpace(it.GUID), "http") {
+                link = strings.TrimSpace(it.GUID)
+            }
+            published, _ := dates.Parse(pickNonEmptyString(it.PubDate, it.Date))
+            out.Entries = append(out.Entries, FeedEntry{Title: strings.TrimSpace(it.Title), URL: link, Summary: markupText(it.Description), Published: published})
+        }
+    case "feed":
+        out.Title = strings.TrimSpace(f.Title)
+        for _, e := range f.Entries {
+            var link string
+            for _, l := range e.Links {
+                if l.Rel == "" || l.Rel == "alternate" {
+                    link = strings.TrimSpace(l.Href)
+                    break
+                }
+            }
+            published, _ := dates.Parse(pickNonEmptyString(e.Published, e.Updated))
+            out.Entries = append(out.Entries, FeedEntry{Title: strings.TrimSpace(e.Title), URL: link, Summary: markupText(pickNonEmptyString(e.Summary, e.Content)), Published: published})
+        }
+    default:
+        return Feed{}, false
+    }
+    return out, true
+}
+
+// FromFeed extracts a feed as a list of its entries' titles and summaries,
+// for when a feed is read as a source itself rather than expanded.
+func FromFeed(input []byte) Document {
+    f, ok := ParseFeed(input)
+    if !ok {
+        return FromXML(input)
+    }
+    var b strings.Builder
+    for _, e := range f.Entries {
+        b.WriteString(e.Title)
+        b.WriteString("\n")
+        if e.Summary != "" {
+            b.WriteString(e.Summary)
+            b.WriteString("\n")
+        }
+        b.WriteString("\n")
+    }
+    return Document{Title: f.Title, Text: normalizeText(b.String())}
+}
+
+// markupText reduces an entry summary, which is often escaped HTML, to text.
+func markupText(s string) string {
+    s = strings.TrimSpace(s)
+    if !strings.Contains(s, "<") {
+        return s
+    }
+    return strings.TrimSpace(FromHTML([]byte("<body>" + s + "</body>")).Text)
+}
+
+func pickNonEmptyString(values ...string) string {
+    for _, v := range values {
+        if strings.TrimSpace(v) != "" {
+            return strings.TrimSpace(v)
+        }
+    }
+    return ""
+}
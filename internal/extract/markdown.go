@@ -0,0 +1,382 @@
+package extract
+
+import (
+    "regexp"
+    "strconv"
+    "strings"
+
+    "golang.org/x/net/html"
+    "golang.org/x/text/unicode/norm"
+)
+
+// toMarkdown renders content nodes as Markdown, keeping the structure that
+// collectText flattens: headings, ordered and unordered lists, pipe tables,
+// fenced code with its language, and blockquotes. Boilerplate is skipped
+// as in collectText; links and images are reduced to their text.
+func toMarkdown(nodes ...*html.Node) string {
+    var w mdWriter
+    for _, n := range nodes {
+        w.walk(n)
+    }
+    return normalizeMarkdown(w.String())
+}
+
+// mdWriter collects Markdown blocks. Inline content accumulates until the
+// next block element or the end, when it becomes a paragraph.
+type mdWriter struct {
+    blocks []string
+    inline strings.Builder
+}
+
+func (w *mdWriter) String() string {
+    w.flush()
+    return strings.Join(w.blocks, "\n\n")
+}
+
+func (w *mdWriter) flush() {
+    if p := cleanInline(w.inline.String()); p != "" {
+        w.blocks = append(w.blocks, p)
+    }
+    w.inline.Reset()
+}
+
+func (w *mdWriter) block(s string) {
+    w.flush()
+    if strings.TrimSpace(s) != "" {
+        w.blocks = append(w.blocks, s)
+    }
+}
+
+func (w *mdWriter) walk(n *html.Node) {
+    switch n.Type {
+    case html.TextNode:
+        w.inline.WriteString(collapseSpaces(n.Data))
+        return
+    case html.ElementNode:
+    default:
+        for c := n.FirstChild; c != nil; c = c.NextSibling {
+            w.walk(c)
+        }
+        return
+    }
+    if skipElement(n) {
+        return
+    }
+    switch name := strings.ToLower(n.Data); name {
+    case "h1", "h2", "h3", "h4", "h5", "h6":
+        if t := cleanInline(inlineMarkdown(n)); t != "" {
+            level, _ := strconv.Atoi(name[1:])
+            w.block(strings.Repeat("#", level) + " " + strings.ReplaceAll(t, "\n", " "))
+        }
+    case "p":
+        w.block(cleanInline(inlineMarkdown(n)))
+    case "ul", "ol":
+        w.block(renderList(n))
+    case "pre":
+        w.block(renderPre(n))
+    case "blockquote":
+        w.block(quote(renderBlocks(n)))
+    case "table":
+        if isLayoutTable(n) {
+            w.children(n)
+            return
+        }
+        w.block(renderTable(n))
+    case "hr":
+        w.block("---")
+    case "br":
+        w.inline.WriteString("\n")
+    case "dt":
+        if t := cleanInline(inlineMarkdown(n)); t != "" {
+            w.block("**" + t + "**")
+        }
+    case "div", "section", "article", "main", "header", "body", "html", "figure", "figcaption", "dl", "dd", "details", "summary", "address", "center", "tbody", "thead", "tr", "td", "th", "li", "caption":
+        w.flush()
+        w.children(n)
+        w.flush()
+    default:
+        w.inline.WriteString(inlineNode(n))
+    }
+}
+
+func (w *mdWriter) children(n *html.Node) {
+    for c := n.FirstChild; c != nil; c = c.NextSibling {
+        w.walk(c)
+    }
+}
+
+// renderBlocks renders n's children as a standalone Markdown fragment.
+func renderBlocks(n *html.Node) string {
+    var w mdWriter
+    w.children(n)
+    return w.String()
+}
+
+// skipElement reports elements whose content is never extracted.
+func skipElement(n *html.Node) bool {
+    switch strings.ToLower(n.Data) {
+    case "script", "style", "noscript", "nav", "footer", "aside", "iframe", "head", "template", "svg", "button", "img":
+        return true
+    }
+    return isBoilerplateContainer(n)
+}
+
+// inlineMarkdown renders the phrasing content of n with emphasis and inline
+// code. Newlines come only from <br>.
+func inlineMarkdown(n *html.Node) string {
+    var b strings.Builder
+    for c := n.FirstChild; c != nil; c = c.NextSibling {
+        b.WriteString(inlineNode(c))
+    }
+    return b.String()
+}
+
+// inlineNode renders a single phrasing node.
+func inlineNode(c *html.Node) string {
+    switch c.Type {
+    case html.TextNode:
+        return collapseSpaces(c.Data)
+    case html.ElementNode:
+    default:
+        return ""
+    }
+    if skipElement(c) {
+        return ""
+    }
+    switch strings.ToLower(c.Data) {
+    case "br":
+        return "\n"
+    case "strong", "b":
+        return wrapInline(inlineMarkdown(c), "**")
+    case "em", "i":
+        return wrapInline(inlineMarkdown(c), "*")
+    case "code", "kbd", "samp", "tt":
+        return inlineCode(rawText(c))
+    }
+    return inlineMarkdown(c)
+}
+
+// wrapInline puts a delimiter around text, keeping surrounding spaces
+// outside so the emphasis stays valid Markdown.
+func wrapInline(s, delim string) string {
+    t := strings.TrimSpace(s)
+    if t == "" {
+        return s
+    }
+    lead := s[:strings.Index(s, t)]
+    trail := s[len(lead)+len(t):]
+    return lead + delim + t + delim + trail
+}
+
+func inlineCode(s string) string {
+    s = strings.TrimSpace(collapseSpaces(s))
+    if s == "" {
+        return ""
+    }
+    if strings.Contains(s, "`") {
+        return "`` " + s + " ``"
+    }
+    return "`" + s + "`"
+}
+
+// cleanInline trims each line of an inline run and drops blank lines.
+func cleanInline(s string) string {
+    lines := strings.Split(s, "\n")
+    out := lines[:0]
+    for _, l := range lines {
+        if l = strings.TrimSpace(collapseSpaces(l)); l != "" {
+            out = append(out, l)
+        }
+    }
+    return strings.Join(out, "\n")
+}
+
+// renderList renders a ul or ol with nested lists indented under their item.
+func renderList(n *html.Node) string {
+    ordered := strings.EqualFold(n.Data, "ol")
+    num := 1
+    if v, err := strconv.Atoi(attr(n, "start")); err == nil && ordered {
+        num = v
+    }
+    var items []string
+    for li := n.FirstChild; li != nil; li = li.NextSibling {
+        if li.Type != html.ElementNode || !strings.EqualFold(li.Data, "li") || skipElement(li) {
+            continue
+        }
+        marker := "- "
+        if ordered {
+            marker = strconv.Itoa(num) + ". "
+            num++
+        }
+        body := renderBlocks(li)
+        if body == "" {
+            continue
+        }
+        // Tight list: blocks inside an item are separated by single lines.
+        lines := strings.Split(strings.ReplaceAll(body, "\n\n", "\n"), "\n")
+        indent := strings.Repeat(" ", len(marker))
+        for i := range lines {
+            if i == 0 {
+                lines[i] = marker + lines[i]
+            } else {
+                lines[i] = indent + lines[i]
+            }
+        }
+        items = append(items, strings.Join(lines, "\n"))
+    }
+    return strings.Join(items, "\n")
+}
+
+var codeLanguage = regexp.MustCompile(`(?:^|\s)(?:language|lang)-([A-Za-z0-9_+#.-]+)`)
+
+// renderPre renders a fenced code block, taking the language from a
+// language-xxx or lang-xxx class on the pre or its code element.
+func renderPre(n *html.Node) string {
+    code := strings.Trim(rawText(n), "\n")
+    if strings.TrimSpace(code) == "" {
+        return ""
+    }
+    lang := ""
+    for _, c := range []*html.Node{n, findFirst(n, "code")} {
+        if c == nil {
+            continue
+        }
+        if m := codeLanguage.FindStringSubmatch(attr(c, "class")); m != nil {
+            lang = strings.ToLower(m[1])
+            break
+        }
+        if v := attr(c, "data-lang"); v != "" {
+            lang = strings.ToLower(v)
+            break
+        }
+    }
+    fence := "```"
+    for strings.Contains(code, fence) {
+        fence += "`"
+    }
+    return fence + lang + "\n" + code + "\n" + fence
+}
+
+// rawText returns the text of n with whitespace as written.
+func rawText(n *html.Node) string {
+    var b strings.Builder
+    var walk func(*html.Node)
+    walk = func(c *html.Node) {
+        switch {
+        case c.Type == html.TextNode:
+            b.WriteString(c.Data)
+        case c.Type == html.ElementNode && strings.EqualFold(c.Data, "br"):
+            b.WriteString("\n")
+        }
+        for k := c.FirstChild; k != nil; k = k.NextSibling {
+            walk(k)
+        }
+    }
+    walk(n)
+    return b.String()
+}
+
+func quote(s string) string {
+    if s == "" {
+        return ""
+    }
+    lines := strings.Split(s, "\n")
+    for i, l := range lines {
+        if l == "" {
+            lines[i] = ">"
+        } else {
+            lines[i] = "> " + l
+        }
+    }
+    return strings.Join(lines, "\n")
+}
+
+// isLayoutTable reports tables used to lay out a page rather than hold
+// data: nested tables or cells with long, block-structured content. Their
+// content is rendered as ordinary blocks.
+func isLayoutTable(n *html.Node) bool {
+    layout := false
+    walkElements(n, func(c *html.Node) {
+        switch strings.ToLower(c.Data) {
+        case "table":
+            layout = true
+        case "td", "th":
+            if len(innerText(c)) > 300 || findFirst(c, "p") != nil || findFirst(c, "ul") != nil || findFirst(c, "pre") != nil {
+                layout = true
+            }
+        }
+    })
+    return layout
+}
+
+// renderTable renders a data table as a pipe table whose header is the
+// first row.
+func renderTable(n *html.Node) string {
+    var rows [][]string
+    var caption string
+    walkElements(n, func(c *html.Node) {
+        switch strings.ToLower(c.Data) {
+        case "caption":
+            caption = cleanInline(inlineMarkdown(c))
+        case "tr":
+            var row []string
+            for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+                if cell.Type != html.ElementNode || (!strings.EqualFold(cell.Data, "td") && !strings.EqualFold(cell.Data, "th")) {
+                    continue
+                }
+                text := strings.ReplaceAll(cleanInline(inlineMarkdown(cell)), "\n", " ")
+                row = append(row, strings.ReplaceAll(text, "|", `\|`))
+                // Repeat spanned cells so columns stay aligned.
+                if span, err := strconv.Atoi(attr(cell, "colspan")); err == nil {
+                    for i := 1; i < span && i < 50; i++ {
+                        row = append(row, "")
+                    }
+                }
+            }
+            if len(row) > 0 {
+                rows = append(rows, row)
+            }
+        }
+    })
+    if len(rows) == 0 {
+        return caption
+    }
+    cols := 0
+    for _, r := range rows {
+        if len(r) > cols {
+            cols = len(r)
+        }
+    }
+    var b strings.Builder
+    if caption != "" {
+        b.WriteString(caption)
+        b.WriteString("\n\n")
+    }
+    for i, r := range rows {
+        for len(r) < cols {
+            r = append(r, "")
+        }
+        b.WriteString("| " + strings.Join(r, " | ") + " |\n")
+        if i == 0 {
+            b.WriteString("|" + strings.Repeat(" --- |", cols) + "\n")
+        }
+    }
+    return strings.TrimRight(b.String(), "\n")
+}
+
+// normalizeMarkdown applies NFC, trims trailing spaces and collapses blank
+// lines. Unlike normalizeText it keeps repeated lines, which table rules
+// and code fences legitimately have.
+func normalizeMarkdown(s string) string {
+    s = norm.NFC.String(s)
+    lines := strings.Split(s, "\n")
+    out := make([]string, 0, len(lines))
+    for _, l := range lines {
+        l = strings.TrimRight(l, " \t\r")
+        if l == "" && (len(out) == 0 || out[len(out)-1] == "") {
+            continue
+        }
+        out = append(out, l)
+    }
+    return strings.TrimSpace(strings.Join(out, "\n"))
+}
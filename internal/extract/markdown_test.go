@@ -0,0 +1,83 @@
+package extract
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestFromHTML_MarkdownKeepsStructure(t *testing.T) {
+    page := `<!doctype html><html><head><title>Guide</title></head><body>
+<nav>Menu</nav>
+<main>
+  <h1>Install   guide</h1>
+  <p>Run the <code>setup</code> script, then <strong>restart</strong> the <em>service</em>.</p>
+  <ol start="3"><li>Download</li><li>Unpack<ul><li>on Linux</li><li>on macOS</li></ul></li></ol>
+  <ul><li><p>Step with a paragraph</p></li></ul>
+  <pre><code class="language-go">func main() {
+    fmt.Println("hi")
+}</code></pre>
+  <blockquote><p>Quoted line one.</p><p>Quoted line two.</p></blockquote>
+  <table><caption>Versions</caption>
+    <thead><tr><th>Version</th><th>Status</th></tr></thead>
+    <tbody><tr><td>1.0</td><td>EOL | unsupported</td></tr><tr><td colspan="2">2.0 pending</td></tr></tbody>
+  </table>
+  <div id="cookie-banner">Accept cookies</div>
+</main>
+<footer>Footer</footer></body></html>`
+    doc := FromHTML([]byte(page))
+    want := strings.Join([]string{
+        "# Install guide",
+        "",
+        "Run the `setup` script, then **restart** the *service*.",
+        "",
+        "3. Download",
+        "4. Unpack",
+        "   - on Linux",
+        "   - on macOS",
+        "",
+        "- Step with a paragraph",
+        "",
+        "```go",
+        "func main() {",
+        `    fmt.Println("hi")`,
+        "}",
+        "```",
+        "",
+        "> Quoted line one.",
+        ">",
+        "> Quoted line two.",
+        "",
+        "Versions",
+        "",
+        "| Version | Status |",
+        "| --- | --- |",
+        `| 1.0 | EOL \| unsupported |`,
+        "| 2.0 pending |  |",
+    }, "\n")
+    if doc.Markdown != want {
+        t.Fatalf("unexpected markdown:\n%s\n--- want ---\n%s", doc.Markdown, want)
+    }
+    // The plain representation stays as before.
+    if strings.Contains(doc.Text, "# Install") || strings.Contains(doc.Text, "```") || strings.Contains(doc.Text, "| --- |") {
+        t.Fatalf("plain text should carry no markup: %q", doc.Text)
+    }
+}
+
+func TestMarkdown_LayoutTablesAndOtherFormats(t *testing.T) {
+    page := `<html><body><table><tr><td><p>` + strings.Repeat("Layout cell text. ", 20) + `</p></td></tr></table></body></html>`
+    md := FromHTML([]byte(page)).Markdown
+    if strings.Contains(md, "|") || !strings.HasPrefix(md, "Layout cell text.") {
+        t.Fatalf("layout table should render as blocks: %q", md)
+    }
+    r := ReadabilityExtractor{}.Extract([]byte(unsemanticPage))
+    if !strings.HasPrefix(r.Markdown, "# How tides are predicted\n\nTide predictions") || strings.Contains(r.Markdown, "Great article") {
+        t.Fatalf("readability markdown should follow its content choice: %q", r.Markdown)
+    }
+    src := FromMarkdown([]byte("---\ntitle: T\n---\n# Head\n\n| a | b |\n| --- | --- |\n| 1 | 2 |\n"))
+    if src.Markdown != "# Head\n\n| a | b |\n| --- | --- |\n| 1 | 2 |" {
+        t.Fatalf("markdown sources keep their structure: %q", src.Markdown)
+    }
+    if FromText([]byte("plain")).Markdown != "" {
+        t.Fatalf("plain text has no structured form")
+    }
+}
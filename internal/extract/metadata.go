@@ -0,0 +1,457 @@
+package extract
+
+import (
+    "encoding/json"
+    "regexp"
+    "strings"
+    "time"
+
+    "golang.org/x/net/html"
+
+    "github.com/hyperifyio/goresearch/internal/dates"
+)
+
+// Metadata holds document-level facts read from page markup rather than
+// from the visible text.
+type Metadata struct {
+    // Published is the publication date; zero when none was found.
+    Published time.Time
+    // Canonical is the page's declared canonical URL from
+    // <link rel="canonical">, falling back to og:url. It may be relative.
+    Canonical string
+    // SchemaTypes lists schema.org types declared in JSON-LD "@type" or
+    // microdata itemtype attributes, e.g. "NewsArticle".
+    SchemaTypes []string
+    // OGType is the og:type value, e.g. "article" or "product".
+    OGType string
+    // Scholarly is true when citation_* (Highwire) or PRISM bibliographic
+    // meta tags are present, as on journal and repository pages.
+    Scholarly bool
+    // Authors lists the declared authors in byline order, without
+    // duplicates or profile URLs.
+    Authors []string
+    // Publisher is the organization that published the document, e.g.
+    // "IETF" or a journal publisher.
+    Publisher string
+    // SiteName is the site's own name from og:site_name.
+    SiteName string
+    // Modified is the last-modified date; zero when none was found.
+    Modified time.Time
+    // Language is the declared language tag, e.g. "en" or "de-CH".
+    Language string
+    // DOI is the bare Digital Object Identifier, e.g. "10.1000/xyz123".
+    DOI string
+}
+
+// maxAuthors bounds the author list so a page listing a whole consortium
+// does not crowd the prompt.
+const maxAuthors = 10
+
+// modifiedMetaKeys lists <meta> keys that carry a last-modified date, in
+// order of preference.
+var modifiedMetaKeys = []string{
+    "article:modified_time",
+    "og:updated_time",
+    "datemodified",
+    "dcterms.modified",
+    "dc.date.modified",
+    "last-modified",
+}
+
+// authorMetaKeys lists <meta> keys that carry author names, after
+// citation_author and JSON-LD, in order of preference.
+var authorMetaKeys = []string{
+    "dc.creator",
+    "dcterms.creator",
+    "author",
+    "article:author",
+    "parsely-author",
+    "sailthru.author",
+}
+
+// doiMetaKeys lists <meta> keys that carry a DOI.
+var doiMetaKeys = []string{
+    "citation_doi",
+    "prism.doi",
+    "bepress_citation_doi",
+    "dc.identifier",
+    "dcterms.identifier",
+    "citation_identifier",
+}
+
+var doiPattern = regexp.MustCompile(`(?i)\b10\.[0-9]{4,9}/[-._;()/:a-z0-9]+`)
+
+// publishedMetaKeys lists <meta> name/property/itemprop values that carry a
+// publication date, in order of preference.
+var publishedMetaKeys = []string{
+    "article:published_time",
+    "og:published_time",
+    "datepublished",
+    "citation_publication_date",
+    "citation_date",
+    "dc.date.issued",
+    "dcterms.issued",
+    "dcterms.created",
+    "dc.date",
+    "dcterms.date",
+    "date",
+    "pubdate",
+    "publish-date",
+    "parsely-pub-date",
+    "sailthru.date",
+}
+
+// extractMetadata collects metadata from a parsed HTML document.
+func extractMetadata(root *html.Node) Metadata {
+    m := collectMarkup(root)
+    var md Metadata
+    md.Published = findPublished(m)
+    md.Canonical = findCanonical(m)
+    md.SchemaTypes, md.OGType, md.Scholarly = findTypeHints(m)
+    findAttribution(m, &md)
+    return md
+}
+
+// markup holds what metadata is read from, gathered in a single walk of the
+// document.
+type markup struct {
+    // lang is the <html lang> attribute.
+    lang string
+    // metas maps lower-cased <meta> keys (property, name, itemprop or
+    // http-equiv) to their non-empty contents in document order.
+    metas map[string][]string
+    // objects are the decoded JSON-LD node objects.
+    objects []map[string]any
+    // canonical is the first non-empty <link rel="canonical"> href.
+    canonical string
+    // itemTypes lists microdata itemtype values in document order.
+    itemTypes []string
+    // scholarly is set by citation_* or prism.* meta keys.
+    scholarly bool
+    // timeTagged is the first <time> marked as the publication date and
+    // timeAny the first <time> with a datetime at all.
+    timeTagged, timeAny string
+}
+
+func collectMarkup(root *html.Node) *markup {
+    m := &markup{metas: map[string][]string{}}
+    var walk func(*html.Node)
+    walk = func(n *html.Node) {
+        if n.Type == html.ElementNode {
+            switch n.Data {
+            case "html":
+                m.lang = strings.TrimSpace(attr(n, "lang"))
+            case "meta":
+                key := strings.ToLower(pickNonEmptyAttr(n, "property", "name", "itemprop", "http-equiv"))
+                if strings.HasPrefix(key, "citation_") || strings.HasPrefix(key, "prism.") {
+                    m.scholarly = true
+                }
+                if v := strings.TrimSpace(attr(n, "content")); key != "" && v != "" {
+                    m.metas[key] = append(m.metas[key], v)
+                }
+            case "link":
+                if m.canonical == "" {
+                    for _, rel := range strings.Fields(strings.ToLower(attr(n, "rel"))) {
+                        if rel == "canonical" {
+                            m.canonical = strings.TrimSpace(attr(n, "href"))
+                            break
+                        }
+                    }
+                }
+            case "script":
+                if strings.EqualFold(strings.TrimSpace(attr(n, "type")), "application/ld+json") && n.FirstChild != nil {
+                    m.objects = append(m.objects, jsonLDObjects(n.FirstChild.Data)...)
+                }
+            case "time":
+                if dt := strings.TrimSpace(attr(n, "datetime")); dt != "" {
+                    if m.timeTagged == "" && (strings.EqualFold(attr(n, "itemprop"), "datePublished") || hasAttr(n, "pubdate")) {
+                        m.timeTagged = dt
+                    }
+                    if m.timeAny == "" {
+                        m.timeAny = dt
+                    }
+                }
+            }
+            if it := attr(n, "itemtype"); it != "" {
+                m.itemTypes = append(m.itemTypes, strings.Fields(it)...)
+            }
+        }
+        for c := n.FirstChild; c != nil; c = c.NextSibling {
+            walk(c)
+        }
+    }
+    walk(root)
+    return m
+}
+
+// first returns the first meta content among keys, in key order.
+func (m *markup) first(keys ...string) string {
+    for _, k := range keys {
+        if vs := m.metas[k]; len(vs) > 0 {
+            return vs[0]
+        }
+    }
+    return ""
+}
+
+// ld returns the strings of key in the first JSON-LD object that has any.
+func (m *markup) ld(key string) []string {
+    for _, o := range m.objects {
+        if names := jsonLDNames(o[key]); len(names) > 0 {
+            return names
+        }
+    }
+    return nil
+}
+
+// findAttribution fills who published the document, when it last changed,
+// its language and DOI from meta tags (including OpenGraph, Dublin Core and
+// Highwire citation tags) and schema.org JSON-LD. Explicit meta tags win
+// over JSON-LD, which wins over fallbacks such as the canonical URL.
+func findAttribution(m *markup, md *Metadata) {
+    metas, first, ld := m.metas, m.first, m.ld
+
+    seen := map[string]bool{}
+    addAuthor := func(name string) {
+        name = collapseSpaces(strings.TrimSpace(name))
+        lower := strings.ToLower(name)
+        if name == "" || strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || seen[lower] || len(md.Authors) >= maxAuthors {
+            return
+        }
+        seen[lower] = true
+        md.Authors = append(md.Authors, name)
+    }
+    // Scholarly tags list one author per tag, in byline order.
+    for _, a := range metas["citation_author"] {
+        addAuthor(a)
+    }
+    if len(md.Authors) == 0 {
+        for _, a := range ld("author") {
+            addAuthor(a)
+        }
+    }
+    for _, k := range authorMetaKeys {
+        if len(md.Authors) > 0 {
+            break
+        }
+        for _, a := range metas[k] {
+            addAuthor(a)
+        }
+    }
+
+    md.Publisher = first("citation_publisher", "dc.publisher", "dcterms.publisher")
+    if md.Publisher == "" {
+        if names := ld("publisher"); len(names) > 0 {
+            md.Publisher = names[0]
+        }
+    }
+    md.SiteName = first("og:site_name", "application-name")
+
+    for _, k := range modifiedMetaKeys {
+        if t, ok := dates.Parse(first(k)); ok {
+            md.Modified = t
+            break
+        }
+    }
+    if md.Modified.IsZero() {
+        for _, v := range ld("dateModified") {
+            if t, ok := dates.Parse(v); ok {
+                md.Modified = t
+                break
+            }
+        }
+    }
+
+    for _, v := range append([]string{m.lang, first("content-language", "dc.language", "dcterms.language", "citation_language", "og:locale")}, ld("inLanguage")...) {
+        if tag := languageTag(v); tag != "" {
+            md.Language = tag
+            break
+        }
+    }
+
+    var candidates []string
+    for _, k := range doiMetaKeys {
+        candidates = append(candidates, metas[k]...)
+    }
+    for _, k := range []string{"identifier", "sameAs", "@id", "url"} {
+        candidates = append(candidates, ld(k)...)
+    }
+    candidates = append(candidates, md.Canonical)
+    for _, v := range candidates {
+        if doi := findDOI(v); doi != "" {
+            md.DOI = doi
+            break
+        }
+    }
+}
+
+// jsonLDObjects decodes a JSON-LD script into its node objects, unwrapping
+// top-level arrays and "@graph" lists. Malformed scripts yield nothing.
+func jsonLDObjects(src string) []map[string]any {
+    var v any
+    if json.Unmarshal([]byte(strings.TrimSpace(src)), &v) != nil {
+        return nil
+    }
+    var out []map[string]any
+    var visit func(any, int)
+    visit = func(v any, depth int) {
+        if depth > 4 {
+            return
+        }
+        switch t := v.(type) {
+        case []any:
+            for _, item := range t {
+                visit(item, depth+1)
+            }
+        case map[string]any:
+            out = append(out, t)
+            visit(t["@graph"], depth+1)
+        }
+    }
+    visit(v, 0)
+    return out
+}
+
+// jsonLDNames returns the strings of a JSON-LD value: a plain string, a
+// node's "name" (or "@value" / "value" for property values), or each item
+// of a list.
+func jsonLDNames(v any) []string {
+    switch t := v.(type) {
+    case string:
+        if s := strings.TrimSpace(t); s != "" {
+            return []string{s}
+        }
+    case map[string]any:
+        for _, k := range []string{"name", "@value", "value", "@id"} {
+            if s, ok := t[k].(string); ok && strings.TrimSpace(s) != "" {
+                return []string{strings.TrimSpace(s)}
+            }
+        }
+    case []any:
+        var out []string
+        for _, item := range t {
+            out = append(out, jsonLDNames(item)...)
+        }
+        return out
+    }
+    return nil
+}
+
+// languageTag normalizes a declared language to a BCP 47 style tag, turning
+// locales such as "en_US" into "en-US" and keeping the first of a list.
+func languageTag(v string) string {
+    v = strings.TrimSpace(v)
+    if i := strings.IndexAny(v, ", "); i >= 0 {
+        v = v[:i]
+    }
+    v = strings.ReplaceAll(v, "_", "-")
+    if len(v) < 2 || len(v) > 35 {
+        return ""
+    }
+    for _, r := range v {
+        if !(r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+            return ""
+        }
+    }
+    return v
+}
+
+// findDOI returns the bare DOI in s, which may be a "doi:" string or a
+// doi.org URL, without trailing punctuation.
+func findDOI(s string) string {
+    doi := doiPattern.FindString(s)
+    return strings.TrimRight(doi, ".,;:")
+}
+
+// findTypeHints collects the markup that says what kind of page this is:
+// schema.org types from JSON-LD and microdata, og:type and scholarly
+// citation tags.
+func findTypeHints(m *markup) (schemaTypes []string, ogType string, scholarly bool) {
+    seen := map[string]bool{}
+    addType := func(t string) {
+        t = strings.TrimSpace(t)
+        // Accept both "NewsArticle" and "https://schema.org/NewsArticle".
+        if i := strings.LastIndex(t, "/"); i >= 0 {
+            t = t[i+1:]
+        }
+        if t != "" && !seen[t] {
+            seen[t] = true
+            schemaTypes = append(schemaTypes, t)
+        }
+    }
+    for _, o := range m.objects {
+        switch t := o["@type"].(type) {
+        case string:
+            addType(t)
+        case []any:
+            for _, item := range t {
+                if s, ok := item.(string); ok {
+                    addType(s)
+                }
+            }
+        }
+    }
+    for _, t := range m.itemTypes {
+        addType(t)
+    }
+    return schemaTypes, m.first("og:type"), m.scholarly
+}
+
+// findCanonical returns the first rel=canonical href, else og:url.
+func findCanonical(m *markup) string {
+    if m.canonical != "" {
+        return m.canonical
+    }
+    return m.first("og:url")
+}
+
+// findPublished looks for a publication date in meta tags, JSON-LD and
+// <time> elements, preferring explicit metadata over body markup.
+func findPublished(m *markup) time.Time {
+    for _, k := range publishedMetaKeys {
+        if t, ok := dates.Parse(m.first(k)); ok {
+            return t
+        }
+    }
+    for _, o := range m.objects {
+        for _, v := range jsonLDNames(o["datePublished"]) {
+            if t, ok := dates.Parse(v); ok {
+                return t
+            }
+        }
+    }
+    for _, v := range []string{m.timeTagged, m.timeAny} {
+        if t, ok := dates.Parse(v); ok {
+            return t
+        }
+    }
+    return time.Time{}
+}
+
+func attr(n *html.Node, key string) string {
+    for _, a := range n.Attr {
+        if strings.EqualFold(a.Key, key) {
+            return a.Val
+        }
+    }
+    return ""
+}
+
+func hasAttr(n *html.Node, key string) bool {
+    for _, a := range n.Attr {
+        if strings.EqualFold(a.Key, key) {
+            return true
+        }
+    }
+    return false
+}
+
+// pickNonEmptyAttr returns the first non-empty value among the given attributes.
+func pickNonEmptyAttr(n *html.Node, keys ...string) string {
+    for _, k := range keys {
+        if v := strings.TrimSpace(attr(n, k)); v != "" {
+            return v
+        }
+    }
+    return ""
+}
@@ -0,0 +1,117 @@
+package extract
+
+import (
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestFromHTML_PublishedDateSources(t *testing.T) {
+    want := time.Date(2021, 4, 9, 0, 0, 0, 0, time.UTC)
+    cases := map[string]string{
+        "opengraph": `<html><head><meta property="article:published_time" content="2021-04-09T00:00:00Z"></head><body><p>x</p></body></html>`,
+        "citation":  `<html><head><meta name="citation_publication_date" content="2021/04/09"></head><body><p>x</p></body></html>`,
+        "jsonld":    `<html><head><script type="application/ld+json">{"@type":"Article","datePublished": "2021-04-09"}</script></head><body><p>x</p></body></html>`,
+        "time":      `<html><body><article><time datetime="2021-04-09">April 9</time><p>x</p></article></body></html>`,
+    }
+    for name, page := range cases {
+        doc := FromHTML([]byte(page))
+        if !doc.Meta.Published.Equal(want) {
+            t.Fatalf("%s: published = %v, want %v", name, doc.Meta.Published, want)
+        }
+    }
+}
+
+func TestFromHTML_PublishedPrefersMetaOverBodyTime(t *testing.T) {
+    page := `<html><head><meta name="date" content="2020-01-02"></head><body><time datetime="2024-05-06">comment</time></body></html>`
+    doc := FromHTML([]byte(page))
+    if got := doc.Meta.Published; !got.Equal(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)) {
+        t.Fatalf("expected meta date to win, got %v", got)
+    }
+    if doc := FromHTML([]byte(`<html><body><p>no dates here</p></body></html>`)); !doc.Meta.Published.IsZero() {
+        t.Fatalf("expected zero published date, got %v", doc.Meta.Published)
+    }
+}
+
+func TestFromHTML_Canonical(t *testing.T) {
+    page := `<html><head><meta property="og:url" content="https://example.com/og"><link rel="Canonical" href="/story"></head><body><p>x</p></body></html>`
+    if got := FromHTML([]byte(page)).Meta.Canonical; got != "/story" {
+        t.Fatalf("expected rel=canonical to win, got %q", got)
+    }
+    page = `<html><head><meta property="og:url" content="https://example.com/og"></head><body><p>x</p></body></html>`
+    if got := FromHTML([]byte(page)).Meta.Canonical; got != "https://example.com/og" {
+        t.Fatalf("expected og:url fallback, got %q", got)
+    }
+}
+
+func TestFromHTML_TypeHints(t *testing.T) {
+    page := `<html><head><meta property="og:type" content="article"><meta name="citation_doi" content="10.1000/x">` +
+        `<script type="application/ld+json">{"@context":"https://schema.org","@type":["NewsArticle","Article"]}</script></head>` +
+        `<body><div itemscope itemtype="https://schema.org/Person"><p>x</p></div></body></html>`
+    md := FromHTML([]byte(page)).Meta
+    if md.OGType != "article" || !md.Scholarly {
+        t.Fatalf("unexpected og:type/scholarly: %+v", md)
+    }
+    if strings.Join(md.SchemaTypes, ",") != "NewsArticle,Article,Person" {
+        t.Fatalf("unexpected schema types: %v", md.SchemaTypes)
+    }
+}
+
+func TestFromHTML_JSONLDIgnoresNestedNodes(t *testing.T) {
+    // The review and its author are nested nodes, not what the page is.
+    page := `<html><head><script type="application/ld+json">{"@type":"Product","name":"Widget",` +
+        `"review":{"@type":"Review","datePublished":"2019-03-04","author":{"@type":"Person","name":"A. Critic"}},` +
+        `"datePublished":"2021-04-09"}</script></head><body><p>x</p></body></html>`
+    md := FromHTML([]byte(page)).Meta
+    if strings.Join(md.SchemaTypes, ",") != "Product" {
+        t.Fatalf("unexpected schema types: %v", md.SchemaTypes)
+    }
+    if !md.Published.Equal(time.Date(2021, 4, 9, 0, 0, 0, 0, time.UTC)) {
+        t.Fatalf("expected the page's own date, got %v", md.Published)
+    }
+}
+
+func TestFromHTML_AttributionFromMetaTags(t *testing.T) {
+    page := `<html lang="en_US"><head>` +
+        `<meta name="citation_author" content="Doe, Jane"><meta name="citation_author" content="Roe, Rick"><meta name="citation_author" content="doe, jane">` +
+        `<meta name="author" content="Ignored Byline">` +
+        `<meta name="DC.publisher" content="Example Press"><meta property="og:site_name" content="Example Journal">` +
+        `<meta property="article:modified_time" content="2023-03-04T05:06:07Z">` +
+        `<meta name="citation_doi" content="doi:10.1234/abc.567.">` +
+        `</head><body><p>x</p></body></html>`
+    md := FromHTML([]byte(page)).Meta
+    if strings.Join(md.Authors, "; ") != "Doe, Jane; Roe, Rick" {
+        t.Fatalf("authors: %q", md.Authors)
+    }
+    if md.Publisher != "Example Press" || md.SiteName != "Example Journal" {
+        t.Fatalf("publisher/site: %q %q", md.Publisher, md.SiteName)
+    }
+    if !md.Modified.Equal(time.Date(2023, 3, 4, 5, 6, 7, 0, time.UTC)) {
+        t.Fatalf("modified: %v", md.Modified)
+    }
+    if md.Language != "en-US" || md.DOI != "10.1234/abc.567" {
+        t.Fatalf("language/doi: %q %q", md.Language, md.DOI)
+    }
+}
+
+func TestFromHTML_AttributionFromJSONLD(t *testing.T) {
+    page := `<html><head><script type="application/ld+json">{"@context":"https://schema.org","@graph":[` +
+        `{"@type":"WebSite","name":"Site"},` +
+        `{"@type":"TechArticle","author":[{"@type":"Person","name":"Ann Lee"},"Bo Chen","https://example.com/profile"],` +
+        `"publisher":{"@type":"Organization","name":"IETF"},"dateModified":"2023-06-01","inLanguage":"de",` +
+        `"sameAs":"https://doi.org/10.5555/ietf.draft"}]}</script>` +
+        `<script type="application/ld+json">{ not json</script></head><body><p>x</p></body></html>`
+    md := FromHTML([]byte(page)).Meta
+    if strings.Join(md.Authors, "; ") != "Ann Lee; Bo Chen" {
+        t.Fatalf("authors: %q", md.Authors)
+    }
+    if md.Publisher != "IETF" || md.Language != "de" || md.DOI != "10.5555/ietf.draft" {
+        t.Fatalf("unexpected metadata: %+v", md)
+    }
+    if !md.Modified.Equal(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)) {
+        t.Fatalf("modified: %v", md.Modified)
+    }
+    if md := FromHTML([]byte(`<html><body><p>x</p></body></html>`)).Meta; len(md.Authors) != 0 || md.Publisher != "" || md.Language != "" || md.DOI != "" {
+        t.Fatalf("expected no attribution, got %+v", md)
+    }
+}
@@ -0,0 +1,1200 @@
+package extract
+
+import (
+    "bytes"
+    "compress/flate"
+    "compress/zlib"
+    "encoding/ascii85"
+    "errors"
+    "fmt"
+    "io"
+    "regexp"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+    "unicode/utf16"
+
+    "golang.org/x/text/encoding/charmap"
+
+    "github.com/hyperifyio/goresearch/internal/dates"
+)
+
+// Page is the text of one page of a paged document such as a PDF.
+type Page struct {
+    // Number is the physical page number, counting from 1.
+    Number int
+    // Label is the page number printed on the page when the document
+    // defines page labels, e.g. "xii" or "345"; empty otherwise.
+    Label string
+    Text  string
+}
+
+// pdfLimits bounds the work spent on one PDF so a hostile file cannot
+// exhaust memory or CPU. Decompression bombs, reference cycles, deep
+// nesting and huge page trees stop at a limit and keep the text found so
+// far.
+type pdfLimits struct {
+    maxPages       int
+    maxObjects     int
+    maxStreamBytes int64 // decoded size of one stream
+    maxTotalBytes  int64 // decoded size of all streams together
+    maxOps         int   // content stream operators across the document
+    maxDepth       int   // nesting of arrays, dictionaries and the page tree
+    maxFormDepth   int   // form XObjects drawn inside form XObjects
+    maxTextBytes   int
+}
+
+var defaultPDFLimits = pdfLimits{
+    maxPages:       2000,
+    maxObjects:     1 << 20,
+    maxStreamBytes: 32 << 20,
+    maxTotalBytes:  256 << 20,
+    maxOps:         5_000_000,
+    maxDepth:       64,
+    maxFormDepth:   8,
+    maxTextBytes:   8 << 20,
+}
+
+var (
+    errPDFSyntax = errors.New("pdf: syntax error")
+    errPDFLimit  = errors.New("pdf: resource limit reached")
+)
+
+// PDF object types. Integers are int64 and reals float64; null is nil.
+type (
+    pdfName    string
+    pdfString  string
+    pdfKeyword string
+    pdfArray   []any
+    pdfDict    map[pdfName]any
+    pdfRef     struct{ num, gen int }
+    pdfStream  struct {
+        dict pdfDict
+        raw  []byte
+    }
+)
+
+// pdfLexer reads PDF objects from a byte slice. With refs set, "n g R"
+// sequences become references; content streams have none.
+type pdfLexer struct {
+    b        []byte
+    pos      int
+    refs     bool
+    depth    int
+    maxDepth int
+}
+
+func isPDFSpace(c byte) bool {
+    switch c {
+    case 0, '\t', '\n', '\f', '\r', ' ':
+        return true
+    }
+    return false
+}
+
+func isPDFDelim(c byte) bool {
+    switch c {
+    case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+        return true
+    }
+    return false
+}
+
+func isKeyword(v any, k string) bool {
+    kw, ok := v.(pdfKeyword)
+    return ok && string(kw) == k
+}
+
+func (l *pdfLexer) skipSpace() {
+    for l.pos < len(l.b) {
+        c := l.b[l.pos]
+        if isPDFSpace(c) {
+            l.pos++
+            continue
+        }
+        if c == '%' {
+            for l.pos < len(l.b) && l.b[l.pos] != '\n' && l.b[l.pos] != '\r' {
+                l.pos++
+            }
+            continue
+        }
+        return
+    }
+}
+
+// object reads the next object or keyword. Closing "]" and ">>" come back
+// as keywords so containers can find their end.
+func (l *pdfLexer) object() (any, error) {
+    l.skipSpace()
+    if l.pos >= len(l.b) {
+        return nil, io.EOF
+    }
+    switch c := l.b[l.pos]; c {
+    case '(':
+        return l.literalString(), nil
+    case '<':
+        if l.pos+1 < len(l.b) && l.b[l.pos+1] == '<' {
+            l.pos += 2
+            return l.dict()
+        }
+        l.pos++
+        return pdfString(l.hex()), nil
+    case '>':
+        if l.pos+1 < len(l.b) && l.b[l.pos+1] == '>' {
+            l.pos += 2
+            return pdfKeyword(">>"), nil
+        }
+        l.pos++
+        return nil, errPDFSyntax
+    case '[':
+        l.pos++
+        return l.array()
+    case ']', '{', '}':
+        l.pos++
+        return pdfKeyword(string(c)), nil
+    case ')':
+        l.pos++
+        return nil, errPDFSyntax
+    case '/':
+        return l.name(), nil
+    }
+    start := l.pos
+    for l.pos < len(l.b) && !isPDFSpace(l.b[l.pos]) && !isPDFDelim(l.b[l.pos]) {
+        l.pos++
+    }
+    tok := string(l.b[start:l.pos])
+    if n, ok := parsePDFNumber(tok); ok {
+        return n, nil
+    }
+    switch tok {
+    case "true":
+        return true, nil
+    case "false":
+        return false, nil
+    case "null":
+        return nil, nil
+    }
+    return pdfKeyword(tok), nil
+}
+
+// value reads an object, turning "n g R" into a reference. The lookahead
+// only scans plain integers, so it never reparses nested containers.
+func (l *pdfLexer) value() (any, error) {
+    v, err := l.object()
+    if err != nil || !l.refs {
+        return v, err
+    }
+    n, ok := v.(int64)
+    if !ok {
+        return v, nil
+    }
+    save := l.pos
+    if gen, ok := l.intToken(); ok {
+        l.skipSpace()
+        if l.pos < len(l.b) && l.b[l.pos] == 'R' && (l.pos+1 == len(l.b) || isPDFSpace(l.b[l.pos+1]) || isPDFDelim(l.b[l.pos+1])) {
+            l.pos++
+            return pdfRef{int(n), gen}, nil
+        }
+    }
+    l.pos = save
+    return v, nil
+}
+
+// intToken reads a non-negative integer token, restoring nothing on
+// failure; callers save the position themselves.
+func (l *pdfLexer) intToken() (int, bool) {
+    l.skipSpace()
+    start := l.pos
+    for l.pos < len(l.b) && l.b[l.pos] >= '0' && l.b[l.pos] <= '9' {
+        l.pos++
+    }
+    if l.pos == start || l.pos-start > 10 {
+        return 0, false
+    }
+    if l.pos < len(l.b) && !isPDFSpace(l.b[l.pos]) && !isPDFDelim(l.b[l.pos]) {
+        return 0, false
+    }
+    n, err := strconv.Atoi(string(l.b[start:l.pos]))
+    return n, err == nil
+}
+
+func (l *pdfLexer) array() (any, error) {
+    if l.depth >= l.maxDepth {
+        return nil, errPDFLimit
+    }
+    l.depth++
+    defer func() { l.depth-- }()
+    var arr pdfArray
+    for {
+        v, err := l.value()
+        if err == errPDFSyntax {
+            continue
+        }
+        if err != nil {
+            return nil, err
+        }
+        if isKeyword(v, "]") {
+            return arr, nil
+        }
+        arr = append(arr, v)
+    }
+}
+
+func (l *pdfLexer) dict() (any, error) {
+    if l.depth >= l.maxDepth {
+        return nil, errPDFLimit
+    }
+    l.depth++
+    defer func() { l.depth-- }()
+    d := pdfDict{}
+    for {
+        k, err := l.value()
+        if err == errPDFSyntax {
+            continue
+        }
+        if err != nil {
+            return nil, err
+        }
+        if isKeyword(k, ">>") {
+            return d, nil
+        }
+        key, ok := k.(pdfName)
+        if !ok {
+            continue
+        }
+        v, err := l.value()
+        if err != nil && err != errPDFSyntax {
+            return nil, err
+        }
+        if isKeyword(v, ">>") {
+            return d, nil
+        }
+        d[key] = v
+    }
+}
+
+func (l *pdfLexer) literalString() pdfString {
+    l.pos++
+    var b []byte
+    depth := 1
+    for l.pos < len(l.b) {
+        c := l.b[l.pos]
+        l.pos++
+        switch c {
+        case '(':
+            depth++
+        case ')':
+            depth--
+            if depth == 0 {
+                return pdfString(b)
+            }
+        case '\\':
+            if l.pos >= len(l.b) {
+                break
+            }
+            e := l.b[l.pos]
+            l.pos++
+            switch e {
+            case 'n':
+                c = '\n'
+            case 'r':
+                c = '\r'
+            case 't':
+                c = '\t'
+            case 'b':
+                c = '\b'
+            case 'f':
+                c = '\f'
+            case '\r':
+                if l.pos < len(l.b) && l.b[l.pos] == '\n' {
+                    l.pos++
+                }
+                continue
+            case '\n':
+                continue
+            case '0', '1', '2', '3', '4', '5', '6', '7':
+                n := int(e - '0')
+                for i := 0; i < 2 && l.pos < len(l.b) && l.b[l.pos] >= '0' && l.b[l.pos] <= '7'; i++ {
+                    n = n*8 + int(l.b[l.pos]-'0')
+                    l.pos++
+                }
+                c = byte(n)
+            default:
+                c = e
+            }
+        }
+        b = append(b, c)
+    }
+    return pdfString(b)
+}
+
+// hex decodes hex digits up to the closing '>', ignoring anything else.
+// An odd final digit is padded with zero.
+func (l *pdfLexer) hex() []byte {
+    var b []byte
+    hi := -1
+    for l.pos < len(l.b) {
+        c := l.b[l.pos]
+        l.pos++
+        if c == '>' {
+            break
+        }
+        v := unhex(c)
+        if v < 0 {
+            continue
+        }
+        if hi < 0 {
+            hi = v
+        } else {
+            b = append(b, byte(hi<<4|v))
+            hi = -1
+        }
+    }
+    if hi >= 0 {
+        b = append(b, byte(hi<<4))
+    }
+    return b
+}
+
+func unhex(c byte) int {
+    switch {
+    case c >= '0' && c <= '9':
+        return int(c - '0')
+    case c >= 'a' && c <= 'f':
+        return int(c-'a') + 10
+    case c >= 'A' && c <= 'F':
+        return int(c-'A') + 10
+    }
+    return -1
+}
+
+func (l *pdfLexer) name() pdfName {
+    l.pos++
+    start := l.pos
+    for l.pos < len(l.b) && !isPDFSpace(l.b[l.pos]) && !isPDFDelim(l.b[l.pos]) {
+        l.pos++
+    }
+    raw := l.b[start:l.pos]
+    if bytes.IndexByte(raw, '#') < 0 {
+        return pdfName(raw)
+    }
+    var b []byte
+    for i := 0; i < len(raw); i++ {
+        if raw[i] == '#' && i+2 < len(raw) && unhex(raw[i+1]) >= 0 && unhex(raw[i+2]) >= 0 {
+            b = append(b, byte(unhex(raw[i+1])<<4|unhex(raw[i+2])))
+            i += 2
+            continue
+        }
+        b = append(b, raw[i])
+    }
+    return pdfName(b)
+}
+
+func parsePDFNumber(tok string) (any, bool) {
+    if tok == "" {
+        return nil, false
+    }
+    if c := tok[0]; !(c >= '0' && c <= '9') && c != '-' && c != '+' && c != '.' {
+        return nil, false
+    }
+    if strings.ContainsRune(tok, '.') {
+        f, err := strconv.ParseFloat(tok, 64)
+        return f, err == nil
+    }
+    n, err := strconv.ParseInt(tok, 10, 64)
+    if err != nil {
+        // Out-of-range integers still work as reals.
+        f, ferr := strconv.ParseFloat(tok, 64)
+        return f, ferr == nil
+    }
+    return n, true
+}
+
+// pdfNum returns a numeric object as float64.
+func pdfNum(v any) (float64, bool) {
+    switch n := v.(type) {
+    case int64:
+        return float64(n), true
+    case float64:
+        return n, true
+    }
+    return 0, false
+}
+
+func pdfInt(v any, def int) int {
+    if n, ok := pdfNum(v); ok && n >= -1<<31 && n <= 1<<31 {
+        return int(n)
+    }
+    return def
+}
+
+func pdfNameOf(v any) string {
+    n, _ := v.(pdfName)
+    return string(n)
+}
+
+// pdfXref locates an object: at a byte offset, or as the index-th object
+// of an object stream.
+type pdfXref struct {
+    offset   int
+    inStream bool
+    stream   int
+    index    int
+}
+
+type pdfObjStm struct {
+    data    []byte
+    first   int
+    nums    []int
+    offsets []int
+}
+
+// pdfFile is a parsed PDF: its cross-reference table, trailer and the
+// objects resolved so far.
+type pdfFile struct {
+    data      []byte
+    limits    pdfLimits
+    xref      map[int]pdfXref
+    trailer   pdfDict
+    objects   map[int]any
+    objStms   map[int]*pdfObjStm
+    resolving map[int]bool
+    fonts     map[pdfRef]*pdfFont
+    decoded   int64
+    ops       int
+}
+
+func openPDF(data []byte, limits pdfLimits) (*pdfFile, error) {
+    f := &pdfFile{data: data, limits: limits}
+    f.reset()
+    if err := f.readXref(); err != nil || f.catalog() == nil {
+        // Damaged or hand-edited files: find the objects by scanning.
+        f.reset()
+        f.rebuildXref()
+    }
+    if f.catalog() == nil {
+        return nil, errors.New("pdf: no document catalog")
+    }
+    return f, nil
+}
+
+func (f *pdfFile) reset() {
+    f.xref = map[int]pdfXref{}
+    f.trailer = nil
+    f.objects = map[int]any{}
+    f.objStms = map[int]*pdfObjStm{}
+    f.resolving = map[int]bool{}
+    f.fonts = map[pdfRef]*pdfFont{}
+}
+
+func (f *pdfFile) lexer(pos int) *pdfLexer {
+    return &pdfLexer{b: f.data, pos: pos, refs: true, maxDepth: f.limits.maxDepth}
+}
+
+func (f *pdfFile) catalog() pdfDict {
+    if f.trailer == nil {
+        return nil
+    }
+    root, _ := f.resolve(f.trailer["Root"]).(pdfDict)
+    if root == nil || root["Pages"] == nil {
+        return nil
+    }
+    return root
+}
+
+// readXref reads the cross-reference sections from startxref back through
+// /Prev links. Entries from newer sections win.
+func (f *pdfFile) readXref() error {
+    i := bytes.LastIndex(f.data, []byte("startxref"))
+    if i < 0 {
+        return errPDFSyntax
+    }
+    off, ok := f.lexer(i + len("startxref")).intToken()
+    if !ok {
+        return errPDFSyntax
+    }
+    seen := map[int]bool{}
+    for len(seen) < 256 && off >= 0 && off < len(f.data) && !seen[off] {
+        seen[off] = true
+        trailer, err := f.readXrefSection(off)
+        if err != nil {
+            break
+        }
+        if f.trailer == nil {
+            f.trailer = trailer
+        }
+        // Hybrid files list compressed objects in a separate xref stream.
+        if stm, ok := trailer["XRefStm"].(int64); ok && stm >= 0 && !seen[int(stm)] {
+            seen[int(stm)] = true
+            _, _ = f.readXrefSection(int(stm))
+        }
+        prev, ok := trailer["Prev"].(int64)
+        if !ok {
+            break
+        }
+        off = int(prev)
+    }
+    if f.trailer == nil {
+        return errPDFSyntax
+    }
+    return nil
+}
+
+func (f *pdfFile) addXref(num int, e pdfXref) {
+    if _, ok := f.xref[num]; ok || num < 0 || len(f.xref) >= f.limits.maxObjects {
+        return
+    }
+    f.xref[num] = e
+}
+
+func (f *pdfFile) readXrefSection(off int) (pdfDict, error) {
+    l := f.lexer(off)
+    if tok, err := l.object(); err == nil && isKeyword(tok, "xref") {
+        return f.readXrefTable(l)
+    }
+    _, obj, err := f.parseIndirect(f.lexer(off))
+    if err != nil {
+        return nil, err
+    }
+    s, ok := obj.(*pdfStream)
+    if !ok || pdfNameOf(s.dict["Type"]) != "XRef" {
+        return nil, errPDFSyntax
+    }
+    return s.dict, f.readXrefStream(s)
+}
+
+func (f *pdfFile) readXrefTable(l *pdfLexer) (pdfDict, error) {
+    for {
+        v, err := l.object()
+        if err != nil {
+            return nil, err
+        }
+        if isKeyword(v, "trailer") {
+            break
+        }
+        start, ok := v.(int64)
+        count, ok2 := l.intToken()
+        if !ok || !ok2 {
+            return nil, errPDFSyntax
+        }
+        for i := 0; i < count; i++ {
+            offset, ok := l.intToken()
+            if _, ok2 := l.intToken(); !ok || !ok2 {
+                return nil, errPDFSyntax
+            }
+            kind, err := l.object()
+            if err != nil {
+                return nil, err
+            }
+            if isKeyword(kind, "n") {
+                f.addXref(int(start)+i, pdfXref{offset: offset})
+            }
+        }
+    }
+    v, err := l.value()
+    if err != nil {
+        return nil, err
+    }
+    trailer, ok := v.(pdfDict)
+    if !ok {
+        return nil, errPDFSyntax
+    }
+    return trailer, nil
+}
+
+func (f *pdfFile) readXrefStream(s *pdfStream) error {
+    data, err := f.decode(s)
+    if err != nil {
+        return err
+    }
+    w, _ := f.resolve(s.dict["W"]).(pdfArray)
+    if len(w) < 3 {
+        return errPDFSyntax
+    }
+    var widths [3]int
+    row := 0
+    for i := range widths {
+        widths[i] = pdfInt(w[i], -1)
+        if widths[i] < 0 || widths[i] > 8 {
+            return errPDFSyntax
+        }
+        row += widths[i]
+    }
+    if row == 0 {
+        return errPDFSyntax
+    }
+    index, _ := f.resolve(s.dict["Index"]).(pdfArray)
+    if len(index) == 0 {
+        index = pdfArray{int64(0), int64(pdfInt(s.dict["Size"], 0))}
+    }
+    pos := 0
+    field := func(n int) int {
+        v := 0
+        for k := 0; k < n; k++ {
+            v = v<<8 | int(data[pos])
+            pos++
+        }
+        return v
+    }
+    for k := 0; k+1 < len(index); k += 2 {
+        start, count := pdfInt(index[k], 0), pdfInt(index[k+1], 0)
+        for i := 0; i < count && pos+row <= len(data); i++ {
+            typ := 1
+            if widths[0] > 0 {
+                typ = field(widths[0])
+            }
+            a, b := field(widths[1]), field(widths[2])
+            switch typ {
+            case 1:
+                f.addXref(start+i, pdfXref{offset: a})
+            case 2:
+                f.addXref(start+i, pdfXref{inStream: true, stream: a, index: b})
+            }
+        }
+    }
+    return nil
+}
+
+var pdfObjHeader = regexp.MustCompile(`(\d{1,10})[ \t\r\n\f\x00]+(\d{1,5})[ \t\r\n\f\x00]+obj\b`)
+
+// rebuildXref recovers the object table of a file whose cross-reference
+// data is missing or wrong by scanning for object headers. Later
+// definitions win, as they would through incremental updates.
+func (f *pdfFile) rebuildXref() {
+    for _, m := range pdfObjHeader.FindAllSubmatchIndex(f.data, -1) {
+        if len(f.xref) >= f.limits.maxObjects {
+            break
+        }
+        num, _ := strconv.Atoi(string(f.data[m[2]:m[3]]))
+        f.xref[num] = pdfXref{offset: m[0]}
+    }
+    nums := make([]int, 0, len(f.xref))
+    for n := range f.xref {
+        nums = append(nums, n)
+    }
+    sort.Ints(nums)
+    for _, n := range nums {
+        s, ok := f.object(n).(*pdfStream)
+        if !ok {
+            continue
+        }
+        switch pdfNameOf(s.dict["Type"]) {
+        case "XRef":
+            if f.trailer == nil && s.dict["Root"] != nil {
+                f.trailer = s.dict
+            }
+        case "ObjStm":
+            if os := f.objStm(n); os != nil {
+                for i, num := range os.nums {
+                    f.addXref(num, pdfXref{inStream: true, stream: n, index: i})
+                }
+            }
+        }
+    }
+    if i := bytes.LastIndex(f.data, []byte("trailer")); i >= 0 {
+        if v, err := f.lexer(i + len("trailer")).value(); err == nil {
+            if d, ok := v.(pdfDict); ok && d["Root"] != nil {
+                f.trailer = d
+            }
+        }
+    }
+    if f.trailer == nil {
+        f.trailer = pdfDict{}
+    }
+    if f.catalog() != nil {
+        return
+    }
+    nums = nums[:0]
+    for n := range f.xref {
+        nums = append(nums, n)
+    }
+    sort.Ints(nums)
+    for _, n := range nums {
+        if d, ok := f.object(n).(pdfDict); ok && pdfNameOf(d["Type"]) == "Catalog" && d["Pages"] != nil {
+            f.trailer["Root"] = pdfRef{num: n}
+            return
+        }
+    }
+}
+
+// parseIndirect reads "n g obj <value> [stream ... endstream]".
+func (f *pdfFile) parseIndirect(l *pdfLexer) (int, any, error) {
+    num, ok := l.intToken()
+    if _, ok2 := l.intToken(); !ok || !ok2 {
+        return 0, nil, errPDFSyntax
+    }
+    if kw, err := l.object(); err != nil || !isKeyword(kw, "obj") {
+        return 0, nil, errPDFSyntax
+    }
+    v, err := l.value()
+    if err != nil {
+        return 0, nil, err
+    }
+    d, ok := v.(pdfDict)
+    if !ok {
+        return num, v, nil
+    }
+    save := l.pos
+    if kw, err := l.object(); err == nil && isKeyword(kw, "stream") {
+        p := l.pos
+        if p < len(l.b) && l.b[p] == '\r' {
+            p++
+        }
+        if p < len(l.b) && l.b[p] == '\n' {
+            p++
+        }
+        return num, &pdfStream{dict: d, raw: f.streamData(d, p)}, nil
+    }
+    l.pos = save
+    return num, d, nil
+}
+
+// streamData returns the raw bytes of a stream starting at start. /Length
+// is trusted only when endstream follows it; otherwise the data runs to the
+// next endstream keyword.
+func (f *pdfFile) streamData(d pdfDict, start int) []byte {
+    if n := pdfInt(f.resolve(d["Length"]), -1); n >= 0 && start+n <= len(f.data) {
+        rest := f.lexer(start + n)
+        rest.skipSpace()
+        if bytes.HasPrefix(f.data[rest.pos:], []byte("endstream")) {
+            return f.data[start : start+n]
+        }
+    }
+    end := bytes.Index(f.data[start:], []byte("endstream"))
+    if end < 0 {
+        return f.data[start:]
+    }
+    return bytes.TrimRight(f.data[start:start+end], "\r\n")
+}
+
+// resolve follows references to the object they name; missing and cyclic
+// references resolve to nil.
+func (f *pdfFile) resolve(v any) any {
+    for i := 0; i < 8; i++ {
+        r, ok := v.(pdfRef)
+        if !ok {
+            return v
+        }
+        v = f.object(r.num)
+    }
+    return nil
+}
+
+func (f *pdfFile) object(num int) any {
+    if v, ok := f.objects[num]; ok {
+        return v
+    }
+    e, ok := f.xref[num]
+    if !ok || f.resolving[num] {
+        return nil
+    }
+    f.resolving[num] = true
+    defer delete(f.resolving, num)
+    var v any
+    if e.inStream {
+        v = f.objectFromStream(e.stream, e.index)
+    } else if e.offset >= 0 && e.offset < len(f.data) {
+        if _, obj, err := f.parseIndirect(f.lexer(e.offset)); err == nil {
+            v = obj
+        }
+    }
+    f.objects[num] = v
+    return v
+}
+
+func (f *pdfFile) objectFromStream(stm, index int) any {
+    os := f.objStm(stm)
+    if os == nil || index < 0 || index >= len(os.offsets) {
+        return nil
+    }
+    start := os.first + os.offsets[index]
+    if start < 0 || start >= len(os.data) {
+        return nil
+    }
+    l := &pdfLexer{b: os.data, pos: start, refs: true, maxDepth: f.limits.maxDepth}
+    v, err := l.value()
+    if err != nil {
+        return nil
+    }
+    return v
+}
+
+func (f *pdfFile) objStm(num int) *pdfObjStm {
+    if os, ok := f.objStms[num]; ok {
+        return os
+    }
+    f.objStms[num] = nil
+    s, ok := f.object(num).(*pdfStream)
+    if !ok {
+        return nil
+    }
+    data, err := f.decode(s)
+    if err != nil {
+        return nil
+    }
+    n := pdfInt(s.dict["N"], 0)
+    os := &pdfObjStm{data: data, first: pdfInt(s.dict["First"], 0)}
+    l := &pdfLexer{b: data, maxDepth: f.limits.maxDepth}
+    for i := 0; i < n && i < f.limits.maxObjects; i++ {
+        num, ok := l.intToken()
+        off, ok2 := l.intToken()
+        if !ok || !ok2 {
+            break
+        }
+        os.nums = append(os.nums, num)
+        os.offsets = append(os.offsets, off)
+    }
+    f.objStms[num] = os
+    return os
+}
+
+// decode applies a stream's filters. Image codecs are not supported, and
+// every stream counts against the document's decoded byte budget.
+func (f *pdfFile) decode(s *pdfStream) ([]byte, error) {
+    var filters pdfArray
+    switch v := f.resolve(s.dict["Filter"]).(type) {
+    case pdfName:
+        filters = pdfArray{v}
+    case pdfArray:
+        filters = v
+    }
+    var parms pdfArray
+    switch v := f.resolve(s.dict["DecodeParms"]).(type) {
+    case pdfDict:
+        parms = pdfArray{v}
+    case pdfArray:
+        parms = v
+    }
+    data := s.raw
+    for i, fv := range filters {
+        var p pdfDict
+        if i < len(parms) {
+            p, _ = f.resolve(parms[i]).(pdfDict)
+        }
+        var err error
+        switch name := pdfNameOf(f.resolve(fv)); name {
+        case "FlateDecode", "Fl":
+            if data, err = f.inflate(data); err == nil {
+                data, err = unpredict(data, p)
+            }
+        case "ASCIIHexDecode", "AHx":
+            l := &pdfLexer{b: data}
+            data = l.hex()
+        case "ASCII85Decode", "A85":
+            data, err = f.ascii85(data)
+        default:
+            return nil, fmt.Errorf("pdf: unsupported filter %q", name)
+        }
+        if err != nil {
+            return nil, err
+        }
+    }
+    if int64(len(data)) > f.limits.maxStreamBytes || f.decoded+int64(len(data)) > f.limits.maxTotalBytes {
+        return nil, errPDFLimit
+    }
+    f.decoded += int64(len(data))
+    return data, nil
+}
+
+func (f *pdfFile) inflate(b []byte) ([]byte, error) {
+    if zr, err := zlib.NewReader(bytes.NewReader(b)); err == nil {
+        return f.readLimited(zr)
+    }
+    // Some writers omit the zlib header.
+    return f.readLimited(flate.NewReader(bytes.NewReader(b)))
+}
+
+func (f *pdfFile) ascii85(b []byte) ([]byte, error) {
+    b = bytes.TrimSpace(b)
+    b = bytes.TrimPrefix(b, []byte("<~"))
+    if i := bytes.Index(b, []byte("~>")); i >= 0 {
+        b = b[:i]
+    }
+    return f.readLimited(ascii85.NewDecoder(bytes.NewReader(b)))
+}
+
+// readLimited reads a decoder's output up to the stream and document
+// limits. Truncated or corrupt data keeps what decoded, as viewers do.
+func (f *pdfFile) readLimited(r io.Reader) ([]byte, error) {
+    max := f.limits.maxStreamBytes
+    if rest := f.limits.maxTotalBytes - f.decoded; rest < max {
+        max = rest
+    }
+    var buf bytes.Buffer
+    _, err := buf.ReadFrom(io.LimitReader(r, max+1))
+    if int64(buf.Len()) > max {
+        return nil, errPDFLimit
+    }
+    if err != nil && buf.Len() == 0 {
+        return nil, err
+    }
+    return buf.Bytes(), nil
+}
+
+// unpredict reverses the PNG predictors used with Flate, notably by xref
+// streams. TIFF prediction is rare in text and left as is.
+func unpredict(data []byte, p pdfDict) ([]byte, error) {
+    if pdfInt(p["Predictor"], 1) < 10 {
+        return data, nil
+    }
+    colors, bpc, columns := pdfInt(p["Colors"], 1), pdfInt(p["BitsPerComponent"], 8), pdfInt(p["Columns"], 1)
+    if colors < 1 || colors > 32 || bpc < 1 || bpc > 16 || columns < 1 || columns > 1<<20 {
+        return nil, errPDFSyntax
+    }
+    bpp := (colors*bpc + 7) / 8
+    row := (colors*bpc*columns + 7) / 8
+    out := make([]byte, 0, len(data))
+    prev := make([]byte, row)
+    for i := 0; i+1+row <= len(data); i += row + 1 {
+        cur := data[i+1 : i+1+row]
+        switch data[i] {
+        case 1:
+            for j := bpp; j < row; j++ {
+                cur[j] += cur[j-bpp]
+            }
+        case 2:
+            for j := range cur {
+                cur[j] += prev[j]
+            }
+        case 3:
+            for j := range cur {
+                left := 0
+                if j >= bpp {
+                    left = int(cur[j-bpp])
+                }
+                cur[j] += byte((left + int(prev[j])) / 2)
+            }
+        case 4:
+            for j := range cur {
+                var a, c int
+                if j >= bpp {
+                    a, c = int(cur[j-bpp]), int(prev[j-bpp])
+                }
+                cur[j] += byte(paeth(a, int(prev[j]), c))
+            }
+        }
+        out = append(out, cur...)
+        prev = cur
+    }
+    return out, nil
+}
+
+func paeth(a, b, c int) int {
+    p := a + b - c
+    pa, pb, pc := absInt(p-a), absInt(p-b), absInt(p-c)
+    switch {
+    case pa <= pb && pa <= pc:
+        return a
+    case pb <= pc:
+        return b
+    }
+    return c
+}
+
+func absInt(n int) int {
+    if n < 0 {
+        return -n
+    }
+    return n
+}
+
+// pdfPage is a leaf of the page tree with its inherited resources.
+type pdfPage struct {
+    dict      pdfDict
+    resources pdfDict
+}
+
+// pages walks the page tree in order. Referenced nodes are visited once,
+// so cyclic trees terminate.
+func (f *pdfFile) pages() []pdfPage {
+    var out []pdfPage
+    seen := map[pdfRef]bool{}
+    var walk func(node any, res pdfDict, depth int)
+    walk = func(node any, res pdfDict, depth int) {
+        if depth > f.limits.maxDepth || len(out) >= f.limits.maxPages {
+            return
+        }
+        if r, ok := node.(pdfRef); ok {
+            if seen[r] {
+                return
+            }
+            seen[r] = true
+        }
+        d, ok := f.resolve(node).(pdfDict)
+        if !ok {
+            return
+        }
+        if r, ok := f.resolve(d["Resources"]).(pdfDict); ok {
+            res = r
+        }
+        if kids, ok := f.resolve(d["Kids"]).(pdfArray); ok && pdfNameOf(d["Type"]) != "Page" {
+            for _, k := range kids {
+                walk(k, res, depth+1)
+            }
+            return
+        }
+        out = append(out, pdfPage{dict: d, resources: res})
+    }
+    walk(f.catalog()["Pages"], nil, 0)
+    return out
+}
+
+// pageLabels returns the printed label of each of n pages from the
+// catalog's /PageLabels number tree, or nil when there is none.
+func (f *pdfFile) pageLabels(n int) []string {
+    tree, ok := f.resolve(f.catalog()["PageLabels"]).(pdfDict)
+    if !ok {
+        return nil
+    }
+    type labelRange struct {
+        start int
+        style pdfDict
+    }
+    var ranges []labelRange
+    seen := map[pdfRef]bool{}
+    var walk func(node any, depth int)
+    walk = func(node any, depth int) {
+        if depth > f.limits.maxDepth || len(ranges) > f.limits.maxPages {
+            return
+        }
+        if r, ok := node.(pdfRef); ok {
+            if seen[r] {
+                return
+            }
+            seen[r] = true
+        }
+        d, ok := f.resolve(node).(pdfDict)
+        if !ok {
+            return
+        }
+        nums, _ := f.resolve(d["Nums"]).(pdfArray)
+        for i := 0; i+1 < len(nums); i += 2 {
+            style, _ := f.resolve(nums[i+1]).(pdfDict)
+            ranges = append(ranges, labelRange{start: pdfInt(nums[i], -1), style: style})
+        }
+        kids, _ := f.resolve(d["Kids"]).(pdfArray)
+        for _, k := range kids {
+            walk(k, depth+1)
+        }
+    }
+    walk(tree, 0)
+    if len(ranges) == 0 {
+        return nil
+    }
+    sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].start < ranges[j].start })
+    labels := make([]string, n)
+    r := -1
+    for i := range labels {
+        for r+1 < len(ranges) && ranges[r+1].start <= i {
+            r++
+        }
+        if r < 0 {
+            continue
+        }
+        style := ranges[r].style
+        num := pdfInt(style["St"], 1) + i - ranges[r].start
+        label := pdfTextString(f.resolve(style["P"]))
+        switch pdfNameOf(style["S"]) {
+        case "D":
+            label += strconv.Itoa(num)
+        case "R":
+            label += strings.ToUpper(romanNumeral(num))
+        case "r":
+            label += romanNumeral(num)
+        case "A":
+            label += strings.ToUpper(letterNumeral(num))
+        case "a":
+            label += letterNumeral(num)
+        }
+        labels[i] = label
+    }
+    return labels
+}
+
+func romanNumeral(n int) string {
+    if n <= 0 || n >= 4000 {
+        return strconv.Itoa(n)
+    }
+    vals := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+    syms := []string{"m", "cm", "d", "cd", "c", "xc", "l", "xl", "x", "ix", "v", "iv", "i"}
+    var b strings.Builder
+    for i, v := range vals {
+        for n >= v {
+            b.WriteString(syms[i])
+            n -= v
+        }
+    }
+    return b.String()
+}
+
+// letterNumeral numbers a, b, ..., z, aa, bb, ... as PDF page labels do.
+func letterNumeral(n int) string {
+    if n <= 0 || n > 26*100 {
+        return strconv.Itoa(n)
+    }
+    return strings.Repeat(string(rune('a'+(n-1)%26)), (n-1)/26+1)
+}
+
+// title returns the document information dictionary's title.
+func (f *pdfFile) title() string {
+    info, _ := f.resolve(f.trailer["Info"]).(pdfDict)
+    return strings.TrimSpace(pdfTextString(f.resolve(info["Title"])))
+}
+
+// metadata returns the author and dates from the document information
+// dictionary.
+func (f *pdfFile) metadata() Metadata {
+    info, _ := f.resolve(f.trailer["Info"]).(pdfDict)
+    var md Metadata
+    if a := strings.TrimSpace(pdfTextString(f.resolve(info["Author"]))); a != "" {
+        md.Authors = []string{a}
+    }
+    md.Published = pdfDate(pdfTextString(f.resolve(info["CreationDate"])))
+    md.Modified = pdfDate(pdfTextString(f.resolve(info["ModDate"])))
+    return md
+}
+
+// pdfDate parses a PDF date such as "D:20230115093000+01'00'". Only the
+// date part is kept, which is all a citation needs.
+func pdfDate(s string) time.Time {
+    s = strings.TrimPrefix(strings.TrimSpace(s), "D:")
+    if len(s) < 8 {
+        return time.Time{}
+    }
+    t, ok := dates.Parse(s[:8])
+    if !ok {
+        return time.Time{}
+    }
+    return t
+}
+
+// pdfTextString decodes a text string outside content streams: UTF-16BE
+// or UTF-8 with a byte order mark, else PDFDocEncoding, which
+// Windows-1252 approximates.
+func pdfTextString(v any) string {
+    s, ok := v.(pdfString)
+    if !ok {
+        return ""
+    }
+    b := []byte(s)
+    switch {
+    case bytes.HasPrefix(b, []byte{0xfe, 0xff}):
+        return utf16BE(b[2:])
+    case bytes.HasPrefix(b, []byte{0xef, 0xbb, 0xbf}):
+        return string(b[3:])
+    }
+    var sb strings.Builder
+    for _, c := range b {
+        sb.WriteRune(charmap.Windows1252.DecodeByte(c))
+    }
+    return sb.String()
+}
+
+func utf16BE(b []byte) string {
+    u := make([]uint16, 0, len(b)/2)
+    for i := 0; i+1 < len(b); i += 2 {
+        u = append(u, uint16(b[i])<<8|uint16(b[i+1]))
+    }
+    return string(utf16.Decode(u))
+}
@@ -0,0 +1,184 @@
+package extract
+
+import (
+    "bytes"
+    "compress/zlib"
+    "fmt"
+    "strings"
+    "testing"
+)
+
+// buildPDF writes objects 1..n with a classic xref table. Object 1 must be
+// the catalog; an Info dictionary is referenced as object 2 when info is
+// set.
+func buildPDF(objs []string, info bool) []byte {
+    var b bytes.Buffer
+    b.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+    offsets := make([]int, len(objs))
+    for i, o := range objs {
+        offsets[i] = b.Len()
+        fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, o)
+    }
+    xref := b.Len()
+    fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+    for _, off := range offsets {
+        fmt.Fprintf(&b, "%010d 00000 n \n", off)
+    }
+    trailer := fmt.Sprintf("/Size %d /Root 1 0 R", len(objs)+1)
+    if info {
+        trailer += " /Info 2 0 R"
+    }
+    fmt.Fprintf(&b, "trailer\n<< %s >>\nstartxref\n%d\n%%%%EOF\n", trailer, xref)
+    return b.Bytes()
+}
+
+func deflate(data []byte) []byte {
+    var b bytes.Buffer
+    zw := zlib.NewWriter(&b)
+    zw.Write(data)
+    zw.Close()
+    return b.Bytes()
+}
+
+func flateStream(dict string, data []byte) string {
+    z := deflate(data)
+    return fmt.Sprintf("<< %s /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream", dict, len(z), z)
+}
+
+func TestFromPDF_CompressedStreamsFontsAndPages(t *testing.T) {
+    toUnicode := `/CIDInit /ProcSet findresource begin 12 dict begin begincmap
+1 begincodespacerange <0000> <FFFF> endcodespacerange
+2 beginbfchar <0001> <0050> <0002> <0044> endbfchar
+1 beginbfrange <0003> <0005> <0061> endbfrange
+endcmap CMapName currentdict /CMap defineresource pop end end`
+    page1 := `BT /F1 12 Tf 72 720 Td (Hello,) Tj 40 0 Td (World) Tj
+0 -14 Td [(Ke) 30 (rning) -400 (two)] TJ [-600 (words)] TJ ET`
+    page2 := `BT /F2 10 Tf 1 0 0 1 72 700 Tm <00010002> Tj 14 TL T* <000300040005> Tj ET`
+    objs := []string{
+        "<< /Type /Catalog /Pages 3 0 R /PageLabels << /Nums [0 << /S /r >> 2 << /S /D /St 10 >>] >> >>",
+        "<< /Title (Test Paper) /Author (Ann Lee) /CreationDate (D:20220304101500+01'00') >>",
+        "<< /Type /Pages /Kids [4 0 R 5 0 R 6 0 R] /Count 3 /Resources << /Font << /F1 7 0 R /F2 8 0 R >> >> >>",
+        "<< /Type /Page /Parent 3 0 R /Contents 9 0 R >>",
+        "<< /Type /Page /Parent 3 0 R /Contents [10 0 R] >>",
+        "<< /Type /Page /Parent 3 0 R /Contents 11 0 R >>",
+        "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+        "<< /Type /Font /Subtype /Type0 /BaseFont /ABCDEF+Sans /Encoding /Identity-H /DescendantFonts [<< /Type /Font /Subtype /CIDFontType2 /DW 1000 /W [1 [600 600] 3 5 500] >>] /ToUnicode 12 0 R >>",
+        flateStream("", []byte(page1)),
+        flateStream("", []byte(page2)),
+        flateStream("", []byte("q 1 0 0 1 0 0 cm Q")),
+        flateStream("", []byte(toUnicode)),
+    }
+    doc := FromPDF(buildPDF(objs, true))
+    if doc.Title != "Test Paper" || len(doc.Meta.Authors) != 1 || doc.Meta.Authors[0] != "Ann Lee" {
+        t.Fatalf("title/author: got %q %q", doc.Title, doc.Meta.Authors)
+    }
+    if got := doc.Meta.Published.Format("2006-01-02"); got != "2022-03-04" {
+        t.Fatalf("creation date: got %s", got)
+    }
+    if len(doc.Pages) != 2 {
+        t.Fatalf("expected 2 pages with text, got %+v", doc.Pages)
+    }
+    p1, p2 := doc.Pages[0], doc.Pages[1]
+    if p1.Number != 1 || p1.Label != "i" || p1.Text != "Hello, World\nKerning two words" {
+        t.Fatalf("page 1: %+v", p1)
+    }
+    if p2.Number != 2 || p2.Label != "ii" || p2.Text != "PD\nabc" {
+        t.Fatalf("page 2: %+v", p2)
+    }
+    if !strings.Contains(doc.Text, "Hello, World") || !strings.Contains(doc.Text, "abc") {
+        t.Fatalf("document text: %q", doc.Text)
+    }
+}
+
+// Test a PDF 1.5 file whose objects live in an object stream indexed by a
+// predicted xref stream.
+func TestFromPDF_XrefAndObjectStreams(t *testing.T) {
+    content := flateStream("", []byte(`BT /F1 11 Tf 50 750 Td (Compressed objects) Tj ET`))
+    inner := []string{
+        "<< /Type /Catalog /Pages 2 0 R >>",
+        "<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+        "<< /Type /Page /Parent 2 0 R /Contents 5 0 R /Resources << /Font << /F1 << /Type /Font /Subtype /Type1 /BaseFont /Times-Roman /Encoding << /Differences [65 /Adieresis /fi] >> >> >> >> >>",
+    }
+    var header, body strings.Builder
+    for i, o := range inner {
+        fmt.Fprintf(&header, "%d %d ", i+1, body.Len())
+        body.WriteString(o + "\n")
+    }
+    objStm := header.String() + body.String()
+
+    var b bytes.Buffer
+    b.WriteString("%PDF-1.5\n")
+    off4 := b.Len()
+    fmt.Fprintf(&b, "4 0 obj\n%s\nendobj\n", flateStream(fmt.Sprintf("/Type /ObjStm /N 3 /First %d", header.Len()), []byte(objStm)))
+    off5 := b.Len()
+    fmt.Fprintf(&b, "5 0 obj\n%s\nendobj\n", content)
+    off6 := b.Len()
+    rows := [][]byte{
+        {0, 0, 0, 0, 0, 0xff, 0xff},
+        {2, 0, 0, 0, 4, 0, 0},
+        {2, 0, 0, 0, 4, 0, 1},
+        {2, 0, 0, 0, 4, 0, 2},
+        {1, byte(off4 >> 24), byte(off4 >> 16), byte(off4 >> 8), byte(off4), 0, 0},
+        {1, byte(off5 >> 24), byte(off5 >> 16), byte(off5 >> 8), byte(off5), 0, 0},
+        {1, byte(off6 >> 24), byte(off6 >> 16), byte(off6 >> 8), byte(off6), 0, 0},
+    }
+    // PNG "Up" prediction, as most writers use for xref streams.
+    var predicted []byte
+    prev := make([]byte, 7)
+    for _, r := range rows {
+        predicted = append(predicted, 2)
+        for i := range r {
+            predicted = append(predicted, r[i]-prev[i])
+        }
+        prev = r
+    }
+    z := deflate(predicted)
+    fmt.Fprintf(&b, "6 0 obj\n<< /Type /XRef /Size 7 /W [1 4 2] /Root 1 0 R /Filter /FlateDecode /DecodeParms << /Predictor 12 /Columns 7 >> /Length %d >>\nstream\n%s\nendstream\nendobj\n", len(z), z)
+    fmt.Fprintf(&b, "startxref\n%d\n%%%%EOF\n", off6)
+
+    doc := FromPDF(b.Bytes())
+    if len(doc.Pages) != 1 || doc.Pages[0].Text != "Compressed objects" {
+        t.Fatalf("unexpected pages: %+v", doc.Pages)
+    }
+    if got := glyphText("Adieresis") + glyphText("f_i") + glyphText("uni00E9"); got != "Äfié" {
+        t.Fatalf("glyph names: %q", got)
+    }
+}
+
+// Test hostile input: a decompression bomb, a cyclic page tree, reference
+// loops, deep nesting and a wrong startxref all end quickly and keep the
+// text that could be read.
+func TestFromPDF_ResourceLimits(t *testing.T) {
+    bomb := flateStream("", bytes.Repeat([]byte("0 0 m "), 200_000))
+    deep := flateStream("", []byte(strings.Repeat("[", 100_000)+" BT (Deep) Tj ET"))
+    objs := []string{
+        "<< /Type /Catalog /Pages 3 0 R >>",
+        "<< >>",
+        "<< /Type /Pages /Kids [4 0 R 3 0 R 5 0 R 6 0 R 7 0 R] >>",
+        "<< /Type /Page /Contents 8 0 R >>",
+        "<< /Type /Page /Contents 9 0 R >>",
+        "<< /Type /Page /Contents 10 0 R >>",
+        "<< /Type /Page /Contents 11 0 R >>",
+        bomb,
+        flateStream("", []byte("BT 72 700 Td (Survives the bomb) Tj ET")),
+        "12 0 R",
+        deep,
+        "10 0 R",
+    }
+    limits := defaultPDFLimits
+    limits.maxStreamBytes = 64 << 10
+    pdf := buildPDF(objs, false)
+    // Point startxref at garbage so the table has to be rebuilt.
+    pdf = bytes.Replace(pdf, []byte("startxref\n"), []byte("startxref\n7"), 1)
+    doc := fromPDF(pdf, limits)
+    if len(doc.Pages) != 1 || doc.Pages[0].Number != 2 || doc.Pages[0].Text != "Survives the bomb" {
+        t.Fatalf("unexpected pages: %+v", doc.Pages)
+    }
+}
+
+func TestFromPDF_NotAPDFFallsBackToStrings(t *testing.T) {
+    doc := FromPDF([]byte("garbage (Loose string) more"))
+    if doc.Text != "Loose string" || len(doc.Pages) != 0 {
+        t.Fatalf("unexpected fallback: %+v", doc)
+    }
+}
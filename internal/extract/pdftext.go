@@ -0,0 +1,873 @@
+package extract
+
+import (
+    "bytes"
+    "io"
+    "math"
+    "strconv"
+    "strings"
+    "unicode"
+
+    "golang.org/x/text/encoding/charmap"
+)
+
+// FromPDF extracts the text of a PDF page by page. It parses the
+// cross-reference data (tables, streams and object streams, rebuilding it
+// by scanning when damaged), decompresses content streams and interprets
+// the text operators, mapping character codes through ToUnicode CMaps or
+// the font's encoding. Resource limits keep hostile files cheap. Encrypted
+// files and scanned pages yield no text. Input that is not a structured PDF
+// falls back to scanning for string literals.
+func FromPDF(input []byte) Document {
+    return fromPDF(input, defaultPDFLimits)
+}
+
+func fromPDF(input []byte, limits pdfLimits) Document {
+    if len(input) == 0 {
+        return Document{}
+    }
+    f, err := openPDF(input, limits)
+    if err != nil {
+        return scanPDFStrings(input)
+    }
+    doc := Document{Title: f.title(), Meta: f.metadata()}
+    pages := f.pages()
+    labels := f.pageLabels(len(pages))
+    var texts []string
+    for i, p := range pages {
+        text := normalizeText(f.pageText(p))
+        if text == "" {
+            continue
+        }
+        page := Page{Number: i + 1, Text: text}
+        if i < len(labels) {
+            page.Label = labels[i]
+        }
+        doc.Pages = append(doc.Pages, page)
+        texts = append(texts, text)
+    }
+    doc.Text = normalizeText(strings.Join(texts, "\n\n"))
+    return doc
+}
+
+// scanPDFStrings is a best-effort fallback for input without a usable PDF
+// structure: it collects parenthesized string literals, which hold the
+// text of uncompressed content streams.
+func scanPDFStrings(input []byte) Document {
+    var b strings.Builder
+    depth := 0
+    for i := 0; i < len(input); i++ {
+        c := input[i]
+        if c == '(' {
+            depth++
+            continue
+        }
+        if c == ')' && depth > 0 {
+            depth--
+            b.WriteString("\n")
+            continue
+        }
+        if depth > 0 {
+            if c == '\\' && i+1 < len(input) {
+                i++
+                switch input[i] {
+                case 'n', 'r':
+                    b.WriteByte('\n')
+                case 't':
+                    b.WriteByte(' ')
+                default:
+                    b.WriteByte(input[i])
+                }
+                continue
+            }
+            if c >= 32 && c <= 126 {
+                b.WriteByte(c)
+            }
+        }
+    }
+    return Document{Text: normalizeText(b.String())}
+}
+
+// pdfMatrix is an affine transform [a b c d e f].
+type pdfMatrix [6]float64
+
+var identityMatrix = pdfMatrix{1, 0, 0, 1, 0, 0}
+
+// mul returns m×n: the transform m followed by n.
+func (m pdfMatrix) mul(n pdfMatrix) pdfMatrix {
+    return pdfMatrix{
+        m[0]*n[0] + m[1]*n[2],
+        m[0]*n[1] + m[1]*n[3],
+        m[2]*n[0] + m[3]*n[2],
+        m[2]*n[1] + m[3]*n[3],
+        m[4]*n[0] + m[5]*n[2] + n[4],
+        m[4]*n[1] + m[5]*n[3] + n[5],
+    }
+}
+
+func translate(tx, ty float64) pdfMatrix {
+    return pdfMatrix{1, 0, 0, 1, tx, ty}
+}
+
+func matrixOf(args []any) (pdfMatrix, bool) {
+    if len(args) < 6 {
+        return pdfMatrix{}, false
+    }
+    var m pdfMatrix
+    for i := range m {
+        v, ok := pdfNum(args[len(args)-6+i])
+        if !ok {
+            return pdfMatrix{}, false
+        }
+        m[i] = v
+    }
+    return m, true
+}
+
+// pdfGState is the graphics state saved by q and restored by Q, including
+// the text state.
+type pdfGState struct {
+    ctm       pdfMatrix
+    font      *pdfFont
+    size      float64
+    charSpace float64
+    wordSpace float64
+    scale     float64
+    leading   float64
+    rise      float64
+}
+
+// pdfTextWriter lays glyphs out as lines. A change of baseline starts a
+// new line, a larger one a paragraph, and a horizontal gap wider than a
+// fraction of the font size becomes a space.
+type pdfTextWriter struct {
+    b       strings.Builder
+    started bool
+    y       float64
+    endX    float64
+    height  float64
+}
+
+func (w *pdfTextWriter) glyph(text string, x, y, endX, height float64) {
+    if height <= 0 {
+        height = 1
+    }
+    if text == "" {
+        w.endX = endX
+        return
+    }
+    if w.started {
+        line := math.Max(height, w.height)
+        dy := math.Abs(y - w.y)
+        switch {
+        case dy > 1.7*line:
+            w.b.WriteString("\n\n")
+        case dy > 0.5*line:
+            w.b.WriteByte('\n')
+        case x-w.endX > 0.15*line || w.endX-x > 3*line:
+            w.b.WriteByte(' ')
+        }
+    }
+    w.b.WriteString(text)
+    w.started, w.y, w.endX, w.height = true, y, endX, height
+}
+
+// pageText returns the text drawn by a page's content streams.
+func (f *pdfFile) pageText(p pdfPage) string {
+    var parts [][]byte
+    add := func(v any) {
+        if s, ok := f.resolve(v).(*pdfStream); ok {
+            if data, err := f.decode(s); err == nil {
+                parts = append(parts, data)
+            }
+        }
+    }
+    switch c := f.resolve(p.dict["Contents"]).(type) {
+    case *pdfStream:
+        add(c)
+    case pdfArray:
+        for _, v := range c {
+            add(v)
+        }
+    }
+    var w pdfTextWriter
+    f.run(bytes.Join(parts, []byte("\n")), p.resources, identityMatrix, &w, 0)
+    return w.b.String()
+}
+
+// run interprets a content stream's text and form operators; everything
+// else only counts toward the operator limit.
+func (f *pdfFile) run(content []byte, res pdfDict, ctm pdfMatrix, w *pdfTextWriter, depth int) {
+    l := &pdfLexer{b: content, maxDepth: f.limits.maxDepth}
+    gs := pdfGState{ctm: ctm, scale: 1}
+    var stack []pdfGState
+    tm, tlm := identityMatrix, identityMatrix
+    var args []any
+    num := func(i int) float64 {
+        if i < len(args) {
+            v, _ := pdfNum(args[i])
+            return v
+        }
+        return 0
+    }
+    nextLine := func(tx, ty float64) {
+        tlm = translate(tx, ty).mul(tlm)
+        tm = tlm
+    }
+    show := func(v any) {
+        s, ok := v.(pdfString)
+        if !ok {
+            return
+        }
+        font := gs.font
+        if font == nil {
+            font = defaultPDFFont
+        }
+        size := pdfMatrix{gs.size * gs.scale, 0, 0, gs.size, 0, gs.rise}
+        for _, g := range font.glyphs(s) {
+            start := size.mul(tm).mul(gs.ctm)
+            adv := g.width/1000*gs.size + gs.charSpace
+            if g.space {
+                adv += gs.wordSpace
+            }
+            tm = translate(adv*gs.scale, 0).mul(tm)
+            end := size.mul(tm).mul(gs.ctm)
+            w.glyph(g.text, start[4], start[5], end[4], math.Hypot(start[2], start[3]))
+        }
+    }
+    for f.ops < f.limits.maxOps && w.b.Len() < f.limits.maxTextBytes {
+        v, err := l.object()
+        if err == errPDFSyntax {
+            args = args[:0]
+            continue
+        }
+        if err != nil {
+            return
+        }
+        op, ok := v.(pdfKeyword)
+        if !ok {
+            if len(args) < 32 {
+                args = append(args, v)
+            }
+            continue
+        }
+        f.ops++
+        switch op {
+        case "q":
+            if len(stack) < 64 {
+                stack = append(stack, gs)
+            }
+        case "Q":
+            if n := len(stack); n > 0 {
+                gs, stack = stack[n-1], stack[:n-1]
+            }
+        case "cm":
+            if m, ok := matrixOf(args); ok {
+                gs.ctm = m.mul(gs.ctm)
+            }
+        case "BT":
+            tm, tlm = identityMatrix, identityMatrix
+        case "Tf":
+            if len(args) >= 2 {
+                gs.font = f.font(res, pdfNameOf(args[0]))
+                gs.size = num(1)
+            }
+        case "Tc":
+            gs.charSpace = num(0)
+        case "Tw":
+            gs.wordSpace = num(0)
+        case "Tz":
+            gs.scale = num(0) / 100
+        case "TL":
+            gs.leading = num(0)
+        case "Ts":
+            gs.rise = num(0)
+        case "Td":
+            nextLine(num(0), num(1))
+        case "TD":
+            gs.leading = -num(1)
+            nextLine(num(0), num(1))
+        case "Tm":
+            if m, ok := matrixOf(args); ok {
+                tm, tlm = m, m
+            }
+        case "T*":
+            nextLine(0, -gs.leading)
+        case "Tj":
+            if len(args) > 0 {
+                show(args[len(args)-1])
+            }
+        case "'":
+            nextLine(0, -gs.leading)
+            if len(args) > 0 {
+                show(args[len(args)-1])
+            }
+        case "\"":
+            if len(args) >= 3 {
+                gs.wordSpace, gs.charSpace = num(0), num(1)
+                nextLine(0, -gs.leading)
+                show(args[2])
+            }
+        case "TJ":
+            if len(args) == 0 {
+                break
+            }
+            arr, _ := args[len(args)-1].(pdfArray)
+            for _, e := range arr {
+                if n, ok := pdfNum(e); ok {
+                    tm = translate(-n/1000*gs.size*gs.scale, 0).mul(tm)
+                    continue
+                }
+                show(e)
+            }
+        case "Do":
+            if len(args) > 0 && depth < f.limits.maxFormDepth {
+                f.form(res, pdfNameOf(args[len(args)-1]), gs.ctm, w, depth)
+            }
+        case "BI":
+            if !skipInlineImage(l) {
+                return
+            }
+        }
+        args = args[:0]
+    }
+}
+
+// form draws a form XObject, which may hold text such as a repeated
+// header or a whole imported page.
+func (f *pdfFile) form(res pdfDict, name string, ctm pdfMatrix, w *pdfTextWriter, depth int) {
+    xobjects, _ := f.resolve(res["XObject"]).(pdfDict)
+    s, ok := f.resolve(xobjects[pdfName(name)]).(*pdfStream)
+    if !ok || pdfNameOf(s.dict["Subtype"]) != "Form" {
+        return
+    }
+    data, err := f.decode(s)
+    if err != nil {
+        return
+    }
+    m := identityMatrix
+    if arr, ok := f.resolve(s.dict["Matrix"]).(pdfArray); ok {
+        if fm, ok := matrixOf(arr); ok {
+            m = fm
+        }
+    }
+    if r, ok := f.resolve(s.dict["Resources"]).(pdfDict); ok {
+        res = r
+    }
+    f.run(data, res, m.mul(ctm), w, depth+1)
+}
+
+// skipInlineImage moves past "BI ... ID <data> EI". It reports false when
+// the image never ends.
+func skipInlineImage(l *pdfLexer) bool {
+    for {
+        v, err := l.object()
+        if err == io.EOF || err == errPDFLimit {
+            return false
+        }
+        if isKeyword(v, "ID") {
+            break
+        }
+    }
+    l.pos++
+    for l.pos < len(l.b) {
+        i := bytes.Index(l.b[l.pos:], []byte("EI"))
+        if i < 0 {
+            return false
+        }
+        at := l.pos + i
+        l.pos = at + 2
+        if at > 0 && isPDFSpace(l.b[at-1]) && (l.pos == len(l.b) || isPDFSpace(l.b[l.pos]) || isPDFDelim(l.b[l.pos])) {
+            return true
+        }
+    }
+    return false
+}
+
+// pdfGlyph is one decoded character code.
+type pdfGlyph struct {
+    text  string
+    width float64 // in thousandths of the font size
+    space bool    // the single-byte code 32, which word spacing applies to
+}
+
+// pdfFont maps the character codes of shown strings to text and widths.
+type pdfFont struct {
+    composite bool
+    codespace []pdfCodespace // code lengths of a composite font's CMap
+    ucs2      bool           // the CMap's codes are UCS-2 themselves
+    toUnicode *pdfCMap
+    encoding  *[256]string
+    firstChar int
+    widths    []float64
+    cidWidths map[int]float64
+    cidRanges []pdfWidthRange
+    defWidth  float64
+    widthMul  float64
+}
+
+type pdfWidthRange struct {
+    lo, hi int
+    width  float64
+}
+
+// defaultPDFFont stands in when text is shown before any font is set.
+var defaultPDFFont = &pdfFont{encoding: winAnsiEncoding(), defWidth: 500, widthMul: 1}
+
+func (ft *pdfFont) glyphs(s pdfString) []pdfGlyph {
+    b := []byte(s)
+    out := make([]pdfGlyph, 0, len(b))
+    for i := 0; i < len(b); {
+        n := ft.codeLen(b[i:])
+        code := b[i : i+n]
+        i += n
+        c := 0
+        for _, x := range code {
+            c = c<<8 | int(x)
+        }
+        g := pdfGlyph{width: ft.width(c) * ft.widthMul, space: n == 1 && c == 32}
+        if t, ok := ft.toUnicode.lookup(code); ok {
+            g.text = t
+        } else if ft.composite {
+            if ft.ucs2 {
+                g.text = string(rune(c))
+            }
+        } else if ft.encoding != nil {
+            g.text = ft.encoding[c]
+        }
+        g.text = cleanGlyphText(g.text)
+        out = append(out, g)
+    }
+    return out
+}
+
+// codeLen returns the byte length of the next character code: one for
+// simple fonts and as the CMap's codespace says for composite ones.
+func (ft *pdfFont) codeLen(b []byte) int {
+    if !ft.composite {
+        return 1
+    }
+    for n := 1; n <= 4 && n <= len(b); n++ {
+        for _, cs := range ft.codespace {
+            if cs.matches(b[:n]) {
+                return n
+            }
+        }
+    }
+    if len(b) >= 2 {
+        return 2
+    }
+    return len(b)
+}
+
+func (ft *pdfFont) width(c int) float64 {
+    if ft.composite {
+        if w, ok := ft.cidWidths[c]; ok {
+            return w
+        }
+        for _, r := range ft.cidRanges {
+            if c >= r.lo && c <= r.hi {
+                return r.width
+            }
+        }
+        return ft.defWidth
+    }
+    if i := c - ft.firstChar; i >= 0 && i < len(ft.widths) && ft.widths[i] > 0 {
+        return ft.widths[i]
+    }
+    return ft.defWidth
+}
+
+// cleanGlyphText drops control characters and replacement characters that
+// ToUnicode maps of subset fonts commonly contain.
+func cleanGlyphText(s string) string {
+    for _, r := range s {
+        if unicode.IsControl(r) || r == unicode.ReplacementChar {
+            return strings.Map(func(r rune) rune {
+                if r == '\t' || r == '\n' || r == '\r' {
+                    return ' '
+                }
+                if unicode.IsControl(r) || r == unicode.ReplacementChar {
+                    return -1
+                }
+                return r
+            }, s)
+        }
+    }
+    return s
+}
+
+// font returns the named font of a resource dictionary, loading it once
+// per referenced font object.
+func (f *pdfFile) font(res pdfDict, name string) *pdfFont {
+    fonts, _ := f.resolve(res["Font"]).(pdfDict)
+    v := fonts[pdfName(name)]
+    ref, isRef := v.(pdfRef)
+    if isRef {
+        if ft, ok := f.fonts[ref]; ok {
+            return ft
+        }
+    }
+    d, ok := f.resolve(v).(pdfDict)
+    if !ok {
+        return nil
+    }
+    ft := f.loadFont(d)
+    if isRef {
+        f.fonts[ref] = ft
+    }
+    return ft
+}
+
+func (f *pdfFile) loadFont(d pdfDict) *pdfFont {
+    ft := &pdfFont{defWidth: 500, widthMul: 1}
+    if s, ok := f.resolve(d["ToUnicode"]).(*pdfStream); ok {
+        if data, err := f.decode(s); err == nil {
+            ft.toUnicode = parseCMap(data, f.limits)
+        }
+    }
+    switch pdfNameOf(d["Subtype"]) {
+    case "Type0":
+        ft.composite = true
+        switch enc := f.resolve(d["Encoding"]).(type) {
+        case pdfName:
+            ft.ucs2 = strings.Contains(string(enc), "UCS2") || strings.Contains(string(enc), "UTF16")
+        case *pdfStream:
+            if data, err := f.decode(enc); err == nil {
+                ft.codespace = parseCMap(data, f.limits).space
+            }
+        }
+        ft.defWidth = 1000
+        descendants, _ := f.resolve(d["DescendantFonts"]).(pdfArray)
+        if len(descendants) > 0 {
+            if cid, ok := f.resolve(descendants[0]).(pdfDict); ok {
+                if dw, ok := pdfNum(f.resolve(cid["DW"])); ok {
+                    ft.defWidth = dw
+                }
+                f.cidWidths(ft, cid)
+            }
+        }
+        return ft
+    case "Type3":
+        // Type 3 widths are in glyph space, scaled by the font matrix.
+        if fm, ok := f.resolve(d["FontMatrix"]).(pdfArray); ok && len(fm) > 0 {
+            if a, ok := pdfNum(fm[0]); ok {
+                ft.widthMul = a * 1000
+            }
+        }
+    }
+    ft.encoding = f.simpleEncoding(d)
+    ft.firstChar = pdfInt(f.resolve(d["FirstChar"]), 0)
+    if ws, ok := f.resolve(d["Widths"]).(pdfArray); ok {
+        for _, w := range ws {
+            n, _ := pdfNum(f.resolve(w))
+            ft.widths = append(ft.widths, n)
+        }
+    }
+    if desc, ok := f.resolve(d["FontDescriptor"]).(pdfDict); ok {
+        if mw, ok := pdfNum(f.resolve(desc["MissingWidth"])); ok && mw > 0 {
+            ft.defWidth = mw
+        }
+    }
+    return ft
+}
+
+// cidWidths reads a CIDFont's /W array: "c [w1 w2 ...]" lists widths from
+// c on, "c1 c2 w" gives a range one width.
+func (f *pdfFile) cidWidths(ft *pdfFont, cid pdfDict) {
+    w, _ := f.resolve(cid["W"]).(pdfArray)
+    ft.cidWidths = map[int]float64{}
+    for i := 0; i < len(w); {
+        first := pdfInt(f.resolve(w[i]), -1)
+        if i+1 >= len(w) || first < 0 {
+            return
+        }
+        if list, ok := f.resolve(w[i+1]).(pdfArray); ok {
+            for k, v := range list {
+                n, _ := pdfNum(f.resolve(v))
+                ft.cidWidths[first+k] = n
+            }
+            i += 2
+            continue
+        }
+        if i+2 >= len(w) {
+            return
+        }
+        n, _ := pdfNum(f.resolve(w[i+2]))
+        ft.cidRanges = append(ft.cidRanges, pdfWidthRange{lo: first, hi: pdfInt(f.resolve(w[i+1]), first), width: n})
+        i += 3
+    }
+}
+
+// simpleEncoding builds the code-to-text table of a simple font from its
+// base encoding and /Differences.
+func (f *pdfFile) simpleEncoding(d pdfDict) *[256]string {
+    base := "StandardEncoding"
+    var diffs pdfArray
+    switch enc := f.resolve(d["Encoding"]).(type) {
+    case pdfName:
+        base = string(enc)
+    case pdfDict:
+        if b := pdfNameOf(f.resolve(enc["BaseEncoding"])); b != "" {
+            base = b
+        }
+        diffs, _ = f.resolve(enc["Differences"]).(pdfArray)
+    }
+    var table *[256]string
+    if base == "MacRomanEncoding" {
+        table = charmapEncoding(charmap.Macintosh)
+    } else {
+        // StandardEncoding differs from WinAnsi mostly in quotes and
+        // accents, which is close enough for text.
+        table = winAnsiEncoding()
+    }
+    code := 0
+    for _, v := range diffs {
+        switch x := f.resolve(v).(type) {
+        case int64:
+            code = int(x)
+        case float64:
+            code = int(x)
+        case pdfName:
+            if code >= 0 && code < 256 {
+                table[code] = glyphText(string(x))
+            }
+            code++
+        }
+    }
+    return table
+}
+
+func winAnsiEncoding() *[256]string {
+    return charmapEncoding(charmap.Windows1252)
+}
+
+func charmapEncoding(cm *charmap.Charmap) *[256]string {
+    var t [256]string
+    for i := 32; i < 256; i++ {
+        if r := cm.DecodeByte(byte(i)); r != unicode.ReplacementChar && !unicode.IsControl(r) {
+            t[i] = string(r)
+        }
+    }
+    return &t
+}
+
+// glyphNames maps Adobe glyph names that are not a single letter or digit
+// name to their text.
+var glyphNames = map[string]string{
+    "space": " ", "nbspace": " ", "exclam": "!", "quotedbl": "\"", "numbersign": "#", "dollar": "$",
+    "percent": "%", "ampersand": "&", "quotesingle": "'", "quoteright": "’", "quoteleft": "‘",
+    "parenleft": "(", "parenright": ")", "asterisk": "*", "plus": "+", "comma": ",", "hyphen": "-",
+    "period": ".", "slash": "/", "colon": ":", "semicolon": ";", "less": "<", "equal": "=",
+    "greater": ">", "question": "?", "at": "@", "bracketleft": "[", "backslash": "\\",
+    "bracketright": "]", "asciicircum": "^", "underscore": "_", "grave": "`", "braceleft": "{",
+    "bar": "|", "braceright": "}", "asciitilde": "~", "endash": "–", "emdash": "—", "bullet": "•",
+    "quotedblleft": "“", "quotedblright": "”", "quotesinglbase": "‚", "quotedblbase": "„",
+    "guillemotleft": "«", "guillemotright": "»", "ellipsis": "…", "dagger": "†", "daggerdbl": "‡",
+    "trademark": "™", "copyright": "©", "registered": "®", "degree": "°", "section": "§",
+    "paragraph": "¶", "periodcentered": "·", "minus": "−", "multiply": "×", "divide": "÷",
+    "plusminus": "±", "Euro": "€", "sterling": "£", "yen": "¥", "cent": "¢", "germandbls": "ß",
+    "ae": "æ", "AE": "Æ", "oe": "œ", "OE": "Œ", "oslash": "ø", "Oslash": "Ø", "dotlessi": "ı",
+    "fi": "fi", "fl": "fl", "ff": "ff", "ffi": "ffi", "ffl": "ffl", "mu": "µ", "exclamdown": "¡",
+    "questiondown": "¿", "zero": "0", "one": "1", "two": "2", "three": "3", "four": "4", "five": "5",
+    "six": "6", "seven": "7", "eight": "8", "nine": "9",
+}
+
+// glyphAccents are the suffixes of accented letter names such as "eacute",
+// as combining marks; NFC normalization composes them.
+var glyphAccents = map[string]string{
+    "acute": "\u0301", "grave": "\u0300", "circumflex": "\u0302", "dieresis": "\u0308",
+    "tilde": "\u0303", "ring": "\u030a", "cedilla": "\u0327", "caron": "\u030c",
+}
+
+// glyphText returns the text of a glyph name: a letter, a known name,
+// uniXXXX or uXXXX[XX], an accented letter, or a ligature of names joined
+// by underscores. Unknown names such as those of subset fonts yield "".
+func glyphText(name string) string {
+    if i := strings.IndexByte(name, '.'); i > 0 {
+        name = name[:i]
+    }
+    if strings.Contains(name, "_") {
+        var b strings.Builder
+        for _, part := range strings.Split(name, "_") {
+            b.WriteString(glyphText(part))
+        }
+        return b.String()
+    }
+    if len(name) == 1 && (name[0] >= 'a' && name[0] <= 'z' || name[0] >= 'A' && name[0] <= 'Z') {
+        return name
+    }
+    if t, ok := glyphNames[name]; ok {
+        return t
+    }
+    if strings.HasPrefix(name, "uni") && len(name) >= 7 && (len(name)-3)%4 == 0 {
+        var b strings.Builder
+        for i := 3; i < len(name); i += 4 {
+            n, err := strconv.ParseUint(name[i:i+4], 16, 32)
+            if err != nil {
+                return ""
+            }
+            b.WriteRune(rune(n))
+        }
+        return b.String()
+    }
+    if strings.HasPrefix(name, "u") && len(name) >= 5 && len(name) <= 7 {
+        if n, err := strconv.ParseUint(name[1:], 16, 32); err == nil && n <= unicode.MaxRune {
+            return string(rune(n))
+        }
+    }
+    if len(name) > 1 {
+        if mark, ok := glyphAccents[name[1:]]; ok && unicode.IsLetter(rune(name[0])) {
+            return name[:1] + mark
+        }
+    }
+    return ""
+}
+
+// pdfCodespace is a CMap codespace range; a code matches when each byte
+// lies within the range's bytes at that position.
+type pdfCodespace struct {
+    lo, hi []byte
+}
+
+func (cs pdfCodespace) matches(code []byte) bool {
+    if len(code) != len(cs.lo) || len(cs.lo) != len(cs.hi) {
+        return false
+    }
+    for i, c := range code {
+        if c < cs.lo[i] || c > cs.hi[i] {
+            return false
+        }
+    }
+    return true
+}
+
+// pdfCMap is a parsed ToUnicode CMap. Ranges are kept as ranges, so a
+// hostile map cannot expand into millions of entries.
+type pdfCMap struct {
+    space  []pdfCodespace
+    chars  map[string]string
+    ranges []pdfCMapRange
+}
+
+type pdfCMapRange struct {
+    n      int
+    lo, hi int
+    dst    []rune   // the first code's text; later codes add to the last rune
+    list   []string // or one text per code
+}
+
+func (m *pdfCMap) lookup(code []byte) (string, bool) {
+    if m == nil {
+        return "", false
+    }
+    if t, ok := m.chars[string(code)]; ok {
+        return t, true
+    }
+    c := 0
+    for _, x := range code {
+        c = c<<8 | int(x)
+    }
+    for _, r := range m.ranges {
+        if r.n != len(code) || c < r.lo || c > r.hi {
+            continue
+        }
+        off := c - r.lo
+        if r.list != nil {
+            if off < len(r.list) {
+                return r.list[off], true
+            }
+            return "", false
+        }
+        if len(r.dst) == 0 {
+            return "", false
+        }
+        out := append([]rune(nil), r.dst...)
+        out[len(out)-1] += rune(off)
+        return string(out), true
+    }
+    return "", false
+}
+
+// parseCMap reads the codespace, bfchar and bfrange sections of a CMap.
+func parseCMap(data []byte, limits pdfLimits) *pdfCMap {
+    m := &pdfCMap{chars: map[string]string{}}
+    l := &pdfLexer{b: data, maxDepth: limits.maxDepth}
+    var operands []any
+    codeInt := func(v any) (int, int, bool) {
+        s, ok := v.(pdfString)
+        if !ok || len(s) == 0 || len(s) > 4 {
+            return 0, 0, false
+        }
+        c := 0
+        for i := 0; i < len(s); i++ {
+            c = c<<8 | int(s[i])
+        }
+        return c, len(s), true
+    }
+    for ops := 0; ops < limits.maxOps; ops++ {
+        v, err := l.object()
+        if err == errPDFSyntax {
+            continue
+        }
+        if err != nil {
+            break
+        }
+        kw, ok := v.(pdfKeyword)
+        if !ok {
+            if len(operands) < 1<<16 {
+                operands = append(operands, v)
+            }
+            continue
+        }
+        switch kw {
+        case "endcodespacerange":
+            for i := 0; i+1 < len(operands); i += 2 {
+                lo, ok1 := operands[i].(pdfString)
+                hi, ok2 := operands[i+1].(pdfString)
+                if ok1 && ok2 && len(lo) == len(hi) && len(lo) > 0 && len(lo) <= 4 {
+                    m.space = append(m.space, pdfCodespace{lo: []byte(lo), hi: []byte(hi)})
+                }
+            }
+        case "endbfchar":
+            for i := 0; i+1 < len(operands); i += 2 {
+                src, ok := operands[i].(pdfString)
+                if !ok {
+                    continue
+                }
+                switch dst := operands[i+1].(type) {
+                case pdfString:
+                    m.chars[string(src)] = utf16BE([]byte(dst))
+                case pdfName:
+                    m.chars[string(src)] = glyphText(string(dst))
+                }
+            }
+        case "endbfrange":
+            for i := 0; i+2 < len(operands); i += 3 {
+                lo, n, ok1 := codeInt(operands[i])
+                hi, _, ok2 := codeInt(operands[i+1])
+                if !ok1 || !ok2 || hi < lo {
+                    continue
+                }
+                r := pdfCMapRange{n: n, lo: lo, hi: hi}
+                switch dst := operands[i+2].(type) {
+                case pdfString:
+                    r.dst = []rune(utf16BE([]byte(dst)))
+                case pdfArray:
+                    for _, d := range dst {
+                        s, _ := d.(pdfString)
+                        r.list = append(r.list, utf16BE([]byte(s)))
+                    }
+                    if r.list == nil {
+                        continue
+                    }
+                default:
+                    continue
+                }
+                m.ranges = append(m.ranges, r)
+            }
+        }
+        operands = operands[:0]
+    }
+    return m
+}
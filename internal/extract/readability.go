@@ -0,0 +1,345 @@
+package extract
+
+import (
+    "bytes"
+    "regexp"
+    "strings"
+
+    "golang.org/x/net/html"
+)
+
+// ReadabilityExtractor scores DOM nodes in the style of Mozilla's
+// Readability and extracts the best content subtree. Paragraph-like blocks
+// award points to their ancestors for text length and punctuation; scores
+// are adjusted by class/id hints and discounted by link density, so pages
+// without <main> or <article> still lose their navigation, banners and
+// comment threads.
+type ReadabilityExtractor struct{}
+
+func (ReadabilityExtractor) Extract(input []byte) Document {
+    return FromHTMLReadability(input)
+}
+
+var (
+    // unlikelyCandidates are class/id hints for page chrome, removed before
+    // scoring unless maybeCandidate also matches.
+    unlikelyCandidates = regexp.MustCompile(`(?i)-ad-|ai2html|banner|breadcrumbs|combx|comment|community|cover-wrap|disqus|extra|footer|gdpr|cookie|consent|header|legends|menu|related|remark|replies|rss|shoutbox|sidebar|skyscraper|social|sponsor|supplemental|ad-break|agegate|pagination|pager|popup|yom-remote`)
+    maybeCandidate     = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+    positiveHint       = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story`)
+    negativeHint       = regexp.MustCompile(`(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+)
+
+// readability holds the per-node state of one extraction.
+type readability struct {
+    scores map[*html.Node]float64
+}
+
+// FromHTMLReadability extracts readable text from HTML by scoring content
+// candidates rather than trusting semantic markup. Title and metadata are
+// read as in FromHTML.
+func FromHTMLReadability(input []byte) Document {
+    root, err := html.Parse(bytes.NewReader(input))
+    if err != nil || root == nil {
+        return Document{}
+    }
+    // Metadata lives in the head and in attributes that pruning may remove.
+    doc := Document{Title: strings.TrimSpace(findTitle(root)), Meta: extractMetadata(root)}
+    body := findFirst(root, "body")
+    if body == nil {
+        return doc
+    }
+    r := &readability{scores: map[*html.Node]float64{}}
+    r.prune(body)
+    top := r.topCandidate(body)
+    var b strings.Builder
+    content := []*html.Node{body}
+    if top != nil {
+        r.clean(top)
+        content = r.withSiblings(top)
+    }
+    for _, n := range content {
+        collectText(&b, n, false)
+    }
+    doc.Text = normalizeText(b.String())
+    doc.Markdown = toMarkdown(content...)
+    return doc
+}
+
+// prune removes elements that never hold content and those whose class or
+// id marks them as page chrome.
+func (r *readability) prune(n *html.Node) {
+    for c := n.FirstChild; c != nil; {
+        next := c.NextSibling
+        if c.Type == html.CommentNode {
+            n.RemoveChild(c)
+        } else if c.Type == html.ElementNode {
+            switch strings.ToLower(c.Data) {
+            case "script", "style", "noscript", "iframe", "form", "nav", "aside", "footer", "button", "select", "svg", "template":
+                n.RemoveChild(c)
+                c = next
+                continue
+            }
+            hints := classAndID(c)
+            if (unlikelyCandidates.MatchString(hints) && !maybeCandidate.MatchString(hints)) || isBoilerplateContainer(c) || strings.EqualFold(attr(c, "role"), "navigation") {
+                n.RemoveChild(c)
+            } else {
+                r.prune(c)
+            }
+        }
+        c = next
+    }
+}
+
+// topCandidate scores paragraph-like blocks into their ancestors and returns
+// the ancestor with the highest link-discounted score, or nil when nothing
+// scored.
+func (r *readability) topCandidate(body *html.Node) *html.Node {
+    var candidates []*html.Node
+    walkElements(body, func(n *html.Node) {
+        switch strings.ToLower(n.Data) {
+        case "p", "pre", "td", "section", "h2", "h3", "h4", "h5", "h6":
+        case "div":
+            // A div holding only text and inline markup acts as a paragraph.
+            if hasBlockChild(n) {
+                return
+            }
+        default:
+            return
+        }
+        text := innerText(n)
+        if len(text) < 25 {
+            return
+        }
+        score := 1 + float64(strings.Count(text, ",")+strings.Count(text, "，"))
+        score += minFloat(float64(len(text))/100, 3)
+        level := 0
+        for a := n.Parent; a != nil && a.Type == html.ElementNode && level < 5; a = a.Parent {
+            if _, ok := r.scores[a]; !ok {
+                r.scores[a] = initialScore(a)
+                candidates = append(candidates, a)
+            }
+            switch level {
+            case 0:
+                r.scores[a] += score
+            case 1:
+                r.scores[a] += score / 2
+            default:
+                r.scores[a] += score / float64(level*3)
+            }
+            level++
+        }
+    })
+    var top *html.Node
+    best := 0.0
+    for _, c := range candidates {
+        s := r.scores[c] * (1 - linkDensity(c))
+        r.scores[c] = s
+        if top == nil || s > best {
+            top, best = c, s
+        }
+    }
+    return top
+}
+
+// withSiblings returns the top candidate together with siblings that look
+// like part of the same content, such as paragraphs split out of the main
+// container, in document order.
+func (r *readability) withSiblings(top *html.Node) []*html.Node {
+    parent := top.Parent
+    if parent == nil {
+        return []*html.Node{top}
+    }
+    threshold := maxFloat(10, r.scores[top]*0.2)
+    topHints := attr(top, "class")
+    var out []*html.Node
+    for s := parent.FirstChild; s != nil; s = s.NextSibling {
+        if s == top {
+            out = append(out, s)
+            continue
+        }
+        if s.Type != html.ElementNode {
+            continue
+        }
+        bonus := 0.0
+        if topHints != "" && attr(s, "class") == topHints {
+            bonus = r.scores[top] * 0.2
+        }
+        if sc, ok := r.scores[s]; ok && sc+bonus >= threshold {
+            out = append(out, s)
+            continue
+        }
+        if strings.EqualFold(s.Data, "p") {
+            text := innerText(s)
+            ld := linkDensity(s)
+            if (len(text) > 80 && ld < 0.25) || (len(text) > 0 && ld == 0 && strings.ContainsAny(text, ".!?")) {
+                out = append(out, s)
+            }
+        }
+    }
+    return out
+}
+
+// clean drops containers inside the chosen content that look like
+// leftovers: negatively hinted blocks, link lists and short fragments with
+// more images or list items than paragraphs.
+func (r *readability) clean(n *html.Node) {
+    for c := n.FirstChild; c != nil; {
+        next := c.NextSibling
+        if c.Type == html.ElementNode {
+            switch strings.ToLower(c.Data) {
+            case "div", "section", "ul", "ol", "table", "header":
+                if r.isFragment(c) {
+                    n.RemoveChild(c)
+                    c = next
+                    continue
+                }
+            }
+            r.clean(c)
+        }
+        c = next
+    }
+}
+
+func (r *readability) isFragment(n *html.Node) bool {
+    weight := classWeight(n)
+    if float64(weight)+r.scores[n] < 0 {
+        return true
+    }
+    text := innerText(n)
+    if strings.Count(text, ",") >= 10 {
+        return false
+    }
+    ld := linkDensity(n)
+    if weight < 25 && ld > 0.2 || weight >= 25 && ld > 0.5 {
+        return true
+    }
+    if tag := strings.ToLower(n.Data); tag == "ul" || tag == "ol" {
+        // Lists are content when their items are; link lists went above.
+        return false
+    }
+    p, li, img := countTag(n, "p"), countTag(n, "li"), countTag(n, "img")
+    if img > 1 && float64(p)/float64(img) < 0.5 {
+        return true
+    }
+    if li > p && len(text) < 200 {
+        return true
+    }
+    return len(text) < 25 && (img == 0 || img > 2)
+}
+
+// initialScore seeds a candidate by tag and class/id hints.
+func initialScore(n *html.Node) float64 {
+    s := float64(classWeight(n))
+    switch strings.ToLower(n.Data) {
+    case "div", "article", "main":
+        s += 5
+    case "pre", "td", "blockquote":
+        s += 3
+    case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+        s -= 3
+    case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+        s -= 5
+    }
+    return s
+}
+
+// classWeight is +25 per positive and -25 per negative class or id hint.
+func classWeight(n *html.Node) int {
+    w := 0
+    for _, v := range []string{attr(n, "class"), attr(n, "id")} {
+        if v == "" {
+            continue
+        }
+        if negativeHint.MatchString(v) {
+            w -= 25
+        }
+        if positiveHint.MatchString(v) {
+            w += 25
+        }
+    }
+    return w
+}
+
+// linkDensity is the share of n's text that sits inside links.
+func linkDensity(n *html.Node) float64 {
+    total := len(innerText(n))
+    if total == 0 {
+        return 0
+    }
+    links := 0
+    walkElements(n, func(a *html.Node) {
+        if strings.EqualFold(a.Data, "a") {
+            links += len(innerText(a))
+        }
+    })
+    return float64(links) / float64(total)
+}
+
+// innerText returns n's text with whitespace collapsed.
+func innerText(n *html.Node) string {
+    var b strings.Builder
+    var walk func(*html.Node)
+    walk = func(c *html.Node) {
+        if c.Type == html.TextNode {
+            b.WriteString(c.Data)
+            b.WriteByte(' ')
+        }
+        for k := c.FirstChild; k != nil; k = k.NextSibling {
+            walk(k)
+        }
+    }
+    walk(n)
+    return strings.TrimSpace(collapseSpaces(b.String()))
+}
+
+// walkElements calls fn for every element below n in document order.
+func walkElements(n *html.Node, fn func(*html.Node)) {
+    for c := n.FirstChild; c != nil; c = c.NextSibling {
+        if c.Type == html.ElementNode {
+            fn(c)
+        }
+        walkElements(c, fn)
+    }
+}
+
+func hasBlockChild(n *html.Node) bool {
+    for c := n.FirstChild; c != nil; c = c.NextSibling {
+        if c.Type != html.ElementNode {
+            continue
+        }
+        switch strings.ToLower(c.Data) {
+        case "a", "abbr", "b", "br", "cite", "code", "em", "i", "img", "kbd", "mark", "q", "s", "small", "span", "strong", "sub", "sup", "time", "u", "var":
+        default:
+            return true
+        }
+    }
+    return false
+}
+
+func countTag(n *html.Node, tag string) int {
+    count := 0
+    walkElements(n, func(c *html.Node) {
+        if strings.EqualFold(c.Data, tag) {
+            count++
+        }
+    })
+    return count
+}
+
+func classAndID(n *html.Node) string {
+    return attr(n, "class") + " " + attr(n, "id")
+}
+
+func minFloat(a, b float64) float64 {
+    if a < b {
+        return a
+    }
+    return b
+}
+
+func maxFloat(a, b float64) float64 {
+    if a > b {
+        return a
+    }
+    return b
+}
@@ -0,0 +1,84 @@
+package extract
+
+import (
+    "strings"
+    "testing"
+)
+
+// unsemanticPage has no <main> or <article>: navigation, a cookie notice and
+// a comment thread are plain divs around the story.
+const unsemanticPage = `<!doctype html>
+<html>
+  <head><title>Tide Tables</title><meta property="article:published_time" content="2023-06-01T08:00:00Z"></head>
+  <body>
+    <div id="top-menu"><a href="/">Home</a> <a href="/news">News</a> <a href="/sport">Sport</a> <a href="/weather">Weather forecast and tide tables</a></div>
+    <div class="notice">We use cookies to personalise content. <a href="/privacy">Privacy policy</a></div>
+    <div class="wrapper">
+      <div class="post-body">
+        <h1>How tides are predicted</h1>
+        <p>Tide predictions combine dozens of harmonic constituents, each tied to the motion of the moon, the sun, or both.</p>
+        <p>Harbour authorities publish tables a year ahead, and local effects, such as wind and pressure, shift the observed levels.</p>
+        <p>Storm surges, which add to the astronomical tide, are forecast separately by weather services.</p>
+      </div>
+      <div class="link-list"><a href="/a">Tides in the Bay of Fundy</a> <a href="/b">Why the moon matters</a> <a href="/c">Spring and neap tides</a></div>
+    </div>
+    <div id="comments">
+      <div class="comment">Great article, thanks! I always wondered about this, and now I finally understand.</div>
+      <div class="comment">Does anyone know where to find tables for Brest, or for the Channel Islands?</div>
+    </div>
+  </body>
+</html>`
+
+func TestReadability_PicksContentOnUnsemanticPage(t *testing.T) {
+    doc := ReadabilityExtractor{}.Extract([]byte(unsemanticPage))
+    if doc.Title != "Tide Tables" || doc.Meta.Published.IsZero() {
+        t.Fatalf("title or metadata lost: %+v", doc)
+    }
+    for _, want := range []string{"How tides are predicted", "harmonic constituents", "Storm surges"} {
+        if !strings.Contains(doc.Text, want) {
+            t.Fatalf("missing %q in %q", want, doc.Text)
+        }
+    }
+    for _, unwanted := range []string{"Weather forecast", "cookies", "Great article", "Bay of Fundy"} {
+        if strings.Contains(doc.Text, unwanted) {
+            t.Fatalf("unexpected %q in %q", unwanted, doc.Text)
+        }
+    }
+    // The heuristic extractor keeps all of it, which is what this one fixes.
+    if h := FromHTML([]byte(unsemanticPage)); !strings.Contains(h.Text, "Great article") {
+        t.Fatalf("fixture no longer distinguishes the extractors: %q", h.Text)
+    }
+}
+
+func TestReadability_KeepsSemanticPagesIntact(t *testing.T) {
+    page := []byte(`<html><head><title>Code and List</title></head><body>
+<nav>Nav should be ignored</nav>
+<article><h3>Examples</h3><p>The examples below show, step by step, how the library is used.</p>
+<ul><li>First item</li><li>Second item</li></ul>
+<pre><code>print("hello")</code></pre></article>
+<footer>Footer text</footer></body></html>`)
+    doc := ReadabilityExtractor{}.Extract(page)
+    for _, want := range []string{"Examples", "step by step", "First item", "Second item", `print("hello")`} {
+        if !strings.Contains(doc.Text, want) {
+            t.Fatalf("missing %q in %q", want, doc.Text)
+        }
+    }
+    if strings.Contains(doc.Text, "Nav should be ignored") || strings.Contains(doc.Text, "Footer text") {
+        t.Fatalf("chrome kept: %q", doc.Text)
+    }
+    if short := (ReadabilityExtractor{}).Extract([]byte("<html><body><p>Hi</p></body></html>")); strings.TrimSpace(short.Text) != "Hi" {
+        t.Fatalf("pages without candidates fall back to the body, got %q", short.Text)
+    }
+}
+
+func TestNewExtractor(t *testing.T) {
+    for name, want := range map[string]Extractor{"": HeuristicExtractor{}, "heuristic": HeuristicExtractor{}, "Readability": ReadabilityExtractor{}} {
+        got, err := NewExtractor(name)
+        if err != nil || got != want {
+            t.Fatalf("NewExtractor(%q) = %T, %v", name, got, err)
+        }
+    }
+    if _, err := NewExtractor("boilerpipe"); err == nil {
+        t.Fatalf("unknown extractor should fail")
+    }
+}
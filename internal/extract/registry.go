@@ -0,0 +1,87 @@
+package extract
+
+import (
+    "mime"
+    "strings"
+)
+
+// ExtractorFunc adapts an ordinary function to the Extractor interface.
+type ExtractorFunc func(input []byte) Document
+
+func (f ExtractorFunc) Extract(input []byte) Document { return f(input) }
+
+// Registry dispatches extraction to an Extractor chosen by content type.
+type Registry struct {
+    byType map[string]Extractor
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+    return &Registry{byType: make(map[string]Extractor)}
+}
+
+// DefaultRegistry returns a registry covering HTML and XHTML, plain text,
+// Markdown, JSON, XML and RSS/Atom feeds. PDF stays opt-in: callers that
+// enable it register FromPDF for application/pdf.
+func DefaultRegistry() *Registry {
+    r := NewRegistry()
+    r.Register("text/html", HeuristicExtractor{})
+    r.Register("application/xhtml+xml", HeuristicExtractor{})
+    r.Register("text/plain", ExtractorFunc(FromText))
+    r.Register("text/markdown", ExtractorFunc(FromMarkdown))
+    r.Register("text/x-markdown", ExtractorFunc(FromMarkdown))
+    r.Register("application/json", ExtractorFunc(FromJSON))
+    r.Register("application/xml", ExtractorFunc(FromXML))
+    r.Register("text/xml", ExtractorFunc(FromXML))
+    r.Register("application/rss+xml", ExtractorFunc(FromFeed))
+    r.Register("application/atom+xml", ExtractorFunc(FromFeed))
+    return r
+}
+
+// Register adds or replaces the extractor for a media type such as
+// "text/markdown". Parameters like charset are ignored.
+func (r *Registry) Register(mediaType string, e Extractor) {
+    if r.byType == nil {
+        r.byType = make(map[string]Extractor)
+    }
+    r.byType[MediaType(mediaType)] = e
+}
+
+// Lookup returns the extractor for a Content-Type value. Types without
+// their own entry fall back by structured syntax suffix, so
+// application/ld+json is handled as JSON and application/foo+xml as XML.
+func (r *Registry) Lookup(contentType string) (Extractor, bool) {
+    mt := MediaType(contentType)
+    if e, ok := r.byType[mt]; ok {
+        return e, true
+    }
+    if i := strings.LastIndex(mt, "+"); i >= 0 {
+        if e, ok := r.byType["application/"+mt[i+1:]]; ok {
+            return e, true
+        }
+    }
+    return nil, false
+}
+
+// Extract extracts input with the extractor for contentType. Unknown and
+// missing types are treated as HTML, as before content types were
+// dispatched.
+func (r *Registry) Extract(contentType string, input []byte) Document {
+    if e, ok := r.Lookup(contentType); ok {
+        return e.Extract(input)
+    }
+    if e, ok := r.byType["text/html"]; ok {
+        return e.Extract(input)
+    }
+    return FromHTML(input)
+}
+
+// MediaType returns the lower-cased media type of a Content-Type value
+// without parameters, e.g. "text/html" for "text/html; charset=utf-8".
+func MediaType(contentType string) string {
+    if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+        return mt
+    }
+    mt, _, _ := strings.Cut(contentType, ";")
+    return strings.ToLower(strings.TrimSpace(mt))
+}
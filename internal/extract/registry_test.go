@@ -0,0 +1,83 @@
+package extract
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestRegistry_DispatchesByContentType(t *testing.T) {
+    r := DefaultRegistry()
+    cases := []struct {
+        contentType string
+        input       string
+        title       string
+        contains    string
+        absent      string
+    }{
+        {"text/html; charset=utf-8", "<html><head><title>Page</title></head><body><p>Hello html</p></body></html>", "Page", "Hello html", "<p>"},
+        {"text/plain", "Network Working Group\n\n   1.  Introduction\n", "", "1. Introduction", ""},
+        {"text/markdown", "---\ntitle: \"Readme\"\ndate: 2024-03-01\n---\n# Project\n\nSee [the docs](https://example.com/docs) and ![logo](logo.png).\n\n```go\nfunc main() {}\n```\n\n[ref]: https://example.com\n", "Readme", "See the docs and logo.", "https://example.com"},
+        {"application/json", `{"name":"API","paths":{"/users":{"get":{"summary":"List users"}}},"version":2}`, "API", "paths./users.get.summary: List users", ""},
+        {"application/ld+json", `{"title":"Linked"}`, "Linked", "title: Linked", ""},
+        {"text/xml", "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><doc><title>Spec</title><para>Body text</para></doc>", "Spec", "Body text", "<para>"},
+        {"application/unknown", "<p>treated as html</p>", "", "treated as html", "<p>"},
+    }
+    for _, tc := range cases {
+        doc := r.Extract(tc.contentType, []byte(tc.input))
+        if doc.Title != tc.title {
+            t.Errorf("%s: title = %q, want %q", tc.contentType, doc.Title, tc.title)
+        }
+        if !strings.Contains(doc.Text, tc.contains) {
+            t.Errorf("%s: text %q lacks %q", tc.contentType, doc.Text, tc.contains)
+        }
+        if tc.absent != "" && strings.Contains(doc.Text, tc.absent) {
+            t.Errorf("%s: text %q should not contain %q", tc.contentType, doc.Text, tc.absent)
+        }
+    }
+    md := r.Extract("text/markdown", []byte("---\ndate: 2024-03-01\n---\nIntro\n=====\n\ntext\n"))
+    if md.Title != "Intro" || md.Meta.Published.Format("2006-01-02") != "2024-03-01" {
+        t.Fatalf("setext title or front matter date not read: %+v", md)
+    }
+}
+
+func TestRegistry_RegisterOverrides(t *testing.T) {
+    r := NewRegistry()
+    r.Register("Text/Plain; charset=utf-8", ExtractorFunc(func([]byte) Document { return Document{Text: "custom"} }))
+    if got := r.Extract("text/plain", nil); got.Text != "custom" {
+        t.Fatalf("expected the registered extractor, got %+v", got)
+    }
+    if _, ok := r.Lookup("application/json"); ok {
+        t.Fatalf("empty registry should not know JSON")
+    }
+}
+
+func TestParseFeed_RSSAndAtom(t *testing.T) {
+    rss := `<?xml version="1.0"?><rss version="2.0"><channel><title>Blog</title>
+<item><title>First</title><link>https://blog.example/first</link><description>&lt;p&gt;Intro &lt;b&gt;text&lt;/b&gt;&lt;/p&gt;</description><pubDate>Mon, 02 Jan 2006 15:04:05 -0700</pubDate></item>
+<item><title>Second</title><guid>https://blog.example/second</guid></item>
+</channel></rss>`
+    f, ok := ParseFeed([]byte(rss))
+    if !ok || f.Title != "Blog" || len(f.Entries) != 2 {
+        t.Fatalf("unexpected rss parse: %v %+v", ok, f)
+    }
+    if e := f.Entries[0]; e.URL != "https://blog.example/first" || e.Summary != "Intro text" || e.Published.IsZero() {
+        t.Fatalf("unexpected first entry: %+v", e)
+    }
+    if f.Entries[1].URL != "https://blog.example/second" {
+        t.Fatalf("guid should stand in for a missing link: %+v", f.Entries[1])
+    }
+
+    atom := `<feed xmlns="http://www.w3.org/2005/Atom"><title>News</title>
+<entry><title>Story</title><link rel="self" href="https://news.example/api/1"/><link href="https://news.example/story"/><summary>Short</summary><updated>2024-05-01T10:00:00Z</updated></entry>
+</feed>`
+    f, ok = ParseFeed([]byte(atom))
+    if !ok || f.Title != "News" || len(f.Entries) != 1 || f.Entries[0].URL != "https://news.example/story" || f.Entries[0].Published.IsZero() {
+        t.Fatalf("unexpected atom parse: %v %+v", ok, f)
+    }
+    if _, ok := ParseFeed([]byte("<doc><title>x</title></doc>")); ok {
+        t.Fatalf("generic XML is not a feed")
+    }
+    if doc := FromFeed([]byte(atom)); doc.Title != "News" || !strings.Contains(doc.Text, "Story\nShort") {
+        t.Fatalf("unexpected feed document: %+v", doc)
+    }
+}
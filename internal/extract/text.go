@@ -0,0 +1,210 @@
+package extract
+
+import (
+    "bytes"
+    "encoding/json"
+    "encoding/xml"
+    "io"
+    "regexp"
+    "sort"
+    "strings"
+
+    "github.com/hyperifyio/goresearch/internal/dates"
+)
+
+// FromText extracts plain text, such as an RFC served as text/plain. Layout
+// is kept; only whitespace and repeated lines are normalized.
+func FromText(input []byte) Document {
+    return Document{Text: normalizeText(string(input))}
+}
+
+var (
+    mdImage      = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+    mdLink       = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+    mdRefDef     = regexp.MustCompile(`^\s{0,3}\[[^\]]+\]:\s+\S+`)
+    mdATXHeading = regexp.MustCompile(`^\s{0,3}(#{1,6})\s+(.*?)\s*#*\s*$`)
+    mdSetext     = regexp.MustCompile(`^\s{0,3}(=+|-+)\s*$`)
+    mdComment    = regexp.MustCompile(`(?s)<!--.*?-->`)
+)
+
+// FromMarkdown extracts text from Markdown, such as a README served raw.
+// Headings, list items and code are kept as lines; link and image syntax is
+// reduced to its text, and YAML front matter supplies the title, author and
+// date.
+// Markdown keeps the source without front matter and comments.
+func FromMarkdown(input []byte) Document {
+    src := strings.ReplaceAll(string(input), "\r\n", "\n")
+    var doc Document
+    if rest, fm, ok := splitFrontMatter(src); ok {
+        src = rest
+        doc.Title = fm["title"]
+        if a := strings.TrimSpace(fm["author"]); a != "" {
+            doc.Meta.Authors = []string{a}
+        }
+        for _, k := range []string{"date", "published", "pubdate"} {
+            if t, ok := dates.Parse(fm[k]); ok {
+                doc.Meta.Published = t
+                break
+            }
+        }
+    }
+    src = mdComment.ReplaceAllString(src, "")
+    // The source already is the structured form.
+    doc.Markdown = normalizeMarkdown(src)
+    lines := strings.Split(src, "\n")
+    out := make([]string, 0, len(lines))
+    inFence := false
+    for i, line := range lines {
+        trimmed := strings.TrimSpace(line)
+        if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+            inFence = !inFence
+            continue
+        }
+        if inFence {
+            out = append(out, line)
+            continue
+        }
+        if mdRefDef.MatchString(line) {
+            continue
+        }
+        if m := mdATXHeading.FindStringSubmatch(line); m != nil {
+            if doc.Title == "" && len(m[1]) == 1 {
+                doc.Title = m[2]
+            }
+            out = append(out, m[2])
+            continue
+        }
+        if mdSetext.MatchString(line) && i > 0 && strings.TrimSpace(lines[i-1]) != "" {
+            if doc.Title == "" && strings.HasPrefix(trimmed, "=") {
+                doc.Title = strings.TrimSpace(lines[i-1])
+            }
+            continue
+        }
+        line = mdImage.ReplaceAllString(line, "$1")
+        line = mdLink.ReplaceAllString(line, "$1")
+        out = append(out, line)
+    }
+    doc.Title = strings.TrimSpace(doc.Title)
+    doc.Text = normalizeText(strings.Join(out, "\n"))
+    return doc
+}
+
+// splitFrontMatter separates a leading "---" YAML block and returns its
+// top-level scalar fields.
+func splitFrontMatter(src string) (string, map[string]string, bool) {
+    if !strings.HasPrefix(src, "---\n") {
+        return src, nil, false
+    }
+    end := strings.Index(src[4:], "\n---")
+    if end < 0 {
+        return src, nil, false
+    }
+    block := src[4 : 4+end]
+    rest := src[4+end+4:]
+    fields := map[string]string{}
+    for _, line := range strings.Split(block, "\n") {
+        if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+            continue
+        }
+        k, v, ok := strings.Cut(line, ":")
+        if !ok {
+            continue
+        }
+        fields[strings.ToLower(strings.TrimSpace(k))] = strings.Trim(strings.TrimSpace(v), `"'`)
+    }
+    return rest, fields, true
+}
+
+// FromJSON extracts text from a JSON document, such as an API reference
+// served as JSON. Each string value becomes a "path: value" line so field
+// names give the model context; a top-level title or name is the title.
+func FromJSON(input []byte) Document {
+    var v interface{}
+    dec := json.NewDecoder(bytes.NewReader(input))
+    dec.UseNumber()
+    if err := dec.Decode(&v); err != nil {
+        return FromText(input)
+    }
+    var doc Document
+    if m, ok := v.(map[string]interface{}); ok {
+        for _, k := range []string{"title", "name"} {
+            if s, ok := m[k].(string); ok && strings.TrimSpace(s) != "" {
+                doc.Title = strings.TrimSpace(s)
+                break
+            }
+        }
+    }
+    var b strings.Builder
+    flattenJSON(&b, "", v)
+    doc.Text = normalizeText(b.String())
+    return doc
+}
+
+func flattenJSON(b *strings.Builder, path string, v interface{}) {
+    switch t := v.(type) {
+    case map[string]interface{}:
+        keys := make([]string, 0, len(t))
+        for k := range t {
+            keys = append(keys, k)
+        }
+        // Sorted keys keep the output deterministic.
+        sort.Strings(keys)
+        for _, k := range keys {
+            p := k
+            if path != "" {
+                p = path + "." + k
+            }
+            flattenJSON(b, p, t[k])
+        }
+    case []interface{}:
+        for _, e := range t {
+            flattenJSON(b, path, e)
+        }
+    case string:
+        if s := strings.TrimSpace(t); s != "" {
+            if path != "" {
+                b.WriteString(path)
+                b.WriteString(": ")
+            }
+            b.WriteString(s)
+            b.WriteString("\n")
+        }
+    }
+}
+
+// FromXML extracts the character data of an XML document, one element per
+// line. A <title> element anywhere in the document supplies the title.
+func FromXML(input []byte) Document {
+    dec := xml.NewDecoder(bytes.NewReader(input))
+    dec.Strict = false
+    dec.CharsetReader = func(_ string, r io.Reader) (io.Reader, error) { return r, nil }
+    var doc Document
+    var b strings.Builder
+    var stack []string
+    for {
+        tok, err := dec.Token()
+        if err != nil {
+            break
+        }
+        switch t := tok.(type) {
+        case xml.StartElement:
+            stack = append(stack, strings.ToLower(t.Name.Local))
+        case xml.EndElement:
+            if len(stack) > 0 {
+                stack = stack[:len(stack)-1]
+            }
+        case xml.CharData:
+            s := strings.TrimSpace(string(t))
+            if s == "" {
+                continue
+            }
+            if doc.Title == "" && len(stack) > 0 && stack[len(stack)-1] == "title" {
+                doc.Title = s
+            }
+            b.WriteString(s)
+            b.WriteString("\n")
+        }
+    }
+    doc.Text = normalizeText(b.String())
+    return doc
+}
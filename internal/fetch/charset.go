@@ -0,0 +1,103 @@
+package fetch
+
+import (
+    "bytes"
+    "unicode"
+    "unicode/utf8"
+
+    "golang.org/x/net/html/charset"
+    "golang.org/x/text/encoding"
+)
+
+// sniffCandidates are the multi-byte legacy encodings tried, in order, when a
+// page declares no charset and is not valid UTF-8. Each is paired with the
+// scripts its text is expected to be written in.
+var sniffCandidates = []struct {
+    name    string
+    scripts []*unicode.RangeTable
+}{
+    {"shift_jis", []*unicode.RangeTable{unicode.Hiragana, unicode.Katakana, unicode.Han}},
+    {"euc-jp", []*unicode.RangeTable{unicode.Hiragana, unicode.Katakana, unicode.Han}},
+    {"euc-kr", []*unicode.RangeTable{unicode.Hangul, unicode.Han}},
+    {"gbk", []*unicode.RangeTable{unicode.Han}},
+    {"big5", []*unicode.RangeTable{unicode.Han}},
+}
+
+// DecodeHTML transcodes an HTML body to UTF-8 and returns it with the
+// canonical name of the source charset. When known is set (for example the
+// charset recorded in the HTTP cache) it is trusted as is. Otherwise the
+// charset comes from a byte order mark, the Content-Type header or a <meta>
+// declaration, in that order, and is sniffed from the bytes when all are
+// missing. JSON is UTF-8 by definition and is never transcoded. Bodies that
+// cannot be decoded are returned unchanged.
+func DecodeHTML(body []byte, contentType, known string) ([]byte, string) {
+    var enc encoding.Encoding
+    name := ""
+    if matchesMediaType(contentType, []string{"application/json", "application/*+json"}) {
+        known = "utf-8"
+    }
+    if known != "" {
+        enc, name = charset.Lookup(known)
+    }
+    if enc == nil {
+        enc, name = detectCharset(body, contentType)
+    }
+    if enc == nil || name == "utf-8" {
+        return bytes.TrimPrefix(body, []byte("\xef\xbb\xbf")), "utf-8"
+    }
+    out, err := enc.NewDecoder().Bytes(body)
+    if err != nil {
+        return body, name
+    }
+    return out, name
+}
+
+// detectCharset determines the encoding of an HTML body. It follows the
+// HTML sniffing order, which only looks at the first 1024 bytes. Undeclared
+// bodies that are valid UTF-8 throughout are UTF-8; for the rest it prefers
+// a legacy CJK encoding that decodes cleanly over the windows-1252 default.
+func detectCharset(body []byte, contentType string) (encoding.Encoding, string) {
+    enc, name, certain := charset.DetermineEncoding(body, contentType)
+    if certain || name == "utf-8" || declaresCharset(body) {
+        return enc, name
+    }
+    if utf8.Valid(body) {
+        return charset.Lookup("utf-8")
+    }
+    for _, c := range sniffCandidates {
+        if e, n := charset.Lookup(c.name); e != nil && decodesAs(e, body, c.scripts) {
+            return e, n
+        }
+    }
+    return enc, name
+}
+
+// declaresCharset reports whether the prescan window mentions a charset, so
+// a <meta> declaration is never overridden by sniffing.
+func declaresCharset(body []byte) bool {
+    head := body
+    if len(head) > 1024 {
+        head = head[:1024]
+    }
+    return bytes.Contains(bytes.ToLower(head), []byte("charset"))
+}
+
+// decodesAs reports whether body decodes under e without replacement
+// characters and most of its non-ASCII text falls in the given scripts.
+func decodesAs(e encoding.Encoding, body []byte, scripts []*unicode.RangeTable) bool {
+    out, err := e.NewDecoder().Bytes(body)
+    if err != nil || bytes.ContainsRune(out, utf8.RuneError) {
+        return false
+    }
+    var nonASCII, inScript int
+    for _, r := range string(out) {
+        if r < utf8.RuneSelf {
+            continue
+        }
+        nonASCII++
+        if unicode.In(r, scripts...) || unicode.Is(unicode.Common, r) {
+            inScript++
+        }
+    }
+    return nonASCII > 0 && inScript*10 >= nonASCII*8
+}
@@ -0,0 +1,80 @@
+package fetch
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "sync/atomic"
+    "testing"
+
+    "github.com/hyperifyio/goresearch/internal/cache"
+    "golang.org/x/text/encoding/japanese"
+)
+
+func TestDecodeHTML_DetectsCharset(t *testing.T) {
+    sjis, err := japanese.ShiftJIS.NewEncoder().String("<html><body><p>日本語のテキストです。文字化けしないこと。</p></body></html>")
+    if err != nil {
+        t.Fatal(err)
+    }
+    cases := []struct {
+        name        string
+        body        string
+        contentType string
+        wantCharset string
+        wantText    string
+    }{
+        {"header", "<p>Caf\xe9 cr\xe8me</p>", "text/html; charset=ISO-8859-1", "windows-1252", "Café crème"},
+        {"meta", "<html><head><meta charset=\"windows-1252\"></head><body>\x93quoted\x94</body></html>", "text/html", "windows-1252", "“quoted”"},
+        {"bom", "\xef\xbb\xbf<p>naïve</p>", "text/html; charset=iso-8859-1", "utf-8", "<p>naïve</p>"},
+        {"utf8 undeclared", "<p>Ångström</p>", "text/html", "utf-8", "Ångström"},
+        {"sniffed shift_jis", sjis, "text/html", "shift_jis", "日本語のテキスト"},
+        // UTF-8 past the 1024-byte prescan window must not fall back to windows-1252.
+        {"utf8 after prescan", "<p>" + strings.Repeat("ascii ", 200) + "</p><p>Grüße café</p>", "text/html", "utf-8", "Grüße café"},
+        {"json", `{"pad":"` + strings.Repeat("x", 1100) + `","t":"Grüße café"}`, "application/json; charset=iso-8859-1", "utf-8", "Grüße café"},
+        {"latin1 fallback", "<p>Caf\xe9 au lait, s'il vous pla\xeet</p>", "text/html", "windows-1252", "Café au lait"},
+    }
+    for _, tc := range cases {
+        out, cs := DecodeHTML([]byte(tc.body), tc.contentType, "")
+        if cs != tc.wantCharset {
+            t.Errorf("%s: charset %q, want %q", tc.name, cs, tc.wantCharset)
+        }
+        if !strings.Contains(string(out), tc.wantText) {
+            t.Errorf("%s: decoded %q, want it to contain %q", tc.name, out, tc.wantText)
+        }
+    }
+}
+
+func TestFetch_TranscodesAndRecordsCharsetInCache(t *testing.T) {
+    var calls int32
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if atomic.AddInt32(&calls, 1) > 1 && r.Header.Get("If-None-Match") == `"v1"` {
+            w.WriteHeader(http.StatusNotModified)
+            return
+        }
+        w.Header().Set("Content-Type", "text/html; charset=iso-8859-1")
+        w.Header().Set("ETag", `"v1"`)
+        _, _ = w.Write([]byte("<html><body><p>Na\xefve caf\xe9</p></body></html>"))
+    }))
+    defer srv.Close()
+
+    hc := &cache.HTTPCache{Dir: t.TempDir()}
+    c := &Client{UserAgent: "goresearch-test", MaxAttempts: 1, Cache: hc, AllowPrivateHosts: true}
+    for i := 0; i < 2; i++ {
+        res, err := c.Fetch(context.Background(), srv.URL)
+        if err != nil {
+            t.Fatalf("fetch %d: %v", i, err)
+        }
+        if res.Charset != "windows-1252" || !strings.Contains(string(res.Body), "Naïve café") {
+            t.Fatalf("fetch %d: charset %q body %q", i, res.Charset, res.Body)
+        }
+    }
+    meta, err := hc.LoadMeta(context.Background(), srv.URL)
+    if err != nil || meta.Charset != "windows-1252" {
+        t.Fatalf("expected charset in cache meta, got %+v (%v)", meta, err)
+    }
+    raw, _ := hc.LoadBody(context.Background(), srv.URL)
+    if !strings.Contains(string(raw), "Na\xefve") {
+        t.Fatalf("expected the cached body as served, got %q", raw)
+    }
+}
@@ -5,16 +5,18 @@ import (
     "context"
     "errors"
     "fmt"
-    "io"
-    "net/http"
     "net"
+    "net/http"
     "net/url"
+    "path"
     "strings"
     "sync"
     "time"
 
     "github.com/hyperifyio/goresearch/internal/cache"
+    "github.com/hyperifyio/goresearch/internal/netguard"
     "github.com/hyperifyio/goresearch/internal/robots"
+    "github.com/hyperifyio/goresearch/internal/warc"
     "golang.org/x/net/html"
 )
 
@@ -42,14 +44,73 @@ type Client struct {
 	limiter     chan struct{}
 	limiterOnce sync.Once
 
+    // MaxPerHost limits concurrent in-flight requests to the same host so a
+    // parallel caller stays as polite to each site as a serial one. Zero
+    // means unlimited. The host slot is taken before the global slot, so a
+    // busy host never holds up requests to other hosts.
+    MaxPerHost int
+
+    // Backoff is the base delay before the first retry of a transient
+    // failure; each further retry doubles it, with jitter. Zero means 200ms.
+    // A server's Retry-After on 429/503 is honored instead.
+    Backoff time.Duration
+    // MaxBackoff caps computed retry delays and the Retry-After the client
+    // will wait out; a longer Retry-After stops retrying. Zero means 30s.
+    MaxBackoff time.Duration
+    // HostRPS, when > 0, rate limits requests per host with a token bucket
+    // refilled at this many requests per second. HostBurst is the bucket
+    // size; values below 1 mean 1.
+    HostRPS   float64
+    HostBurst int
+    // GlobalRPS, when > 0, caps requests per second across all hosts.
+    GlobalRPS float64
+
+    // internal per-host limiters and rate buckets keyed by lowercase host
+    hostMu       sync.Mutex
+    hostLimiters map[string]chan struct{}
+    hostBuckets  map[string]*tokenBucket
+    globalBucket *tokenBucket
+    globalOnce   sync.Once
+
     // AllowPrivateHosts, when true, disables the "public web only" guard that
-    // rejects localhost and private IP ranges. Intended for tests only.
+    // rejects localhost and private IP ranges, both by hostname and for every
+    // address resolved at dial time. Intended for tests only.
     AllowPrivateHosts bool
 
+    // WARC, when set, records every request and response, including
+    // redirect hops and 304 revalidations, to a WARC file.
+    WARC *warc.Writer
+
+    // internal copy of HTTPClient with the guard and recorder applied,
+    // built on first use
+    clientOnce sync.Once
+    client     *http.Client
+
     // EnablePDF, when true, allows fetching and accepting application/pdf bodies.
     // The caller is responsible for choosing an appropriate extractor.
     EnablePDF bool
 
+    // AcceptTypes lists media types accepted in addition to HTML and XHTML,
+    // as path.Match patterns such as "text/plain" or "application/*+json".
+    // Text bodies of these types are transcoded to UTF-8 like HTML.
+    AcceptTypes []string
+
+    // MaxBodyBytes caps response bodies; BodyLimits overrides it for the
+    // media types they match, first match wins. Zero means
+    // DefaultMaxBodyBytes and a negative value means no limit. Bodies are
+    // streamed, so an oversized one is abandoned without being buffered.
+    MaxBodyBytes int64
+    BodyLimits   []BodyLimit
+    // MemoryBudget, when > 0, bounds the bytes buffered by concurrent
+    // downloads; a download reserves its Content-Length, or its limit when
+    // the length is unknown, and waits while the budget is spent. Under
+    // HoldBudget the reservation lasts until the caller releases it.
+    MemoryBudget int64
+
+    // internal memory budget built on first use
+    budgetOnce sync.Once
+    memBudget  *memoryBudget
+
     // Robots, when provided, enables crawl-delay compliance based on robots.txt
     // rules. The client will schedule requests per host to respect any declared
     // Crawl-delay for the most specific matching User-agent group.
@@ -135,84 +196,268 @@ func IsRobotsDenied(err error) (string, bool) {
 }
 
 func (c *Client) getHTTPClient() *http.Client {
-	if c.HTTPClient != nil {
-		// Clone to attach our redirect policy without mutating caller's client
-		base := *c.HTTPClient
-		base.CheckRedirect = c.checkRedirectFunc()
-		return &base
-	}
-	return &http.Client{Timeout: c.PerRequestTimeout, CheckRedirect: c.checkRedirectFunc()}
+	// Guard the dialer and attach the WARC recorder once so pooled
+	// connections are reused.
+	c.clientOnce.Do(func() {
+		base := c.HTTPClient
+		if base == nil {
+			base = &http.Client{Timeout: c.PerRequestTimeout}
+		}
+		if !c.AllowPrivateHosts {
+			base = netguard.Client(base)
+		}
+		c.client = warc.Client(base, c.WARC)
+	})
+	// Clone to attach our redirect policy without mutating caller's client
+	base := *c.client
+	base.CheckRedirect = c.checkRedirectFunc()
+	return &base
 }
 
+// Result is a successfully fetched response.
+type Result struct {
+    Body        []byte
+    ContentType string
+    // FinalURL is the URL that served the body after following redirects.
+    FinalURL string
+    // Retries lists the retry decisions made, also on failure.
+    Retries []Retry
+    // Throttled is the time spent waiting on rate limits.
+    Throttled time.Duration
+    // Charset is the detected source encoding of an HTML body; Body has
+    // been transcoded from it to UTF-8. Empty for non-HTML bodies.
+    Charset string
+    // WARCRecordID identifies the record of the final response when WARC
+    // recording is enabled.
+    WARCRecordID string
+    // CacheStatus says how the cache took part: CacheFresh, CacheRevalidated
+    // or CacheStale. Empty when the body came from the origin.
+    CacheStatus string
+}
+
+// Cache statuses reported in Result.CacheStatus.
+const (
+    // CacheFresh is a cached copy within its freshness lifetime, served
+    // without contacting the origin.
+    CacheFresh = "fresh"
+    // CacheRevalidated is a cached copy the origin confirmed with a 304.
+    CacheRevalidated = "revalidated"
+    // CacheStale is a cached copy served because the origin failed.
+    CacheStale = "stale"
+)
+
+// exchange records the response headers and timing of the last request made
+// by tryOnce, for cache freshness bookkeeping.
+type exchange struct {
+    header       http.Header
+    requestTime  time.Time
+    responseTime time.Time
+}
+
+// exchangeKey carries an *exchange through the request context.
+type exchangeKey struct{}
+
+// finalURLKey carries a *string through the request context so tryOnce can
+// report where redirects ended without widening its return values.
+type finalURLKey struct{}
+
+// throttleKey carries a *time.Duration accumulating rate-limit waits.
+type throttleKey struct{}
+
 // Get issues a GET with context, user-agent, and bounded retry for transient errors.
 func (c *Client) Get(ctx context.Context, url string) ([]byte, string, error) {
-	// If cache exists, attempt conditional request
+    res, err := c.Fetch(ctx, url)
+    return res.Body, res.ContentType, err
+}
+
+// Fetch behaves like Get and also reports the final URL after redirects.
+func (c *Client) Fetch(ctx context.Context, url string) (Result, error) {
+    finalURL := url
+    ctx = context.WithValue(ctx, finalURLKey{}, &finalURL)
+    var throttled time.Duration
+    ctx = context.WithValue(ctx, throttleKey{}, &throttled)
+    ctx, recorded := warc.Track(ctx)
+    var ex exchange
+    ctx = context.WithValue(ctx, exchangeKey{}, &ex)
+	// A matching cache entry is served while fresh and otherwise revalidated
+	// with its validators.
+	var entry *cache.HTTPEntry
 	var etag, lastMod string
 	if c.Cache != nil && !c.BypassCache {
-		if meta, err := c.Cache.LoadMeta(ctx, url); err == nil && meta != nil {
+		if meta, err := c.Cache.LoadMeta(ctx, url); err == nil && meta != nil && meta.MatchesVary(c.requestHeader()) {
+			entry = meta
 			etag = meta.ETag
 			lastMod = meta.LastModified
 		}
 	}
+	if entry != nil && entry.Fresh(time.Now()) {
+		if res, ok := c.fromCache(ctx, url, entry, CacheFresh); ok {
+			return res, nil
+		}
+	}
 	attempts := c.MaxAttempts
 	if attempts <= 0 {
 		attempts = 1
 	}
-	var lastErr error
-	for i := 0; i < attempts; i++ {
-		body, ct, newEtag, newLastMod, status, err := c.tryOnce(ctx, url, etag, lastMod)
+	var retries []Retry
+	for i := 0; ; i++ {
+		body, ct, _, _, status, err := c.tryOnce(ctx, url, etag, lastMod)
+		if err == nil && status == http.StatusNotModified && entry != nil {
+			if c.Cache != nil {
+				entry.Refresh(ex.header, ex.requestTime, ex.responseTime)
+				entry.FinalURL = finalURL
+				_ = c.Cache.SaveMeta(ctx, *entry)
+			}
+			if res, ok := c.fromCache(ctx, url, entry, CacheRevalidated); ok {
+				res.Retries, res.Throttled, res.WARCRecordID = retries, throttled, recorded.ResponseID()
+				return res, nil
+			}
+		}
 		if err == nil {
-			// Save/serve from cache
-			if c.Cache != nil && status == 200 {
-				_ = c.Cache.Save(ctx, url, ct, newEtag, newLastMod, body)
+			raw := body
+			// The cache keeps the body as served; only the returned copy is
+			// transcoded, using the charset recorded alongside it.
+			var cs string
+			if IsTextContentType(ct) {
+				body, cs = DecodeHTML(body, ct, cs)
 			}
-			// If 304 and cache available, return cached body
-			if status == 304 && c.Cache != nil {
-				if cached, err := c.Cache.LoadBody(ctx, url); err == nil {
-					return cached, ct, nil
+			if c.Cache != nil && status == 200 {
+				if cache.Storable(ex.header) {
+					meta := cache.NewHTTPEntry(url, ex.header, c.requestHeader(), ex.requestTime, ex.responseTime)
+					meta.Charset = cs
+					meta.FinalURL = finalURL
+					_ = c.Cache.SaveEntry(ctx, meta, raw)
+				} else {
+					// no-store: keep no copy, not even an older one.
+					_ = c.Cache.Delete(ctx, url)
 				}
 			}
-			return body, ct, nil
+			return Result{Body: body, ContentType: ct, FinalURL: finalURL, Retries: retries, Throttled: throttled, Charset: cs, WARCRecordID: recorded.ResponseID()}, nil
 		}
+		failed := Result{Retries: retries, Throttled: throttled}
 		if !isTransient(err) || i == attempts-1 {
-			return nil, "", err
+			if entry != nil && originFailed(err) && entry.UsableOnError(time.Now()) {
+				if res, ok := c.fromCache(ctx, url, entry, CacheStale); ok {
+					res.Retries, res.Throttled = retries, throttled
+					return res, nil
+				}
+			}
+			return failed, err
+		}
+		delay, fromServer, ok := c.retryDelay(i, err)
+		if !ok {
+			// The server wants us gone for longer than we are willing to wait.
+			c.backOffHost(url, c.maxBackoff())
+			return failed, err
+		}
+		if fromServer {
+			c.backOffHost(url, delay)
+		}
+		retries = append(retries, Retry{Attempt: i + 1, Status: status, Error: err.Error(), DelayMillis: delay.Milliseconds(), RetryAfter: fromServer})
+		if err := sleepCtx(ctx, delay); err != nil {
+			return Result{Retries: retries, Throttled: throttled}, err
 		}
-		lastErr = err
-		time.Sleep(time.Duration(i+1) * 200 * time.Millisecond)
-	}
-	if lastErr == nil {
-		lastErr = errors.New("unknown error")
 	}
-	return nil, "", lastErr
 }
 
-func (c *Client) tryOnce(ctx context.Context, url string, etag string, lastMod string) ([]byte, string, string, string, int, error) {
-    // Concurrency gate per client instance
-    c.acquire()
-    defer c.release()
+// fromCache returns the cached body for url, transcoded like a live
+// response. ok is false when the body is missing.
+func (c *Client) fromCache(ctx context.Context, url string, entry *cache.HTTPEntry, status string) (Result, bool) {
+    if c.Cache == nil {
+        return Result{}, false
+    }
+    // Local policy still applies to cached copies; robots.txt is not
+    // consulted again, since the copy was allowed when it was fetched.
+    if err := c.checkPolicy(url); err != nil {
+        return Result{}, false
+    }
+    body, err := c.Cache.LoadBody(ctx, url)
+    if err != nil {
+        return Result{}, false
+    }
+    ct, cs := entry.ContentType, entry.Charset
+    if IsTextContentType(ct) {
+        body, cs = DecodeHTML(body, ct, cs)
+    }
+    final := entry.FinalURL
+    if final == "" {
+        final = url
+    }
+    return Result{Body: body, ContentType: ct, FinalURL: final, Charset: cs, CacheStatus: status}, true
+}
 
-    req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, "", "", "", 0, fmt.Errorf("new request: %w", err)
-	}
+// requestHeader returns the request headers a response may Vary on.
+func (c *Client) requestHeader() http.Header {
+    h := http.Header{}
+    if c.UserAgent != "" {
+        h.Set("User-Agent", c.UserAgent)
+    }
+    return h
+}
+
+// originFailed reports whether err means the origin could not answer, as
+// opposed to answering with a refusal, so a stale copy may stand in.
+func originFailed(err error) bool {
+    if isTransient(err) {
+        return true
+    }
+    var op *net.OpError
+    var dns *net.DNSError
+    return errors.As(err, &op) || errors.As(err, &dns)
+}
+
+// checkPolicy applies the checks that need no network: scheme, embedded
+// credentials, literal private hosts and the domain allow/deny lists.
+func (c *Client) checkPolicy(rawURL string) error {
+    u, err := url.Parse(rawURL)
+    if err != nil {
+        return fmt.Errorf("new request: %w", err)
+    }
 	// Reject non-HTTP(S) schemes early
-	if req.URL == nil || !isHTTPScheme(req.URL) {
-		return nil, "", "", "", 0, fmt.Errorf("unsupported URL scheme: %q", req.URL.String())
+	if !isHTTPScheme(u) {
+		return fmt.Errorf("unsupported URL scheme: %q", u.String())
 	}
     // Reject embedded credentials to avoid authenticating to private services
-    if req.URL.User != nil {
-        return nil, "", "", "", 0, errors.New("credentials in URL unsupported")
+    if u.User != nil {
+        return errors.New("credentials in URL unsupported")
     }
     // Public web only: reject localhost and private/link-local addresses by literal IP or known names
-    host := req.URL.Hostname()
-    if !c.AllowPrivateHosts && isLocalOrPrivateHost(host) {
-        return nil, "", "", "", 0, fmt.Errorf("private host not allowed: %s", host)
+    host := u.Hostname()
+    if !c.AllowPrivateHosts {
+        if reason := netguard.HostReason(host); reason != "" {
+            return netguard.PolicyError{Host: host, IP: host, Reason: reason}
+        }
     }
-
     // Centralized domain allow/deny policy
     if blocked, reason := isDomainBlocked(host, c.DomainAllowlist, c.DomainDenylist); blocked {
-        return nil, "", "", "", 0, fmt.Errorf("domain blocked: %s (%s)", host, reason)
+        return fmt.Errorf("domain blocked: %s (%s)", host, reason)
     }
+    return nil
+}
+
+func (c *Client) tryOnce(ctx context.Context, url string, etag string, lastMod string) ([]byte, string, string, string, int, error) {
+    // Per-host gate, then the concurrency gate per client instance
+    if err := c.acquireHost(ctx, url); err != nil {
+        return nil, "", "", "", 0, err
+    }
+    defer c.releaseHost(url)
+    waited, err := c.awaitRateLimit(ctx, url)
+    if t, ok := ctx.Value(throttleKey{}).(*time.Duration); ok {
+        *t += waited
+    }
+    if err != nil {
+        return nil, "", "", "", 0, err
+    }
+    c.acquire()
+    defer c.release()
+
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", "", "", 0, fmt.Errorf("new request: %w", err)
+	}
+	if err := c.checkPolicy(url); err != nil {
+		return nil, "", "", "", 0, err
+	}
 
     // Deny-on-disallow enforcement: consult robots rules before any network fetch
     if c.Robots != nil {
@@ -257,51 +502,99 @@ func (c *Client) tryOnce(ctx context.Context, url string, etag string, lastMod s
 		req = req.WithContext(ctx)
 	}
 
+	requestTime := time.Now()
 	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, "", "", "", 0, err
 	}
 	defer resp.Body.Close()
+    if ex, ok := ctx.Value(exchangeKey{}).(*exchange); ok {
+        *ex = exchange{header: resp.Header, requestTime: requestTime, responseTime: time.Now()}
+    }
+    if final, ok := ctx.Value(finalURLKey{}).(*string); ok && resp.Request != nil && resp.Request.URL != nil {
+        *final = resp.Request.URL.String()
+    }
 
-	if resp.StatusCode >= 500 && resp.StatusCode <= 599 {
-		return nil, "", "", "", resp.StatusCode, fmt.Errorf("server error: %d", resp.StatusCode)
+	if resp.StatusCode >= 500 && resp.StatusCode <= 599 || resp.StatusCode == http.StatusTooManyRequests {
+		return nil, "", "", "", resp.StatusCode, StatusError{StatusCode: resp.StatusCode, RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())}
 	}
 	if resp.StatusCode == http.StatusNotModified {
 		// 304: no body expected; return no error with status 304
 		return nil, resp.Header.Get("Content-Type"), resp.Header.Get("ETag"), resp.Header.Get("Last-Modified"), resp.StatusCode, nil
 	}
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return nil, "", "", "", resp.StatusCode, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+		return nil, "", "", "", resp.StatusCode, StatusError{StatusCode: resp.StatusCode}
 	}
 
-    // Honor X-Robots-Tag opt-out directives for AI/TDM reuse
-    if reason, denied := detectTDMOptOut(resp.Header, c.UserAgent); denied {
-        return nil, "", "", "", resp.StatusCode, ReuseDeniedError{Reason: reason}
-    }
-    // Honor TDM reservation via HTTP Link headers (in or around content)
-    if reason, denied := detectTDMReservationLinkHeader(resp.Header); denied {
-        return nil, "", "", "", resp.StatusCode, ReuseDeniedError{Reason: reason}
+    if err := c.checkReuseHeaders(resp.Header); err != nil {
+        return nil, "", "", "", resp.StatusCode, err
     }
 
     contentType := resp.Header.Get("Content-Type")
-    if !(isAllowedHTMLContentType(contentType) || (c.EnablePDF && isAllowedPDFContentType(contentType))) {
+    if !c.accepts(contentType) {
 		return nil, "", "", "", resp.StatusCode, fmt.Errorf("unsupported content type: %s", contentType)
 	}
-	b, err := io.ReadAll(resp.Body)
+	b, err := c.readBody(ctx, req.URL.String(), contentType, resp.ContentLength, resp.Body)
 	if err != nil {
-		return nil, "", "", "", resp.StatusCode, fmt.Errorf("read body: %w", err)
+		return nil, "", "", "", resp.StatusCode, err
 	}
-    // For HTML/XHTML, also honor page-level meta robots/googlebot opt-out
-    if isAllowedHTMLContentType(contentType) {
-        if reason, denied := detectMetaTDMOptOut(b); denied {
-            return nil, "", "", "", resp.StatusCode, ReuseDeniedError{Reason: reason}
+    if err := checkReuseBody(contentType, b); err != nil {
+        return nil, "", "", "", resp.StatusCode, err
+    }
+	return b, contentType, resp.Header.Get("ETag"), resp.Header.Get("Last-Modified"), resp.StatusCode, nil
+}
+
+// checkReuseHeaders honors AI/TDM opt-outs sent as X-Robots-Tag directives
+// or as a tdm-reservation Link header.
+func (c *Client) checkReuseHeaders(h http.Header) error {
+    if reason, denied := detectTDMOptOut(h, c.UserAgent); denied {
+        return ReuseDeniedError{Reason: reason}
+    }
+    if reason, denied := detectTDMReservationLinkHeader(h); denied {
+        return ReuseDeniedError{Reason: reason}
+    }
+    return nil
+}
+
+// checkReuseBody honors page-level opt-outs in HTML: meta robots/googlebot
+// directives and <link rel="tdm-reservation"> in the document head.
+func checkReuseBody(contentType string, body []byte) error {
+    if !isAllowedHTMLContentType(contentType) {
+        return nil
+    }
+    if reason, denied := detectMetaTDMOptOut(body); denied {
+        return ReuseDeniedError{Reason: reason}
+    }
+    if reason, denied := detectHTMLTDMReservationLink(body); denied {
+        return ReuseDeniedError{Reason: reason}
+    }
+    return nil
+}
+
+// CheckRecorded applies the checks of a live fetch to a response recorded
+// elsewhere, such as in a WARC archive: the URL policy for the requested
+// and final URLs, reuse opt-outs in the headers and page, the accepted
+// content types and the body size limit. body is the raw recorded body.
+func (c *Client) CheckRecorded(rawURL, finalURL string, header http.Header, body []byte) error {
+    for _, u := range []string{rawURL, finalURL} {
+        if u == "" {
+            continue
         }
-        // And honor <link rel="tdm-reservation"> in the document head
-        if reason, denied := detectHTMLTDMReservationLink(b); denied {
-            return nil, "", "", "", resp.StatusCode, ReuseDeniedError{Reason: reason}
+        if err := c.checkPolicy(u); err != nil {
+            return err
         }
     }
-	return b, contentType, resp.Header.Get("ETag"), resp.Header.Get("Last-Modified"), resp.StatusCode, nil
+    if err := c.checkReuseHeaders(header); err != nil {
+        return err
+    }
+    ct := header.Get("Content-Type")
+    if !c.accepts(ct) {
+        return fmt.Errorf("unsupported content type: %s", ct)
+    }
+    if limit := c.BodyLimit(ct); limit >= 0 && int64(len(body)) > limit {
+        return TooLargeError{URL: rawURL, ContentType: ct, Limit: limit, Declared: int64(len(body))}
+    }
+    return checkReuseBody(ct, body)
 }
 
 // detectTDMOptOut checks X-Robots-Tag style headers for AI/TDM opt-out signals.
@@ -548,10 +841,14 @@ func (c *Client) awaitCrawlDelay(ctx context.Context, u *url.URL) error {
 }
 
 func isTransient(err error) bool {
-	// Treat HTTP 5xx and context deadline as transient.
+	// Treat HTTP 5xx, 429 and context deadline as transient.
 	if errors.Is(err, context.DeadlineExceeded) {
 		return true
 	}
+	var se StatusError
+	if errors.As(err, &se) {
+		return se.StatusCode == http.StatusTooManyRequests || se.StatusCode >= 500
+	}
 	// crude check for server error text
 	return contains(err.Error(), "server error:")
 }
@@ -616,28 +913,44 @@ func isAllowedHTMLContentType(ct string) bool {
 	return strings.HasPrefix(ct, "text/html") || strings.HasPrefix(ct, "application/xhtml+xml")
 }
 
-// isAllowedPDFContentType returns true for PDF content types.
-func isAllowedPDFContentType(ct string) bool {
-    ct = strings.ToLower(strings.TrimSpace(ct))
-    return strings.HasPrefix(ct, "application/pdf")
+// accepts reports whether a response of this content type is wanted.
+func (c *Client) accepts(ct string) bool {
+    if isAllowedHTMLContentType(ct) || (c.EnablePDF && isAllowedPDFContentType(ct)) {
+        return true
+    }
+    return matchesMediaType(ct, c.AcceptTypes)
 }
 
-// isLocalOrPrivateHost returns true for localhost names and literal IPs that are
-// loopback, private, or link-local. Hostname resolution is intentionally not
-// performed here to keep this check deterministic and side-effect free.
-func isLocalOrPrivateHost(host string) bool {
-    h := strings.ToLower(strings.TrimSpace(host))
-    if h == "localhost" || h == "localhost.localdomain" || h == "::1" || h == "[::1]" {
-        return true
+// matchesMediaType reports whether ct's media type matches any pattern.
+func matchesMediaType(ct string, patterns []string) bool {
+    mt, _, _ := strings.Cut(ct, ";")
+    mt = strings.ToLower(strings.TrimSpace(mt))
+    if mt == "" {
+        return false
     }
-    if ip := net.ParseIP(h); ip != nil {
-        if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+    for _, p := range patterns {
+        if ok, _ := path.Match(strings.ToLower(strings.TrimSpace(p)), mt); ok {
             return true
         }
     }
     return false
 }
 
+// IsTextContentType reports whether a body is text whose charset should be
+// normalized: HTML, text/*, and XML or JSON formats.
+func IsTextContentType(ct string) bool {
+    if isAllowedHTMLContentType(ct) {
+        return true
+    }
+    return matchesMediaType(ct, []string{"text/*", "application/json", "application/*+json", "application/xml", "application/*+xml"})
+}
+
+// isAllowedPDFContentType returns true for PDF content types.
+func isAllowedPDFContentType(ct string) bool {
+    ct = strings.ToLower(strings.TrimSpace(ct))
+    return strings.HasPrefix(ct, "application/pdf")
+}
+
 // isDomainBlocked evaluates host against denylist and allowlist.
 // - If host matches any deny entry (exact or as a subdomain), returns (true, "denylist").
 // - Else if allowlist is non-empty and host does not match any allow entry, returns (true, "not-allowed").
@@ -683,6 +996,54 @@ func (c *Client) acquire() {
 	c.limiter <- struct{}{}
 }
 
+// hostLimiter returns the per-host semaphore for rawURL, or nil when per-host
+// limiting is disabled or the URL has no host.
+func (c *Client) hostLimiter(rawURL string) chan struct{} {
+	if c.MaxPerHost <= 0 {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return nil
+	}
+	host := strings.ToLower(u.Hostname())
+	c.hostMu.Lock()
+	defer c.hostMu.Unlock()
+	if c.hostLimiters == nil {
+		c.hostLimiters = make(map[string]chan struct{})
+	}
+	l, ok := c.hostLimiters[host]
+	if !ok {
+		l = make(chan struct{}, c.MaxPerHost)
+		c.hostLimiters[host] = l
+	}
+	return l
+}
+
+func (c *Client) acquireHost(ctx context.Context, rawURL string) error {
+	l := c.hostLimiter(rawURL)
+	if l == nil {
+		return nil
+	}
+	select {
+	case l <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Client) releaseHost(rawURL string) {
+	l := c.hostLimiter(rawURL)
+	if l == nil {
+		return
+	}
+	select {
+	case <-l:
+	default:
+	}
+}
+
 func (c *Client) release() {
 	if c.MaxConcurrent <= 0 || c.limiter == nil {
 		return
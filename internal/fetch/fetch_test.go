@@ -2,10 +2,12 @@ package fetch
 
 import (
     "context"
+    "errors"
     "fmt"
     "io"
     "net/http"
     "net/http/httptest"
+    "path/filepath"
     "strings"
     "sync"
     "sync/atomic"
@@ -14,6 +16,7 @@ import (
 
     "github.com/hyperifyio/goresearch/internal/cache"
     "github.com/hyperifyio/goresearch/internal/robots"
+    "github.com/hyperifyio/goresearch/internal/warc"
 )
 
 func TestRejectsCredentialsInURL(t *testing.T) {
@@ -305,6 +308,27 @@ func TestGet_RedirectLimit(t *testing.T) {
 	}
 }
 
+func TestFetch_ReportsFinalURLAfterRedirect(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+    c := &Client{UserAgent: "goresearch-test", MaxAttempts: 1, PerRequestTimeout: 2 * time.Second, RedirectMaxHops: 3, AllowPrivateHosts: true}
+	res, err := c.Fetch(context.Background(), srv.URL+"/old")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.FinalURL != srv.URL+"/new" || string(res.Body) != "ok" {
+		t.Fatalf("unexpected result: final=%q body=%q", res.FinalURL, res.Body)
+	}
+}
+
 func TestGet_MaxConcurrent(t *testing.T) {
 	var inFlight int32
 	var maxObserved int32
@@ -348,6 +372,53 @@ func TestGet_MaxConcurrent(t *testing.T) {
 	}
 }
 
+func TestGet_MaxPerHost(t *testing.T) {
+	var mu sync.Mutex
+	inFlight := map[string]int{}
+	maxPerHost, maxTotal, total := 0, 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := strings.Split(r.Host, ":")[0]
+		mu.Lock()
+		inFlight[host]++
+		total++
+		if inFlight[host] > maxPerHost {
+			maxPerHost = inFlight[host]
+		}
+		if total > maxTotal {
+			maxTotal = total
+		}
+		mu.Unlock()
+		time.Sleep(100 * time.Millisecond)
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("ok"))
+		mu.Lock()
+		inFlight[host]--
+		total--
+		mu.Unlock()
+	}))
+	defer srv.Close()
+	port := srv.URL[strings.LastIndex(srv.URL, ":"):]
+
+    c := &Client{UserAgent: "goresearch-test", MaxAttempts: 1, PerRequestTimeout: 2 * time.Second, MaxPerHost: 1, AllowPrivateHosts: true}
+
+	var wg sync.WaitGroup
+	for _, host := range []string{"127.0.0.1", "127.0.0.1", "localhost", "localhost"} {
+		wg.Add(1)
+		go func(u string) {
+			defer wg.Done()
+			_, _, _ = c.Get(context.Background(), u)
+		}("http://" + host + port + "/")
+	}
+	wg.Wait()
+
+	if maxPerHost != 1 {
+		t.Fatalf("expected one request per host at a time, got %d", maxPerHost)
+	}
+	if maxTotal != 2 {
+		t.Fatalf("expected different hosts to be fetched in parallel, got %d in flight", maxTotal)
+	}
+}
+
 func TestGet_RespectsCrawlDelayPerHost(t *testing.T) {
     t.Parallel()
     // Single server serves both content and robots.txt with Crawl-delay
@@ -453,3 +524,304 @@ func TestRedirect_ShortCircuitOnRobotsDisallow(t *testing.T) {
         t.Fatalf("expected IsRobotsDenied for redirect, got ok=%v reason=%q err=%v", ok, reason, err)
     }
 }
+
+func TestFetch_HonorsRetryAfterAndRecordsRetries(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	c := &Client{UserAgent: "goresearch-test", MaxAttempts: 3, Backoff: time.Millisecond, AllowPrivateHosts: true}
+	start := time.Now()
+	res, err := c.Fetch(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("expected to wait out Retry-After, took %s", elapsed)
+	}
+	if len(res.Retries) != 1 || !res.Retries[0].RetryAfter || res.Retries[0].Status != 429 || res.Retries[0].DelayMillis != 1000 {
+		t.Fatalf("unexpected retries: %+v", res.Retries)
+	}
+}
+
+func TestFetch_GivesUpOnLongRetryAfter(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := &Client{UserAgent: "goresearch-test", MaxAttempts: 3, MaxBackoff: time.Second, AllowPrivateHosts: true}
+	_, err := c.Fetch(context.Background(), srv.URL)
+	var se StatusError
+	if !errors.As(err, &se) || se.StatusCode != 503 || se.RetryAfter != time.Hour {
+		t.Fatalf("expected 503 status error with Retry-After, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected no retry beyond MaxBackoff, got %d calls", calls)
+	}
+}
+
+func TestFetch_HostRateLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	c := &Client{UserAgent: "goresearch-test", MaxAttempts: 1, HostRPS: 10, HostBurst: 1, AllowPrivateHosts: true}
+	start := time.Now()
+	var throttled time.Duration
+	for i := 0; i < 3; i++ {
+		res, err := c.Fetch(context.Background(), srv.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		throttled += res.Throttled
+	}
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Fatalf("expected 10 rps with burst 1 to space three requests, took %s", elapsed)
+	}
+	if throttled < 150*time.Millisecond {
+		t.Fatalf("expected throttling to be reported, got %s", throttled)
+	}
+}
+
+func TestFetch_GlobalRateLimitAcrossHosts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+	port := srv.URL[strings.LastIndex(srv.URL, ":"):]
+
+	c := &Client{UserAgent: "goresearch-test", MaxAttempts: 1, GlobalRPS: 5, AllowPrivateHosts: true}
+	start := time.Now()
+	// The first five fit the one-second burst; the sixth waits for a token.
+	for i := 0; i < 6; i++ {
+		host := "127.0.0.1"
+		if i%2 == 1 {
+			host = "localhost"
+		}
+		if _, err := c.Fetch(context.Background(), "http://"+host+port+"/"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Fatalf("expected the global cap to delay the sixth request, took %s", elapsed)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := map[string]time.Duration{
+		"120":                           2 * time.Minute,
+		"Mon, 01 Jan 2024 00:00:30 GMT": 30 * time.Second,
+		"Sun, 31 Dec 2023 23:00:00 GMT": 0,
+		"-5":                            0,
+		"soon":                          0,
+		"":                              0,
+	}
+	for in, want := range cases {
+		if got := parseRetryAfter(in, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestRetryDelay_ExponentialWithJitter(t *testing.T) {
+	c := &Client{Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for attempt, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		d, fromServer, ok := c.retryDelay(attempt, errors.New("server error: 502"))
+		if !ok || fromServer || d < want/2 || d > want {
+			t.Fatalf("attempt %d: delay %s outside [%s, %s]", attempt, d, want/2, want)
+		}
+	}
+}
+
+func TestFetch_LinksWARCResponseRecord(t *testing.T) {
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "text/html; charset=utf-8")
+        _, _ = w.Write([]byte("<html>ok</html>"))
+    }))
+    defer srv.Close()
+
+    w, err := warc.Create(filepath.Join(t.TempDir(), "fetch.warc.gz"), "goresearch-test")
+    if err != nil {
+        t.Fatalf("create: %v", err)
+    }
+    defer w.Close()
+    c := &Client{UserAgent: "goresearch-test", MaxAttempts: 1, PerRequestTimeout: 2 * time.Second, AllowPrivateHosts: true, WARC: w}
+    res, err := c.Fetch(context.Background(), srv.URL)
+    if err != nil {
+        t.Fatalf("fetch: %v", err)
+    }
+    if !strings.HasPrefix(res.WARCRecordID, "<urn:uuid:") {
+        t.Fatalf("expected a WARC record id, got %q", res.WARCRecordID)
+    }
+}
+
+func TestFetch_ServesFreshCacheWithoutRequest(t *testing.T) {
+    var calls int32
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        atomic.AddInt32(&calls, 1)
+        w.Header().Set("Content-Type", "text/html; charset=utf-8")
+        w.Header().Set("Cache-Control", "max-age=3600")
+        _, _ = w.Write([]byte("<html>cached</html>"))
+    }))
+    defer srv.Close()
+    c := &Client{UserAgent: "goresearch-test", MaxAttempts: 1, PerRequestTimeout: 2 * time.Second, Cache: &cache.HTTPCache{Dir: t.TempDir()}, AllowPrivateHosts: true}
+
+    if res, err := c.Fetch(context.Background(), srv.URL); err != nil || res.CacheStatus != "" {
+        t.Fatalf("first fetch: %v %q", err, res.CacheStatus)
+    }
+    res, err := c.Fetch(context.Background(), srv.URL)
+    if err != nil {
+        t.Fatalf("second fetch: %v", err)
+    }
+    if res.CacheStatus != CacheFresh || string(res.Body) != "<html>cached</html>" || res.FinalURL != srv.URL {
+        t.Fatalf("expected a fresh cache hit, got %q %q %q", res.CacheStatus, res.Body, res.FinalURL)
+    }
+    if n := atomic.LoadInt32(&calls); n != 1 {
+        t.Fatalf("fresh entry should not hit the network; calls=%d", n)
+    }
+}
+
+func TestFetch_NoStoreIsNeverPersisted(t *testing.T) {
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "text/html")
+        w.Header().Set("Cache-Control", "no-store")
+        w.Header().Set("ETag", `"v1"`)
+        _, _ = w.Write([]byte("<html>secret</html>"))
+    }))
+    defer srv.Close()
+    hc := &cache.HTTPCache{Dir: t.TempDir()}
+    // An older copy must go too once the origin says no-store.
+    if err := hc.Save(context.Background(), srv.URL, "text/html", `"v0"`, "", []byte("old")); err != nil {
+        t.Fatalf("seed: %v", err)
+    }
+    c := &Client{UserAgent: "goresearch-test", MaxAttempts: 1, PerRequestTimeout: 2 * time.Second, Cache: hc, AllowPrivateHosts: true}
+    if _, err := c.Fetch(context.Background(), srv.URL); err != nil {
+        t.Fatalf("fetch: %v", err)
+    }
+    if _, err := hc.LoadBody(context.Background(), srv.URL); err == nil {
+        t.Fatalf("no-store response must not be cached")
+    }
+}
+
+func TestFetch_RevalidatesAndRefreshesFreshness(t *testing.T) {
+    var calls int32
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        atomic.AddInt32(&calls, 1)
+        w.Header().Set("ETag", `"v1"`)
+        if r.Header.Get("If-None-Match") == `"v1"` {
+            // The 304 grants a lifetime the original response lacked.
+            w.Header().Set("Cache-Control", "max-age=3600")
+            w.WriteHeader(http.StatusNotModified)
+            return
+        }
+        w.Header().Set("Content-Type", "text/html")
+        w.Header().Set("Cache-Control", "no-cache")
+        _, _ = w.Write([]byte("<html>v1</html>"))
+    }))
+    defer srv.Close()
+    c := &Client{UserAgent: "goresearch-test", MaxAttempts: 1, PerRequestTimeout: 2 * time.Second, Cache: &cache.HTTPCache{Dir: t.TempDir()}, AllowPrivateHosts: true}
+
+    var statuses []string
+    for i := 0; i < 3; i++ {
+        res, err := c.Fetch(context.Background(), srv.URL)
+        if err != nil || string(res.Body) != "<html>v1</html>" {
+            t.Fatalf("fetch %d: %v %q", i, err, res.Body)
+        }
+        statuses = append(statuses, res.CacheStatus)
+    }
+    if got := strings.Join(statuses, ","); got != ",revalidated,fresh" {
+        t.Fatalf("cache statuses = %q", got)
+    }
+    if n := atomic.LoadInt32(&calls); n != 2 {
+        t.Fatalf("expected 2 requests, got %d", n)
+    }
+}
+
+func TestFetch_StaleIfErrorFallback(t *testing.T) {
+    for _, tc := range []struct {
+        cacheControl string
+        wantStale    bool
+    }{
+        {"max-age=0", true},
+        {"max-age=0, must-revalidate", false},
+    } {
+        var fail atomic.Bool
+        srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+            if fail.Load() {
+                w.WriteHeader(http.StatusServiceUnavailable)
+                return
+            }
+            w.Header().Set("Content-Type", "text/html")
+            w.Header().Set("Cache-Control", tc.cacheControl)
+            _, _ = w.Write([]byte("<html>last good</html>"))
+        }))
+        c := &Client{UserAgent: "goresearch-test", MaxAttempts: 2, Backoff: time.Millisecond, PerRequestTimeout: 2 * time.Second, Cache: &cache.HTTPCache{Dir: t.TempDir()}, AllowPrivateHosts: true}
+        if _, err := c.Fetch(context.Background(), srv.URL); err != nil {
+            t.Fatalf("%s: first fetch: %v", tc.cacheControl, err)
+        }
+        fail.Store(true)
+        res, err := c.Fetch(context.Background(), srv.URL)
+        srv.Close()
+        if !tc.wantStale {
+            if err == nil {
+                t.Fatalf("%s: expected the origin error, got %q", tc.cacheControl, res.CacheStatus)
+            }
+            continue
+        }
+        if err != nil || res.CacheStatus != CacheStale || string(res.Body) != "<html>last good</html>" {
+            t.Fatalf("%s: expected stale fallback, got %v %q %q", tc.cacheControl, err, res.CacheStatus, res.Body)
+        }
+        if len(res.Retries) != 1 {
+            t.Fatalf("%s: retries before the fallback should be kept: %+v", tc.cacheControl, res.Retries)
+        }
+    }
+}
+
+func TestGet_AcceptTypesWidenContentTypeGating(t *testing.T) {
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/rfc.txt":
+            w.Header().Set("Content-Type", "text/plain; charset=iso-8859-1")
+            _, _ = w.Write([]byte("Caf\xe9"))
+        case "/feed":
+            w.Header().Set("Content-Type", "application/atom+xml")
+            _, _ = io.WriteString(w, "<feed/>")
+        default:
+            w.Header().Set("Content-Type", "image/png")
+            _, _ = w.Write([]byte{0x89, 'P', 'N', 'G'})
+        }
+    }))
+    defer srv.Close()
+
+    c := &Client{UserAgent: "goresearch-test", MaxAttempts: 1, PerRequestTimeout: 2 * time.Second, AllowPrivateHosts: true}
+    if _, _, err := c.Get(context.Background(), srv.URL+"/rfc.txt"); err == nil {
+        t.Fatalf("text/plain should be rejected without AcceptTypes")
+    }
+    c.AcceptTypes = []string{"text/plain", "application/*+xml"}
+    body, _, err := c.Get(context.Background(), srv.URL+"/rfc.txt")
+    if err != nil || string(body) != "Café" {
+        t.Fatalf("expected transcoded text body, got %q err=%v", body, err)
+    }
+    if _, _, err := c.Get(context.Background(), srv.URL+"/feed"); err != nil {
+        t.Fatalf("pattern should accept atom: %v", err)
+    }
+    if _, _, err := c.Get(context.Background(), srv.URL+"/image"); err == nil {
+        t.Fatalf("unlisted types stay rejected")
+    }
+}
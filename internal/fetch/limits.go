@@ -0,0 +1,292 @@
+package fetch
+
+import (
+    "bytes"
+    "context"
+    "errors"
+    "fmt"
+    "io"
+    "path"
+    "sort"
+    "strconv"
+    "strings"
+    "sync"
+)
+
+// DefaultMaxBodyBytes is the body size limit when Client.MaxBodyBytes is
+// zero.
+const DefaultMaxBodyBytes int64 = 10 << 20
+
+// BodyLimit caps the body size of responses whose media type matches
+// Pattern, a path.Match pattern such as "application/pdf" or "text/*".
+type BodyLimit struct {
+    Pattern  string
+    MaxBytes int64
+}
+
+// TooLargeError is returned when a response body exceeds its size limit.
+// Declared is the Content-Length when the server sent one that was already
+// over the limit; otherwise the download was aborted once Limit bytes had
+// been read.
+type TooLargeError struct {
+    URL         string
+    ContentType string
+    Limit       int64
+    Declared    int64
+}
+
+func (e TooLargeError) Error() string {
+    mt, _, _ := strings.Cut(e.ContentType, ";")
+    mt = strings.TrimSpace(mt)
+    if mt == "" {
+        mt = "unknown type"
+    }
+    if e.Declared > 0 {
+        return fmt.Sprintf("too large: Content-Length %d exceeds the %d-byte limit for %s", e.Declared, e.Limit, mt)
+    }
+    return fmt.Sprintf("too large: body exceeds the %d-byte limit for %s", e.Limit, mt)
+}
+
+// IsTooLarge reports whether err was caused by a body size limit and
+// returns a short reason suitable for a skipped-source entry.
+func IsTooLarge(err error) (string, bool) {
+    var tl TooLargeError
+    if errors.As(err, &tl) {
+        return tl.Error(), true
+    }
+    return "", false
+}
+
+// BodyLimit returns the body size limit for a content type: the first
+// matching entry of BodyLimits, else MaxBodyBytes, else
+// DefaultMaxBodyBytes. A negative result means unlimited.
+func (c *Client) BodyLimit(contentType string) int64 {
+    for _, l := range c.BodyLimits {
+        if matchesMediaType(contentType, []string{l.Pattern}) {
+            return l.MaxBytes
+        }
+    }
+    if c.MaxBodyBytes != 0 {
+        return c.MaxBodyBytes
+    }
+    return DefaultMaxBodyBytes
+}
+
+// readBody reads a response body while enforcing the limit for its content
+// type. A Content-Length over the limit fails before anything is read, and
+// a body without one is aborted as soon as it passes the limit. The bytes
+// being downloaded are reserved from the memory budget while reading, and
+// past the read when ctx carries a HoldBudget hold.
+func (c *Client) readBody(ctx context.Context, url, contentType string, declared int64, body io.Reader) ([]byte, error) {
+    limit := c.BodyLimit(contentType)
+    if limit >= 0 && declared > limit {
+        return nil, TooLargeError{URL: url, ContentType: contentType, Limit: limit, Declared: declared}
+    }
+    reserve := limit
+    if declared >= 0 {
+        reserve = declared
+    }
+    release, err := c.budget().acquire(ctx, reserve)
+    if err != nil {
+        return nil, err
+    }
+    kept := false
+    defer func() {
+        if !kept {
+            release()
+        }
+    }()
+    r := body
+    if limit >= 0 {
+        // One byte past the limit tells an exact fit from an overrun.
+        r = io.LimitReader(body, limit+1)
+    }
+    var buf bytes.Buffer
+    if declared > 0 {
+        buf.Grow(int(declared))
+    }
+    if _, err := buf.ReadFrom(r); err != nil {
+        return nil, fmt.Errorf("read body: %w", err)
+    }
+    if limit >= 0 && int64(buf.Len()) > limit {
+        return nil, TooLargeError{URL: url, ContentType: contentType, Limit: limit}
+    }
+    if h, ok := ctx.Value(holdKey{}).(*budgetHold); ok {
+        kept = h.keep(release)
+    }
+    return buf.Bytes(), nil
+}
+
+// holdKey carries a *budgetHold through the request context.
+type holdKey struct{}
+
+// budgetHold collects the memory reservations of bodies read under it.
+type budgetHold struct {
+    mu       sync.Mutex
+    done     bool
+    releases []func()
+}
+
+// HoldBudget returns a context under which the memory reserved for a body
+// stays reserved after Fetch returns, until release is called. Callers that
+// go on to transcode, cache and extract the body use it so that work counts
+// against the budget too. Every body read under the hold stays reserved,
+// so release it before fetching the next source.
+func HoldBudget(ctx context.Context) (context.Context, func()) {
+    h := &budgetHold{}
+    return context.WithValue(ctx, holdKey{}, h), h.release
+}
+
+// keep takes over release; it reports false when the hold was already
+// released, leaving release to the caller.
+func (h *budgetHold) keep(release func()) bool {
+    h.mu.Lock()
+    defer h.mu.Unlock()
+    if h.done {
+        return false
+    }
+    h.releases = append(h.releases, release)
+    return true
+}
+
+func (h *budgetHold) release() {
+    h.mu.Lock()
+    releases := h.releases
+    h.releases, h.done = nil, true
+    h.mu.Unlock()
+    for _, r := range releases {
+        r()
+    }
+}
+
+func (c *Client) budget() *memoryBudget {
+    c.budgetOnce.Do(func() {
+        c.memBudget = &memoryBudget{total: c.MemoryBudget}
+    })
+    return c.memBudget
+}
+
+// memoryBudget bounds the bytes buffered by concurrent downloads. Requests
+// larger than the whole budget wait until it is empty and then take all of
+// it, so one oversized body cannot deadlock the client.
+type memoryBudget struct {
+    mu      sync.Mutex
+    total   int64
+    used    int64
+    changed chan struct{}
+}
+
+func (b *memoryBudget) acquire(ctx context.Context, n int64) (func(), error) {
+    if b.total <= 0 {
+        return func() {}, nil
+    }
+    if n == 0 {
+        return func() {}, nil
+    }
+    if n < 0 || n > b.total {
+        // No size known and no limit, or more than the whole budget.
+        n = b.total
+    }
+    for {
+        b.mu.Lock()
+        if b.used+n <= b.total {
+            b.used += n
+            b.mu.Unlock()
+            return func() { b.release(n) }, nil
+        }
+        if b.changed == nil {
+            b.changed = make(chan struct{})
+        }
+        ch := b.changed
+        b.mu.Unlock()
+        select {
+        case <-ctx.Done():
+            return nil, ctx.Err()
+        case <-ch:
+        }
+    }
+}
+
+func (b *memoryBudget) release(n int64) {
+    b.mu.Lock()
+    b.used -= n
+    if b.changed != nil {
+        close(b.changed)
+        b.changed = nil
+    }
+    b.mu.Unlock()
+}
+
+// ParseSize parses a byte count such as "10485760", "512KB", "10MiB" or
+// "1GB". Decimal suffixes are powers of 1000 and binary ones powers of
+// 1024; "-1" and "none" mean no limit.
+func ParseSize(s string) (int64, error) {
+    s = strings.TrimSpace(s)
+    if strings.EqualFold(s, "none") || s == "-1" {
+        return -1, nil
+    }
+    i := len(s)
+    for i > 0 && (s[i-1] < '0' || s[i-1] > '9') {
+        i--
+    }
+    num, unit := strings.TrimSpace(s[:i]), strings.ToLower(strings.TrimSpace(s[i:]))
+    mult := map[string]int64{
+        "": 1, "b": 1,
+        "kb": 1e3, "mb": 1e6, "gb": 1e9,
+        "kib": 1 << 10, "mib": 1 << 20, "gib": 1 << 30,
+    }[unit]
+    n, err := strconv.ParseInt(num, 10, 64)
+    if err != nil || n < 0 || mult == 0 {
+        return 0, fmt.Errorf("size %q: want a byte count such as 10MiB, 512KB or none", s)
+    }
+    if n > (1<<62)/mult {
+        return 0, fmt.Errorf("size %q: too large", s)
+    }
+    return n * mult, nil
+}
+
+// ParseBodyLimits parses per-type limits given as <pattern>=<size>,
+// comma-separated, e.g. "application/pdf=20MiB,text/*=2MiB".
+func ParseBodyLimits(spec string) (map[string]int64, error) {
+    out := map[string]int64{}
+    for _, part := range strings.Split(spec, ",") {
+        part = strings.TrimSpace(part)
+        if part == "" {
+            continue
+        }
+        pattern, size, ok := strings.Cut(part, "=")
+        pattern = strings.ToLower(strings.TrimSpace(pattern))
+        if !ok || pattern == "" {
+            return nil, fmt.Errorf("body limit %q: want <type>=<size>", part)
+        }
+        if _, err := path.Match(pattern, ""); err != nil {
+            return nil, fmt.Errorf("body limit %q: %w", part, err)
+        }
+        n, err := ParseSize(size)
+        if err != nil {
+            return nil, fmt.Errorf("body limit %q: %w", part, err)
+        }
+        out[pattern] = n
+    }
+    return out, nil
+}
+
+// SortBodyLimits orders per-type limits so exact media types are matched
+// before patterns and narrower patterns before broader ones.
+func SortBodyLimits(m map[string]int64) []BodyLimit {
+    out := make([]BodyLimit, 0, len(m))
+    for p, n := range m {
+        out = append(out, BodyLimit{Pattern: p, MaxBytes: n})
+    }
+    sort.Slice(out, func(i, j int) bool {
+        wi, wj := strings.Contains(out[i].Pattern, "*"), strings.Contains(out[j].Pattern, "*")
+        if wi != wj {
+            return !wi
+        }
+        if len(out[i].Pattern) != len(out[j].Pattern) {
+            return len(out[i].Pattern) > len(out[j].Pattern)
+        }
+        return out[i].Pattern < out[j].Pattern
+    })
+    return out
+}
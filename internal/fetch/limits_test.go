@@ -0,0 +1,213 @@
+package fetch
+
+import (
+    "compress/gzip"
+    "context"
+    "errors"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "path/filepath"
+    "strings"
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "github.com/hyperifyio/goresearch/internal/warc"
+)
+
+func TestFetch_EnforcesBodyLimitsPerContentType(t *testing.T) {
+    var streamed atomic.Int64
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/big.pdf":
+            // Declared size over the limit: refused before reading.
+            w.Header().Set("Content-Type", "application/pdf")
+            w.Header().Set("Content-Length", "4096")
+            _, _ = w.Write([]byte(strings.Repeat("x", 4096)))
+        case "/stream":
+            // No Content-Length: aborted once past the limit.
+            w.Header().Set("Content-Type", "text/html")
+            f := w.(http.Flusher)
+            for i := 0; i < 64; i++ {
+                n, err := w.Write([]byte(strings.Repeat("y", 1024)))
+                streamed.Add(int64(n))
+                if err != nil {
+                    return
+                }
+                f.Flush()
+            }
+        default:
+            w.Header().Set("Content-Type", "text/html")
+            _, _ = w.Write([]byte("<p>" + strings.Repeat("z", 1500) + "</p>"))
+        }
+    }))
+    defer srv.Close()
+
+    c := &Client{UserAgent: "goresearch-test", MaxAttempts: 3, Backoff: time.Millisecond, PerRequestTimeout: 2 * time.Second, AllowPrivateHosts: true, EnablePDF: true,
+        MaxBodyBytes: 2048, BodyLimits: []BodyLimit{{Pattern: "application/pdf", MaxBytes: 1024}}}
+    _, err := c.Fetch(context.Background(), srv.URL+"/big.pdf")
+    var tl TooLargeError
+    if !errors.As(err, &tl) || tl.Declared != 4096 || tl.Limit != 1024 {
+        t.Fatalf("expected declared-size rejection with the PDF limit, got %v", err)
+    }
+    if reason, ok := IsTooLarge(err); !ok || !strings.Contains(reason, "application/pdf") {
+        t.Fatalf("unexpected skip reason %q", reason)
+    }
+    res, err := c.Fetch(context.Background(), srv.URL+"/stream")
+    if !errors.As(err, &tl) || tl.Declared != 0 || tl.Limit != 2048 {
+        t.Fatalf("expected streaming abort at the default limit, got %v", err)
+    }
+    if len(res.Retries) != 0 {
+        t.Fatalf("too large is not transient, got retries %+v", res.Retries)
+    }
+    if _, err := c.Fetch(context.Background(), srv.URL+"/small"); err != nil {
+        t.Fatalf("body under the limit should pass: %v", err)
+    }
+}
+
+// Test WARC recording buffers only what the body limit lets through and
+// marks the record truncated.
+func TestFetch_BodyLimitAppliesBeforeWARCRecording(t *testing.T) {
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/pdf")
+        f := w.(http.Flusher)
+        for i := 0; i < 1024; i++ {
+            if _, err := w.Write([]byte(strings.Repeat("p", 1024))); err != nil {
+                return
+            }
+            f.Flush()
+        }
+    }))
+    defer srv.Close()
+
+    path := filepath.Join(t.TempDir(), "fetch.warc.gz")
+    w, err := warc.Create(path, "goresearch-test")
+    if err != nil {
+        t.Fatalf("create: %v", err)
+    }
+    c := &Client{UserAgent: "goresearch-test", MaxAttempts: 1, PerRequestTimeout: 2 * time.Second, AllowPrivateHosts: true, EnablePDF: true,
+        BodyLimits: []BodyLimit{{Pattern: "application/pdf", MaxBytes: 2048}}, WARC: w}
+    if _, err := c.Fetch(context.Background(), srv.URL); !errors.As(err, &TooLargeError{}) {
+        t.Fatalf("expected the body limit to apply with WARC on, got %v", err)
+    }
+    if err := w.Close(); err != nil {
+        t.Fatalf("close: %v", err)
+    }
+    f, err := os.Open(path)
+    if err != nil {
+        t.Fatalf("open: %v", err)
+    }
+    defer f.Close()
+    gz, err := gzip.NewReader(f)
+    if err != nil {
+        t.Fatalf("gzip: %v", err)
+    }
+    raw, _ := io.ReadAll(gz)
+    if !strings.Contains(string(raw), "WARC-Truncated: length") || len(raw) > 16<<10 {
+        t.Fatalf("expected a small truncated record, got %d bytes", len(raw))
+    }
+}
+
+func TestFetch_HoldBudgetKeepsReservationUntilReleased(t *testing.T) {
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "text/html")
+        _, _ = w.Write([]byte(strings.Repeat("h", 80)))
+    }))
+    defer srv.Close()
+
+    c := &Client{UserAgent: "goresearch-test", MaxAttempts: 1, PerRequestTimeout: 2 * time.Second, AllowPrivateHosts: true, MemoryBudget: 100}
+    held, release := HoldBudget(context.Background())
+    if _, err := c.Fetch(held, srv.URL); err != nil {
+        t.Fatal(err)
+    }
+    done := make(chan error, 1)
+    go func() {
+        _, err := c.Fetch(context.Background(), srv.URL)
+        done <- err
+    }()
+    select {
+    case err := <-done:
+        t.Fatalf("second fetch should wait while the first body is held, got %v", err)
+    case <-time.After(50 * time.Millisecond):
+    }
+    release()
+    select {
+    case err := <-done:
+        if err != nil {
+            t.Fatal(err)
+        }
+    case <-time.After(2 * time.Second):
+        t.Fatalf("second fetch should proceed once the hold is released")
+    }
+    release()
+}
+
+func TestMemoryBudget_WaitsForRelease(t *testing.T) {
+    b := &memoryBudget{total: 100}
+    release, err := b.acquire(context.Background(), 80)
+    if err != nil {
+        t.Fatal(err)
+    }
+    got := make(chan struct{})
+    go func() {
+        r, err := b.acquire(context.Background(), 50)
+        if err == nil {
+            r()
+        }
+        close(got)
+    }()
+    select {
+    case <-got:
+        t.Fatalf("second reservation should wait while the budget is spent")
+    case <-time.After(20 * time.Millisecond):
+    }
+    release()
+    select {
+    case <-got:
+    case <-time.After(time.Second):
+        t.Fatalf("second reservation should proceed after release")
+    }
+    // A reservation over the whole budget takes all of it instead of
+    // waiting forever, and a cancelled wait gives up.
+    all, err := b.acquire(context.Background(), 1000)
+    if err != nil {
+        t.Fatal(err)
+    }
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+    if _, err := b.acquire(ctx, 1); !errors.Is(err, context.Canceled) {
+        t.Fatalf("expected cancellation, got %v", err)
+    }
+    all()
+}
+
+func TestParseSizeAndBodyLimits(t *testing.T) {
+    cases := map[string]int64{"1024": 1024, "512KB": 512000, "10MiB": 10 << 20, " 2 GB ": 2e9, "none": -1, "-1": -1}
+    for in, want := range cases {
+        if got, err := ParseSize(in); err != nil || got != want {
+            t.Errorf("ParseSize(%q) = %d, %v; want %d", in, got, err, want)
+        }
+    }
+    for _, bad := range []string{"", "ten", "5XB", "-5MB"} {
+        if _, err := ParseSize(bad); err == nil {
+            t.Errorf("ParseSize(%q) should fail", bad)
+        }
+    }
+    m, err := ParseBodyLimits("text/*=1MiB, application/pdf=20MiB,application/*+xml=2MiB")
+    if err != nil {
+        t.Fatal(err)
+    }
+    got := SortBodyLimits(m)
+    if len(got) != 3 || got[0].Pattern != "application/pdf" || got[1].Pattern != "application/*+xml" || got[2].Pattern != "text/*" {
+        t.Fatalf("unexpected order %+v", got)
+    }
+    c := &Client{BodyLimits: got}
+    if c.BodyLimit("application/pdf") != 20<<20 || c.BodyLimit("text/plain; charset=utf-8") != 1<<20 || c.BodyLimit("image/png") != DefaultMaxBodyBytes {
+        t.Fatalf("unexpected limits lookup")
+    }
+    if _, err := ParseBodyLimits("application/pdf"); err == nil {
+        t.Fatalf("missing size should fail")
+    }
+}
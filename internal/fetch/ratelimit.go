@@ -0,0 +1,115 @@
+package fetch
+
+import (
+    "context"
+    "math"
+    "net/url"
+    "strings"
+    "sync"
+    "time"
+)
+
+// tokenBucket is a reservation-based token bucket. A zero rate disables
+// token accounting so the bucket only enforces holds, which are set when a
+// server asks the client to back off via Retry-After.
+type tokenBucket struct {
+    mu     sync.Mutex
+    rate   float64 // tokens per second
+    burst  float64
+    tokens float64
+    last   time.Time
+    hold   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+    if burst < 1 {
+        burst = 1
+    }
+    return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// reserve takes a token and returns how long the caller must wait before
+// using it.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    var wait time.Duration
+    if b.rate > 0 {
+        if !b.last.IsZero() {
+            b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+        }
+        b.last = now
+        b.tokens--
+        if b.tokens < 0 {
+            wait = time.Duration(-b.tokens / b.rate * float64(time.Second))
+        }
+    }
+    if until := b.hold.Sub(now); until > wait {
+        wait = until
+    }
+    return wait
+}
+
+// holdUntil blocks new reservations from starting before t.
+func (b *tokenBucket) holdUntil(t time.Time) {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    if t.After(b.hold) {
+        b.hold = t
+    }
+}
+
+// hostBucket returns the rate limiter for rawURL's host, creating it on
+// first use. Every host gets a bucket so Retry-After holds apply even when
+// HostRPS is unset.
+func (c *Client) hostBucket(rawURL string) *tokenBucket {
+    u, err := url.Parse(rawURL)
+    if err != nil || u.Hostname() == "" {
+        return nil
+    }
+    host := strings.ToLower(u.Hostname())
+    c.hostMu.Lock()
+    defer c.hostMu.Unlock()
+    if c.hostBuckets == nil {
+        c.hostBuckets = make(map[string]*tokenBucket)
+    }
+    b, ok := c.hostBuckets[host]
+    if !ok {
+        b = newTokenBucket(c.HostRPS, c.HostBurst)
+        c.hostBuckets[host] = b
+    }
+    return b
+}
+
+// awaitRateLimit waits for the per-host and global rate limits and returns
+// the total time spent waiting.
+func (c *Client) awaitRateLimit(ctx context.Context, rawURL string) (time.Duration, error) {
+    var waited time.Duration
+    if b := c.hostBucket(rawURL); b != nil {
+        d := b.reserve(time.Now())
+        if err := sleepCtx(ctx, d); err != nil {
+            return waited, err
+        }
+        waited += d
+    }
+    if c.GlobalRPS > 0 {
+        c.globalOnce.Do(func() {
+            burst := int(math.Ceil(c.GlobalRPS))
+            c.globalBucket = newTokenBucket(c.GlobalRPS, burst)
+        })
+        d := c.globalBucket.reserve(time.Now())
+        if err := sleepCtx(ctx, d); err != nil {
+            return waited, err
+        }
+        waited += d
+    }
+    return waited, nil
+}
+
+// backOffHost holds further requests to rawURL's host for d, so one
+// Retry-After answer slows every request to that host, not just the retry.
+func (c *Client) backOffHost(rawURL string, d time.Duration) {
+    if b := c.hostBucket(rawURL); b != nil && d > 0 {
+        b.holdUntil(time.Now().Add(d))
+    }
+}
@@ -0,0 +1,115 @@
+package fetch
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "math/rand"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+)
+
+const (
+    defaultBackoff    = 200 * time.Millisecond
+    defaultMaxBackoff = 30 * time.Second
+)
+
+// StatusError reports a non-2xx response. RetryAfter is the delay the server
+// asked for via the Retry-After header, or zero when absent.
+type StatusError struct {
+    StatusCode int
+    RetryAfter time.Duration
+}
+
+func (e StatusError) Error() string {
+    if e.StatusCode >= 500 && e.StatusCode <= 599 {
+        return fmt.Sprintf("server error: %d", e.StatusCode)
+    }
+    if e.StatusCode == http.StatusTooManyRequests {
+        return fmt.Sprintf("too many requests: %d", e.StatusCode)
+    }
+    return fmt.Sprintf("unexpected status: %d", e.StatusCode)
+}
+
+// Retry records one retry decision: which attempt failed, why, and how long
+// the client waited before trying again.
+type Retry struct {
+    Attempt int    `json:"attempt"`
+    Status  int    `json:"status,omitempty"`
+    Error   string `json:"error"`
+    // DelayMillis is the wait before the next attempt.
+    DelayMillis int64 `json:"delay_ms"`
+    // RetryAfter is true when the delay came from the server's Retry-After.
+    RetryAfter bool `json:"retry_after,omitempty"`
+}
+
+// parseRetryAfter parses a Retry-After value given as delta seconds or an
+// HTTP date. It returns zero for missing, malformed or past values.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+    v = strings.TrimSpace(v)
+    if v == "" {
+        return 0
+    }
+    if secs, err := strconv.Atoi(v); err == nil {
+        if secs <= 0 {
+            return 0
+        }
+        return time.Duration(secs) * time.Second
+    }
+    if t, err := http.ParseTime(v); err == nil && t.After(now) {
+        return t.Sub(now)
+    }
+    return 0
+}
+
+// retryDelay decides how long to wait before retrying after attempt (0-based)
+// failed with err. A server-provided Retry-After is honored as is; otherwise
+// the delay grows exponentially from Backoff with jitter. ok is false when
+// the server asks for a longer wait than MaxBackoff, in which case retrying
+// within this run is pointless.
+func (c *Client) retryDelay(attempt int, err error) (delay time.Duration, fromServer bool, ok bool) {
+    maxBackoff := c.maxBackoff()
+    var se StatusError
+    if errors.As(err, &se) && se.RetryAfter > 0 {
+        if se.RetryAfter > maxBackoff {
+            return 0, true, false
+        }
+        return se.RetryAfter, true, true
+    }
+    base := c.Backoff
+    if base <= 0 {
+        base = defaultBackoff
+    }
+    d := base << attempt
+    if d <= 0 || d > maxBackoff {
+        d = maxBackoff
+    }
+    // Equal jitter: half fixed, half random, so concurrent clients spread out
+    // without any retry firing immediately.
+    half := d / 2
+    return half + time.Duration(rand.Int63n(int64(half)+1)), false, true
+}
+
+func (c *Client) maxBackoff() time.Duration {
+    if c.MaxBackoff <= 0 {
+        return defaultMaxBackoff
+    }
+    return c.MaxBackoff
+}
+
+// sleepCtx waits for d or until ctx is done.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+    if d <= 0 {
+        return nil
+    }
+    timer := time.NewTimer(d)
+    defer timer.Stop()
+    select {
+    case <-ctx.Done():
+        return ctx.Err()
+    case <-timer.C:
+        return nil
+    }
+}
@@ -0,0 +1,27 @@
+package langid
+
+// corpus holds the training text each language profile is built from. The
+// samples are ordinary expository prose — about technology, research and
+// everyday life — so the common function words and inflections that make
+// languages distinguishable dominate the n-gram counts. Keep samples of
+// similar length so no profile is favored by sheer size.
+var corpus = map[string]string{
+    "en": `The report describes how the new system was designed and why the team chose this approach over the alternatives. In the first section we explain the background of the project and the problems that users reported with the previous version. Most of the issues were related to performance, but there were also questions about security and about how the data is stored. The second part of the document shows the results of our measurements, which were collected over several weeks in a real production environment. We found that the average response time dropped by almost half, while the number of errors stayed the same. However, there are still some limitations that should be considered before the system is used more widely. For example, it does not yet support older browsers, and the documentation for developers is not complete. We would like to thank everyone who took part in the testing and gave us their feedback. If you have any questions about this work, please contact the authors through the project website, where you can also find the source code and further information. Yesterday I went to the market with my sister because we needed bread, cheese and some fresh fruit for the weekend. It was raining, so we took the bus instead of walking. She told me that her new job is going well, although her boss is very strict and she often has to work late. We talked about our parents, who are planning a trip to the mountains in the summer. When we got home, I made tea and we watched an old film that we both loved when we were children. I think we should meet more often, because these small moments are what I remember best.`,
+    "de": `Der Bericht beschreibt, wie das neue System entworfen wurde und warum sich das Team für diesen Ansatz entschieden hat. Im ersten Abschnitt erklären wir den Hintergrund des Projekts und die Probleme, die Benutzer mit der vorherigen Version gemeldet haben. Die meisten Fehler hatten mit der Leistung zu tun, aber es gab auch Fragen zur Sicherheit und dazu, wie die Daten gespeichert werden. Der zweite Teil des Dokuments zeigt die Ergebnisse unserer Messungen, die über mehrere Wochen in einer echten Produktionsumgebung gesammelt wurden. Wir haben festgestellt, dass die durchschnittliche Antwortzeit um fast die Hälfte gesunken ist, während die Anzahl der Fehler gleich geblieben ist. Allerdings gibt es noch einige Einschränkungen, die berücksichtigt werden sollten, bevor das System breiter eingesetzt wird. Zum Beispiel werden ältere Browser noch nicht unterstützt, und die Dokumentation für Entwickler ist nicht vollständig. Wir möchten allen danken, die an den Tests teilgenommen und uns ihre Rückmeldungen gegeben haben. Bei Fragen zu dieser Arbeit wenden Sie sich bitte über die Webseite des Projekts an die Autoren. Gestern bin ich mit meiner Schwester auf den Markt gegangen, weil wir Brot, Käse und frisches Obst für das Wochenende brauchten. Es hat geregnet, deshalb sind wir mit dem Bus gefahren, statt zu Fuß zu gehen. Sie hat mir erzählt, dass ihre neue Arbeit gut läuft, obwohl ihr Chef sehr streng ist und sie oft lange arbeiten muss. Wir haben über unsere Eltern gesprochen, die im Sommer eine Reise in die Berge planen. Als wir nach Hause kamen, habe ich Tee gekocht, und wir haben einen alten Film gesehen, den wir beide als Kinder geliebt haben. Ich glaube, wir sollten uns öfter treffen.`,
+    "fr": `Le rapport décrit comment le nouveau système a été conçu et pourquoi l'équipe a choisi cette approche plutôt que les autres. Dans la première partie, nous expliquons le contexte du projet et les problèmes que les utilisateurs ont signalés avec la version précédente. La plupart des erreurs étaient liées aux performances, mais il y avait aussi des questions sur la sécurité et sur la manière dont les données sont stockées. La deuxième partie du document présente les résultats de nos mesures, qui ont été recueillies pendant plusieurs semaines dans un environnement de production réel. Nous avons constaté que le temps de réponse moyen a diminué de presque la moitié, tandis que le nombre d'erreurs est resté le même. Cependant, il existe encore certaines limites qu'il faut prendre en compte avant d'utiliser le système plus largement. Par exemple, les anciens navigateurs ne sont pas encore pris en charge et la documentation pour les développeurs n'est pas complète. Nous remercions toutes les personnes qui ont participé aux tests et qui nous ont donné leur avis. Pour toute question sur ce travail, veuillez contacter les auteurs par le site du projet. Hier, je suis allé au marché avec ma sœur parce que nous avions besoin de pain, de fromage et de fruits frais pour le week-end. Il pleuvait, alors nous avons pris le bus au lieu de marcher. Elle m'a dit que son nouveau travail se passe bien, même si son chef est très sévère et qu'elle doit souvent travailler tard. Nous avons parlé de nos parents, qui prévoient un voyage à la montagne cet été. Quand nous sommes rentrés, j'ai fait du thé et nous avons regardé un vieux film que nous aimions tous les deux quand nous étions enfants. Je pense que nous devrions nous voir plus souvent.`,
+    "es": `El informe describe cómo se diseñó el nuevo sistema y por qué el equipo eligió este enfoque en lugar de las alternativas. En la primera sección explicamos los antecedentes del proyecto y los problemas que los usuarios señalaron con la versión anterior. La mayoría de los errores estaban relacionados con el rendimiento, pero también había preguntas sobre la seguridad y sobre cómo se almacenan los datos. La segunda parte del documento muestra los resultados de nuestras mediciones, que se recogieron durante varias semanas en un entorno de producción real. Descubrimos que el tiempo medio de respuesta bajó casi a la mitad, mientras que el número de errores se mantuvo igual. Sin embargo, todavía hay algunas limitaciones que deben tenerse en cuenta antes de usar el sistema de forma más amplia. Por ejemplo, aún no es compatible con los navegadores antiguos y la documentación para los desarrolladores no está completa. Queremos dar las gracias a todas las personas que participaron en las pruebas y nos dieron su opinión. Si tiene alguna pregunta sobre este trabajo, póngase en contacto con los autores a través del sitio web del proyecto. Ayer fui al mercado con mi hermana porque necesitábamos pan, queso y algo de fruta fresca para el fin de semana. Estaba lloviendo, así que tomamos el autobús en vez de caminar. Ella me contó que su nuevo trabajo va bien, aunque su jefe es muy estricto y a menudo tiene que trabajar hasta tarde. Hablamos de nuestros padres, que están planeando un viaje a la montaña en verano. Cuando llegamos a casa, preparé té y vimos una película antigua que a los dos nos encantaba cuando éramos niños. Creo que deberíamos vernos más a menudo, porque estos pequeños momentos son los que mejor recuerdo.`,
+    "it": `Il rapporto descrive come è stato progettato il nuovo sistema e perché il gruppo ha scelto questo approccio invece delle alternative. Nella prima sezione spieghiamo il contesto del progetto e i problemi che gli utenti hanno segnalato con la versione precedente. La maggior parte degli errori era legata alle prestazioni, ma c'erano anche domande sulla sicurezza e su come vengono conservati i dati. La seconda parte del documento mostra i risultati delle nostre misurazioni, che sono state raccolte per diverse settimane in un ambiente di produzione reale. Abbiamo scoperto che il tempo medio di risposta è sceso quasi della metà, mentre il numero degli errori è rimasto lo stesso. Tuttavia ci sono ancora alcuni limiti che devono essere considerati prima di usare il sistema in modo più ampio. Per esempio, non supporta ancora i browser più vecchi e la documentazione per gli sviluppatori non è completa. Vogliamo ringraziare tutte le persone che hanno partecipato alle prove e ci hanno dato il loro parere. Per qualsiasi domanda su questo lavoro, contattate gli autori attraverso il sito del progetto, dove si trovano anche il codice e altre informazioni. Ieri sono andato al mercato con mia sorella perché avevamo bisogno di pane, formaggio e un po' di frutta fresca per il fine settimana. Pioveva, quindi abbiamo preso l'autobus invece di camminare. Lei mi ha detto che il suo nuovo lavoro va bene, anche se il suo capo è molto severo e spesso deve lavorare fino a tardi. Abbiamo parlato dei nostri genitori, che stanno organizzando un viaggio in montagna per l'estate. Quando siamo tornati a casa, ho preparato il tè e abbiamo guardato un vecchio film che tutti e due amavamo da bambini. Penso che dovremmo vederci più spesso.`,
+    "pt": `O relatório descreve como o novo sistema foi projetado e por que a equipe escolheu esta abordagem em vez das alternativas. Na primeira seção explicamos o contexto do projeto e os problemas que os usuários relataram com a versão anterior. A maioria dos erros estava relacionada ao desempenho, mas também havia perguntas sobre a segurança e sobre como os dados são armazenados. A segunda parte do documento mostra os resultados das nossas medições, que foram coletadas durante várias semanas em um ambiente de produção real. Descobrimos que o tempo médio de resposta caiu quase pela metade, enquanto o número de erros continuou o mesmo. No entanto, ainda existem algumas limitações que devem ser consideradas antes de o sistema ser usado de forma mais ampla. Por exemplo, ele ainda não é compatível com navegadores antigos, e a documentação para os desenvolvedores não está completa. Agradecemos a todas as pessoas que participaram dos testes e nos deram a sua opinião. Se você tiver alguma dúvida sobre este trabalho, entre em contato com os autores pelo site do projeto, onde também estão o código e mais informações. Ontem fui ao mercado com a minha irmã porque precisávamos de pão, queijo e algumas frutas frescas para o fim de semana. Estava chovendo, então pegamos o ônibus em vez de ir a pé. Ela me contou que o novo trabalho dela está indo bem, embora o chefe seja muito rigoroso e ela muitas vezes tenha que trabalhar até tarde. Conversamos sobre os nossos pais, que estão planejando uma viagem para as montanhas no verão. Quando chegamos em casa, fiz chá e assistimos a um filme antigo que nós dois adorávamos quando éramos crianças. Acho que deveríamos nos encontrar mais vezes.`,
+    "nl": `Het rapport beschrijft hoe het nieuwe systeem is ontworpen en waarom het team voor deze aanpak heeft gekozen in plaats van de alternatieven. In het eerste deel leggen we de achtergrond van het project uit en de problemen die gebruikers met de vorige versie hebben gemeld. De meeste fouten hadden te maken met de prestaties, maar er waren ook vragen over de beveiliging en over hoe de gegevens worden opgeslagen. Het tweede deel van het document laat de resultaten zien van onze metingen, die gedurende enkele weken in een echte productieomgeving zijn verzameld. We hebben vastgesteld dat de gemiddelde reactietijd bijna met de helft is gedaald, terwijl het aantal fouten gelijk is gebleven. Er zijn echter nog enkele beperkingen waarmee rekening moet worden gehouden voordat het systeem op grotere schaal wordt gebruikt. Zo worden oudere browsers nog niet ondersteund en is de documentatie voor ontwikkelaars niet volledig. We willen iedereen bedanken die aan de tests heeft deelgenomen en ons feedback heeft gegeven. Als u vragen heeft over dit werk, neem dan contact op met de auteurs via de website van het project. Gisteren ben ik met mijn zus naar de markt gegaan, omdat we brood, kaas en wat vers fruit nodig hadden voor het weekend. Het regende, dus we hebben de bus genomen in plaats van te lopen. Ze vertelde me dat haar nieuwe baan goed gaat, hoewel haar baas erg streng is en ze vaak tot laat moet werken. We hebben over onze ouders gepraat, die in de zomer een reis naar de bergen plannen. Toen we thuiskwamen, heb ik thee gezet en hebben we naar een oude film gekeken waar we als kinderen allebei dol op waren. Ik denk dat we elkaar vaker moeten zien.`,
+    "sv": `Rapporten beskriver hur det nya systemet utformades och varför gruppen valde detta tillvägagångssätt i stället för alternativen. I det första avsnittet förklarar vi bakgrunden till projektet och de problem som användarna rapporterade med den tidigare versionen. De flesta felen hade att göra med prestanda, men det fanns också frågor om säkerheten och om hur uppgifterna lagras. Den andra delen av dokumentet visar resultaten av våra mätningar, som samlades in under flera veckor i en verklig produktionsmiljö. Vi fann att den genomsnittliga svarstiden sjönk med nästan hälften, medan antalet fel förblev detsamma. Det finns dock fortfarande vissa begränsningar som bör beaktas innan systemet används i större skala. Till exempel stöds äldre webbläsare ännu inte, och dokumentationen för utvecklare är inte fullständig. Vi vill tacka alla som deltog i testerna och gav oss sina synpunkter. Om du har frågor om detta arbete kan du kontakta författarna via projektets webbplats, där du också hittar källkoden och mer information. Igår gick jag till torget med min syster eftersom vi behövde bröd, ost och lite färsk frukt till helgen. Det regnade, så vi tog bussen i stället för att gå. Hon berättade att hennes nya jobb går bra, även om hennes chef är mycket sträng och hon ofta måste arbeta sent. Vi pratade om våra föräldrar, som planerar en resa till fjällen i sommar. När vi kom hem kokade jag te och vi tittade på en gammal film som vi båda älskade när vi var barn. Jag tycker att vi borde träffas oftare, eftersom det är sådana små stunder som jag minns bäst. Katten sov hela dagen och ville inte flytta sig.`,
+    "da": `Rapporten beskriver, hvordan det nye system blev udformet, og hvorfor holdet valgte denne fremgangsmåde frem for alternativerne. I det første afsnit forklarer vi baggrunden for projektet og de problemer, som brugerne har meldt om med den tidligere version. De fleste fejl havde noget at gøre med ydelsen, men der var også spørgsmål om sikkerheden og om, hvordan oplysningerne bliver gemt. Den anden del af dokumentet viser resultaterne af vores målinger, som blev indsamlet over flere uger i et rigtigt produktionsmiljø. Vi fandt ud af, at den gennemsnitlige svartid faldt med næsten halvdelen, mens antallet af fejl forblev det samme. Der er dog stadig nogle begrænsninger, som man bør tage hensyn til, før systemet bliver brugt i større omfang. For eksempel understøttes ældre browsere endnu ikke, og dokumentationen til udviklere er ikke færdig. Vi vil gerne takke alle, der deltog i afprøvningen og gav os deres mening. Hvis du har spørgsmål om dette arbejde, kan du kontakte forfatterne via projektets hjemmeside, hvor du også finder kildekoden og flere oplysninger. I går gik jeg på torvet med min søster, fordi vi manglede brød, ost og noget frisk frugt til weekenden. Det regnede, så vi tog bussen i stedet for at gå. Hun fortalte mig, at hendes nye arbejde går godt, selvom hendes chef er meget streng, og hun tit må arbejde sent. Vi snakkede om vores forældre, som planlægger en rejse til bjergene til sommer. Da vi kom hjem, lavede jeg te, og vi så en gammel film, som vi begge elskede, da vi var børn. Jeg synes, vi burde ses noget oftere, fordi det er de små øjeblikke, jeg husker bedst. Katten sov hele dagen og ville ikke flytte sig.`,
+    "no": `Rapporten beskriver hvordan det nye systemet ble utformet, og hvorfor teamet valgte denne fremgangsmåten fremfor alternativene. I den første delen forklarer vi bakgrunnen for prosjektet og problemene som brukerne meldte om med den forrige versjonen. De fleste feilene hadde å gjøre med ytelsen, men det var også spørsmål om sikkerheten og om hvordan opplysningene blir lagret. Den andre delen av dokumentet viser resultatene av målingene våre, som ble samlet inn over flere uker i et ekte produksjonsmiljø. Vi fant ut at den gjennomsnittlige svartiden sank med nesten halvparten, mens antallet feil forble det samme. Det finnes likevel noen begrensninger som man bør ta hensyn til før systemet blir tatt i bruk i større skala. For eksempel støttes ikke eldre nettlesere ennå, og dokumentasjonen for utviklere er ikke ferdig. Vi vil gjerne takke alle som deltok i testingen og ga oss sine tilbakemeldinger. Hvis du har spørsmål om dette arbeidet, kan du kontakte forfatterne via nettsiden til prosjektet, hvor du også finner kildekoden og mer informasjon. I går gikk jeg på torget med søsteren min fordi vi trengte brød, ost og litt frisk frukt til helgen. Det regnet, så vi tok bussen i stedet for å gå. Hun fortalte meg at den nye jobben hennes går bra, selv om sjefen hennes er veldig streng og hun ofte må jobbe sent. Vi snakket om foreldrene våre, som planlegger en tur til fjellet i sommer. Da vi kom hjem, lagde jeg te, og vi så en gammel film som vi begge var glade i da vi var barn. Jeg synes vi burde treffes oftere, fordi det er slike små øyeblikk jeg husker best. Katten sov hele dagen og ville ikke flytte seg.`,
+    "fi": `Raportti kuvaa, miten uusi järjestelmä suunniteltiin ja miksi ryhmä valitsi tämän lähestymistavan vaihtoehtojen sijaan. Ensimmäisessä osassa selitämme hankkeen taustan ja ongelmat, joista käyttäjät ilmoittivat edellisen version kanssa. Suurin osa virheistä liittyi suorituskykyyn, mutta myös tietoturvasta ja tietojen tallentamisesta esitettiin kysymyksiä. Asiakirjan toinen osa esittelee mittaustemme tulokset, jotka kerättiin usean viikon aikana todellisessa tuotantoympäristössä. Huomasimme, että keskimääräinen vasteaika lyheni lähes puoleen, kun taas virheiden määrä pysyi ennallaan. Järjestelmällä on kuitenkin edelleen joitakin rajoituksia, jotka kannattaa ottaa huomioon ennen kuin sitä käytetään laajemmin. Esimerkiksi vanhempia selaimia ei vielä tueta, eikä kehittäjien dokumentaatio ole valmis. Haluamme kiittää kaikkia, jotka osallistuivat testaukseen ja antoivat meille palautetta. Jos sinulla on kysyttävää tästä työstä, ota yhteyttä tekijöihin hankkeen verkkosivuston kautta, josta löydät myös lähdekoodin ja lisätietoja. Eilen kävin siskoni kanssa torilla, koska tarvitsimme leipää, juustoa ja vähän tuoreita hedelmiä viikonlopuksi. Satoi, joten menimme bussilla emmekä kävelleet. Hän kertoi minulle, että hänen uusi työnsä sujuu hyvin, vaikka hänen pomonsa on hyvin tiukka ja hänen täytyy usein tehdä töitä myöhään. Puhuimme vanhemmistamme, jotka suunnittelevat matkaa vuorille kesällä. Kun tulimme kotiin, keitin teetä ja katsoimme vanhan elokuvan, jota rakastimme molemmat lapsina. Minusta meidän pitäisi tavata useammin, koska juuri nämä pienet hetket muistan parhaiten.`,
+    "pl": `Raport opisuje, w jaki sposób zaprojektowano nowy system i dlaczego zespół wybrał to podejście zamiast innych rozwiązań. W pierwszej części wyjaśniamy tło projektu oraz problemy, które użytkownicy zgłaszali w poprzedniej wersji. Większość błędów dotyczyła wydajności, ale pojawiły się również pytania o bezpieczeństwo i o to, jak przechowywane są dane. Druga część dokumentu przedstawia wyniki naszych pomiarów, które zbierano przez kilka tygodni w rzeczywistym środowisku produkcyjnym. Stwierdziliśmy, że średni czas odpowiedzi spadł prawie o połowę, podczas gdy liczba błędów pozostała taka sama. Nadal jednak istnieją pewne ograniczenia, które należy wziąć pod uwagę, zanim system zostanie szerzej wykorzystany. Na przykład starsze przeglądarki nie są jeszcze obsługiwane, a dokumentacja dla programistów nie jest kompletna. Chcielibyśmy podziękować wszystkim, którzy wzięli udział w testach i podzielili się z nami swoją opinią. Jeśli masz pytania dotyczące tej pracy, skontaktuj się z autorami przez stronę internetową projektu, gdzie znajdziesz również kod źródłowy. Wczoraj poszedłem z siostrą na targ, bo potrzebowaliśmy chleba, sera i trochę świeżych owoców na weekend. Padał deszcz, więc pojechaliśmy autobusem zamiast iść pieszo. Powiedziała mi, że jej nowa praca idzie dobrze, chociaż jej szef jest bardzo surowy i często musi pracować do późna. Rozmawialiśmy o naszych rodzicach, którzy planują latem wyjazd w góry. Kiedy wróciliśmy do domu, zrobiłem herbatę i obejrzeliśmy stary film, który oboje kochaliśmy jako dzieci. Myślę, że powinniśmy spotykać się częściej, bo właśnie takie małe chwile pamiętam najlepiej.`,
+    "cs": `Zpráva popisuje, jak byl nový systém navržen a proč se tým rozhodl pro tento přístup místo jiných možností. V první části vysvětlujeme pozadí projektu a problémy, které uživatelé hlásili u předchozí verze. Většina chyb souvisela s výkonem, ale objevily se také otázky týkající se bezpečnosti a toho, jak jsou data ukládána. Druhá část dokumentu ukazuje výsledky našich měření, která byla shromažďována po několik týdnů ve skutečném produkčním prostředí. Zjistili jsme, že průměrná doba odezvy klesla téměř na polovinu, zatímco počet chyb zůstal stejný. Přesto stále existují určitá omezení, která je třeba vzít v úvahu dříve, než se systém začne používat ve větším měřítku. Například starší prohlížeče zatím nejsou podporovány a dokumentace pro vývojáře není úplná. Rádi bychom poděkovali všem, kteří se zúčastnili testování a poskytli nám svůj názor. Pokud máte k této práci nějaké otázky, kontaktujte prosím autory prostřednictvím webové stránky projektu, kde najdete také zdrojový kód a další informace. Včera jsem šel se sestrou na trh, protože jsme potřebovali chléb, sýr a trochu čerstvého ovoce na víkend. Pršelo, takže jsme jeli autobusem místo toho, abychom šli pěšky. Řekla mi, že její nová práce jde dobře, i když její šéf je velmi přísný a ona často musí pracovat dlouho do noci. Mluvili jsme o našich rodičích, kteří v létě plánují výlet do hor. Když jsme přišli domů, uvařil jsem čaj a podívali jsme se na starý film, který jsme oba milovali jako děti. Myslím, že bychom se měli vídat častěji, protože právě na takové malé chvíle vzpomínám nejraději.`,
+    "hu": `A jelentés bemutatja, hogyan tervezték meg az új rendszert, és miért választotta a csapat ezt a megközelítést a többi lehetőség helyett. Az első részben ismertetjük a projekt hátterét és azokat a problémákat, amelyeket a felhasználók az előző változattal kapcsolatban jeleztek. A hibák többsége a teljesítménnyel függött össze, de kérdések merültek fel a biztonsággal és az adatok tárolásának módjával kapcsolatban is. A dokumentum második része a méréseink eredményeit mutatja be, amelyeket több héten keresztül gyűjtöttünk egy valódi éles környezetben. Azt tapasztaltuk, hogy az átlagos válaszidő majdnem a felére csökkent, miközben a hibák száma nem változott. Ennek ellenére még mindig vannak bizonyos korlátok, amelyeket figyelembe kell venni, mielőtt a rendszert szélesebb körben használnák. Például a régebbi böngészőket még nem támogatja, és a fejlesztőknek szóló dokumentáció sem teljes. Szeretnénk megköszönni mindenkinek, aki részt vett a tesztelésben és elmondta a véleményét. Ha kérdése van ezzel a munkával kapcsolatban, keresse a szerzőket a projekt honlapján keresztül. Tegnap elmentem a húgommal a piacra, mert kenyérre, sajtra és egy kis friss gyümölcsre volt szükségünk a hétvégére. Esett az eső, ezért busszal mentünk, ahelyett hogy gyalogoltunk volna. Elmesélte, hogy az új munkahelyén jól mennek a dolgok, bár a főnöke nagyon szigorú, és gyakran későig kell dolgoznia. Beszélgettünk a szüleinkről, akik nyáron hegyi kirándulást terveznek. Amikor hazaértünk, teát főztem, és megnéztünk egy régi filmet, amelyet gyerekkorunkban mindketten nagyon szerettünk. Szerintem gyakrabban kellene találkoznunk, mert az ilyen apró pillanatokra emlékszem a legszívesebben.`,
+    "ro": `Raportul descrie modul în care a fost proiectat noul sistem și motivul pentru care echipa a ales această abordare în locul celorlalte variante. În prima secțiune explicăm contextul proiectului și problemele pe care utilizatorii le-au semnalat la versiunea anterioară. Cele mai multe erori erau legate de performanță, dar au existat și întrebări despre securitate și despre felul în care sunt stocate datele. A doua parte a documentului prezintă rezultatele măsurătorilor noastre, care au fost colectate timp de mai multe săptămâni într-un mediu de producție real. Am constatat că timpul mediu de răspuns a scăzut aproape la jumătate, în timp ce numărul de erori a rămas același. Totuși, există încă unele limitări care trebuie luate în considerare înainte ca sistemul să fie folosit pe scară mai largă. De exemplu, nu sunt încă acceptate browserele mai vechi, iar documentația pentru dezvoltatori nu este completă. Dorim să mulțumim tuturor celor care au participat la teste și ne-au spus părerea lor. Dacă aveți întrebări despre această lucrare, vă rugăm să contactați autorii prin site-ul proiectului. Ieri am mers la piață cu sora mea, pentru că aveam nevoie de pâine, brânză și câteva fructe proaspete pentru sfârșitul de săptămână. Ploua, așa că am luat autobuzul în loc să mergem pe jos. Mi-a spus că noul ei loc de muncă merge bine, deși șeful ei este foarte sever și de multe ori trebuie să lucreze până târziu. Am vorbit despre părinții noștri, care plănuiesc o excursie la munte în vară. Când am ajuns acasă, am făcut ceai și ne-am uitat la un film vechi pe care amândoi îl iubeam când eram copii. Cred că ar trebui să ne vedem mai des.`,
+    "ru": `В отчёте описано, как была спроектирована новая система и почему команда выбрала этот подход вместо других вариантов. В первом разделе мы объясняем предысторию проекта и проблемы, о которых пользователи сообщали в предыдущей версии. Большинство ошибок было связано с производительностью, но также возникали вопросы о безопасности и о том, как хранятся данные. Во второй части документа представлены результаты наших измерений, которые собирались в течение нескольких недель в реальной рабочей среде. Мы обнаружили, что среднее время ответа сократилось почти вдвое, тогда как количество ошибок осталось прежним. Однако всё ещё есть некоторые ограничения, которые следует учитывать, прежде чем использовать систему более широко. Например, старые браузеры пока не поддерживаются, а документация для разработчиков ещё не закончена. Мы хотим поблагодарить всех, кто участвовал в тестировании и поделился с нами своим мнением. Если у вас есть вопросы об этой работе, пожалуйста, свяжитесь с авторами через сайт проекта, где также можно найти исходный код и дополнительную информацию. Вчера я ходил с сестрой на рынок, потому что нам нужны были хлеб, сыр и немного свежих фруктов на выходные. Шёл дождь, поэтому мы поехали на автобусе, а не пошли пешком. Она рассказала мне, что на новой работе у неё всё хорошо, хотя её начальник очень строгий и ей часто приходится задерживаться допоздна. Мы поговорили о наших родителях, которые летом собираются поехать в горы. Когда мы вернулись домой, я заварил чай, и мы посмотрели старый фильм, который оба очень любили в детстве. Кошка спала на столе с самого утра. Мне кажется, нам стоит встречаться чаще, ведь именно такие мелочи запоминаются лучше всего.`,
+    "uk": `У звіті описано, як було спроєктовано нову систему і чому команда обрала цей підхід замість інших варіантів. У першому розділі ми пояснюємо передісторію проєкту та проблеми, про які користувачі повідомляли в попередній версії. Більшість помилок була пов'язана з продуктивністю, але також виникали питання щодо безпеки та того, як зберігаються дані. У другій частині документа наведено результати наших вимірювань, які збиралися протягом кількох тижнів у реальному робочому середовищі. Ми з'ясували, що середній час відповіді скоротився майже вдвічі, тоді як кількість помилок залишилася такою ж. Проте все ще існують певні обмеження, які слід враховувати, перш ніж використовувати систему ширше. Наприклад, старі браузери поки що не підтримуються, а документація для розробників ще не завершена. Ми хочемо подякувати всім, хто брав участь у тестуванні та поділився з нами своєю думкою. Якщо у вас є запитання щодо цієї роботи, будь ласка, зв'яжіться з авторами через сайт проєкту, де також можна знайти вихідний код і додаткову інформацію. Учора я ходив із сестрою на ринок, бо нам потрібні були хліб, сир і трохи свіжих фруктів на вихідні. Ішов дощ, тому ми поїхали автобусом, а не пішли пішки. Вона розповіла мені, що на новій роботі в неї все добре, хоча її начальник дуже суворий і їй часто доводиться працювати допізна. Ми поговорили про наших батьків, які влітку збираються поїхати в гори. Коли ми повернулися додому, я заварив чай, і ми подивилися старий фільм, який обоє дуже любили в дитинстві. Мені здається, що нам варто зустрічатися частіше, адже саме такі дрібниці запам'ятовуються найкраще.`,
+    "bg": `В доклада е описано как е проектирана новата система и защо екипът е избрал този подход вместо другите възможности. В първия раздел обясняваме предисторията на проекта и проблемите, за които потребителите съобщиха при предишната версия. Повечето грешки бяха свързани с производителността, но имаше и въпроси относно сигурността и начина, по който се съхраняват данните. Втората част на документа представя резултатите от нашите измервания, които бяха събирани в продължение на няколко седмици в реална работна среда. Установихме, че средното време за отговор е намаляло почти наполовина, докато броят на грешките е останал същият. Въпреки това все още има някои ограничения, които трябва да се вземат предвид, преди системата да се използва по-широко. Например по-старите браузъри все още не се поддържат, а документацията за разработчиците не е завършена. Искаме да благодарим на всички, които участваха в тестовете и споделиха мнението си с нас. Ако имате въпроси за тази работа, моля, свържете се с авторите чрез сайта на проекта. Вчера отидох със сестра си на пазара, защото ни трябваха хляб, сирене и малко пресни плодове за събота и неделя. Валеше дъжд, затова се качихме на автобуса, вместо да вървим пеша. Тя ми разказа, че новата ѝ работа върви добре, въпреки че шефът ѝ е много строг и често ѝ се налага да работи до късно. Говорихме за нашите родители, които през лятото планират пътуване в планината. Когато се прибрахме вкъщи, направих чай и гледахме един стар филм, който и двамата обичахме като деца. Мисля, че трябва да се виждаме по-често, защото именно такива малки моменти помня най-добре.`,
+}
@@ -0,0 +1,265 @@
+// Package langid identifies the natural language of a text offline. Scripts
+// with a single major language (Greek, Korean) or a distinctive writing
+// system (Japanese kana, Chinese Han) are recognized by script alone; Latin
+// and Cyrillic text is scored against character n-gram profiles built at
+// startup from the samples embedded in corpus.go. No model is downloaded.
+package langid
+
+import (
+    "math"
+    "sort"
+    "strings"
+    "unicode"
+)
+
+// ReliableConfidence is the confidence at or above which a detection is
+// trusted for filtering and ranking. Below it, Result.Reliable is false.
+const ReliableConfidence = 0.8
+
+// minLetters is the fewest letters Detect needs to guess at all.
+const minLetters = 3
+
+// Result is a detected language with the detector's confidence in [0,1].
+// Lang is a lowercase ISO 639-1 code, or "" when the text has too few
+// letters to judge.
+type Result struct {
+    Lang       string
+    Confidence float64
+}
+
+// Reliable reports whether the detection is confident enough to act on.
+func (r Result) Reliable() bool {
+    return r.Lang != "" && r.Confidence >= ReliableConfidence
+}
+
+// Script-only languages recognized without a profile.
+var scriptLanguages = []string{"el", "ja", "ko", "zh"}
+
+// profile holds smoothed n-gram log-probabilities for one language.
+type profile struct {
+    lang    string
+    logp    map[string]float64
+    unknown float64
+}
+
+var latinProfiles, cyrillicProfiles []profile
+
+func init() {
+    langs := make([]string, 0, len(corpus))
+    for l := range corpus {
+        langs = append(langs, l)
+    }
+    sort.Strings(langs)
+    for _, l := range langs {
+        p := buildProfile(l, corpus[l])
+        if dominantScript(corpus[l]) == scriptCyrillic {
+            cyrillicProfiles = append(cyrillicProfiles, p)
+        } else {
+            latinProfiles = append(latinProfiles, p)
+        }
+    }
+}
+
+// Languages returns every supported language code in sorted order.
+func Languages() []string {
+    out := append([]string(nil), scriptLanguages...)
+    for l := range corpus {
+        out = append(out, l)
+    }
+    sort.Strings(out)
+    return out
+}
+
+// Supported reports whether code is a language Detect can return.
+func Supported(code string) bool {
+    code = strings.ToLower(strings.TrimSpace(code))
+    if _, ok := corpus[code]; ok {
+        return true
+    }
+    for _, l := range scriptLanguages {
+        if l == code {
+            return true
+        }
+    }
+    return false
+}
+
+// Detect identifies the language of text.
+func Detect(text string) Result {
+    counts := scriptCounts(text)
+    letters := 0
+    for _, n := range counts {
+        letters += n
+    }
+    if letters < minLetters {
+        return Result{}
+    }
+    share := func(n int) float64 { return float64(n) / float64(letters) }
+    switch dominantFromCounts(counts) {
+    case scriptHangul:
+        return Result{Lang: "ko", Confidence: share(counts[scriptHangul])}
+    case scriptKana, scriptHan:
+        cjk := counts[scriptKana] + counts[scriptHan]
+        // Japanese mixes kana into Han text; Chinese uses Han alone.
+        if counts[scriptKana]*20 > cjk {
+            return Result{Lang: "ja", Confidence: share(cjk)}
+        }
+        return Result{Lang: "zh", Confidence: share(cjk)}
+    case scriptGreek:
+        return Result{Lang: "el", Confidence: share(counts[scriptGreek])}
+    case scriptCyrillic:
+        r := classify(text, cyrillicProfiles)
+        r.Confidence *= share(counts[scriptCyrillic])
+        return r
+    default:
+        r := classify(text, latinProfiles)
+        r.Confidence *= share(counts[scriptLatin])
+        return r
+    }
+}
+
+// classify scores text against profiles with naive Bayes over character n-grams and
+// returns the best language with its posterior probability. Evidence is
+// tempered so a handful of n-grams cannot yield near-certainty.
+func classify(text string, profiles []profile) Result {
+    grams := ngrams(text)
+    if len(grams) == 0 || len(profiles) == 0 {
+        return Result{}
+    }
+    scores := make([]float64, len(profiles))
+    for i, p := range profiles {
+        for _, g := range grams {
+            if lp, ok := p.logp[g]; ok {
+                scores[i] += lp
+            } else {
+                scores[i] += p.unknown
+            }
+        }
+    }
+    best := 0
+    for i := range scores {
+        if scores[i] > scores[best] {
+            best = i
+        }
+    }
+    // Softmax over tempered scores: evidence counts at most evidenceCap
+    // n-grams, and texts shorter than shortEvidence are discounted further
+    // since a name or two says little about the surrounding language.
+    n := float64(len(grams))
+    weight := math.Min(n, evidenceCap) / n * math.Min(1, n/shortEvidence) * temperature
+    sum := 0.0
+    for i := range scores {
+        sum += math.Exp((scores[i] - scores[best]) * weight)
+    }
+    return Result{Lang: profiles[best].lang, Confidence: 1 / sum}
+}
+
+const (
+    // evidenceCap bounds how many n-grams count toward confidence.
+    evidenceCap = 60
+    // shortEvidence is the n-gram count below which confidence is damped,
+    // roughly four words.
+    shortEvidence = 100
+    // temperature scales log-likelihood differences before the softmax.
+    temperature = 0.5
+)
+
+func buildProfile(lang, sample string) profile {
+    counts := map[string]int{}
+    total := 0
+    for _, g := range ngrams(sample) {
+        counts[g]++
+        total++
+    }
+    denom := float64(total) + smoothing*vocabulary
+    p := profile{lang: lang, logp: make(map[string]float64, len(counts)), unknown: math.Log(smoothing / denom)}
+    for g, n := range counts {
+        p.logp[g] = math.Log((float64(n) + smoothing) / denom)
+    }
+    return p
+}
+
+const (
+    // smoothing is the additive pseudo-count for every n-gram.
+    smoothing = 0.5
+    // vocabulary approximates the number of distinct n-grams in a language.
+    vocabulary = 8000
+)
+
+// ngrams lowercases text, splits it into words of letters and returns the
+// letters, bigrams and trigrams of each word padded with spaces, so word
+// starts and endings — where function words and inflections live — are
+// captured alongside language-specific letters.
+func ngrams(text string) []string {
+    var out []string
+    for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+        return !unicode.IsLetter(r) && r != '\''
+    }) {
+        w = strings.Trim(w, "'")
+        if w == "" {
+            continue
+        }
+        rs := []rune(" " + w + " ")
+        for n := 1; n <= 3; n++ {
+            for i := 0; i+n <= len(rs); i++ {
+                if n == 1 && rs[i] == ' ' {
+                    continue
+                }
+                out = append(out, string(rs[i:i+n]))
+            }
+        }
+    }
+    return out
+}
+
+type script int
+
+const (
+    scriptLatin script = iota
+    scriptCyrillic
+    scriptGreek
+    scriptHan
+    scriptKana
+    scriptHangul
+    numScripts
+)
+
+func scriptCounts(text string) [numScripts]int {
+    var c [numScripts]int
+    for _, r := range text {
+        switch {
+        case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+            c[scriptKana]++
+        case unicode.Is(unicode.Hangul, r):
+            c[scriptHangul]++
+        case unicode.Is(unicode.Han, r):
+            c[scriptHan]++
+        case unicode.Is(unicode.Cyrillic, r):
+            c[scriptCyrillic]++
+        case unicode.Is(unicode.Greek, r):
+            c[scriptGreek]++
+        case unicode.Is(unicode.Latin, r):
+            c[scriptLatin]++
+        }
+    }
+    return c
+}
+
+func dominantFromCounts(c [numScripts]int) script {
+    // Kana and Han together form Japanese text, so they compete as one.
+    cjk := c[scriptHan] + c[scriptKana]
+    best, bestN := scriptLatin, c[scriptLatin]
+    for _, s := range []script{scriptCyrillic, scriptGreek, scriptHangul} {
+        if c[s] > bestN {
+            best, bestN = s, c[s]
+        }
+    }
+    if cjk > bestN {
+        return scriptHan
+    }
+    return best
+}
+
+func dominantScript(text string) script {
+    return dominantFromCounts(scriptCounts(text))
+}
@@ -0,0 +1,62 @@
+package langid
+
+import "testing"
+
+func TestDetect_Languages(t *testing.T) {
+    cases := []struct{ want, text string }{
+        {"en", "This is an introductory guide to Kubernetes and its architecture."},
+        {"de", "Die Katze sitzt auf dem Tisch und schläft seit heute Morgen."},
+        {"fr", "Le chat dort sur la table depuis ce matin."},
+        {"es", "Esta es una guía introductoria de Kubernetes y su arquitectura."},
+        {"it", "Il gatto dorme sul tavolo da stamattina."},
+        {"pt", "O gato está dormindo na mesa desde hoje de manhã."},
+        {"nl", "De kat slaapt sinds vanochtend op de tafel."},
+        {"sv", "Vi har fortfarande inte fått några svar från användarna, eftersom undersökningen skickades ut först igår."},
+        {"da", "Vi har stadig ikke fået nogen svar fra brugerne, fordi undersøgelsen først blev sendt ud i går."},
+        {"no", "Vi har fortsatt ikke fått noen svar fra brukerne, fordi undersøkelsen først ble sendt ut i går, og jeg vet ikke hva de mener om den nye versjonen."},
+        {"fi", "Kissa nukkuu pöydällä aamusta asti."},
+        {"pl", "Kot śpi na stole od samego rana i nie chce się ruszyć."},
+        {"cs", "Kočka spí na stole od rána a nechce se pohnout."},
+        {"hu", "A macska reggel óta az asztalon alszik."},
+        {"ro", "Pisica doarme pe masă de azi dimineață."},
+        {"ru", "Кошка спит на столе с самого утра."},
+        {"uk", "Кішка спить на столі з самого ранку."},
+        {"bg", "Котката спи на масата от сутринта."},
+        {"el", "Η γάτα κοιμάται στο τραπέζι από το πρωί."},
+        {"ja", "猫は朝からテーブルの上で寝ている。"},
+        {"zh", "猫从早上开始就在桌子上睡觉。"},
+        {"ko", "고양이가 아침부터 식탁 위에서 자고 있다."},
+    }
+    for _, c := range cases {
+        got := Detect(c.text)
+        if got.Lang != c.want || !got.Reliable() {
+            t.Errorf("Detect(%q) = %+v, want reliable %q", c.text, got, c.want)
+        }
+    }
+}
+
+func TestDetect_ShortTextIsUnreliable(t *testing.T) {
+    if r := Detect(""); r.Lang != "" || r.Reliable() {
+        t.Fatalf("empty text: %+v", r)
+    }
+    if r := Detect("42 — ok"); r.Lang != "" {
+        t.Fatalf("too few letters: %+v", r)
+    }
+    if r := Detect("Kubernetes"); r.Reliable() {
+        t.Fatalf("a lone name should not be reliable: %+v", r)
+    }
+}
+
+func TestSupported(t *testing.T) {
+    for _, code := range []string{"en", "ZH", " fi "} {
+        if !Supported(code) {
+            t.Errorf("Supported(%q) = false", code)
+        }
+    }
+    if Supported("tlh") {
+        t.Error("Klingon should not be supported")
+    }
+    if n := len(Languages()); n != len(corpus)+len(scriptLanguages) {
+        t.Errorf("Languages() has %d entries", n)
+    }
+}
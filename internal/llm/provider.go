@@ -32,3 +32,14 @@ func (p *OpenAIProvider) CreateChatCompletion(ctx context.Context, request opena
 func (p *OpenAIProvider) ListModels(ctx context.Context) (openai.ModelsList, error) {
     return p.Inner.ListModels(ctx)
 }
+
+// Embedder is an optional capability for providers that expose an
+// OpenAI-compatible /v1/embeddings endpoint. Callers should use a type
+// assertion to detect availability.
+type Embedder interface {
+    CreateEmbeddings(ctx context.Context, conv openai.EmbeddingRequestConverter) (openai.EmbeddingResponse, error)
+}
+
+func (p *OpenAIProvider) CreateEmbeddings(ctx context.Context, conv openai.EmbeddingRequestConverter) (openai.EmbeddingResponse, error) {
+    return p.Inner.CreateEmbeddings(ctx, conv)
+}
@@ -0,0 +1,272 @@
+// Package netguard enforces the "public web only" policy at connection time.
+//
+// Checking the hostname of a URL is not enough: a public name may resolve to
+// a private address, and a rebinding host may resolve differently between a
+// check and the dial. The guard therefore inspects every IP the dialer is
+// about to connect to, through a net.Dialer Control hook.
+package netguard
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "net"
+    "net/http"
+    "net/url"
+    "strings"
+    "syscall"
+    "time"
+)
+
+// PolicyError reports a connection refused by the public-web guard. IP is
+// empty when the hostname itself was rejected before resolution.
+type PolicyError struct {
+    Host   string
+    IP     string
+    Reason string
+}
+
+func (e PolicyError) Error() string {
+    if e.IP == "" || e.IP == e.Host {
+        return fmt.Sprintf("private host not allowed: %s (%s)", e.Host, e.Reason)
+    }
+    return fmt.Sprintf("private host not allowed: %s resolves to %s (%s)", e.Host, e.IP, e.Reason)
+}
+
+// IsDenied reports whether err was caused by the public-web guard and
+// returns a short reason suitable for a skipped-source entry.
+func IsDenied(err error) (string, bool) {
+    var pe PolicyError
+    if errors.As(err, &pe) {
+        return pe.Error(), true
+    }
+    return "", false
+}
+
+type blockedRange struct {
+    prefix *net.IPNet
+    reason string
+}
+
+var blockedRanges = func() []blockedRange {
+    specs := []struct{ cidr, reason string }{
+        {"0.0.0.0/8", "unspecified"},
+        {"10.0.0.0/8", "private"},
+        {"100.64.0.0/10", "carrier-grade NAT"},
+        {"127.0.0.0/8", "loopback"},
+        {"169.254.0.0/16", "link-local"},
+        {"172.16.0.0/12", "private"},
+        {"192.0.0.0/24", "reserved"},
+        {"192.0.2.0/24", "documentation"},
+        {"192.168.0.0/16", "private"},
+        {"198.18.0.0/15", "benchmarking"},
+        {"198.51.100.0/24", "documentation"},
+        {"203.0.113.0/24", "documentation"},
+        {"224.0.0.0/4", "multicast"},
+        {"240.0.0.0/4", "reserved"},
+        {"::/128", "unspecified"},
+        {"::1/128", "loopback"},
+        {"100::/64", "discard"},
+        {"2001:db8::/32", "documentation"},
+        {"fc00::/7", "unique-local"},
+        {"fe80::/10", "link-local"},
+        {"ff00::/8", "multicast"},
+    }
+    out := make([]blockedRange, 0, len(specs))
+    for _, s := range specs {
+        _, n, err := net.ParseCIDR(s.cidr)
+        if err != nil {
+            panic(err)
+        }
+        out = append(out, blockedRange{prefix: n, reason: s.reason})
+    }
+    return out
+}()
+
+// Prefixes that embed an IPv4 address which decides the outcome.
+var (
+    nat64Prefix = mustCIDR("64:ff9b::/96")
+    sixToFour   = mustCIDR("2002::/16")
+)
+
+func mustCIDR(s string) *net.IPNet {
+    _, n, err := net.ParseCIDR(s)
+    if err != nil {
+        panic(err)
+    }
+    return n
+}
+
+// BlockedReason returns why ip is not a public unicast address, or "" when
+// it is. IPv4-mapped, NAT64 and 6to4 IPv6 addresses are judged by the IPv4
+// address they carry.
+func BlockedReason(ip net.IP) string {
+    if ip == nil {
+        return "invalid address"
+    }
+    if v4 := ip.To4(); v4 != nil {
+        ip = v4
+    } else if nat64Prefix.Contains(ip) {
+        ip = net.IP(ip[12:16])
+    } else if sixToFour.Contains(ip) {
+        ip = net.IP(ip[2:6])
+    }
+    for _, r := range blockedRanges {
+        if r.prefix.Contains(ip) {
+            return r.reason
+        }
+    }
+    return ""
+}
+
+// HostReason checks a hostname before resolution: well-known local names and
+// IP literals. It returns "" for names that must be checked when dialing.
+func HostReason(host string) string {
+    h := strings.Trim(strings.ToLower(strings.TrimSpace(host)), "[]")
+    h = strings.TrimSuffix(h, ".")
+    if h == "localhost" || h == "localhost.localdomain" || strings.HasSuffix(h, ".localhost") {
+        return "loopback"
+    }
+    if ip := net.ParseIP(h); ip != nil {
+        return BlockedReason(ip)
+    }
+    return ""
+}
+
+// control is the net.Dialer Control hook. It runs for every address the
+// dialer tries, after resolution and before connecting.
+func control(network, address string, _ syscall.RawConn) error {
+    host, _, err := net.SplitHostPort(address)
+    if err != nil {
+        host = address
+    }
+    ip := net.ParseIP(host)
+    if reason := BlockedReason(ip); reason != "" {
+        return PolicyError{IP: host, Reason: reason}
+    }
+    return nil
+}
+
+// Dialer returns a copy of base, or a dialer with the usual timeouts when
+// base is nil, that refuses connections to non-public addresses.
+func Dialer(base *net.Dialer) *net.Dialer {
+    d := &net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second}
+    if base != nil {
+        cp := *base
+        d = &cp
+    }
+    d.Control = control
+    d.ControlContext = nil
+    return d
+}
+
+// lookupIPAddr resolves hosts for proxied requests; tests replace it.
+var lookupIPAddr = net.DefaultResolver.LookupIPAddr
+
+// Transport returns a clone of tr whose connections go through the guarded
+// dialer. Connections to the transport's own proxy are exempt, since the
+// proxy, not this process, resolves the destination. Requests sent through
+// a proxy are instead checked by resolving their host locally first. Names
+// that only the proxy can resolve, and a name the proxy resolves
+// differently, are not caught.
+func Transport(tr *http.Transport) *http.Transport {
+    if tr == nil {
+        tr = http.DefaultTransport.(*http.Transport)
+    }
+    out := tr.Clone()
+    // Taken from the unwrapped proxy func, so no lookup runs here.
+    exempt := proxyAddrs(out)
+    if proxy := out.Proxy; proxy != nil {
+        out.Proxy = func(req *http.Request) (*url.URL, error) {
+            u, err := proxy(req)
+            if err != nil || u == nil {
+                return u, err
+            }
+            if err := checkProxiedHost(req.Context(), req.URL.Hostname()); err != nil {
+                return nil, err
+            }
+            return u, nil
+        }
+    }
+    guarded := Dialer(nil)
+    direct := &net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second}
+    out.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+        if exempt[strings.ToLower(addr)] {
+            return direct.DialContext(ctx, network, addr)
+        }
+        conn, err := guarded.DialContext(ctx, network, addr)
+        var pe PolicyError
+        if err != nil && errors.As(err, &pe) {
+            pe.Host, _, _ = net.SplitHostPort(addr)
+            return nil, pe
+        }
+        return conn, err
+    }
+    return out
+}
+
+// checkProxiedHost refuses a request the proxy would forward to a
+// non-public address, judged by the host name and its local resolution.
+// Names that do not resolve locally are left to the proxy.
+func checkProxiedHost(ctx context.Context, host string) error {
+    if reason := HostReason(host); reason != "" {
+        return PolicyError{Host: host, IP: host, Reason: reason}
+    }
+    if net.ParseIP(strings.Trim(host, "[]")) != nil {
+        return nil
+    }
+    addrs, err := lookupIPAddr(ctx, host)
+    if err != nil {
+        return nil
+    }
+    for _, a := range addrs {
+        if reason := BlockedReason(a.IP); reason != "" {
+            return PolicyError{Host: host, IP: a.IP.String(), Reason: reason}
+        }
+    }
+    return nil
+}
+
+// Client returns a shallow copy of c whose transport is guarded. Clients
+// with a custom RoundTripper other than *http.Transport are returned as is.
+func Client(c *http.Client) *http.Client {
+    if c == nil {
+        c = &http.Client{Timeout: 10 * time.Second}
+    }
+    out := *c
+    switch tr := c.Transport.(type) {
+    case nil:
+        out.Transport = Transport(nil)
+    case *http.Transport:
+        out.Transport = Transport(tr)
+    }
+    return &out
+}
+
+// proxyAddrs returns the host:port of the proxies tr would use for ordinary
+// public http and https requests.
+func proxyAddrs(tr *http.Transport) map[string]bool {
+    addrs := map[string]bool{}
+    if tr.Proxy == nil {
+        return addrs
+    }
+    for _, scheme := range []string{"http", "https"} {
+        u, err := tr.Proxy(&http.Request{URL: &url.URL{Scheme: scheme, Host: "example.com"}})
+        if err != nil || u == nil {
+            continue
+        }
+        port := u.Port()
+        if port == "" {
+            switch u.Scheme {
+            case "https":
+                port = "443"
+            case "socks5":
+                port = "1080"
+            default:
+                port = "80"
+            }
+        }
+        addrs[strings.ToLower(net.JoinHostPort(u.Hostname(), port))] = true
+    }
+    return addrs
+}
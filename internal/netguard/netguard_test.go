@@ -0,0 +1,128 @@
+package netguard
+
+import (
+    "context"
+    "errors"
+    "io"
+    "net"
+    "net/http"
+    "net/http/httptest"
+    "net/url"
+    "strings"
+    "testing"
+)
+
+func TestBlockedReason(t *testing.T) {
+    cases := map[string]string{
+        "10.1.2.3":               "private",
+        "172.20.0.1":             "private",
+        "192.168.1.1":            "private",
+        "169.254.169.254":        "link-local",
+        "100.64.12.1":            "carrier-grade NAT",
+        "127.0.0.1":              "loopback",
+        "0.0.0.0":                "unspecified",
+        "::1":                    "loopback",
+        "::ffff:10.0.0.5":        "private",
+        "::ffff:169.254.169.254": "link-local",
+        "64:ff9b::a9fe:a9fe":     "link-local",
+        "2002:7f00:1::":          "loopback",
+        "fd12:3456::1":           "unique-local",
+        "fe80::1":                "link-local",
+        "8.8.8.8":                "",
+        "2606:4700:4700::1111":   "",
+    }
+    for in, want := range cases {
+        if got := BlockedReason(net.ParseIP(in)); got != want {
+            t.Errorf("BlockedReason(%s) = %q, want %q", in, got, want)
+        }
+    }
+}
+
+func TestHostReason(t *testing.T) {
+    for host, want := range map[string]string{
+        "localhost":      "loopback",
+        "api.localhost":  "loopback",
+        "[::1]":          "loopback",
+        "10.0.0.1":       "private",
+        "docs.example":   "",
+        "93.184.216.34":  "",
+    } {
+        if got := HostReason(host); got != want {
+            t.Errorf("HostReason(%q) = %q, want %q", host, got, want)
+        }
+    }
+}
+
+func TestClient_RefusesPrivateAddressAtDialTime(t *testing.T) {
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        _, _ = io.WriteString(w, "secret")
+    }))
+    defer srv.Close()
+
+    c := Client(srv.Client())
+    _, err := c.Get(srv.URL)
+    reason, denied := IsDenied(err)
+    if !denied {
+        t.Fatalf("expected a policy error, got %v", err)
+    }
+    if !strings.Contains(reason, "127.0.0.1") || !strings.Contains(reason, "loopback") {
+        t.Fatalf("unexpected reason %q", reason)
+    }
+}
+
+func TestTransport_ExemptsConfiguredProxy(t *testing.T) {
+    proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        _, _ = io.WriteString(w, "via proxy")
+    }))
+    defer proxy.Close()
+    pu, _ := url.Parse(proxy.URL)
+    defer func(old func(context.Context, string) ([]net.IPAddr, error)) { lookupIPAddr = old }(lookupIPAddr)
+    var lookups []string
+    lookupIPAddr = func(_ context.Context, host string) ([]net.IPAddr, error) {
+        lookups = append(lookups, host)
+        return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}}, nil
+    }
+
+    tr := Transport(&http.Transport{Proxy: http.ProxyURL(pu)})
+    if len(lookups) != 0 {
+        t.Fatalf("building the transport should not resolve anything, looked up %v", lookups)
+    }
+    resp, err := (&http.Client{Transport: tr}).Get("http://docs.example/")
+    if err != nil {
+        t.Fatalf("expected the proxy connection to be allowed: %v", err)
+    }
+    resp.Body.Close()
+}
+
+func TestTransport_ChecksProxiedHostsByLocalResolution(t *testing.T) {
+    proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        _, _ = io.WriteString(w, "via proxy")
+    }))
+    defer proxy.Close()
+    pu, _ := url.Parse(proxy.URL)
+    defer func(old func(context.Context, string) ([]net.IPAddr, error)) { lookupIPAddr = old }(lookupIPAddr)
+    lookupIPAddr = func(_ context.Context, host string) ([]net.IPAddr, error) {
+        switch host {
+        case "intranet.example":
+            return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}, {IP: net.ParseIP("10.0.0.7")}}, nil
+        case "docs.example":
+            return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}}, nil
+        }
+        return nil, errors.New("no such host")
+    }
+
+    c := &http.Client{Transport: Transport(&http.Transport{Proxy: http.ProxyURL(pu)})}
+    for _, target := range []string{"http://intranet.example/", "http://169.254.169.254/latest/meta-data/"} {
+        _, err := c.Get(target)
+        if _, denied := IsDenied(err); !denied {
+            t.Fatalf("%s: expected a policy error through the proxy, got %v", target, err)
+        }
+    }
+    for _, target := range []string{"http://docs.example/", "http://proxy-only.example/"} {
+        resp, err := c.Get(target)
+        if err != nil {
+            t.Fatalf("%s: expected the proxied request to pass: %v", target, err)
+        }
+        resp.Body.Close()
+    }
+}
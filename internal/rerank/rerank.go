@@ -0,0 +1,237 @@
+// Package rerank orders search results by semantic relevance to the research
+// brief using embeddings from an OpenAI-compatible /v1/embeddings endpoint.
+//
+// Each result's title and snippet is embedded and compared to an embedding of
+// the brief and outline. The final order uses maximal marginal relevance
+// (MMR): every pick maximizes
+//
+//  lambda*sim(query, d) - (1-lambda)*max sim(d, already picked)
+//
+// so near-identical results do not crowd out other relevant angles.
+package rerank
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "math"
+    "strings"
+
+    openai "github.com/sashabaranov/go-openai"
+
+    "github.com/hyperifyio/goresearch/internal/cache"
+    "github.com/hyperifyio/goresearch/internal/llm"
+    "github.com/hyperifyio/goresearch/internal/search"
+)
+
+// DefaultLambda weighs relevance against diversity in MMR. 1 ranks purely by
+// relevance; 0 purely by novelty.
+const DefaultLambda = 0.7
+
+// batchSize bounds the number of inputs sent in one embeddings request.
+const batchSize = 64
+
+// Reranker embeds results and orders them with MMR.
+type Reranker struct {
+    Client llm.Embedder
+    Model  string
+    // Lambda is the MMR trade-off in [0,1]; zero means DefaultLambda.
+    Lambda float64
+    // Cache stores vectors keyed by model and text so repeat runs do not
+    // re-embed the same snippets.
+    Cache *cache.LLMCache
+    // CacheOnly, when true, fails instead of calling the endpoint on a miss.
+    CacheOnly bool
+}
+
+// Score records why a result landed where it did.
+type Score struct {
+    URL string `json:"url"`
+    // Relevance is the cosine similarity to the query.
+    Relevance float64 `json:"relevance"`
+    // MMR is the marginal score at the time the result was picked.
+    MMR float64 `json:"mmr"`
+}
+
+// Rerank returns results reordered by MMR against query, with one Score per
+// result in the returned order. The input slice is not modified.
+func (r *Reranker) Rerank(ctx context.Context, query string, results []search.Result) ([]search.Result, []Score, error) {
+    if r == nil || r.Client == nil || strings.TrimSpace(r.Model) == "" {
+        return nil, nil, errors.New("rerank: embeddings not configured")
+    }
+    if len(results) == 0 {
+        return nil, nil, nil
+    }
+    texts := make([]string, 0, len(results)+1)
+    texts = append(texts, Text(query))
+    for _, res := range results {
+        texts = append(texts, Text(res.Title+"\n"+res.Snippet))
+    }
+    vecs, err := r.embed(ctx, texts)
+    if err != nil {
+        return nil, nil, err
+    }
+    lambda := r.Lambda
+    if lambda <= 0 || lambda > 1 {
+        lambda = DefaultLambda
+    }
+    order, scores := MMR(vecs[0], vecs[1:], lambda)
+    out := make([]search.Result, len(order))
+    for i, idx := range order {
+        out[i] = results[idx]
+        scores[i].URL = results[idx].URL
+    }
+    return out, scores, nil
+}
+
+// MMR returns the indices of docs in maximal-marginal-relevance order along
+// with the relevance and marginal score of each pick. Ties keep input order.
+func MMR(query []float32, docs [][]float32, lambda float64) ([]int, []Score) {
+    rel := make([]float64, len(docs))
+    for i, d := range docs {
+        rel[i] = Cosine(query, d)
+    }
+    // maxSim[i] is the highest similarity of doc i to any picked doc.
+    maxSim := make([]float64, len(docs))
+    picked := make([]bool, len(docs))
+    order := make([]int, 0, len(docs))
+    scores := make([]Score, 0, len(docs))
+    for len(order) < len(docs) {
+        best, bestScore := -1, math.Inf(-1)
+        for i := range docs {
+            if picked[i] {
+                continue
+            }
+            s := lambda * rel[i]
+            if len(order) > 0 {
+                s -= (1 - lambda) * maxSim[i]
+            }
+            if s > bestScore {
+                best, bestScore = i, s
+            }
+        }
+        picked[best] = true
+        order = append(order, best)
+        scores = append(scores, Score{Relevance: round3(rel[best]), MMR: round3(bestScore)})
+        for i := range docs {
+            if !picked[i] {
+                if s := Cosine(docs[i], docs[best]); s > maxSim[i] || len(order) == 1 {
+                    maxSim[i] = s
+                }
+            }
+        }
+    }
+    return order, scores
+}
+
+// Cosine returns the cosine similarity of a and b, or 0 when either is empty
+// or their lengths differ.
+func Cosine(a, b []float32) float64 {
+    if len(a) == 0 || len(a) != len(b) {
+        return 0
+    }
+    var dot, na, nb float64
+    for i := range a {
+        dot += float64(a[i]) * float64(b[i])
+        na += float64(a[i]) * float64(a[i])
+        nb += float64(b[i]) * float64(b[i])
+    }
+    if na == 0 || nb == 0 {
+        return 0
+    }
+    return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// Text normalizes an input before embedding: whitespace, including
+// newlines, is collapsed to single spaces as the embeddings API recommends.
+func Text(s string) string {
+    return strings.Join(strings.Fields(s), " ")
+}
+
+// embed returns one vector per text, serving cached vectors first and
+// requesting the rest in batches.
+func (r *Reranker) embed(ctx context.Context, texts []string) ([][]float32, error) {
+    vecs := make([][]float32, len(texts))
+    var missing []int
+    for i, t := range texts {
+        if r.Cache != nil {
+            if raw, ok, _ := r.Cache.Get(ctx, cacheKey(r.Model, t)); ok {
+                var v []float32
+                if err := json.Unmarshal(raw, &v); err == nil && len(v) > 0 {
+                    vecs[i] = v
+                    continue
+                }
+            }
+        }
+        missing = append(missing, i)
+    }
+    if len(missing) > 0 && r.CacheOnly {
+        return nil, fmt.Errorf("rerank cache-only: %d embeddings not found", len(missing))
+    }
+    for start := 0; start < len(missing); start += batchSize {
+        end := start + batchSize
+        if end > len(missing) {
+            end = len(missing)
+        }
+        batch := missing[start:end]
+        input := make([]string, len(batch))
+        for i, idx := range batch {
+            input[i] = texts[idx]
+        }
+        resp, err := r.Client.CreateEmbeddings(ctx, openai.EmbeddingRequestStrings{Input: input, Model: openai.EmbeddingModel(r.Model)})
+        if err != nil {
+            return nil, fmt.Errorf("embeddings call: %w", err)
+        }
+        if len(resp.Data) != len(batch) {
+            return nil, fmt.Errorf("embeddings: got %d vectors for %d inputs", len(resp.Data), len(batch))
+        }
+        byIndex := indicesArePermutation(resp.Data)
+        for i, d := range resp.Data {
+            pos := i
+            if byIndex {
+                pos = d.Index
+            }
+            vecs[batch[pos]] = d.Embedding
+        }
+    }
+    for i, v := range vecs {
+        if len(v) == 0 {
+            return nil, fmt.Errorf("embeddings: missing vector for input %d", i)
+        }
+    }
+    // Only vectors that all arrived are cached, so a bad response cannot
+    // poison later runs.
+    if r.Cache != nil {
+        for _, idx := range missing {
+            if b, err := json.Marshal(vecs[idx]); err == nil {
+                _ = r.Cache.Save(ctx, cacheKey(r.Model, texts[idx]), b)
+            }
+        }
+    }
+    return vecs, nil
+}
+
+// indicesArePermutation reports whether the response's index fields number
+// its vectors 0..n-1 exactly once each. Servers that omit index decode as
+// all zeros, in which case vectors are matched by position.
+func indicesArePermutation(data []openai.Embedding) bool {
+    seen := make([]bool, len(data))
+    for _, d := range data {
+        if d.Index < 0 || d.Index >= len(data) || seen[d.Index] {
+            return false
+        }
+        seen[d.Index] = true
+    }
+    return true
+}
+
+// cacheKey namespaces embedding vectors apart from chat responses that share
+// the LLM cache directory.
+func cacheKey(model, text string) string {
+    return cache.KeyFrom("embeddings:"+model, text)
+}
+
+func round3(v float64) float64 {
+    return math.Round(v*1000) / 1000
+}
@@ -0,0 +1,160 @@
+package rerank
+
+import (
+    "context"
+    "errors"
+    "hash/fnv"
+    "math"
+    "strings"
+    "testing"
+
+    openai "github.com/sashabaranov/go-openai"
+
+    "github.com/hyperifyio/goresearch/internal/cache"
+    "github.com/hyperifyio/goresearch/internal/search"
+)
+
+// bagOfWords embeds texts as hashed word counts so similar wording yields
+// similar vectors. noIndex leaves every index zero, as servers that omit
+// the field decode.
+type bagOfWords struct {
+    calls   int
+    noIndex bool
+}
+
+func (e *bagOfWords) CreateEmbeddings(_ context.Context, conv openai.EmbeddingRequestConverter) (openai.EmbeddingResponse, error) {
+    e.calls++
+    input, _ := conv.Convert().Input.([]string)
+    var resp openai.EmbeddingResponse
+    for i, s := range input {
+        v := make([]float32, 32)
+        for _, w := range strings.Fields(strings.ToLower(s)) {
+            h := fnv.New32a()
+            _, _ = h.Write([]byte(w))
+            v[h.Sum32()%32]++
+        }
+        d := openai.Embedding{Index: i, Embedding: v}
+        if e.noIndex {
+            d.Index = 0
+        }
+        resp.Data = append(resp.Data, d)
+    }
+    return resp, nil
+}
+
+type failingEmbedder struct{}
+
+func (failingEmbedder) CreateEmbeddings(context.Context, openai.EmbeddingRequestConverter) (openai.EmbeddingResponse, error) {
+    return openai.EmbeddingResponse{}, errors.New("unreachable")
+}
+
+func TestRerank_DemotesNearDuplicatesAndIrrelevant(t *testing.T) {
+    results := []search.Result{
+        {Title: "Pasta recipes", URL: "https://c.example/", Snippet: "boil water add salt"},
+        {Title: "Go channels", URL: "https://a.example/", Snippet: "concurrency with go channels and goroutines"},
+        {Title: "Go channels", URL: "https://mirror.example/", Snippet: "concurrency with go channels and goroutines"},
+        {Title: "Go mutexes", URL: "https://b.example/", Snippet: "concurrency with go mutexes and locks"},
+    }
+    r := &Reranker{Client: &bagOfWords{}, Model: "embed"}
+    out, scores, err := r.Rerank(context.Background(), "go concurrency channels goroutines mutexes", results)
+    if err != nil {
+        t.Fatal(err)
+    }
+    got := make([]string, len(out))
+    for i, res := range out {
+        got[i] = res.URL
+    }
+    want := []string{"https://a.example/", "https://b.example/", "https://mirror.example/", "https://c.example/"}
+    if strings.Join(got, " ") != strings.Join(want, " ") {
+        t.Fatalf("order = %v, want %v", got, want)
+    }
+    if len(scores) != 4 || scores[0].URL != want[0] || scores[0].Relevance <= scores[3].Relevance {
+        t.Fatalf("unexpected scores: %+v", scores)
+    }
+    // The mirror is as relevant as the original but ranks behind a less
+    // relevant, novel result.
+    if scores[2].Relevance < scores[1].Relevance {
+        t.Fatalf("expected mirror to be demoted for redundancy, not relevance: %+v", scores)
+    }
+}
+
+func TestRerank_CachesVectors(t *testing.T) {
+    dir := t.TempDir()
+    results := []search.Result{{Title: "A", URL: "https://a.example/", Snippet: "alpha"}, {Title: "B", URL: "https://b.example/", Snippet: "beta"}}
+    emb := &bagOfWords{}
+    r := &Reranker{Client: emb, Model: "embed", Cache: &cache.LLMCache{Dir: dir}}
+    first, _, err := r.Rerank(context.Background(), "alpha", results)
+    if err != nil || emb.calls != 1 {
+        t.Fatalf("first run: err=%v calls=%d", err, emb.calls)
+    }
+    offline := &Reranker{Client: failingEmbedder{}, Model: "embed", Cache: &cache.LLMCache{Dir: dir}, CacheOnly: true}
+    second, _, err := offline.Rerank(context.Background(), "alpha", results)
+    if err != nil {
+        t.Fatalf("cached run: %v", err)
+    }
+    if first[0].URL != second[0].URL || first[1].URL != second[1].URL {
+        t.Fatalf("cached order differs: %v vs %v", first, second)
+    }
+    if _, _, err := offline.Rerank(context.Background(), "gamma", results); err == nil {
+        t.Fatal("expected cache-only miss to fail")
+    }
+}
+
+// Test vectors from a server that omits index are matched by position and
+// cached under their own texts.
+func TestEmbed_MissingIndexFallsBackToPosition(t *testing.T) {
+    texts := []string{"alpha words", "beta words", "gamma"}
+    want, err := (&Reranker{Client: &bagOfWords{}, Model: "embed"}).embed(context.Background(), texts)
+    if err != nil {
+        t.Fatal(err)
+    }
+    dir := t.TempDir()
+    r := &Reranker{Client: &bagOfWords{noIndex: true}, Model: "embed", Cache: &cache.LLMCache{Dir: dir}}
+    got, err := r.embed(context.Background(), texts)
+    if err != nil {
+        t.Fatalf("embed without index: %v", err)
+    }
+    cached, err := (&Reranker{Client: failingEmbedder{}, Model: "embed", Cache: &cache.LLMCache{Dir: dir}, CacheOnly: true}).embed(context.Background(), texts)
+    if err != nil {
+        t.Fatalf("cached embed: %v", err)
+    }
+    for i := range texts {
+        if Cosine(got[i], want[i]) < 0.999 || Cosine(cached[i], want[i]) < 0.999 {
+            t.Fatalf("vector %d mismatched: got %v cached %v want %v", i, got[i], cached[i], want[i])
+        }
+    }
+}
+
+// shortEmbedder returns an empty vector for the last input.
+type shortEmbedder struct{ bagOfWords }
+
+func (e *shortEmbedder) CreateEmbeddings(ctx context.Context, conv openai.EmbeddingRequestConverter) (openai.EmbeddingResponse, error) {
+    resp, err := e.bagOfWords.CreateEmbeddings(ctx, conv)
+    resp.Data[len(resp.Data)-1].Embedding = nil
+    return resp, err
+}
+
+// Test a response with a missing vector fails without caching any of it.
+func TestEmbed_IncompleteResponseIsNotCached(t *testing.T) {
+    dir := t.TempDir()
+    r := &Reranker{Client: &shortEmbedder{}, Model: "embed", Cache: &cache.LLMCache{Dir: dir}}
+    if _, err := r.embed(context.Background(), []string{"alpha", "beta"}); err == nil {
+        t.Fatal("expected a missing vector to fail")
+    }
+    offline := &Reranker{Client: failingEmbedder{}, Model: "embed", Cache: &cache.LLMCache{Dir: dir}, CacheOnly: true}
+    if _, err := offline.embed(context.Background(), []string{"alpha"}); err == nil {
+        t.Fatal("expected nothing cached from the failed call")
+    }
+}
+
+func TestCosine(t *testing.T) {
+    if got := Cosine([]float32{1, 0}, []float32{1, 0}); math.Abs(got-1) > 1e-9 {
+        t.Fatalf("identical vectors: %v", got)
+    }
+    if got := Cosine([]float32{1, 0}, []float32{0, 1}); got != 0 {
+        t.Fatalf("orthogonal vectors: %v", got)
+    }
+    if got := Cosine([]float32{1}, []float32{1, 0}); got != 0 {
+        t.Fatalf("length mismatch: %v", got)
+    }
+}
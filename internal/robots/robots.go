@@ -5,7 +5,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
 	"net/url"
     "regexp"
@@ -14,6 +13,8 @@ import (
 	"time"
 
 	"github.com/hyperifyio/goresearch/internal/cache"
+	"github.com/hyperifyio/goresearch/internal/netguard"
+	"github.com/hyperifyio/goresearch/internal/warc"
     "github.com/rs/zerolog/log"
 )
 
@@ -59,10 +60,32 @@ type Manager struct {
     // OverrideConfirm must be true to activate OverrideAllowlist. This serves
     // as a second confirmation flag to avoid accidental policy bypass.
     OverrideConfirm   bool
+    // WARC, when set, records robots.txt requests and responses.
+    WARC *warc.Writer
 
 	mu  sync.Mutex
 	mem map[string]memEntry
 	now func() time.Time
+
+	clientOnce sync.Once
+	httpClient *http.Client
+}
+
+// client returns the HTTP client for robots.txt requests, guarded against
+// non-public addresses at dial time unless AllowPrivateHosts is set and
+// recording to WARC when configured.
+func (m *Manager) client() *http.Client {
+	m.clientOnce.Do(func() {
+		base := m.HTTPClient
+		if base == nil {
+			base = &http.Client{Timeout: 10 * time.Second}
+		}
+		if !m.AllowPrivateHosts {
+			base = netguard.Client(base)
+		}
+		m.httpClient = warc.Client(base, m.WARC)
+	})
+	return m.httpClient
 }
 
 type memEntry struct {
@@ -89,8 +112,10 @@ func (m *Manager) Get(ctx context.Context, robotsURL string) (Rules, Source, err
 		return Rules{}, SourceNetwork, fmt.Errorf("unsupported url scheme: %q", robotsURL)
 	}
 	host := u.Hostname()
-	if !m.AllowPrivateHosts && isLocalOrPrivateHost(host) {
-		return Rules{}, SourceNetwork, fmt.Errorf("private host not allowed: %s", host)
+	if !m.AllowPrivateHosts {
+		if reason := netguard.HostReason(host); reason != "" {
+			return Rules{}, SourceNetwork, netguard.PolicyError{Host: host, IP: host, Reason: reason}
+		}
 	}
 
     // Respect explicit override allowlist (requires confirmation). When active,
@@ -132,11 +157,7 @@ func (m *Manager) Get(ctx context.Context, robotsURL string) (Rules, Source, err
 	if lastMod != "" {
 		req.Header.Set("If-Modified-Since", lastMod)
 	}
-	client := m.HTTPClient
-	if client == nil {
-		client = &http.Client{Timeout: 10 * time.Second}
-	}
-    resp, err := client.Do(req)
+    resp, err := m.client().Do(req)
     if err != nil {
         // Treat network/timeouts as temporary disallow per policy; cache in memory
         rules := disallowAllRules()
@@ -431,16 +452,3 @@ func isHTTPScheme(u *url.URL) bool {
 	scheme := strings.ToLower(u.Scheme)
 	return scheme == "http" || scheme == "https"
 }
-
-func isLocalOrPrivateHost(host string) bool {
-	h := strings.ToLower(strings.TrimSpace(host))
-	if h == "localhost" || h == "localhost.localdomain" || h == "::1" || h == "[::1]" {
-		return true
-	}
-	if ip := net.ParseIP(h); ip != nil {
-		if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
-			return true
-		}
-	}
-	return false
-}
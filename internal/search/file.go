@@ -1,16 +1,43 @@
 package search
 
 import (
+    "bufio"
+    "bytes"
     "context"
     "encoding/json"
     "errors"
+    "fmt"
     "os"
+    "path/filepath"
     "regexp"
+    "sort"
     "strings"
+    "time"
+
+    "github.com/hyperifyio/goresearch/internal/dates"
 )
 
-// FileProvider loads search results from a local JSON file for offline/testing use.
-// The JSON file format is an array of objects: {"title": "...", "url": "...", "snippet": "..."}.
+// FileProvider loads search results from local fixtures for offline/testing use.
+//
+// Path may point at a single file or at a directory; a directory is read as
+// the concatenation of its *.json, *.jsonl and *.ndjson files in name order.
+// Three fixture shapes are accepted:
+//
+//   - Legacy JSON array of results: [{"title": "...", "url": "...", "snippet": "..."}].
+//     Every query is matched loosely against titles and snippets.
+//   - JSON object with per-query entries and an optional loose pool:
+//     {"queries": [{"query": "exact text", "results": [...]},
+//                  {"regex": "^golang .*", "error": "boom", "latency": "250ms"}],
+//      "results": [...]}
+//   - JSONL/NDJSON, one object per line: a line with "query" or "regex" is a
+//     query entry, any other line is a result for the loose pool.
+//
+// Query entries are tried in file order, exact matches (case-insensitive,
+// whitespace-collapsed) before regex matches, and the first hit wins. Its
+// results are returned in listed order, or by ascending "rank" when ranks are
+// present, so fixtures can model ranking. "error" makes the query fail with
+// that message and "latency" delays the response (honoring cancellation).
+// Queries without an entry fall back to the loose pool.
 type FileProvider struct {
     Path string
     Policy DomainPolicy // optional: filter results by domain
@@ -18,39 +45,232 @@ type FileProvider struct {
 
 func (f *FileProvider) Name() string { return "file" }
 
-func (f *FileProvider) Search(_ context.Context, query string, limit int) ([]Result, error) {
+// fixtureResult is a result as written in fixtures. Rank is optional; zero
+// means "keep listed order".
+type fixtureResult struct {
+    Title   string `json:"title"`
+    URL     string `json:"url"`
+    Snippet string `json:"snippet"`
+    Rank    int    `json:"rank"`
+    // Published is an optional publication date, e.g. "2024-03-01".
+    Published string `json:"published"`
+}
+
+// fixtureQuery maps an exact or regex query to a scripted response.
+type fixtureQuery struct {
+    Query   string          `json:"query"`
+    Regex   string          `json:"regex"`
+    Results []fixtureResult `json:"results"`
+    Error   string          `json:"error"`
+    Latency string          `json:"latency"`
+
+    re *regexp.Regexp
+}
+
+// fixtureSet is the merged content of all fixture files.
+type fixtureSet struct {
+    Queries []fixtureQuery  `json:"queries"`
+    Results []fixtureResult `json:"results"`
+}
+
+func (f *FileProvider) Search(ctx context.Context, query string, limit int) ([]Result, error) {
     if strings.TrimSpace(f.Path) == "" {
         return nil, errors.New("file provider path is empty")
     }
-    b, err := os.ReadFile(f.Path)
+    set, err := loadFixtures(f.Path)
     if err != nil {
         return nil, err
     }
-    var raw []Result
-    if err := json.Unmarshal(b, &raw); err != nil {
-        return nil, err
+    if entry := set.match(query); entry != nil {
+        if d := strings.TrimSpace(entry.Latency); d != "" {
+            dur, err := time.ParseDuration(d)
+            if err != nil {
+                return nil, fmt.Errorf("file provider: invalid latency %q: %w", d, err)
+            }
+            timer := time.NewTimer(dur)
+            select {
+            case <-ctx.Done():
+                timer.Stop()
+                return nil, ctx.Err()
+            case <-timer.C:
+            }
+        }
+        if entry.Error != "" {
+            return nil, fmt.Errorf("file provider: %s", entry.Error)
+        }
+        ranked := make([]fixtureResult, len(entry.Results))
+        copy(ranked, entry.Results)
+        sort.SliceStable(ranked, func(i, j int) bool {
+            ri, rj := ranked[i].Rank, ranked[j].Rank
+            if ri == 0 || rj == 0 {
+                return ri != 0 && rj == 0
+            }
+            return ri < rj
+        })
+        return f.collect(ranked, limit, func(fixtureResult) bool { return true }), nil
     }
     q := strings.ToLower(strings.TrimSpace(query))
+    return f.collect(set.Results, limit, func(r fixtureResult) bool {
+        return q == "" || strings.Contains(strings.ToLower(r.Title), q) || strings.Contains(strings.ToLower(r.Snippet), q) || matchesByTokens(q, r.Title+"\n"+r.Snippet)
+    }), nil
+}
+
+// collect converts fixture results that pass keep and the domain policy into
+// Results, stopping at limit when positive.
+func (f *FileProvider) collect(raw []fixtureResult, limit int, keep func(fixtureResult) bool) []Result {
     out := make([]Result, 0, len(raw))
     for _, r := range raw {
         if r.URL == "" || r.Title == "" {
             continue
         }
-        if q == "" || strings.Contains(strings.ToLower(r.Title), q) || strings.Contains(strings.ToLower(r.Snippet), q) || matchesByTokens(q, r.Title+"\n"+r.Snippet) {
-            // Apply optional domain policy
-            if f.Policy.Denylist != nil || f.Policy.Allowlist != nil {
-                if blocked, _ := isDomainBlocked(r.URL, f.Policy.Allowlist, f.Policy.Denylist); blocked {
-                    continue
-                }
+        if !keep(r) {
+            continue
+        }
+        // Apply optional domain policy
+        if f.Policy.Denylist != nil || f.Policy.Allowlist != nil {
+            if blocked, _ := isDomainBlocked(r.URL, f.Policy.Allowlist, f.Policy.Denylist); blocked {
+                continue
+            }
+        }
+        published, _ := dates.Parse(r.Published)
+        out = append(out, Result{Title: r.Title, URL: r.URL, Snippet: r.Snippet, Source: f.Name(), Published: published})
+        if limit > 0 && len(out) >= limit {
+            break
+        }
+    }
+    return out
+}
+
+// match returns the first query entry that matches exactly, then the first
+// regex entry that matches, or nil.
+func (s *fixtureSet) match(query string) *fixtureQuery {
+    norm := normalizeFixtureQuery(query)
+    for i := range s.Queries {
+        e := &s.Queries[i]
+        if e.Query != "" && normalizeFixtureQuery(e.Query) == norm {
+            return e
+        }
+    }
+    for i := range s.Queries {
+        e := &s.Queries[i]
+        if e.re != nil && e.re.MatchString(query) {
+            return e
+        }
+    }
+    return nil
+}
+
+func normalizeFixtureQuery(q string) string {
+    return strings.Join(strings.Fields(strings.ToLower(q)), " ")
+}
+
+// loadFixtures reads a fixture file or directory into a single set.
+func loadFixtures(path string) (*fixtureSet, error) {
+    info, err := os.Stat(path)
+    if err != nil {
+        return nil, err
+    }
+    files := []string{path}
+    if info.IsDir() {
+        entries, err := os.ReadDir(path)
+        if err != nil {
+            return nil, err
+        }
+        files = files[:0]
+        for _, e := range entries {
+            if e.IsDir() {
+                continue
+            }
+            switch strings.ToLower(filepath.Ext(e.Name())) {
+            case ".json", ".jsonl", ".ndjson":
+                files = append(files, filepath.Join(path, e.Name()))
+            }
+        }
+        sort.Strings(files)
+    }
+    set := &fixtureSet{}
+    for _, name := range files {
+        if err := set.loadFile(name); err != nil {
+            return nil, fmt.Errorf("%s: %w", name, err)
+        }
+    }
+    for i := range set.Queries {
+        e := &set.Queries[i]
+        if e.Query == "" && e.Regex == "" {
+            return nil, fmt.Errorf("file provider: query entry %d has neither query nor regex", i)
+        }
+        if e.Regex != "" {
+            re, err := regexp.Compile(e.Regex)
+            if err != nil {
+                return nil, fmt.Errorf("file provider: invalid regex %q: %w", e.Regex, err)
             }
-            r.Source = f.Name()
-            out = append(out, r)
-            if limit > 0 && len(out) >= limit {
-                break
+            e.re = re
+        }
+    }
+    return set, nil
+}
+
+// loadFile appends the content of one fixture file to the set.
+func (s *fixtureSet) loadFile(name string) error {
+    b, err := os.ReadFile(name)
+    if err != nil {
+        return err
+    }
+    switch strings.ToLower(filepath.Ext(name)) {
+    case ".jsonl", ".ndjson":
+        return s.loadLines(b)
+    }
+    trimmed := bytes.TrimSpace(b)
+    if len(trimmed) > 0 && trimmed[0] == '{' {
+        var obj fixtureSet
+        if err := json.Unmarshal(trimmed, &obj); err != nil {
+            return err
+        }
+        s.Queries = append(s.Queries, obj.Queries...)
+        s.Results = append(s.Results, obj.Results...)
+        return nil
+    }
+    var raw []fixtureResult
+    if err := json.Unmarshal(b, &raw); err != nil {
+        return err
+    }
+    s.Results = append(s.Results, raw...)
+    return nil
+}
+
+// loadLines parses JSONL content where each line is either a query entry or
+// a pool result. Blank lines and lines starting with '#' are ignored.
+func (s *fixtureSet) loadLines(b []byte) error {
+    sc := bufio.NewScanner(bytes.NewReader(b))
+    sc.Buffer(make([]byte, 0, 64*1024), 4<<20)
+    lineNo := 0
+    for sc.Scan() {
+        lineNo++
+        line := bytes.TrimSpace(sc.Bytes())
+        if len(line) == 0 || line[0] == '#' {
+            continue
+        }
+        var probe map[string]json.RawMessage
+        if err := json.Unmarshal(line, &probe); err != nil {
+            return fmt.Errorf("line %d: %w", lineNo, err)
+        }
+        _, hasQuery := probe["query"]
+        _, hasRegex := probe["regex"]
+        if hasQuery || hasRegex {
+            var e fixtureQuery
+            if err := json.Unmarshal(line, &e); err != nil {
+                return fmt.Errorf("line %d: %w", lineNo, err)
             }
+            s.Queries = append(s.Queries, e)
+            continue
+        }
+        var r fixtureResult
+        if err := json.Unmarshal(line, &r); err != nil {
+            return fmt.Errorf("line %d: %w", lineNo, err)
         }
+        s.Results = append(s.Results, r)
     }
-    return out, nil
+    return sc.Err()
 }
 
 // matchesByTokens performs a loose token-based match between the query and the
@@ -80,5 +300,3 @@ func matchesByTokens(query, text string) bool {
     }
     return false
 }
-
-
@@ -0,0 +1,141 @@
+package search
+
+import (
+    "context"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+    "time"
+)
+
+func writeFixture(t *testing.T, dir, name, content string) string {
+    t.Helper()
+    p := filepath.Join(dir, name)
+    if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+        t.Fatalf("write fixture: %v", err)
+    }
+    return p
+}
+
+func urlsOf(rs []Result) []string {
+    out := make([]string, len(rs))
+    for i, r := range rs {
+        out[i] = r.URL
+    }
+    return out
+}
+
+func TestFileProvider_LegacyArray(t *testing.T) {
+    p := writeFixture(t, t.TempDir(), "legacy.json", `[
+        {"title": "Go generics", "url": "https://go.dev/generics", "snippet": "type parameters"},
+        {"title": "Rust traits", "url": "https://rust-lang.org/traits", "snippet": "shared behavior"}
+    ]`)
+    f := &FileProvider{Path: p}
+    got, err := f.Search(context.Background(), "generics", 10)
+    if err != nil {
+        t.Fatalf("search: %v", err)
+    }
+    if len(got) != 1 || got[0].URL != "https://go.dev/generics" || got[0].Source != "file" {
+        t.Fatalf("unexpected results: %+v", got)
+    }
+}
+
+func TestFileProvider_PerQueryMappingAndRanking(t *testing.T) {
+    p := writeFixture(t, t.TempDir(), "fixtures.json", `{
+        "queries": [
+            {"query": "Query  A", "results": [
+                {"title": "Third", "url": "https://a.example/3", "rank": 3},
+                {"title": "Unranked", "url": "https://a.example/u"},
+                {"title": "First", "url": "https://a.example/1", "rank": 1}
+            ]},
+            {"regex": "^query b\\b", "results": [{"title": "B", "url": "https://b.example/"}]},
+            {"query": "query b exact", "results": [{"title": "Exact", "url": "https://b.example/exact"}]}
+        ],
+        "results": [{"title": "Pool hit", "url": "https://pool.example/", "snippet": "fallback pool"}]
+    }`)
+    f := &FileProvider{Path: p}
+
+    got, err := f.Search(context.Background(), "query a", 10)
+    if err != nil {
+        t.Fatalf("search: %v", err)
+    }
+    if want := "https://a.example/1,https://a.example/3,https://a.example/u"; strings.Join(urlsOf(got), ",") != want {
+        t.Fatalf("unexpected ranking: %v", urlsOf(got))
+    }
+
+    got, _ = f.Search(context.Background(), "query b exact", 10)
+    if len(got) != 1 || got[0].URL != "https://b.example/exact" {
+        t.Fatalf("expected exact entry to beat regex, got %+v", got)
+    }
+    got, _ = f.Search(context.Background(), "query b something", 10)
+    if len(got) != 1 || got[0].URL != "https://b.example/" {
+        t.Fatalf("expected regex entry, got %+v", got)
+    }
+    got, _ = f.Search(context.Background(), "fallback", 10)
+    if len(got) != 1 || got[0].URL != "https://pool.example/" {
+        t.Fatalf("expected pool fallback, got %+v", got)
+    }
+    got, _ = f.Search(context.Background(), "query a", 2)
+    if len(got) != 2 {
+        t.Fatalf("expected limit to apply, got %d", len(got))
+    }
+}
+
+func TestFileProvider_SimulatedErrorAndLatency(t *testing.T) {
+    p := writeFixture(t, t.TempDir(), "fixtures.jsonl", strings.Join([]string{
+        `# scripted failures`,
+        `{"query": "broken", "error": "upstream unavailable"}`,
+        `{"query": "slow", "latency": "50ms", "results": [{"title": "Slow", "url": "https://slow.example/"}]}`,
+        ``,
+        `{"title": "Pooled", "url": "https://pool.example/", "snippet": "jsonl pool entry"}`,
+    }, "\n"))
+    f := &FileProvider{Path: p}
+
+    if _, err := f.Search(context.Background(), "broken", 10); err == nil || !strings.Contains(err.Error(), "upstream unavailable") {
+        t.Fatalf("expected simulated error, got %v", err)
+    }
+
+    start := time.Now()
+    got, err := f.Search(context.Background(), "slow", 10)
+    if err != nil || len(got) != 1 {
+        t.Fatalf("slow search: %v %+v", err, got)
+    }
+    if time.Since(start) < 50*time.Millisecond {
+        t.Fatalf("expected simulated latency")
+    }
+
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+    if _, err := f.Search(ctx, "slow", 10); err == nil {
+        t.Fatalf("expected cancellation during simulated latency")
+    }
+
+    got, _ = f.Search(context.Background(), "pool", 10)
+    if len(got) != 1 || got[0].URL != "https://pool.example/" {
+        t.Fatalf("expected jsonl pool result, got %+v", got)
+    }
+}
+
+func TestFileProvider_Directory(t *testing.T) {
+    dir := t.TempDir()
+    writeFixture(t, dir, "b.jsonl", `{"query": "shared", "results": [{"title": "From B", "url": "https://b.example/"}]}`)
+    writeFixture(t, dir, "a.json", `{"queries": [{"query": "shared", "results": [{"title": "From A", "url": "https://a.example/"}]}]}`)
+    writeFixture(t, dir, "notes.txt", `ignored`)
+
+    f := &FileProvider{Path: dir}
+    got, err := f.Search(context.Background(), "shared", 10)
+    if err != nil {
+        t.Fatalf("search: %v", err)
+    }
+    if len(got) != 1 || got[0].URL != "https://a.example/" {
+        t.Fatalf("expected first file in name order to win, got %+v", got)
+    }
+}
+
+func TestFileProvider_InvalidRegex(t *testing.T) {
+    p := writeFixture(t, t.TempDir(), "bad.json", `{"queries": [{"regex": "("}]}`)
+    if _, err := (&FileProvider{Path: p}).Search(context.Background(), "q", 10); err == nil {
+        t.Fatalf("expected error for invalid regex")
+    }
+}
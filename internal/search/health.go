@@ -0,0 +1,262 @@
+package search
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "sort"
+    "strings"
+    "sync"
+    "time"
+)
+
+// ErrCircuitOpen is returned by Guarded once a provider has failed often
+// enough that further calls are skipped for the rest of the run.
+var ErrCircuitOpen = errors.New("search provider circuit open")
+
+// ProviderHealth summarizes how a provider behaved during a run.
+type ProviderHealth struct {
+    Name        string `json:"name"`
+    Requests    int    `json:"requests"`
+    Failures    int    `json:"failures"`
+    Retries     int    `json:"retries"`
+    Skipped     int    `json:"skipped"`
+    CircuitOpen bool   `json:"circuit_open"`
+    LastError   string `json:"last_error,omitempty"`
+}
+
+// ErrorRate returns the fraction of requests that failed.
+func (h ProviderHealth) ErrorRate() float64 {
+    if h.Requests == 0 {
+        return 0
+    }
+    return float64(h.Failures) / float64(h.Requests)
+}
+
+// EngineHealth summarizes how an upstream SearxNG engine behaved.
+type EngineHealth struct {
+    Name         string `json:"name"`
+    OK           int    `json:"ok"`
+    Unresponsive int    `json:"unresponsive"`
+    LastReason   string `json:"last_reason,omitempty"`
+}
+
+// HealthTracker aggregates provider and engine health across a run. It is
+// safe for concurrent use; the zero value is ready to use.
+type HealthTracker struct {
+    // EngineFailureThreshold is the number of consecutive unresponsive reports
+    // after which an engine is considered down. Defaults to 2.
+    EngineFailureThreshold int
+
+    mu            sync.Mutex
+    providers     map[string]*ProviderHealth
+    engines       map[string]*EngineHealth
+    engineStrikes map[string]int
+}
+
+func (t *HealthTracker) provider(name string) *ProviderHealth {
+    if t.providers == nil {
+        t.providers = map[string]*ProviderHealth{}
+    }
+    p := t.providers[name]
+    if p == nil {
+        p = &ProviderHealth{Name: name}
+        t.providers[name] = p
+    }
+    return p
+}
+
+func (t *HealthTracker) engine(name string) *EngineHealth {
+    if t.engines == nil {
+        t.engines = map[string]*EngineHealth{}
+        t.engineStrikes = map[string]int{}
+    }
+    e := t.engines[name]
+    if e == nil {
+        e = &EngineHealth{Name: name}
+        t.engines[name] = e
+    }
+    return e
+}
+
+// RecordAttempt records the outcome of a single provider request.
+func (t *HealthTracker) RecordAttempt(provider string, err error) {
+    if t == nil {
+        return
+    }
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    p := t.provider(provider)
+    p.Requests++
+    if err != nil {
+        p.Failures++
+        p.LastError = err.Error()
+    }
+}
+
+func (t *HealthTracker) recordRetry(provider string) {
+    if t == nil {
+        return
+    }
+    t.mu.Lock()
+    t.provider(provider).Retries++
+    t.mu.Unlock()
+}
+
+func (t *HealthTracker) recordSkip(provider string) {
+    if t == nil {
+        return
+    }
+    t.mu.Lock()
+    t.provider(provider).Skipped++
+    t.mu.Unlock()
+}
+
+func (t *HealthTracker) markOpen(provider string) {
+    if t == nil {
+        return
+    }
+    t.mu.Lock()
+    t.provider(provider).CircuitOpen = true
+    t.mu.Unlock()
+}
+
+// RecordEngine records whether an upstream engine answered. A non-empty
+// reason marks the engine unresponsive (e.g. "CAPTCHA", "timeout").
+func (t *HealthTracker) RecordEngine(name, reason string) {
+    if t == nil || strings.TrimSpace(name) == "" {
+        return
+    }
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    e := t.engine(name)
+    if strings.TrimSpace(reason) == "" {
+        e.OK++
+        t.engineStrikes[name] = 0
+        return
+    }
+    e.Unresponsive++
+    e.LastReason = reason
+    t.engineStrikes[name]++
+}
+
+// EngineDown reports whether the engine has been unresponsive for at least
+// EngineFailureThreshold consecutive reports.
+func (t *HealthTracker) EngineDown(name string) bool {
+    if t == nil {
+        return false
+    }
+    threshold := t.EngineFailureThreshold
+    if threshold <= 0 {
+        threshold = 2
+    }
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    return t.engineStrikes[name] >= threshold
+}
+
+// Providers returns a snapshot of provider health sorted by name.
+func (t *HealthTracker) Providers() []ProviderHealth {
+    if t == nil {
+        return nil
+    }
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    out := make([]ProviderHealth, 0, len(t.providers))
+    for _, p := range t.providers {
+        out = append(out, *p)
+    }
+    sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+    return out
+}
+
+// Engines returns a snapshot of engine health sorted by name.
+func (t *HealthTracker) Engines() []EngineHealth {
+    if t == nil {
+        return nil
+    }
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    out := make([]EngineHealth, 0, len(t.engines))
+    for _, e := range t.engines {
+        out = append(out, *e)
+    }
+    sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+    return out
+}
+
+// Guarded wraps a Provider with retry/backoff and a circuit breaker. After
+// FailureThreshold consecutive failed searches (each after exhausting its
+// retries) the circuit opens and every later call fails fast with
+// ErrCircuitOpen for the lifetime of the wrapper, which is one run.
+type Guarded struct {
+    Provider Provider
+    Health   *HealthTracker
+    // MaxAttempts is the total number of tries per search. Defaults to 1.
+    MaxAttempts int
+    // Backoff is the delay before the first retry; it doubles per retry.
+    Backoff time.Duration
+    // FailureThreshold opens the circuit after this many consecutive failed
+    // searches. Zero disables the circuit breaker.
+    FailureThreshold int
+
+    mu          sync.Mutex
+    consecutive int
+    open        bool
+}
+
+func (g *Guarded) Name() string { return g.Provider.Name() }
+
+func (g *Guarded) Search(ctx context.Context, query string, limit int) ([]Result, error) {
+    name := g.Name()
+    g.mu.Lock()
+    open := g.open
+    g.mu.Unlock()
+    if open {
+        g.Health.recordSkip(name)
+        return nil, fmt.Errorf("%s: %w", name, ErrCircuitOpen)
+    }
+    attempts := g.MaxAttempts
+    if attempts <= 0 {
+        attempts = 1
+    }
+    delay := g.Backoff
+    var lastErr error
+    for i := 0; i < attempts; i++ {
+        if i > 0 {
+            g.Health.recordRetry(name)
+            if delay > 0 {
+                timer := time.NewTimer(delay)
+                select {
+                case <-ctx.Done():
+                    timer.Stop()
+                    return nil, ctx.Err()
+                case <-timer.C:
+                }
+                delay *= 2
+            }
+        }
+        res, err := g.Provider.Search(ctx, query, limit)
+        if err == nil {
+            g.Health.RecordAttempt(name, nil)
+            g.mu.Lock()
+            g.consecutive = 0
+            g.mu.Unlock()
+            return res, nil
+        }
+        // Cancellation is the caller's decision, not a provider fault.
+        if ctx.Err() != nil {
+            return nil, err
+        }
+        g.Health.RecordAttempt(name, err)
+        lastErr = err
+    }
+    g.mu.Lock()
+    g.consecutive++
+    if g.FailureThreshold > 0 && g.consecutive >= g.FailureThreshold && !g.open {
+        g.open = true
+        g.Health.markOpen(name)
+    }
+    g.mu.Unlock()
+    return nil, lastErr
+}
@@ -0,0 +1,145 @@
+package search
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "sync/atomic"
+    "testing"
+)
+
+type flakyProvider struct {
+    calls    int32
+    failures int32 // number of leading calls that fail; -1 fails forever
+}
+
+func (f *flakyProvider) Name() string { return "flaky" }
+
+func (f *flakyProvider) Search(ctx context.Context, query string, limit int) ([]Result, error) {
+    n := atomic.AddInt32(&f.calls, 1)
+    if f.failures < 0 || n <= f.failures {
+        return nil, errors.New("upstream 502")
+    }
+    return []Result{{Title: "ok", URL: "https://ok.example/"}}, nil
+}
+
+func TestGuarded_RetriesThenSucceeds(t *testing.T) {
+    h := &HealthTracker{}
+    inner := &flakyProvider{failures: 1}
+    g := &Guarded{Provider: inner, Health: h, MaxAttempts: 3}
+    got, err := g.Search(context.Background(), "q", 5)
+    if err != nil || len(got) != 1 {
+        t.Fatalf("expected success after retry, got %v %+v", err, got)
+    }
+    ph := h.Providers()
+    if len(ph) != 1 || ph[0].Requests != 2 || ph[0].Failures != 1 || ph[0].Retries != 1 {
+        t.Fatalf("unexpected health: %+v", ph)
+    }
+    if ph[0].ErrorRate() != 0.5 {
+        t.Fatalf("unexpected error rate: %v", ph[0].ErrorRate())
+    }
+}
+
+func TestGuarded_OpensCircuitAfterConsecutiveFailures(t *testing.T) {
+    h := &HealthTracker{}
+    inner := &flakyProvider{failures: -1}
+    g := &Guarded{Provider: inner, Health: h, MaxAttempts: 2, FailureThreshold: 2}
+    for i := 0; i < 2; i++ {
+        if _, err := g.Search(context.Background(), "q", 5); err == nil || errors.Is(err, ErrCircuitOpen) {
+            t.Fatalf("call %d: expected provider error, got %v", i, err)
+        }
+    }
+    if _, err := g.Search(context.Background(), "q", 5); !errors.Is(err, ErrCircuitOpen) {
+        t.Fatalf("expected circuit open, got %v", err)
+    }
+    if c := atomic.LoadInt32(&inner.calls); c != 4 {
+        t.Fatalf("expected no provider calls once open, got %d", c)
+    }
+    ph := h.Providers()[0]
+    if !ph.CircuitOpen || ph.Skipped != 1 || ph.Failures != 4 {
+        t.Fatalf("unexpected health: %+v", ph)
+    }
+}
+
+func TestGuarded_DoesNotCountCancellation(t *testing.T) {
+    h := &HealthTracker{}
+    g := &Guarded{Provider: &flakyProvider{failures: -1}, Health: h, MaxAttempts: 3, FailureThreshold: 1}
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+    if _, err := g.Search(ctx, "q", 5); err == nil {
+        t.Fatalf("expected error")
+    }
+    if _, err := g.Search(context.Background(), "q", 5); errors.Is(err, ErrCircuitOpen) {
+        t.Fatalf("cancellation must not open the circuit")
+    }
+}
+
+func TestSearxNG_SkipsEnginesKnownDown(t *testing.T) {
+    var engineCalls int32
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        if r.URL.Path == "/w/api.php" {
+            _ = json.NewEncoder(w).Encode([]any{"q", []string{}, []string{}, []string{}})
+            return
+        }
+        if r.URL.Query().Get("engines") != "" {
+            atomic.AddInt32(&engineCalls, 1)
+        }
+        _ = json.NewEncoder(w).Encode(map[string]any{
+            "results": []any{},
+            "unresponsive_engines": [][]string{
+                {"duckduckgo_html", "CAPTCHA"},
+                {"wikipedia", "timeout"},
+            },
+        })
+    }))
+    defer srv.Close()
+
+    h := &HealthTracker{}
+    s := &SearxNG{BaseURL: srv.URL, HTTPClient: srv.Client(), Health: h}
+    // The direct OpenSearch fallback goes to the real Wikipedia; mark it down
+    // so the test stays offline.
+    h.RecordEngine(wikipediaFallbackEngine, "offline")
+    h.RecordEngine(wikipediaFallbackEngine, "offline")
+
+    if _, err := s.Search(context.Background(), "q", 5); err != nil {
+        t.Fatalf("search: %v", err)
+    }
+    first := atomic.LoadInt32(&engineCalls)
+    if first == 0 {
+        t.Fatalf("expected engine fallbacks on first query")
+    }
+    if !h.EngineDown("duckduckgo_html") || !h.EngineDown("wikipedia") {
+        t.Fatalf("expected engines marked down: %+v", h.Engines())
+    }
+    if _, err := s.Search(context.Background(), "q", 5); err != nil {
+        t.Fatalf("search: %v", err)
+    }
+    if atomic.LoadInt32(&engineCalls) != first {
+        t.Fatalf("expected down engines to be skipped on later queries")
+    }
+    var reasons []string
+    for _, e := range h.Engines() {
+        reasons = append(reasons, e.Name+"="+e.LastReason)
+    }
+    if !strings.Contains(strings.Join(reasons, ","), "duckduckgo_html=CAPTCHA") {
+        t.Fatalf("expected CAPTCHA reason recorded: %v", reasons)
+    }
+}
+
+func TestHealthTracker_EngineRecovers(t *testing.T) {
+    h := &HealthTracker{EngineFailureThreshold: 2}
+    h.RecordEngine("bing", "timeout")
+    h.RecordEngine("bing", "")
+    h.RecordEngine("bing", "timeout")
+    if h.EngineDown("bing") {
+        t.Fatalf("a success in between should reset the failure streak")
+    }
+    h.RecordEngine("bing", "timeout")
+    if !h.EngineDown("bing") {
+        t.Fatalf("expected engine down after consecutive failures")
+    }
+}
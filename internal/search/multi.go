@@ -0,0 +1,72 @@
+package search
+
+import (
+    "context"
+    "strings"
+)
+
+// Multi composes several providers into one. Each query is sent to every
+// provider in order; results are interleaved round-robin so that no single
+// provider crowds out the others, and exact duplicate URLs are dropped.
+// Provider errors are tolerated as long as at least one provider succeeds.
+type Multi struct {
+    Providers []Provider
+}
+
+func (m *Multi) Name() string {
+    names := make([]string, 0, len(m.Providers))
+    for _, p := range m.Providers {
+        if p != nil {
+            names = append(names, p.Name())
+        }
+    }
+    return strings.Join(names, "+")
+}
+
+func (m *Multi) Search(ctx context.Context, query string, limit int) ([]Result, error) {
+    if limit <= 0 {
+        limit = 10
+    }
+    groups := make([][]Result, 0, len(m.Providers))
+    var firstErr error
+    for _, p := range m.Providers {
+        if p == nil {
+            continue
+        }
+        res, err := p.Search(ctx, query, limit)
+        if err != nil {
+            if firstErr == nil {
+                firstErr = err
+            }
+            continue
+        }
+        groups = append(groups, res)
+    }
+    if len(groups) == 0 && firstErr != nil {
+        return nil, firstErr
+    }
+    seen := map[string]struct{}{}
+    out := make([]Result, 0, limit)
+    for i := 0; len(out) < limit; i++ {
+        progressed := false
+        for _, g := range groups {
+            if i >= len(g) {
+                continue
+            }
+            progressed = true
+            r := g[i]
+            if _, dup := seen[r.URL]; dup {
+                continue
+            }
+            seen[r.URL] = struct{}{}
+            out = append(out, r)
+            if len(out) >= limit {
+                break
+            }
+        }
+        if !progressed {
+            break
+        }
+    }
+    return out, nil
+}
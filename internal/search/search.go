@@ -2,6 +2,7 @@ package search
 
 import (
 	"context"
+	"time"
 )
 
 // Result represents a single search hit from any provider.
@@ -10,6 +11,9 @@ type Result struct {
 	URL     string
 	Snippet string
 	Source  string // provider name for observability
+	// Published is the publication date reported by the provider; zero when
+	// unknown.
+	Published time.Time
 }
 
 // Provider is a minimal interface for search providers.
@@ -24,3 +28,9 @@ type DomainPolicy struct {
     Allowlist []string
     Denylist  []string
 }
+
+// Blocked reports whether rawURL's host is rejected by the policy and, when
+// it is, why ("denylist" or "not-allowed").
+func (p DomainPolicy) Blocked(rawURL string) (bool, string) {
+    return isDomainBlocked(rawURL, p.Allowlist, p.Denylist)
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-    "io"
 	"net/http"
 	"net/url"
-    "sort"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/hyperifyio/goresearch/internal/dates"
+	"github.com/hyperifyio/goresearch/internal/netguard"
 )
 
 // isDomainBlocked returns true when urlStr's host is blocked by policy.
@@ -43,10 +45,32 @@ type SearxNG struct {
 	HTTPClient *http.Client
     UserAgent  string // optional custom UA
     Policy     DomainPolicy // optional: filter results by domain
+    // Health, when set, receives per-engine reports parsed from responses and
+    // lets the fallback chain skip engines that keep failing.
+    Health *HealthTracker
+    // TimeRange, when set, restricts results to "day", "week", "month" or
+    // "year" on engines that support SearxNG's time_range parameter.
+    TimeRange string
+    // AllowPrivateHosts disables the dial-time public-web guard on follow-up
+    // requests that leave the SearxNG instance (the direct Wikipedia
+    // fallback). The instance itself is usually local and never guarded.
+    AllowPrivateHosts bool
+
+    followUpOnce   sync.Once
+    followUpClient *http.Client
 }
 
 func (s *SearxNG) Name() string { return "searxng" }
 
+// followUpHTTPClient returns the client for requests to the public web.
+func (s *SearxNG) followUpHTTPClient() *http.Client {
+    if s.AllowPrivateHosts {
+        return s.HTTPClient
+    }
+    s.followUpOnce.Do(func() { s.followUpClient = netguard.Client(s.HTTPClient) })
+    return s.followUpClient
+}
+
 func (s *SearxNG) Search(ctx context.Context, query string, limit int) ([]Result, error) {
 	if s.BaseURL == "" {
 		return nil, fmt.Errorf("missing searxng base url")
@@ -70,6 +94,9 @@ func (s *SearxNG) Search(ctx context.Context, query string, limit int) ([]Result
 	q.Set("safesearch", "1")
 	q.Set("categories", "general")
 	q.Set("count", fmt.Sprintf("%d", limit))
+	if s.TimeRange != "" {
+		q.Set("time_range", s.TimeRange)
+	}
 	if s.APIKey != "" {
 		q.Set("apikey", s.APIKey)
 	}
@@ -98,6 +125,7 @@ func (s *SearxNG) Search(ctx context.Context, query string, limit int) ([]Result
     if err := json.NewDecoder(resp.Body).Decode(&sr); err != nil {
 		return nil, err
 	}
+    s.recordEngines(&sr)
     out := make([]Result, 0, len(sr.Results))
     for _, r := range sr.Results {
 		if r.URL == "" || r.Title == "" {
@@ -112,7 +140,8 @@ func (s *SearxNG) Search(ctx context.Context, query string, limit int) ([]Result
                 continue
             }
         }
-        out = append(out, Result{Title: title, URL: urlStr, Snippet: snippet, Source: s.Name()})
+        published, _ := dates.Parse(r.PublishedDate)
+        out = append(out, Result{Title: title, URL: urlStr, Snippet: snippet, Source: s.Name(), Published: published})
 		if len(out) >= limit {
 			break
 		}
@@ -138,32 +167,86 @@ func (s *SearxNG) Search(ctx context.Context, query string, limit int) ([]Result
     // Second-chance query: some configurations yield only unresponsive engines (e.g., CAPTCHA)
     // Try a targeted engines set that tends to work without API keys.
     if len(out) == 0 {
+        // Engines already known to be down (CAPTCHA, timeouts) are skipped so
+        // each query does not pay for the whole chain again.
         // First try: duckduckgo_html + wikipedia
-        if more := s.searchWithEngines(ctx, query, limit, []string{"duckduckgo_html", "wikipedia"}); len(more) > 0 {
+        if more := s.searchWithEngines(ctx, query, limit, s.liveEngines("duckduckgo_html", "wikipedia")); len(more) > 0 {
             return more, nil
         }
         // Second try: wikipedia only (for definition-like queries)
-        if more := s.searchWithEngines(ctx, query, limit, []string{"wikipedia"}); len(more) > 0 {
+        if more := s.searchWithEngines(ctx, query, limit, s.liveEngines("wikipedia")); len(more) > 0 {
             return more, nil
         }
-        // Third try: direct Wikipedia opensearch API as a last resort
-        if more := wikipediaOpenSearch(ctx, query, limit, s.HTTPClient); len(more) > 0 {
-            return more, nil
-        }
-        // Fourth try: simplified query through Wikipedia opensearch
-        if simp := simplifyForOpenSearch(query); simp != query {
-            if more := wikipediaOpenSearch(ctx, simp, limit, s.HTTPClient); len(more) > 0 {
-                return more, nil
+        // Last resort: direct Wikipedia OpenSearch (which retries with a
+        // simplified query itself). Summaries are skipped to keep the fallback
+        // cheap; use the standalone Wikipedia provider for richer snippets.
+        if !s.Health.EngineDown(wikipediaFallbackEngine) {
+            wp := &Wikipedia{HTTPClient: s.followUpHTTPClient(), UserAgent: s.UserAgent, Policy: s.Policy, DisableSummaries: true}
+            more, err := wp.Search(ctx, query, limit)
+            if err != nil {
+                s.Health.RecordEngine(wikipediaFallbackEngine, err.Error())
+            } else {
+                s.Health.RecordEngine(wikipediaFallbackEngine, "")
+                if len(more) > 0 {
+                    return more, nil
+                }
             }
         }
     }
 	return out, nil
 }
 
+// wikipediaFallbackEngine names the direct OpenSearch fallback in engine
+// health reports.
+const wikipediaFallbackEngine = "wikipedia_opensearch"
+
+// liveEngines filters out engines the health tracker considers down.
+func (s *SearxNG) liveEngines(engines ...string) []string {
+    out := make([]string, 0, len(engines))
+    for _, e := range engines {
+        if !s.Health.EngineDown(e) {
+            out = append(out, e)
+        }
+    }
+    return out
+}
+
+// recordEngines feeds engine outcomes from a response into the health
+// tracker: engines listed as unresponsive count as failures and engines that
+// contributed results count as successes.
+func (s *SearxNG) recordEngines(sr *searxResponse) {
+    if s.Health == nil {
+        return
+    }
+    down := map[string]bool{}
+    for _, pair := range sr.UnresponsiveEngines {
+        if len(pair) == 0 || strings.TrimSpace(pair[0]) == "" {
+            continue
+        }
+        reason := "unresponsive"
+        if len(pair) > 1 && strings.TrimSpace(pair[1]) != "" {
+            reason = strings.TrimSpace(pair[1])
+        }
+        down[pair[0]] = true
+        s.Health.RecordEngine(pair[0], reason)
+    }
+    seen := map[string]bool{}
+    for _, r := range sr.Results {
+        for _, e := range append([]string{r.Engine}, r.Engines...) {
+            if e == "" || seen[e] || down[e] {
+                continue
+            }
+            seen[e] = true
+            s.Health.RecordEngine(e, "")
+        }
+    }
+}
+
 // searchWithEngines performs a follow-up query forcing a specific engines list
 // and returns parsed results (including infobox fallback). Errors are swallowed
-// and an empty slice is returned on failure.
+// and an empty slice is returned on failure or when no engines are left.
 func (s *SearxNG) searchWithEngines(ctx context.Context, query string, limit int, engines []string) []Result {
+    if len(engines) == 0 { return nil }
     base := strings.TrimRight(s.BaseURL, "/")
     u, err := url.Parse(base)
     if err != nil { return nil }
@@ -182,6 +265,7 @@ func (s *SearxNG) searchWithEngines(ctx context.Context, query string, limit int
     if len(engines) > 0 {
         q.Set("engines", strings.Join(engines, ","))
     }
+    if s.TimeRange != "" { q.Set("time_range", s.TimeRange) }
     if s.APIKey != "" { q.Set("apikey", s.APIKey) }
     u.RawQuery = q.Encode()
     req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
@@ -195,6 +279,7 @@ func (s *SearxNG) searchWithEngines(ctx context.Context, query string, limit int
     if resp.StatusCode < 200 || resp.StatusCode > 299 { return nil }
     var sr searxResponse
     if err := json.NewDecoder(resp.Body).Decode(&sr); err != nil { return nil }
+    s.recordEngines(&sr)
     out := make([]Result, 0, len(sr.Results))
     for _, r := range sr.Results {
         if r.URL == "" || r.Title == "" { continue }
@@ -202,7 +287,8 @@ func (s *SearxNG) searchWithEngines(ctx context.Context, query string, limit int
         if s.Policy.Denylist != nil || s.Policy.Allowlist != nil {
             if blocked, _ := isDomainBlocked(urlStr, s.Policy.Allowlist, s.Policy.Denylist); blocked { continue }
         }
-        out = append(out, Result{Title: strings.TrimSpace(r.Title), URL: urlStr, Snippet: strings.TrimSpace(r.Content), Source: s.Name()})
+        published, _ := dates.Parse(r.PublishedDate)
+        out = append(out, Result{Title: strings.TrimSpace(r.Title), URL: urlStr, Snippet: strings.TrimSpace(r.Content), Source: s.Name(), Published: published})
         if len(out) >= limit { break }
     }
     if len(out) == 0 && len(sr.Infoboxes) > 0 {
@@ -224,82 +310,6 @@ func (s *SearxNG) searchWithEngines(ctx context.Context, query string, limit int
     return out
 }
 
-// wikipediaOpenSearch queries the Wikipedia opensearch API directly and returns
-// lightweight title/url/snippet tuples. It is used only as a last resort when
-// SearxNG engines yield zero results in constrained environments.
-func wikipediaOpenSearch(ctx context.Context, query string, limit int, hc *http.Client) []Result {
-    api := "https://en.wikipedia.org/w/api.php"
-    v := url.Values{}
-    v.Set("action", "opensearch")
-    v.Set("format", "json")
-    v.Set("limit", fmt.Sprintf("%d", limit))
-    v.Set("search", query)
-    req, err := http.NewRequestWithContext(ctx, http.MethodGet, api+"?"+v.Encode(), nil)
-    if err != nil { return nil }
-    if hc == nil { hc = &http.Client{Timeout: 10 * time.Second} }
-    resp, err := hc.Do(req)
-    if err != nil { return nil }
-    defer resp.Body.Close()
-    if resp.StatusCode < 200 || resp.StatusCode > 299 { return nil }
-    body, err := io.ReadAll(resp.Body)
-    if err != nil { return nil }
-    // The opensearch response is a JSON array: [query, titles[], descriptions[], urls[]]
-    var arr []any
-    if err := json.Unmarshal(body, &arr); err != nil { return nil }
-    if len(arr) < 4 { return nil }
-    titles, _ := arr[1].([]any)
-    descs, _ := arr[2].([]any)
-    urls, _ := arr[3].([]any)
-    n := len(urls)
-    out := make([]Result, 0, n)
-    for i := 0; i < n && i < limit; i++ {
-        t := ""
-        if i < len(titles) { if s, ok := titles[i].(string); ok { t = s } }
-        u := ""
-        if i < len(urls) { if s, ok := urls[i].(string); ok { u = s } }
-        snip := ""
-        if i < len(descs) { if s, ok := descs[i].(string); ok { snip = s } }
-        if strings.TrimSpace(u) == "" || strings.TrimSpace(t) == "" { continue }
-        out = append(out, Result{Title: t, URL: u, Snippet: snip, Source: "wikipedia"})
-    }
-    return out
-}
-
-// simplifyForOpenSearch reduces a natural-language query to a compact set of
-// keywords suitable for Wikipedia's opensearch. It removes punctuation and
-// common English stopwords, then keeps up to five longest remaining tokens.
-func simplifyForOpenSearch(q string) string {
-    s := strings.ToLower(q)
-    if strings.Contains(s, "love") {
-        return "love"
-    }
-    // Replace punctuation with spaces
-    repl := func(r rune) rune {
-        if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == ' ' {
-            return r
-        }
-        return ' '
-    }
-    s = strings.Map(repl, s)
-    stop := map[string]struct{}{"what": {}, "is": {}, "are": {}, "the": {}, "a": {}, "an": {}, "of": {}, "to": {}, "and": {}, "in": {}, "on": {}, "for": {}, "with": {}, "about": {}, "as": {}, "by": {}, "from": {}, "how": {}, "does": {}, "do": {}, "across": {}, "into": {}, "over": {}, "under": {}, "through": {}, "without": {}, "can": {}, "could": {}, "would": {}, "should": {}, "did": {}, "be": {}, "being": {}, "been": {}, "that": {}, "this": {}, "those": {}, "these": {}, "it": {}, "its": {}, "their": {}, "your": {}, "my": {}, "our": {}, "you": {}, "we": {}, "they": {}}
-    tokens := make([]string, 0, 8)
-    for _, t := range strings.Fields(s) {
-        if len(t) < 3 { continue }
-        if _, skip := stop[t]; skip { continue }
-        tokens = append(tokens, t)
-    }
-    if len(tokens) == 0 {
-        // Fallback: keep first two words from original
-        parts := strings.Fields(s)
-        if len(parts) > 2 { parts = parts[:2] }
-        return strings.Join(parts, " ")
-    }
-    // Keep up to five longest tokens
-    sort.Slice(tokens, func(i, j int) bool { return len(tokens[i]) > len(tokens[j]) })
-    if len(tokens) > 5 { tokens = tokens[:5] }
-    return strings.Join(tokens, " ")
-}
-
 // pickNonEmpty returns the first non-empty string among the two inputs.
 func pickNonEmpty(a, b string) string {
     if strings.TrimSpace(a) != "" {
@@ -313,7 +323,14 @@ type searxResponse struct {
         Title   string `json:"title"`
         URL     string `json:"url"`
         Content string `json:"content"`
+        Engine  string   `json:"engine"`
+        Engines []string `json:"engines"`
+        // PublishedDate is set by news-capable engines, usually ISO 8601.
+        PublishedDate string `json:"publishedDate"`
     } `json:"results"`
+    // UnresponsiveEngines lists [engine, reason] pairs for engines that
+    // failed during this query (e.g. CAPTCHA, timeout, access denied).
+    UnresponsiveEngines [][]string `json:"unresponsive_engines"`
     // Some engines like wikipedia often return rich data in infoboxes but
     // leave the results array empty. We treat these as fallback candidates.
     Infoboxes []struct {
@@ -0,0 +1,223 @@
+package search
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "sort"
+    "strings"
+    "time"
+)
+
+// Wikipedia implements Provider against a single Wikipedia language edition.
+// Titles and URLs come from the MediaWiki OpenSearch API; snippets come from
+// the REST summary endpoint so that results carry a short lead paragraph that
+// is useful for definition-style briefs.
+type Wikipedia struct {
+    // Language selects the edition, e.g. "en" or "fi". Defaults to "en".
+    Language string
+    // BaseURL overrides the edition root (e.g. "https://en.wikipedia.org" or a
+    // mirror). When set, Language is not used to build request URLs.
+    BaseURL    string
+    HTTPClient *http.Client
+    UserAgent  string       // optional custom UA
+    Policy     DomainPolicy // optional: filter results by domain
+    // DisableSummaries skips the REST summary lookups and keeps the (often
+    // empty) OpenSearch descriptions as snippets. This saves one request per
+    // result when the provider is only used as a cheap fallback.
+    DisableSummaries bool
+}
+
+func (w *Wikipedia) Name() string { return "wikipedia" }
+
+// Search runs an OpenSearch lookup for the query and, when nothing matches,
+// retries once with a keyword-only simplification of the query. Each hit is
+// then enriched with its REST summary extract unless summaries are disabled.
+func (w *Wikipedia) Search(ctx context.Context, query string, limit int) ([]Result, error) {
+    if limit <= 0 {
+        limit = 10
+    }
+    out, err := w.openSearch(ctx, query, limit)
+    if err != nil {
+        return nil, err
+    }
+    if len(out) == 0 {
+        if simp := simplifyForOpenSearch(query); simp != "" && simp != query {
+            out, err = w.openSearch(ctx, simp, limit)
+            if err != nil {
+                return nil, err
+            }
+        }
+    }
+    if !w.DisableSummaries {
+        for i := range out {
+            sum, err := w.summary(ctx, out[i].Title)
+            if err != nil || sum == nil {
+                // Summaries are best-effort; keep the OpenSearch description.
+                continue
+            }
+            if s := strings.TrimSpace(sum.Extract); s != "" {
+                out[i].Snippet = s
+            } else if s := strings.TrimSpace(sum.Description); s != "" && strings.TrimSpace(out[i].Snippet) == "" {
+                out[i].Snippet = s
+            }
+            if page := strings.TrimSpace(sum.ContentURLs.Desktop.Page); page != "" {
+                out[i].URL = page
+            }
+        }
+    }
+    return out, nil
+}
+
+// baseURL returns the edition root without a trailing slash.
+func (w *Wikipedia) baseURL() string {
+    if b := strings.TrimSpace(w.BaseURL); b != "" {
+        return strings.TrimRight(b, "/")
+    }
+    lang := strings.ToLower(strings.TrimSpace(w.Language))
+    if lang == "" {
+        lang = "en"
+    }
+    return "https://" + lang + ".wikipedia.org"
+}
+
+func (w *Wikipedia) client() *http.Client {
+    if w.HTTPClient != nil {
+        return w.HTTPClient
+    }
+    return &http.Client{Timeout: 10 * time.Second}
+}
+
+func (w *Wikipedia) get(ctx context.Context, rawURL string) ([]byte, int, error) {
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+    if err != nil {
+        return nil, 0, err
+    }
+    if w.UserAgent != "" {
+        req.Header.Set("User-Agent", w.UserAgent)
+    }
+    req.Header.Set("Accept", "application/json")
+    resp, err := w.client().Do(req)
+    if err != nil {
+        return nil, 0, err
+    }
+    defer resp.Body.Close()
+    body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+    if err != nil {
+        return nil, resp.StatusCode, err
+    }
+    return body, resp.StatusCode, nil
+}
+
+// openSearch queries the OpenSearch API and returns lightweight
+// title/url/description tuples.
+func (w *Wikipedia) openSearch(ctx context.Context, query string, limit int) ([]Result, error) {
+    v := url.Values{}
+    v.Set("action", "opensearch")
+    v.Set("format", "json")
+    v.Set("limit", fmt.Sprintf("%d", limit))
+    v.Set("search", query)
+    body, status, err := w.get(ctx, w.baseURL()+"/w/api.php?"+v.Encode())
+    if err != nil {
+        return nil, err
+    }
+    if status < 200 || status > 299 {
+        return nil, fmt.Errorf("wikipedia opensearch status: %d", status)
+    }
+    // The opensearch response is a JSON array: [query, titles[], descriptions[], urls[]]
+    var arr []any
+    if err := json.Unmarshal(body, &arr); err != nil {
+        return nil, fmt.Errorf("wikipedia opensearch: %w", err)
+    }
+    if len(arr) < 4 {
+        return nil, nil
+    }
+    titles, _ := arr[1].([]any)
+    descs, _ := arr[2].([]any)
+    urls, _ := arr[3].([]any)
+    out := make([]Result, 0, len(urls))
+    for i := 0; i < len(urls) && len(out) < limit; i++ {
+        t := ""
+        if i < len(titles) { if s, ok := titles[i].(string); ok { t = s } }
+        u, _ := urls[i].(string)
+        snip := ""
+        if i < len(descs) { if s, ok := descs[i].(string); ok { snip = s } }
+        t, u = strings.TrimSpace(t), strings.TrimSpace(u)
+        if u == "" || t == "" { continue }
+        if w.Policy.Denylist != nil || w.Policy.Allowlist != nil {
+            if blocked, _ := isDomainBlocked(u, w.Policy.Allowlist, w.Policy.Denylist); blocked { continue }
+        }
+        out = append(out, Result{Title: t, URL: u, Snippet: strings.TrimSpace(snip), Source: w.Name()})
+    }
+    return out, nil
+}
+
+// wikiSummary is the subset of the REST page summary payload we consume.
+type wikiSummary struct {
+    Title       string `json:"title"`
+    Description string `json:"description"`
+    Extract     string `json:"extract"`
+    ContentURLs struct {
+        Desktop struct {
+            Page string `json:"page"`
+        } `json:"desktop"`
+    } `json:"content_urls"`
+}
+
+// summary fetches the REST summary for an article title.
+func (w *Wikipedia) summary(ctx context.Context, title string) (*wikiSummary, error) {
+    t := strings.ReplaceAll(strings.TrimSpace(title), " ", "_")
+    if t == "" {
+        return nil, nil
+    }
+    body, status, err := w.get(ctx, w.baseURL()+"/api/rest_v1/page/summary/"+url.PathEscape(t))
+    if err != nil {
+        return nil, err
+    }
+    if status < 200 || status > 299 {
+        return nil, fmt.Errorf("wikipedia summary status: %d", status)
+    }
+    var s wikiSummary
+    if err := json.Unmarshal(body, &s); err != nil {
+        return nil, fmt.Errorf("wikipedia summary: %w", err)
+    }
+    return &s, nil
+}
+
+// simplifyForOpenSearch reduces a natural-language query to a compact set of
+// keywords suitable for Wikipedia's opensearch. It removes punctuation and
+// common English stopwords, then keeps up to five longest remaining tokens.
+func simplifyForOpenSearch(q string) string {
+    s := strings.ToLower(q)
+    if strings.Contains(s, "love") {
+        return "love"
+    }
+    // Replace punctuation with spaces
+    repl := func(r rune) rune {
+        if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == ' ' {
+            return r
+        }
+        return ' '
+    }
+    s = strings.Map(repl, s)
+    stop := map[string]struct{}{"what": {}, "is": {}, "are": {}, "the": {}, "a": {}, "an": {}, "of": {}, "to": {}, "and": {}, "in": {}, "on": {}, "for": {}, "with": {}, "about": {}, "as": {}, "by": {}, "from": {}, "how": {}, "does": {}, "do": {}, "across": {}, "into": {}, "over": {}, "under": {}, "through": {}, "without": {}, "can": {}, "could": {}, "would": {}, "should": {}, "did": {}, "be": {}, "being": {}, "been": {}, "that": {}, "this": {}, "those": {}, "these": {}, "it": {}, "its": {}, "their": {}, "your": {}, "my": {}, "our": {}, "you": {}, "we": {}, "they": {}}
+    tokens := make([]string, 0, 8)
+    for _, t := range strings.Fields(s) {
+        if len(t) < 3 { continue }
+        if _, skip := stop[t]; skip { continue }
+        tokens = append(tokens, t)
+    }
+    if len(tokens) == 0 {
+        // Fallback: keep first two words from original
+        parts := strings.Fields(s)
+        if len(parts) > 2 { parts = parts[:2] }
+        return strings.Join(parts, " ")
+    }
+    // Keep up to five longest tokens
+    sort.Slice(tokens, func(i, j int) bool { return len(tokens[i]) > len(tokens[j]) })
+    if len(tokens) > 5 { tokens = tokens[:5] }
+    return strings.Join(tokens, " ")
+}
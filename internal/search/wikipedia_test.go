@@ -0,0 +1,152 @@
+package search
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+)
+
+func newWikipediaStub(t *testing.T, hits map[string][]string) *httptest.Server {
+    t.Helper()
+    var srv *httptest.Server
+    srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "application/json")
+        switch {
+        case r.URL.Path == "/w/api.php":
+            q := r.URL.Query().Get("search")
+            titles := hits[q]
+            descs := make([]string, len(titles))
+            urls := make([]string, len(titles))
+            for i, title := range titles {
+                urls[i] = srv.URL + "/wiki/" + strings.ReplaceAll(title, " ", "_")
+            }
+            _ = json.NewEncoder(w).Encode([]any{q, titles, descs, urls})
+        case strings.HasPrefix(r.URL.Path, "/api/rest_v1/page/summary/"):
+            title := strings.TrimPrefix(r.URL.Path, "/api/rest_v1/page/summary/")
+            if title == "Missing" {
+                http.NotFound(w, r)
+                return
+            }
+            _ = json.NewEncoder(w).Encode(map[string]any{
+                "title":   title,
+                "extract": "Lead paragraph about " + strings.ReplaceAll(title, "_", " ") + ".",
+                "content_urls": map[string]any{
+                    "desktop": map[string]any{"page": "https://en.wikipedia.org/wiki/" + title},
+                },
+            })
+        default:
+            http.NotFound(w, r)
+        }
+    }))
+    return srv
+}
+
+func TestWikipedia_Search_UsesSummaryExtracts(t *testing.T) {
+    srv := newWikipediaStub(t, map[string][]string{"graph theory": {"Graph theory", "Missing"}})
+    defer srv.Close()
+
+    w := &Wikipedia{BaseURL: srv.URL, HTTPClient: srv.Client()}
+    got, err := w.Search(context.Background(), "graph theory", 5)
+    if err != nil {
+        t.Fatalf("search: %v", err)
+    }
+    if len(got) != 2 {
+        t.Fatalf("expected 2 results, got %d: %+v", len(got), got)
+    }
+    if got[0].Snippet != "Lead paragraph about Graph theory." {
+        t.Fatalf("unexpected snippet: %q", got[0].Snippet)
+    }
+    if got[0].URL != "https://en.wikipedia.org/wiki/Graph_theory" {
+        t.Fatalf("expected canonical page url, got %q", got[0].URL)
+    }
+    if got[0].Source != "wikipedia" {
+        t.Fatalf("unexpected source: %q", got[0].Source)
+    }
+    // A failed summary lookup keeps the OpenSearch result as-is.
+    if got[1].Title != "Missing" || !strings.HasPrefix(got[1].URL, srv.URL) {
+        t.Fatalf("unexpected fallback result: %+v", got[1])
+    }
+}
+
+func TestWikipedia_Search_RetriesSimplifiedQuery(t *testing.T) {
+    srv := newWikipediaStub(t, map[string][]string{"theory graph": {"Graph theory"}})
+    defer srv.Close()
+
+    w := &Wikipedia{BaseURL: srv.URL, HTTPClient: srv.Client(), DisableSummaries: true}
+    got, err := w.Search(context.Background(), "What is the graph theory?", 5)
+    if err != nil {
+        t.Fatalf("search: %v", err)
+    }
+    if len(got) != 1 || got[0].Title != "Graph theory" {
+        t.Fatalf("expected simplified retry to find Graph theory, got %+v", got)
+    }
+    if got[0].Snippet != "" {
+        t.Fatalf("summaries disabled; expected empty snippet, got %q", got[0].Snippet)
+    }
+}
+
+func TestWikipedia_Search_DomainPolicyAndLanguage(t *testing.T) {
+    srv := newWikipediaStub(t, map[string][]string{"q": {"Topic"}})
+    defer srv.Close()
+
+    w := &Wikipedia{BaseURL: srv.URL, HTTPClient: srv.Client(), Policy: DomainPolicy{Denylist: []string{"127.0.0.1"}}}
+    got, err := w.Search(context.Background(), "q", 5)
+    if err != nil {
+        t.Fatalf("search: %v", err)
+    }
+    if len(got) != 0 {
+        t.Fatalf("expected denylist to drop results, got %+v", got)
+    }
+
+    if b := (&Wikipedia{Language: "FI"}).baseURL(); b != "https://fi.wikipedia.org" {
+        t.Fatalf("unexpected language base url: %q", b)
+    }
+    if b := (&Wikipedia{}).baseURL(); b != "https://en.wikipedia.org" {
+        t.Fatalf("unexpected default base url: %q", b)
+    }
+}
+
+type stubProvider struct {
+    name    string
+    results []Result
+    err     error
+}
+
+func (s stubProvider) Name() string { return s.name }
+
+func (s stubProvider) Search(ctx context.Context, query string, limit int) ([]Result, error) {
+    return s.results, s.err
+}
+
+func TestMulti_InterleavesAndDedupes(t *testing.T) {
+    m := &Multi{Providers: []Provider{
+        stubProvider{name: "a", results: []Result{{URL: "https://x/1"}, {URL: "https://x/2"}, {URL: "https://x/3"}}},
+        stubProvider{name: "b", err: errors.New("down")},
+        stubProvider{name: "c", results: []Result{{URL: "https://x/2"}, {URL: "https://y/1"}}},
+    }}
+    if m.Name() != "a+b+c" {
+        t.Fatalf("unexpected name: %q", m.Name())
+    }
+    got, err := m.Search(context.Background(), "q", 4)
+    if err != nil {
+        t.Fatalf("search: %v", err)
+    }
+    want := []string{"https://x/1", "https://x/2", "https://y/1", "https://x/3"}
+    if len(got) != len(want) {
+        t.Fatalf("expected %d results, got %+v", len(want), got)
+    }
+    for i, u := range want {
+        if got[i].URL != u {
+            t.Fatalf("result %d: want %q got %q", i, u, got[i].URL)
+        }
+    }
+
+    all := &Multi{Providers: []Provider{stubProvider{name: "b", err: errors.New("down")}}}
+    if _, err := all.Search(context.Background(), "q", 4); err == nil {
+        t.Fatalf("expected error when every provider fails")
+    }
+}
@@ -4,8 +4,13 @@ import (
     "net/url"
     "sort"
     "strings"
+    "time"
 
+    "github.com/hyperifyio/goresearch/internal/credibility"
+    "github.com/hyperifyio/goresearch/internal/langid"
     "github.com/hyperifyio/goresearch/internal/search"
+    "github.com/hyperifyio/goresearch/internal/sourcetype"
+    "github.com/hyperifyio/goresearch/internal/urlnorm"
 )
 
 // Options configures selection constraints.
@@ -20,10 +25,78 @@ type Options struct {
     // detected language matches. This is a preference only; non-matching
     // languages are not filtered out.
     PreferredLanguage string
+    // AllowedLanguages, when non-empty, drops results confidently detected
+    // as a language outside this set of ISO 639-1 codes. Results whose
+    // language cannot be told reliably are kept.
+    AllowedLanguages []string
+    // RecencyMonths, when > 0, demotes results whose known publication date
+    // is older than this many months below fresher or undated results.
+    // Stale results are still eligible when nothing better is available.
+    RecencyMonths int
+    // RecencyExemptHostPatterns lists hosts never treated as stale, such as
+    // standards bodies whose documents stay authoritative for years. A
+    // pattern matches when the host equals it or ends with "."+pattern.
+    RecencyExemptHostPatterns []string
+    // Now is the reference time for recency; zero means time.Now.
+    Now time.Time
+    // URLNorm decides when two URLs name the same document.
+    URLNorm urlnorm.Options
+    // Scorer, when set, ranks candidates by credibility score (after the
+    // language and recency tiers) and records each score in the report.
+    Scorer *credibility.Scorer
+    // Ranked marks the input as already ordered by relevance, e.g. by the
+    // embeddings reranker. That order is kept within the language and
+    // recency tiers instead of re-sorting by score and snippet length.
+    Ranked bool
+    // Quotas balances the mix of source types (see package sourcetype).
+    // Minimums are filled first from the best-ranked candidates of each
+    // type; maximums cap a type outright.
+    Quotas sourcetype.Quotas
+}
+
+// Drop reasons reported by SelectWithReport.
+const (
+    ReasonMinSnippet    = "min-snippet"
+    ReasonInvalidURL    = "invalid-url"
+    ReasonSearchResults = "search-results-page"
+    ReasonDuplicate     = "duplicate"
+    ReasonPerDomainCap  = "per-domain-cap"
+    ReasonMaxTotal      = "max-total"
+    ReasonTypeQuota     = "type-quota"
+    ReasonLanguage      = "language"
+)
+
+// Decision explains what selection did with one candidate. Candidates are
+// reported in ranked order; Rank is 1-based.
+type Decision struct {
+    Result   search.Result
+    Rank     int
+    Selected bool
+    // Language is the reliably detected language of the title and snippet,
+    // or empty when unknown; LanguageConfidence is the detector's confidence.
+    Language           string
+    LanguageConfidence float64
+    // Stale is true when the result was demoted by the recency policy.
+    Stale bool
+    // SourceType is the URL-based source type (see package sourcetype).
+    SourceType string
+    // Credibility is the score breakdown when Options.Scorer is set.
+    Credibility *credibility.Breakdown
+    // Reason is empty for selected results, otherwise one of the Reason*
+    // constants.
+    Reason string
 }
 
 // Select applies diversity-aware selection with per-domain caps.
 func Select(results []search.Result, opt Options) []search.Result {
+    out, _ := SelectWithReport(results, opt)
+    return out
+}
+
+// SelectWithReport behaves like Select and additionally returns a decision
+// for every candidate, including the detected language and why dropped
+// candidates were not selected. It is meant for debugging selection.
+func SelectWithReport(results []search.Result, opt Options) ([]search.Result, []Decision) {
     if opt.MaxTotal <= 0 {
         opt.MaxTotal = 10
     }
@@ -37,22 +110,45 @@ func Select(results []search.Result, opt Options) []search.Result {
     // Simple heuristic: prefer results with longer snippets first to increase signal.
     // If PreferPrimary is set, apply a stable reordering that bumps known
     // authoritative hosts (e.g., standards bodies and vendor docs) to the top.
-    sorted := make([]search.Result, len(results))
-    copy(sorted, results)
-    // Precompute detected languages once for stability and to avoid recomputation in comparator
-    detected := make([]string, len(sorted))
-    if strings.TrimSpace(opt.PreferredLanguage) != "" {
-        for i, r := range sorted {
-            detected[i] = detectLanguage(strings.Join([]string{r.Title, r.Snippet}, " \n "))
+    // Languages are detected up front so the comparator stays cheap and the
+    // report can show them.
+    type candidate struct {
+        r     search.Result
+        lang  string
+        conf  float64
+        stale bool
+        cred  *credibility.Breakdown
+        typ   string
+    }
+    var cutoff time.Time
+    if opt.RecencyMonths > 0 {
+        now := opt.Now
+        if now.IsZero() {
+            now = time.Now()
+        }
+        cutoff = now.AddDate(0, -opt.RecencyMonths, 0)
+    }
+    sorted := make([]candidate, len(results))
+    for i, r := range results {
+        sorted[i] = candidate{r: r, typ: sourcetype.Classify(r.URL, sourcetype.Hints{})}
+        if det := langid.Detect(strings.Join([]string{r.Title, r.Snippet}, " \n ")); det.Reliable() {
+            sorted[i].lang, sorted[i].conf = det.Lang, det.Confidence
+        }
+        if !cutoff.IsZero() && !r.Published.IsZero() && r.Published.Before(cutoff) && !IsRecencyExempt(r.URL, opt.RecencyExemptHostPatterns) {
+            sorted[i].stale = true
+        }
+        if opt.Scorer != nil {
+            b := opt.Scorer.Score(r)
+            sorted[i].cred = &b
         }
     }
 
-    if opt.PreferPrimary || strings.TrimSpace(opt.PreferredLanguage) != "" {
+    if opt.PreferPrimary || strings.TrimSpace(opt.PreferredLanguage) != "" || opt.RecencyMonths > 0 || opt.Scorer != nil || opt.Ranked {
         sort.SliceStable(sorted, func(i, j int) bool {
             // First, apply language preference if requested
             if lang := strings.TrimSpace(opt.PreferredLanguage); lang != "" {
-                mi := strings.EqualFold(detected[i], lang)
-                mj := strings.EqualFold(detected[j], lang)
+                mi := strings.EqualFold(sorted[i].lang, lang)
+                mj := strings.EqualFold(sorted[j].lang, lang)
                 if mi && !mj {
                     return true
                 }
@@ -60,10 +156,22 @@ func Select(results []search.Result, opt Options) []search.Result {
                     return false
                 }
             }
+            // Then, demote stale results behind fresh or undated ones
+            if sorted[i].stale != sorted[j].stale {
+                return !sorted[i].stale
+            }
+            // A relevance-ranked input keeps its order from here on
+            if opt.Ranked {
+                return false
+            }
+            // Then, rank by credibility score when a scorer is configured
+            if opt.Scorer != nil && sorted[i].cred.Score != sorted[j].cred.Score {
+                return sorted[i].cred.Score > sorted[j].cred.Score
+            }
             // Then, apply primary host preference if requested
             if opt.PreferPrimary {
-                hi := isPrimaryHost(sorted[i].URL)
-                hj := isPrimaryHost(sorted[j].URL)
+                hi := isPrimaryHost(sorted[i].r.URL)
+                hj := isPrimaryHost(sorted[j].r.URL)
                 if hi && !hj {
                     return true
                 }
@@ -72,58 +180,96 @@ func Select(results []search.Result, opt Options) []search.Result {
                 }
             }
             // Finally, fall back to snippet-length descending
-            return len(sorted[i].Snippet) > len(sorted[j].Snippet)
+            return len(sorted[i].r.Snippet) > len(sorted[j].r.Snippet)
         })
     } else {
         sort.SliceStable(sorted, func(i, j int) bool {
-            return len(sorted[i].Snippet) > len(sorted[j].Snippet)
+            return len(sorted[i].r.Snippet) > len(sorted[j].r.Snippet)
         })
     }
 
-    out := make([]search.Result, 0, opt.MaxTotal)
-    for _, r := range sorted {
+    typeCounts := map[string]int{}
+    picked := make([]bool, len(sorted))
+    total := 0
+    // admit applies the per-candidate constraints and, when the candidate is
+    // accepted, records it against the caps.
+    admit := func(c candidate) string {
+        r := c.r
+        if c.lang != "" && len(opt.AllowedLanguages) > 0 && !LanguageAllowed(c.lang, opt.AllowedLanguages) {
+            return ReasonLanguage
+        }
         if opt.MinSnippetChars > 0 {
             // Treat very short snippets as low-signal and skip them early.
             if len(strings.TrimSpace(r.Snippet)) < opt.MinSnippetChars {
-                continue
+                return ReasonMinSnippet
             }
         }
         u, err := url.Parse(strings.TrimSpace(r.URL))
         if err != nil || u.Host == "" {
-            continue
+            return ReasonInvalidURL
         }
         // Avoid crawling behind search result pages per etiquette policy.
         if isSearchResultsPage(u) {
-            continue
+            return ReasonSearchResults
         }
-        canon := canonicalizeURL(u)
+        canon := opt.URLNorm.Key(u.String())
         if _, ok := seenURL[canon]; ok {
-            continue
+            return ReasonDuplicate
         }
         host := strings.ToLower(u.Host)
         if domainCounts[host] >= opt.PerDomain {
-            continue
+            return ReasonPerDomainCap
+        }
+        if max, ok := opt.Quotas.Max[c.typ]; ok && typeCounts[c.typ] >= max {
+            return ReasonTypeQuota
         }
         seenURL[canon] = struct{}{}
         domainCounts[host]++
-        out = append(out, r)
-        if len(out) >= opt.MaxTotal {
-            break
+        typeCounts[c.typ]++
+        total++
+        return ""
+    }
+    // First reserve slots for type minimums using the best-ranked candidates
+    // of each under-represented type.
+    if len(opt.Quotas.Min) > 0 {
+        for i, c := range sorted {
+            if total >= opt.MaxTotal {
+                break
+            }
+            if typeCounts[c.typ] < opt.Quotas.Min[c.typ] && admit(c) == "" {
+                picked[i] = true
+            }
         }
     }
-    return out
+
+    out := make([]search.Result, 0, opt.MaxTotal)
+    decisions := make([]Decision, 0, len(sorted))
+    for i, c := range sorted {
+        d := Decision{Result: c.r, Rank: i + 1, Language: c.lang, LanguageConfidence: c.conf, Stale: c.stale, SourceType: c.typ, Credibility: c.cred}
+        switch {
+        case picked[i]:
+        case total >= opt.MaxTotal:
+            d.Reason = ReasonMaxTotal
+        default:
+            d.Reason = admit(c)
+        }
+        if d.Reason == "" {
+            d.Selected = true
+            out = append(out, c.r)
+        }
+        decisions = append(decisions, d)
+    }
+    return out, decisions
 }
 
-func canonicalizeURL(u *url.URL) string {
-    // drop fragments and default ports; lower-case host
-    u2 := *u
-    u2.Fragment = ""
-    u2.Host = strings.ToLower(u2.Host)
-    if (u2.Scheme == "http" && strings.HasSuffix(u2.Host, ":80")) || (u2.Scheme == "https" && strings.HasSuffix(u2.Host, ":443")) {
-        host := u2.Hostname()
-        u2.Host = host
+// IsRecencyExempt reports whether rawURL's host matches one of the exempt
+// host patterns (equal, or a subdomain of the pattern).
+func IsRecencyExempt(rawURL string, patterns []string) bool {
+    u, err := url.Parse(strings.TrimSpace(rawURL))
+    if err != nil || u.Hostname() == "" {
+        return false
     }
-    return u2.String()
+    return urlnorm.HostMatches(u.Hostname(), patterns)
 }
 
 // isPrimaryHost returns true if the URL host appears to be an authoritative
@@ -144,25 +290,18 @@ func isPrimaryHost(rawURL string) bool {
     return false
 }
 
-// detectLanguage is a lightweight heuristic detector for common languages.
-// It returns lowercase ISO-like codes such as "en" or "es" when it can guess,
-// otherwise an empty string. This is intentionally simple to keep selection
-// deterministic and dependency-free.
-func detectLanguage(text string) string {
-    s := " " + strings.ToLower(text) + " "
-    // Quick checks for Spanish markers (including accented characters)
-    if strings.ContainsAny(s, "áéíóúñ") ||
-        strings.Contains(s, " el ") || strings.Contains(s, " la ") || strings.Contains(s, " los ") || strings.Contains(s, " las ") ||
-        strings.Contains(s, " de ") || strings.Contains(s, " y ") || strings.Contains(s, " en ") || strings.Contains(s, " para ") ||
-        strings.Contains(s, " con ") || strings.Contains(s, " una ") || strings.Contains(s, " uno ") || strings.Contains(s, " este ") ||
-        strings.Contains(s, " guía ") || strings.Contains(s, " introducción ") || strings.Contains(s, " arquitectura ") {
-        return "es"
-    }
-    // Quick checks for English markers
-    if strings.Contains(s, " the ") || strings.Contains(s, " and ") || strings.Contains(s, " of ") || strings.Contains(s, " to ") || strings.Contains(s, " in ") || strings.Contains(s, " guide ") || strings.Contains(s, " introduction ") {
-        return "en"
-    }
-    return ""
+// LanguageAllowed reports whether lang is in allowed, ignoring case. An
+// empty allow-list permits every language.
+func LanguageAllowed(lang string, allowed []string) bool {
+    if len(allowed) == 0 {
+        return true
+    }
+    for _, a := range allowed {
+        if strings.EqualFold(strings.TrimSpace(a), lang) {
+            return true
+        }
+    }
+    return false
 }
 
 // isSearchResultsPage heuristically detects URLs that point to search engine
@@ -3,8 +3,11 @@ package selecter
 import (
     "strings"
     "testing"
+    "time"
 
+    "github.com/hyperifyio/goresearch/internal/credibility"
     "github.com/hyperifyio/goresearch/internal/search"
+    "github.com/hyperifyio/goresearch/internal/sourcetype"
 )
 
 func TestSelect_PerDomainCap(t *testing.T) {
@@ -105,6 +108,23 @@ func TestSelect_PreferLanguageMatchWithoutFiltering(t *testing.T) {
     }
 }
 
+func TestSelect_AllowedLanguagesDropsOthers(t *testing.T) {
+    in := []search.Result{
+        {Title: "Intro a Kubernetes", URL: "https://es.example.com/k8s", Snippet: "Esta es una guía introductoria de Kubernetes y su arquitectura."},
+        {Title: "Kubernetes overview", URL: "https://en.example.com/k8s", Snippet: "This is an introductory guide to Kubernetes and its architecture."},
+        {Title: "Kubernetes", URL: "https://x.example.com/k8s", Snippet: "Kubernetes"},
+    }
+    out, decisions := SelectWithReport(in, Options{MaxTotal: 10, PerDomain: 5, AllowedLanguages: []string{"EN"}})
+    if len(out) != 2 {
+        t.Fatalf("expected Spanish result dropped and undetected one kept; got %v", out)
+    }
+    for _, d := range decisions {
+        if d.Result.Title == "Intro a Kubernetes" && (d.Reason != ReasonLanguage || d.Language != "es" || d.LanguageConfidence < 0.8) {
+            t.Fatalf("unexpected decision for Spanish result: %+v", d)
+        }
+    }
+}
+
 func TestSelect_SkipsSearchResultPages(t *testing.T) {
     in := []search.Result{
         {Title: "Google result page", URL: "https://www.google.com/search?q=golang", Snippet: "results"},
@@ -117,3 +137,148 @@ func TestSelect_SkipsSearchResultPages(t *testing.T) {
         t.Fatalf("expected only content page to remain; got %v", out)
     }
 }
+
+func TestSelectWithReport_ExplainsDrops(t *testing.T) {
+    in := []search.Result{
+        {Title: "Guide", URL: "https://a.com/guide", Snippet: "the guide to the topic and more"},
+        {Title: "Dup", URL: "https://a.com/guide#top", Snippet: "the guide to the topic again"},
+        {Title: "Second", URL: "https://a.com/two", Snippet: "another page of the site"},
+        {Title: "Short", URL: "https://b.com/x", Snippet: "tiny"},
+        {Title: "SERP", URL: "https://www.google.com/search?q=topic", Snippet: "search results page for topic"},
+        {Title: "Late", URL: "https://c.com/late", Snippet: "a later candidate with text"},
+    }
+    out, decisions := SelectWithReport(in, Options{MaxTotal: 3, PerDomain: 1, MinSnippetChars: 10})
+    if len(out) != 2 || len(decisions) != len(in) {
+        t.Fatalf("unexpected sizes: out=%d decisions=%d", len(out), len(decisions))
+    }
+    reasons := map[string]string{}
+    for i, d := range decisions {
+        if d.Rank != i+1 {
+            t.Fatalf("expected ranks in order, got %d at %d", d.Rank, i)
+        }
+        if d.Selected != (d.Reason == "") {
+            t.Fatalf("selected and reason disagree: %+v", d)
+        }
+        reasons[d.Result.Title] = d.Reason
+    }
+    want := map[string]string{
+        "Guide":  "",
+        "Late":   "",
+        "Dup":    ReasonDuplicate,
+        "Second": ReasonPerDomainCap,
+        "Short":  ReasonMinSnippet,
+        "SERP":   ReasonSearchResults,
+    }
+    for title, reason := range want {
+        if reasons[title] != reason {
+            t.Fatalf("%s: want reason %q, got %q (all: %v)", title, reason, reasons[title], reasons)
+        }
+    }
+    if decisions[0].Language != "en" {
+        t.Fatalf("expected detected language on decisions, got %q", decisions[0].Language)
+    }
+    // Select must agree with the report.
+    plain := Select(in, Options{MaxTotal: 3, PerDomain: 1, MinSnippetChars: 10})
+    if len(plain) != len(out) || plain[0].URL != out[0].URL || plain[1].URL != out[1].URL {
+        t.Fatalf("Select and SelectWithReport disagree: %+v vs %+v", plain, out)
+    }
+    _, decisions = SelectWithReport(in, Options{MaxTotal: 1, PerDomain: 1})
+    if last := decisions[len(decisions)-1]; last.Reason != ReasonMaxTotal {
+        t.Fatalf("expected candidates past the cap to report max-total, got %+v", last)
+    }
+}
+
+func TestSelect_RecencyDemotesStaleUnlessExempt(t *testing.T) {
+    now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+    in := []search.Result{
+        {Title: "Old blog", URL: "https://blog.example.com/2015/tls", Snippet: "a very long and detailed snippet from an old blog post", Published: time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC)},
+        {Title: "RFC", URL: "https://www.rfc-editor.org/rfc/rfc6797", Snippet: "a long snippet describing the HSTS specification text", Published: time.Date(2012, 11, 1, 0, 0, 0, 0, time.UTC)},
+        {Title: "Undated", URL: "https://docs.example.org/hsts", Snippet: "short undated snippet"},
+        {Title: "Fresh", URL: "https://news.example.net/hsts", Snippet: "fresh snippet", Published: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+    }
+    out, decisions := SelectWithReport(in, Options{MaxTotal: 4, PerDomain: 2, RecencyMonths: 24, RecencyExemptHostPatterns: []string{"rfc-editor.org"}, Now: now})
+    if len(out) != 4 {
+        t.Fatalf("stale results are demoted, not dropped; got %d", len(out))
+    }
+    if out[len(out)-1].Title != "Old blog" {
+        t.Fatalf("expected stale blog last, got order %v", []string{out[0].Title, out[1].Title, out[2].Title, out[3].Title})
+    }
+    if out[0].Title != "RFC" {
+        t.Fatalf("expected exempt RFC to keep its snippet-length rank, got %q", out[0].Title)
+    }
+    for _, d := range decisions {
+        if d.Stale != (d.Result.Title == "Old blog") {
+            t.Fatalf("unexpected stale flag: %+v", d)
+        }
+    }
+    // Without a recency policy the long stale snippet wins.
+    if plain := Select(in, Options{MaxTotal: 4, PerDomain: 2}); plain[0].Title != "Old blog" {
+        t.Fatalf("expected snippet-length order without recency, got %q", plain[0].Title)
+    }
+}
+
+func TestSelectWithReport_RanksByCredibilityScore(t *testing.T) {
+    long := "A long and detailed snippet about HTTP semantics that would normally sort first by length alone."
+    in := []search.Result{
+        {Title: "Blog", URL: "https://someone.medium.com/http", Snippet: long + " Extra words to be the longest."},
+        {Title: "RFC", URL: "https://www.rfc-editor.org/rfc/rfc9110", Snippet: long},
+    }
+    out, decisions := SelectWithReport(in, Options{MaxTotal: 2, PerDomain: 2, Scorer: &credibility.Scorer{}})
+    if out[0].Title != "RFC" {
+        t.Fatalf("expected the higher-scored RFC first, got %q", out[0].Title)
+    }
+    if decisions[0].Credibility == nil || decisions[0].Credibility.Tier != "primary" {
+        t.Fatalf("expected credibility breakdown on decisions, got %+v", decisions[0].Credibility)
+    }
+}
+
+func TestSelect_RankedKeepsInputOrderWithinTiers(t *testing.T) {
+    old := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+    in := []search.Result{
+        {Title: "short", URL: "https://a.com/1", Snippet: "x"},
+        {Title: "stale", URL: "https://b.com/1", Snippet: "a much longer snippet", Published: old},
+        {Title: "long", URL: "https://c.com/1", Snippet: "a much longer snippet here"},
+    }
+    out := Select(in, Options{MaxTotal: 10, Ranked: true, RecencyMonths: 12, Now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Scorer: &credibility.Scorer{}})
+    got := []string{out[0].Title, out[1].Title, out[2].Title}
+    if strings.Join(got, ",") != "short,long,stale" {
+        t.Fatalf("expected input order with stale demoted, got %v", got)
+    }
+}
+
+func TestSelectWithReport_TypeQuotas(t *testing.T) {
+    in := []search.Result{
+        {Title: "q1", URL: "https://stackoverflow.com/questions/1", Snippet: "xxxxxxxx"},
+        {Title: "q2", URL: "https://superuser.com/questions/2", Snippet: "xxxxxxx"},
+        {Title: "q3", URL: "https://reddit.com/r/x/3", Snippet: "xxxxxx"},
+        {Title: "b1", URL: "https://blog.example.com/post", Snippet: "xxxxx"},
+        {Title: "s1", URL: "https://www.rfc-editor.org/rfc/rfc9110", Snippet: "xxxx"},
+        {Title: "s2", URL: "https://www.w3.org/TR/html/", Snippet: "xxx"},
+    }
+    opt := Options{MaxTotal: 4, Quotas: sourcetype.Quotas{
+        Min: map[string]int{sourcetype.Primary: 2},
+        Max: map[string]int{sourcetype.Forum: 1},
+    }}
+    out, decisions := SelectWithReport(in, opt)
+    var got []string
+    for _, r := range out {
+        got = append(got, r.Title)
+    }
+    // Rank order is kept; both standards make the cut despite short snippets
+    // and only one forum post is taken.
+    if strings.Join(got, ",") != "q1,b1,s1,s2" {
+        t.Fatalf("unexpected selection: %v", got)
+    }
+    reasons := map[string]string{}
+    types := map[string]string{}
+    for _, d := range decisions {
+        reasons[d.Result.Title] = d.Reason
+        types[d.Result.Title] = d.SourceType
+    }
+    if reasons["q2"] != ReasonTypeQuota || reasons["q3"] != ReasonTypeQuota {
+        t.Fatalf("expected forum posts dropped by quota, got %v", reasons)
+    }
+    if types["s1"] != sourcetype.Primary || types["b1"] != sourcetype.Blog {
+        t.Fatalf("unexpected source types: %v", types)
+    }
+}
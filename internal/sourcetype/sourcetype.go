@@ -0,0 +1,238 @@
+// Package sourcetype classifies sources by kind — official documentation or
+// standards, academic work, news, vendor marketing, forums and blogs — so
+// selection can balance the evidence base and reports can show it.
+//
+// Classification uses well-known domains first, then page metadata such as
+// schema.org types and citation tags when available, then URL path patterns.
+package sourcetype
+
+import (
+    "fmt"
+    "net/url"
+    "strconv"
+    "strings"
+)
+
+// Source types returned by Classify.
+const (
+    // Primary is official documentation or a standard.
+    Primary = "primary"
+    // Academic is a paper, preprint or other scholarly publication.
+    Academic = "academic"
+    // Reference is an encyclopedia or similar tertiary source.
+    Reference = "reference"
+    News      = "news"
+    // Vendor is product or marketing material.
+    Vendor = "vendor"
+    // Forum is a forum thread or Q&A page.
+    Forum = "forum"
+    // Blog is a personal or company blog post.
+    Blog = "blog"
+    // Web is any page that matches no other type.
+    Web = "web"
+)
+
+// All lists every type in display order.
+var All = []string{Primary, Academic, Reference, News, Vendor, Forum, Blog, Web}
+
+var labels = map[string]string{
+    Primary:   "official docs/standard",
+    Academic:  "academic",
+    Reference: "reference work",
+    News:      "news",
+    Vendor:    "vendor marketing",
+    Forum:     "forum/Q&A",
+    Blog:      "blog",
+    Web:       "web page",
+}
+
+// Label returns a human-readable name for t.
+func Label(t string) string {
+    if l, ok := labels[t]; ok {
+        return l
+    }
+    return t
+}
+
+// Valid reports whether t is a known type.
+func Valid(t string) bool {
+    _, ok := labels[t]
+    return ok
+}
+
+// Hints carries page metadata that sharpens URL-based classification. The
+// zero value classifies from the URL alone, as selection must before pages
+// are fetched.
+type Hints struct {
+    // SchemaTypes are schema.org @type values from JSON-LD or microdata,
+    // e.g. "NewsArticle" or "ScholarlyArticle".
+    SchemaTypes []string
+    // OGType is the og:type value, e.g. "article" or "product".
+    OGType string
+    // Scholarly is true when the page carries citation_* or similar
+    // bibliographic meta tags.
+    Scholarly bool
+}
+
+var (
+    primaryDomains   = []string{"rfc-editor.org", "ietf.org", "w3.org", "whatwg.org", "iso.org", "ecma-international.org", "nist.gov", "developer.mozilla.org", "unicode.org", "khronos.org", "opengroup.org", "python.org", "go.dev", "golang.org", "rust-lang.org", "kernel.org"}
+    academicDomains  = []string{"arxiv.org", "doi.org", "acm.org", "ieee.org", "springer.com", "sciencedirect.com", "nature.com", "science.org", "wiley.com", "jstor.org", "ncbi.nlm.nih.gov", "semanticscholar.org", "researchgate.net", "ssrn.com", "plos.org", "usenix.org"}
+    referenceDomains = []string{"wikipedia.org", "britannica.com", "wiktionary.org"}
+    forumDomains     = []string{"stackoverflow.com", "stackexchange.com", "superuser.com", "serverfault.com", "askubuntu.com", "reddit.com", "news.ycombinator.com", "quora.com", "discourse.org", "lobste.rs"}
+    newsDomains      = []string{"nytimes.com", "bbc.co.uk", "bbc.com", "reuters.com", "apnews.com", "theguardian.com", "washingtonpost.com", "wsj.com", "ft.com", "bloomberg.com", "cnn.com", "arstechnica.com", "theverge.com", "techcrunch.com", "wired.com", "zdnet.com", "theregister.com", "lwn.net", "infoq.com"}
+    blogDomains      = []string{"medium.com", "substack.com", "dev.to", "hashnode.dev", "blogspot.com", "wordpress.com", "tumblr.com", "ghost.io"}
+)
+
+// Classify returns the type of the source at rawURL.
+func Classify(rawURL string, h Hints) string {
+    u, err := url.Parse(strings.TrimSpace(rawURL))
+    if err != nil || u.Host == "" {
+        return Web
+    }
+    host := strings.ToLower(u.Hostname())
+    path := strings.ToLower(u.Path)
+    switch {
+    case hostIn(host, primaryDomains):
+        return Primary
+    case hostIn(host, academicDomains):
+        return Academic
+    case hostIn(host, referenceDomains):
+        return Reference
+    case hostIn(host, forumDomains):
+        return Forum
+    case hostIn(host, newsDomains):
+        return News
+    case hostIn(host, blogDomains):
+        return Blog
+    }
+    if t := fromHints(h); t != "" {
+        return t
+    }
+    return fromPath(host, path)
+}
+
+// fromHints maps page metadata to a type, or "" when inconclusive.
+func fromHints(h Hints) string {
+    if h.Scholarly {
+        return Academic
+    }
+    for _, st := range h.SchemaTypes {
+        switch strings.ToLower(strings.TrimSpace(st)) {
+        case "scholarlyarticle", "medicalscholarlyarticle", "thesis":
+            return Academic
+        case "newsarticle", "reportagenewsarticle", "analysisnewsarticle", "opinionnewsarticle":
+            return News
+        case "blogposting", "liveblogposting", "blog":
+            return Blog
+        case "qapage", "discussionforumposting":
+            return Forum
+        case "techarticle", "apireference":
+            return Primary
+        case "product", "offer", "softwareapplication":
+            return Vendor
+        }
+    }
+    if strings.EqualFold(strings.TrimSpace(h.OGType), "product") {
+        return Vendor
+    }
+    return ""
+}
+
+var (
+    docsPathPrefixes   = []string{"/docs/", "/doc/", "/documentation/", "/reference/", "/manual/", "/spec/", "/specs/", "/api/"}
+    vendorPathPrefixes = []string{"/pricing", "/product/", "/products/", "/solutions/", "/features", "/customers/", "/platform/", "/enterprise", "/why-"}
+)
+
+// fromPath classifies by host prefix and path patterns.
+func fromPath(host, path string) string {
+    switch {
+    case hasAnyPrefix(host, "docs.", "developer.", "developers.", "spec.", "specs."), hasAnyPrefix(path, docsPathPrefixes...):
+        return Primary
+    case hasAnyPrefix(host, "forum.", "forums.", "community.", "discuss.", "answers."), strings.Contains(path, "/forum/"), strings.Contains(path, "/questions/"):
+        return Forum
+    case hasAnyPrefix(host, "blog.", "blogs."), strings.Contains(path, "/blog/"), path == "/blog":
+        return Blog
+    case hasAnyPrefix(host, "news."), strings.HasPrefix(path, "/news/"):
+        return News
+    case hasAnyPrefix(path, vendorPathPrefixes...):
+        return Vendor
+    case strings.HasSuffix(host, ".edu"):
+        return Academic
+    }
+    return Web
+}
+
+func hostIn(host string, domains []string) bool {
+    for _, d := range domains {
+        if host == d || strings.HasSuffix(host, "."+d) {
+            return true
+        }
+    }
+    return false
+}
+
+func hasAnyPrefix(s string, prefixes ...string) bool {
+    for _, p := range prefixes {
+        if strings.HasPrefix(s, p) {
+            return true
+        }
+    }
+    return false
+}
+
+// Quotas bounds how many sources of each type selection may take. Min is a
+// floor honored when enough candidates of that type exist; Max is a hard
+// ceiling. Types absent from a map are unconstrained.
+type Quotas struct {
+    Min map[string]int
+    Max map[string]int
+}
+
+// Empty reports whether q constrains nothing.
+func (q Quotas) Empty() bool {
+    return len(q.Min) == 0 && len(q.Max) == 0
+}
+
+// ParseCounts parses a quota list such as "primary=2,forum=1" into a map.
+// Types must be known and counts non-negative.
+func ParseCounts(spec string) (map[string]int, error) {
+    out := map[string]int{}
+    for _, part := range strings.Split(spec, ",") {
+        part = strings.TrimSpace(part)
+        if part == "" {
+            continue
+        }
+        name, num, ok := strings.Cut(part, "=")
+        name = strings.ToLower(strings.TrimSpace(name))
+        if !ok || !Valid(name) {
+            return nil, fmt.Errorf("source type quota %q: want <type>=<count> with type one of %s", part, strings.Join(All, ", "))
+        }
+        n, err := strconv.Atoi(strings.TrimSpace(num))
+        if err != nil || n < 0 {
+            return nil, fmt.Errorf("source type quota %q: count must be a non-negative integer", part)
+        }
+        out[name] = n
+    }
+    return out, nil
+}
+
+// Validate checks that every quota names a known type, counts are
+// non-negative, and no minimum exceeds its maximum.
+func (q Quotas) Validate() error {
+    for _, m := range []map[string]int{q.Min, q.Max} {
+        for t, n := range m {
+            if !Valid(t) {
+                return fmt.Errorf("unknown source type %q (want one of %s)", t, strings.Join(All, ", "))
+            }
+            if n < 0 {
+                return fmt.Errorf("source type %q: negative quota", t)
+            }
+        }
+    }
+    for t, min := range q.Min {
+        if max, ok := q.Max[t]; ok && min > max {
+            return fmt.Errorf("source type %q: minimum %d exceeds maximum %d", t, min, max)
+        }
+    }
+    return nil
+}
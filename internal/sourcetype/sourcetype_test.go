@@ -0,0 +1,62 @@
+package sourcetype
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestClassify(t *testing.T) {
+    cases := []struct {
+        url   string
+        hints Hints
+        want  string
+    }{
+        {"https://www.rfc-editor.org/rfc/rfc9110", Hints{}, Primary},
+        {"https://docs.python.org/3/library/json.html", Hints{}, Primary},
+        {"https://example.com/docs/install", Hints{}, Primary},
+        {"https://arxiv.org/abs/1706.03762", Hints{}, Academic},
+        {"https://cs.stanford.edu/people/x/", Hints{}, Academic},
+        {"https://en.wikipedia.org/wiki/HTTP", Hints{}, Reference},
+        {"https://www.reuters.com/technology/story", Hints{}, News},
+        {"https://stackoverflow.com/questions/1/x", Hints{}, Forum},
+        {"https://community.example.com/t/123", Hints{}, Forum},
+        {"https://medium.com/@someone/post", Hints{}, Blog},
+        {"https://example.com/blog/launch", Hints{}, Blog},
+        {"https://example.com/pricing", Hints{}, Vendor},
+        {"https://example.com/article", Hints{}, Web},
+        {"not a url", Hints{}, Web},
+        // Metadata sharpens pages whose URL says little.
+        {"https://example.com/article", Hints{SchemaTypes: []string{"NewsArticle"}}, News},
+        {"https://example.com/article", Hints{Scholarly: true}, Academic},
+        {"https://example.com/item/42", Hints{OGType: "product"}, Vendor},
+        // Well-known domains win over page metadata.
+        {"https://medium.com/@someone/post", Hints{SchemaTypes: []string{"NewsArticle"}}, Blog},
+    }
+    for _, c := range cases {
+        if got := Classify(c.url, c.hints); got != c.want {
+            t.Errorf("Classify(%q, %+v) = %q, want %q", c.url, c.hints, got, c.want)
+        }
+    }
+}
+
+func TestParseCounts(t *testing.T) {
+    got, err := ParseCounts(" primary=2, forum = 1 ,")
+    if err != nil || got[Primary] != 2 || got[Forum] != 1 || len(got) != 2 {
+        t.Fatalf("ParseCounts = %v, %v", got, err)
+    }
+    for _, bad := range []string{"primary", "podcast=1", "blog=-1", "news=x"} {
+        if _, err := ParseCounts(bad); err == nil {
+            t.Errorf("expected error for %q", bad)
+        }
+    }
+}
+
+func TestQuotasValidate(t *testing.T) {
+    q := Quotas{Min: map[string]int{Forum: 3}, Max: map[string]int{Forum: 1}}
+    if err := q.Validate(); err == nil || !strings.Contains(err.Error(), "exceeds maximum") {
+        t.Fatalf("expected min>max error, got %v", err)
+    }
+    if err := (Quotas{Max: map[string]int{"podcast": 1}}).Validate(); err == nil {
+        t.Fatal("expected unknown type error")
+    }
+}
@@ -26,6 +26,80 @@ type SourceExcerpt struct {
     Title   string
     URL     string
     Excerpt string
+    // Published is the source's publication date as YYYY-MM-DD, or empty
+    // when unknown. It is shown to the model so it can weigh freshness.
+    Published string
+    // Aliases lists other URLs that served this source, such as the URL
+    // found by search before redirects or canonicalization. URL is the
+    // identity used for citation.
+    Aliases []string
+    // SourceType is the kind of source (see package sourcetype), shown in
+    // References and the manifest so readers can judge the evidence base.
+    SourceType string
+    // Language is the detected ISO 639-1 language of the excerpt, or empty
+    // when it could not be told; LanguageConfidence is the detector's
+    // confidence in [0,1].
+    Language           string
+    LanguageConfidence float64
+    // WARCRecordID identifies the recorded HTTP response this excerpt was
+    // extracted from, when WARC recording is enabled.
+    WARCRecordID string
+    // CacheStatus is fresh, revalidated or stale when the text came from the
+    // HTTP cache; empty when it was fetched from the origin.
+    CacheStatus string
+    // Plain is the unstructured text of a Markdown excerpt, which budgeting
+    // may use instead to save tokens; empty when Excerpt is already plain.
+    Plain string
+    // Paged is true when Excerpt marks page boundaries with [Page N] lines,
+    // so the source can be cited by page.
+    Paged bool
+    // Authors, Publisher, Modified (YYYY-MM-DD) and DOI come from the page's
+    // own metadata and are empty when it declares none. They let the model
+    // attribute claims, as in "according to the 2023 IETF draft".
+    Authors   []string
+    Publisher string
+    Modified  string
+    DOI       string
+    // DeclaredLanguage is the language tag the page declares, such as
+    // "en-US"; it is recorded next to the detected Language, which it may
+    // contradict.
+    DeclaredLanguage string
+}
+
+// sourcesIntro introduces the numbered sources. Page citations are only
+// offered when some excerpt marks its pages.
+func sourcesIntro(sources []SourceExcerpt) string {
+    for _, src := range sources {
+        if src.Paged {
+            return "\n\nSources (use only these; cite with [n], or [n, p. N] for page N of a source whose excerpt marks pages):\n"
+        }
+    }
+    return "\n\nSources (use only these; cite with [n]):\n"
+}
+
+// SourceHeader renders the numbered header line for a source, with the
+// dates, authors, publisher and DOI it declares in parentheses.
+func SourceHeader(src SourceExcerpt) string {
+    var facts []string
+    if p := strings.TrimSpace(src.Published); p != "" {
+        facts = append(facts, "published "+p)
+    }
+    if m := strings.TrimSpace(src.Modified); m != "" && m != strings.TrimSpace(src.Published) {
+        facts = append(facts, "updated "+m)
+    }
+    if len(src.Authors) > 0 {
+        facts = append(facts, "by "+strings.Join(src.Authors, "; "))
+    }
+    if p := strings.TrimSpace(src.Publisher); p != "" {
+        facts = append(facts, "publisher "+p)
+    }
+    if d := strings.TrimSpace(src.DOI); d != "" {
+        facts = append(facts, "DOI "+d)
+    }
+    if len(facts) > 0 {
+        return fmt.Sprintf("%d. %s — %s (%s)\n", src.Index, src.Title, src.URL, strings.Join(facts, "; "))
+    }
+    return fmt.Sprintf("%d. %s — %s\n", src.Index, src.Title, src.URL)
 }
 
 // Input bundles all information needed to synthesize the report.
@@ -262,10 +336,10 @@ func buildUserMessage(in Input) string {
         sb.WriteString("\nTarget length: ")
         sb.WriteString(fmt.Sprintf("%d words", in.Brief.TargetLengthWords))
     }
-    sb.WriteString("\n\nSources (use only these; cite with [n]):\n")
+    sb.WriteString(sourcesIntro(in.Sources))
     for _, src := range in.Sources {
         // Each source begins with its numbered header, then an excerpt block.
-        sb.WriteString(fmt.Sprintf("%d. %s — %s\n", src.Index, src.Title, src.URL))
+        sb.WriteString(SourceHeader(src))
         if strings.TrimSpace(src.Excerpt) != "" {
             sb.WriteString("Excerpt:\n\n")
             sb.WriteString(src.Excerpt)
@@ -321,9 +395,9 @@ func buildUserMessageWithoutBodies(in Input) string {
         sb.WriteString("\nTarget length: ")
         sb.WriteString(fmt.Sprintf("%d words", in.Brief.TargetLengthWords))
     }
-    sb.WriteString("\n\nSources (use only these; cite with [n]):\n")
+    sb.WriteString(sourcesIntro(in.Sources))
     for _, src := range in.Sources {
-        sb.WriteString(fmt.Sprintf("%d. %s — %s\n", src.Index, src.Title, src.URL))
+        sb.WriteString(SourceHeader(src))
         // Keep label but omit body
         sb.WriteString("Excerpt:\n\n\n")
     }
@@ -183,3 +183,42 @@ func indexOf(s, sub string) int {
 func stringsIndex(haystack, needle string) int {
     return strings.Index(haystack, needle)
 }
+
+func TestBuildUserMessage_IncludesPublishedDateWhenKnown(t *testing.T) {
+    in := Input{Sources: []SourceExcerpt{
+        {Index: 1, Title: "Dated", URL: "https://a.example/", Excerpt: "x", Published: "2019-07-01"},
+        {Index: 2, Title: "Undated", URL: "https://b.example/", Excerpt: "y"},
+    }}
+    msg := buildUserMessage(in)
+    if !strings.Contains(msg, "1. Dated — https://a.example/ (published 2019-07-01)\n") {
+        t.Fatalf("expected published date in header:\n%s", msg)
+    }
+    if !strings.Contains(msg, "2. Undated — https://b.example/\n") {
+        t.Fatalf("expected plain header for undated source:\n%s", msg)
+    }
+}
+
+func TestBuildUserMessage_IncludesSourceAttribution(t *testing.T) {
+    in := Input{Sources: []SourceExcerpt{{
+        Index: 1, Title: "Draft", URL: "https://datatracker.ietf.org/doc/draft-x/", Excerpt: "x",
+        Published: "2023-03-01", Modified: "2023-06-01", Authors: []string{"Ann Lee", "Bo Chen"}, Publisher: "IETF", DOI: "10.5555/x",
+    }}}
+    want := "1. Draft — https://datatracker.ietf.org/doc/draft-x/ (published 2023-03-01; updated 2023-06-01; by Ann Lee; Bo Chen; publisher IETF; DOI 10.5555/x)\n"
+    if msg := buildUserMessage(in); !strings.Contains(msg, want) {
+        t.Fatalf("expected attribution in header:\n%s", msg)
+    }
+    if msg := buildUserMessageWithoutBodies(in); !strings.Contains(msg, want) {
+        t.Fatalf("expected attribution in header without bodies:\n%s", msg)
+    }
+}
+
+func TestBuildUserMessage_OffersPageCitationsForPagedSources(t *testing.T) {
+    in := Input{Sources: []SourceExcerpt{{Index: 1, Title: "Page", URL: "https://a.example/", Excerpt: "x"}}}
+    if msg := buildUserMessage(in); strings.Contains(msg, "[n, p. N]") {
+        t.Fatalf("page citations offered without paged sources:\n%s", msg)
+    }
+    in.Sources = append(in.Sources, SourceExcerpt{Index: 2, Title: "Paper", URL: "https://b.example/p.pdf", Excerpt: "[Page 1]\ny", Paged: true})
+    if msg := buildUserMessage(in); !strings.Contains(msg, "cite with [n], or [n, p. N]") {
+        t.Fatalf("expected page citations offered:\n%s", msg)
+    }
+}
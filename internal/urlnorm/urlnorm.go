@@ -0,0 +1,170 @@
+// Package urlnorm normalizes URLs so the same document reached through
+// tracking links, default ports, AMP or mobile variants gets one identity.
+package urlnorm
+
+import (
+    "net/url"
+    "strings"
+)
+
+// DefaultTrackerParams lists query parameters removed by default. A trailing
+// "*" matches any parameter with that prefix.
+var DefaultTrackerParams = []string{
+    "utm_*", "gclid", "dclid", "gbraid", "wbraid", "fbclid", "msclkid", "yclid",
+    "mc_cid", "mc_eid", "igshid", "_ga", "_gl", "_hsenc", "_hsmi", "ref_src", "spm",
+}
+
+// Options configures normalization. The zero value uses DefaultTrackerParams.
+type Options struct {
+    // TrackerParams replaces DefaultTrackerParams when non-nil.
+    TrackerParams []string
+}
+
+// Clean returns raw with the fragment, default port and tracker parameters
+// removed and the scheme and host lower-cased. The result is still the
+// address to fetch; use Key for identity comparisons.
+func (o Options) Clean(raw string) (string, error) {
+    u, err := url.Parse(strings.TrimSpace(raw))
+    if err != nil {
+        return "", err
+    }
+    o.clean(u)
+    return u.String(), nil
+}
+
+// Key returns the identity of raw for de-duplication: the Clean form with
+// AMP and mobile variants folded onto the main site and trailing slashes
+// dropped. Keys are for comparison only and may not be fetchable. Unparsable
+// input is returned trimmed.
+func (o Options) Key(raw string) string {
+    u, err := url.Parse(strings.TrimSpace(raw))
+    if err != nil {
+        return strings.TrimSpace(raw)
+    }
+    o.clean(u)
+    foldVariants(u)
+    if len(u.Path) > 1 {
+        u.Path = strings.TrimRight(u.Path, "/")
+        u.RawPath = ""
+    }
+    if u.Path == "/" {
+        u.Path = ""
+    }
+    return u.String()
+}
+
+// Clean applies Options{}.Clean.
+func Clean(raw string) (string, error) { return Options{}.Clean(raw) }
+
+// Key applies Options{}.Key.
+func Key(raw string) string { return Options{}.Key(raw) }
+
+// HostMatches reports whether host equals one of the domain patterns or is
+// a subdomain of one, ignoring case. Empty patterns match nothing.
+func HostMatches(host string, patterns []string) bool {
+    h := strings.ToLower(strings.TrimSpace(host))
+    if h == "" {
+        return false
+    }
+    for _, p := range patterns {
+        pp := strings.ToLower(strings.TrimSpace(p))
+        if pp == "" {
+            continue
+        }
+        if h == pp || strings.HasSuffix(h, "."+pp) {
+            return true
+        }
+    }
+    return false
+}
+
+func (o Options) clean(u *url.URL) {
+    u.Fragment = ""
+    u.RawFragment = ""
+    u.Scheme = strings.ToLower(u.Scheme)
+    u.Host = strings.ToLower(u.Host)
+    if port := u.Port(); (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+        u.Host = u.Hostname()
+    }
+    if u.RawQuery == "" {
+        return
+    }
+    params := o.TrackerParams
+    if params == nil {
+        params = DefaultTrackerParams
+    }
+    q := u.Query()
+    for name := range q {
+        if isTracker(strings.ToLower(name), params) {
+            q.Del(name)
+        }
+    }
+    u.RawQuery = q.Encode()
+}
+
+func isTracker(name string, params []string) bool {
+    for _, p := range params {
+        p = strings.ToLower(strings.TrimSpace(p))
+        if p == "" {
+            continue
+        }
+        if strings.HasSuffix(p, "*") {
+            if strings.HasPrefix(name, strings.TrimSuffix(p, "*")) {
+                return true
+            }
+            continue
+        }
+        if name == p {
+            return true
+        }
+    }
+    return false
+}
+
+// foldVariants rewrites AMP and mobile URLs to their main-site form:
+// amp./m./mobile. hosts, /amp path suffixes, *.amp.html files and
+// amp/outputType=amp query flags.
+func foldVariants(u *url.URL) {
+    host := u.Hostname()
+    for _, prefix := range []string{"amp.", "m.", "mobile."} {
+        if strings.HasPrefix(host, prefix) && strings.Count(host, ".") >= 2 {
+            rest := strings.TrimPrefix(host, prefix)
+            if port := u.Port(); port != "" {
+                u.Host = rest + ":" + port
+            } else {
+                u.Host = rest
+            }
+            break
+        }
+    }
+    p := u.Path
+    switch {
+    case strings.HasSuffix(p, "/amp"):
+        p = strings.TrimSuffix(p, "/amp")
+    case strings.HasSuffix(p, "/amp/"):
+        p = strings.TrimSuffix(p, "/amp/")
+    case strings.HasSuffix(p, ".amp.html"):
+        p = strings.TrimSuffix(p, ".amp.html") + ".html"
+    case strings.HasSuffix(p, ".amp"):
+        p = strings.TrimSuffix(p, ".amp")
+    }
+    if p != u.Path {
+        u.Path = p
+        u.RawPath = ""
+    }
+    if u.RawQuery != "" {
+        q := u.Query()
+        changed := false
+        if _, ok := q["amp"]; ok {
+            q.Del("amp")
+            changed = true
+        }
+        if strings.EqualFold(q.Get("outputType"), "amp") {
+            q.Del("outputType")
+            changed = true
+        }
+        if changed {
+            u.RawQuery = q.Encode()
+        }
+    }
+}
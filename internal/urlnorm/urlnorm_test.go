@@ -0,0 +1,67 @@
+package urlnorm
+
+import "testing"
+
+func TestClean(t *testing.T) {
+    cases := map[string]string{
+        "HTTPS://Example.COM:443/a?utm_source=x&id=2#frag": "https://example.com/a?id=2",
+        "http://example.com:80/a/?gclid=1&fbclid=2":          "http://example.com/a/",
+        "http://example.com:8080/a?b=2&a=1":                  "http://example.com:8080/a?a=1&b=2",
+    }
+    for in, want := range cases {
+        got, err := Clean(in)
+        if err != nil {
+            t.Fatalf("clean %q: %v", in, err)
+        }
+        if got != want {
+            t.Fatalf("clean %q: want %q got %q", in, want, got)
+        }
+    }
+}
+
+func TestKey_FoldsVariants(t *testing.T) {
+    want := "https://news.example.com/story"
+    for _, in := range []string{
+        "https://news.example.com/story/",
+        "https://m.news.example.com/story",
+        "https://amp.news.example.com/story",
+        "https://news.example.com/story/amp",
+        "https://news.example.com/story?amp=1",
+        "https://news.example.com/story?outputType=amp&utm_medium=social",
+        "https://news.example.com:443/story#top",
+    } {
+        if got := Key(in); got != want {
+            t.Fatalf("key %q: want %q got %q", in, want, got)
+        }
+    }
+    if got := Key("https://example.com/a.amp.html"); got != "https://example.com/a.html" {
+        t.Fatalf("unexpected amp.html key: %q", got)
+    }
+    // A bare two-label host is not treated as a mobile variant.
+    if got := Key("https://m.com/"); got != "https://m.com" {
+        t.Fatalf("unexpected key for m.com: %q", got)
+    }
+}
+
+func TestOptions_CustomTrackerParams(t *testing.T) {
+    o := Options{TrackerParams: []string{"ref", "campaign_*"}}
+    got, _ := o.Clean("https://example.com/?ref=hn&campaign_id=3&utm_source=x")
+    if got != "https://example.com/?utm_source=x" {
+        t.Fatalf("unexpected clean with custom params: %q", got)
+    }
+}
+
+func TestHostMatches(t *testing.T) {
+    patterns := []string{" IETF.org ", "", "w3.org"}
+    for host, want := range map[string]bool{
+        "ietf.org":            true,
+        "datatracker.ietf.org": true,
+        "WWW.W3.ORG":          true,
+        "notietf.org":         false,
+        "":                    false,
+    } {
+        if got := HostMatches(host, patterns); got != want {
+            t.Errorf("HostMatches(%q) = %v, want %v", host, got, want)
+        }
+    }
+}
@@ -8,6 +8,8 @@ import (
     "strings"
     "time"
     "unicode"
+
+    "github.com/hyperifyio/goresearch/internal/urlnorm"
 )
 
 // Citations represents the validation result for inline [n] citations
@@ -21,10 +23,12 @@ type Citations struct {
     MissingReferences bool
 }
 
-var citeRe = regexp.MustCompile(`\[(\d+)\]`)
+// citeRe matches [n] and page citations such as [n, p. 12] or
+// [n, pp. iv-vi].
+var citeRe = regexp.MustCompile(`\[(\d+)(?:,\s*pp?\.\s*[0-9A-Za-z]+(?:\s*[-–]\s*[0-9A-Za-z]+)?)?\]`)
 
-// ValidateCitations scans the markdown body for [n] patterns and compares
-// against the number of references.
+// ValidateCitations scans the markdown body for [n] patterns, including
+// page citations, and compares against the number of references.
 func ValidateCitations(markdown string, numReferences int) Citations {
     matches := citeRe.FindAllStringSubmatch(markdown, -1)
     seen := map[int]struct{}{}
@@ -397,30 +401,10 @@ func ValidateReferenceQuality(markdown string, policy ReferenceQualityPolicy) er
 
     // Build helper predicates
     isPreferred := func(host string) bool {
-        h := strings.ToLower(host)
-        for _, p := range policy.PreferredHostPatterns {
-            pp := strings.ToLower(strings.TrimSpace(p))
-            if pp == "" {
-                continue
-            }
-            if h == pp || strings.HasSuffix(h, "."+pp) {
-                return true
-            }
-        }
-        return false
+        return urlnorm.HostMatches(host, policy.PreferredHostPatterns)
     }
     isRecencyExempt := func(host string) bool {
-        h := strings.ToLower(host)
-        for _, p := range policy.RecencyExemptHostPatterns {
-            pp := strings.ToLower(strings.TrimSpace(p))
-            if pp == "" {
-                continue
-            }
-            if h == pp || strings.HasSuffix(h, "."+pp) {
-                return true
-            }
-        }
-        return false
+        return urlnorm.HostMatches(host, policy.RecencyExemptHostPatterns)
     }
 
     // Preferred sources checks
@@ -1348,3 +1348,10 @@ func TestIsGenericAltText(t *testing.T) {
     }
 }
 
+
+func TestValidateCitations_PageCitations(t *testing.T) {
+    got := ValidateCitations("A [1, p. 12]. B [2, pp. iv–vi]. C [3].", 2)
+    if len(got.InRange) != 2 || len(got.OutOfRange) != 1 || got.OutOfRange[0] != 3 {
+        t.Fatalf("unexpected citations: %+v", got)
+    }
+}
@@ -113,7 +113,8 @@ func buildUserMessage(markdown string, languageHint string) string {
     return sb.String()
 }
 
-var citeRe = regexp.MustCompile(`\[(\d+)\]`)
+// citeRe matches [n] and page citations such as [n, p. 12].
+var citeRe = regexp.MustCompile(`\[(\d+)(?:,\s*pp?\.\s*[0-9A-Za-z]+(?:\s*[-–]\s*[0-9A-Za-z]+)?)?\]`)
 
 // fallbackVerify implements a deterministic claim extraction:
 // - splits into sentences
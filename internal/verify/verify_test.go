@@ -50,3 +50,10 @@ func TestFallbackVerifyFlagsUnsupportedClaims(t *testing.T) {
 }
 
 
+
+func TestParseCitations_PageCitations(t *testing.T) {
+    got := parseCitations("Throughput doubled [3, p. 12] while latency held [1][3, pp. 4-6] and [2,p.xii].")
+    if len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+        t.Fatalf("unexpected citations: %v", got)
+    }
+}
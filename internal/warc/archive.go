@@ -0,0 +1,425 @@
+package warc
+
+import (
+    "bufio"
+    "bytes"
+    "compress/flate"
+    "compress/gzip"
+    "errors"
+    "fmt"
+    "io"
+    "net/http"
+    "net/url"
+    "os"
+    "strconv"
+    "strings"
+)
+
+// ErrNotFound is returned when an archive holds no response for a URL.
+var ErrNotFound = errors.New("warc: no record for url")
+
+// maxArchiveRedirects bounds redirect chains followed inside an archive.
+const maxArchiveRedirects = 10
+
+// Archive serves recorded HTTP responses from one or more WARC files. Only
+// an index of record locations is kept in memory; bodies are read from disk
+// on lookup, so large crawls can be used. It is safe for concurrent use.
+type Archive struct {
+    // BodyLimit, when set, returns the largest body accepted for a content
+    // type; a negative result means no limit. A longer recorded body fails
+    // with a TooLargeError without being read into memory in full.
+    BodyLimit func(contentType string) int64
+
+    paths []string
+    index map[string]location
+}
+
+// TooLargeError is returned when a recorded body is longer than BodyLimit
+// allows. Size is the declared length when the record states one over the
+// limit, and zero when reading stopped at the limit.
+type TooLargeError struct {
+    ContentType string
+    Limit       int64
+    Size        int64
+}
+
+func (e TooLargeError) Error() string {
+    return fmt.Sprintf("warc: recorded %s body exceeds the %d-byte limit", e.ContentType, e.Limit)
+}
+
+// location addresses one record: the gzip member (or, for uncompressed
+// files, the record) starting at offset, then skip records into it.
+type location struct {
+    file   int
+    offset int64
+    skip   int
+    gz     bool
+}
+
+// Response is an archived HTTP response with transfer and content encodings
+// removed from Body.
+type Response struct {
+    // URL is the target URI of the record served, which after redirects is
+    // the final URL.
+    URL        string
+    RecordID   string
+    StatusCode int
+    Header     http.Header
+    Body       []byte
+}
+
+// Open indexes the response records of the given WARC files, gzip-compressed
+// or not. When several records target the same URL, the last one wins.
+func Open(paths ...string) (*Archive, error) {
+    a := &Archive{index: map[string]location{}}
+    for _, p := range paths {
+        p = strings.TrimSpace(p)
+        if p == "" {
+            continue
+        }
+        a.paths = append(a.paths, p)
+        if err := a.scan(len(a.paths)-1, p); err != nil {
+            return nil, fmt.Errorf("warc: %s: %w", p, err)
+        }
+    }
+    return a, nil
+}
+
+// Len returns the number of distinct URLs with a recorded response.
+func (a *Archive) Len() int {
+    if a == nil {
+        return 0
+    }
+    return len(a.index)
+}
+
+// Lookup returns the response recorded for rawURL, without following
+// redirects.
+func (a *Archive) Lookup(rawURL string) (*Response, error) {
+    if a == nil {
+        return nil, ErrNotFound
+    }
+    loc, ok := a.index[indexKey(rawURL)]
+    if !ok {
+        return nil, ErrNotFound
+    }
+    return a.read(loc)
+}
+
+// Fetch returns the response for rawURL, following recorded redirects the
+// way a client would. A redirect to a URL the archive lacks is ErrNotFound.
+func (a *Archive) Fetch(rawURL string) (*Response, error) {
+    cur := rawURL
+    for hop := 0; ; hop++ {
+        resp, err := a.Lookup(cur)
+        if err != nil {
+            return nil, err
+        }
+        loc := resp.Header.Get("Location")
+        if resp.StatusCode < 300 || resp.StatusCode > 399 || loc == "" {
+            return resp, nil
+        }
+        if hop >= maxArchiveRedirects {
+            return nil, fmt.Errorf("warc: too many redirects for %s", rawURL)
+        }
+        base, err := url.Parse(cur)
+        if err != nil {
+            return nil, err
+        }
+        next, err := base.Parse(loc)
+        if err != nil {
+            return nil, fmt.Errorf("warc: bad redirect location %q: %w", loc, err)
+        }
+        cur = next.String()
+    }
+}
+
+func (a *Archive) scan(file int, path string) error {
+    f, err := os.Open(path)
+    if err != nil {
+        return err
+    }
+    defer f.Close()
+    cr := &countingReader{r: f}
+    br := bufio.NewReader(cr)
+    magic, _ := br.Peek(2)
+    if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+        for {
+            off := cr.n - int64(br.Buffered())
+            zr, err := gzip.NewReader(br)
+            if err == io.EOF {
+                return nil
+            }
+            if err != nil {
+                return err
+            }
+            zr.Multistream(false)
+            if err := a.scanRecords(bufio.NewReader(zr), func(i int) location {
+                return location{file: file, offset: off, skip: i, gz: true}
+            }, nil); err != nil {
+                return err
+            }
+        }
+    }
+    return a.scanRecords(br, func(int) location {
+        return location{file: file}
+    }, func() int64 { return cr.n - int64(br.Buffered()) })
+}
+
+// scanRecords indexes every record in r. at, when set, reports the offset
+// of the next record for uncompressed files.
+func (a *Archive) scanRecords(r *bufio.Reader, loc func(i int) location, at func() int64) error {
+    for i := 0; ; i++ {
+        var off int64
+        if at != nil {
+            if err := skipBlankLines(r); err != nil {
+                return err
+            }
+            off = at()
+        }
+        fields, err := readFields(r)
+        if err == io.EOF {
+            return nil
+        }
+        if err != nil {
+            return err
+        }
+        n, err := strconv.ParseInt(fields["content-length"], 10, 64)
+        if err != nil || n < 0 {
+            return fmt.Errorf("record %d: bad Content-Length", i)
+        }
+        if _, err := io.CopyN(io.Discard, r, n); err != nil {
+            return fmt.Errorf("record %d: %w", i, err)
+        }
+        // Truncated records hold only part of the body and cannot be replayed.
+        if fields["warc-type"] != TypeResponse || !strings.HasPrefix(strings.ToLower(fields["content-type"]), "application/http") || fields["warc-truncated"] != "" {
+            continue
+        }
+        l := loc(i)
+        if at != nil {
+            l.offset = off
+        }
+        a.index[indexKey(fields["warc-target-uri"])] = l
+    }
+}
+
+func (a *Archive) read(loc location) (*Response, error) {
+    f, err := os.Open(a.paths[loc.file])
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+    if _, err := f.Seek(loc.offset, io.SeekStart); err != nil {
+        return nil, err
+    }
+    var r *bufio.Reader
+    if loc.gz {
+        zr, err := gzip.NewReader(f)
+        if err != nil {
+            return nil, err
+        }
+        zr.Multistream(false)
+        r = bufio.NewReader(zr)
+    } else {
+        r = bufio.NewReader(f)
+    }
+    for i := 0; ; i++ {
+        fields, err := readFields(r)
+        if err != nil {
+            return nil, fmt.Errorf("warc: read record: %w", err)
+        }
+        n, err := strconv.ParseInt(fields["content-length"], 10, 64)
+        if err != nil || n < 0 {
+            return nil, fmt.Errorf("warc: bad Content-Length")
+        }
+        if i < loc.skip {
+            if _, err := io.CopyN(io.Discard, r, n); err != nil {
+                return nil, err
+            }
+            continue
+        }
+        return a.parseResponse(fields, io.LimitReader(r, n), n)
+    }
+}
+
+// parseResponse reads the HTTP response in a record block of size bytes,
+// applying BodyLimit before the body is buffered.
+func (a *Archive) parseResponse(fields map[string]string, block io.Reader, size int64) (*Response, error) {
+    hr, err := http.ReadResponse(bufio.NewReader(block), nil)
+    if err != nil {
+        return nil, fmt.Errorf("warc: parse HTTP response: %w", err)
+    }
+    defer hr.Body.Close()
+    ct := hr.Header.Get("Content-Type")
+    limit := int64(-1)
+    if a.BodyLimit != nil {
+        limit = a.BodyLimit(ct)
+    }
+    if limit >= 0 && hr.ContentLength > limit {
+        return nil, TooLargeError{ContentType: ct, Limit: limit, Size: hr.ContentLength}
+    }
+    // The block bounds the stored body; no more than the limit of it is read.
+    var tl TooLargeError
+    body, err := readAtMost(hr.Body, size, limit)
+    if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+        if errors.As(err, &tl) {
+            tl.ContentType = ct
+            return nil, tl
+        }
+        return nil, fmt.Errorf("warc: read HTTP body: %w", err)
+    }
+    body, err = decodeContent(hr.Header.Get("Content-Encoding"), body, limit)
+    if err != nil {
+        if errors.As(err, &tl) {
+            tl.ContentType = ct
+            return nil, tl
+        }
+        return nil, err
+    }
+    hr.Header.Del("Content-Encoding")
+    return &Response{
+        URL:        trimURI(fields["warc-target-uri"]),
+        RecordID:   fields["warc-record-id"],
+        StatusCode: hr.StatusCode,
+        Header:     hr.Header,
+        Body:       body,
+    }, nil
+}
+
+// decodeContent removes the gzip or deflate content codings crawlers such
+// as wget and Heritrix store verbatim.
+// The decoded body is bounded by limit like the stored one.
+func decodeContent(coding string, body []byte, limit int64) ([]byte, error) {
+    switch strings.ToLower(strings.TrimSpace(coding)) {
+    case "", "identity":
+        return body, nil
+    case "gzip", "x-gzip":
+        zr, err := gzip.NewReader(bytes.NewReader(body))
+        if err != nil {
+            return nil, fmt.Errorf("warc: gzip body: %w", err)
+        }
+        return readAtMost(zr, -1, limit)
+    case "deflate":
+        return readAtMost(flate.NewReader(bytes.NewReader(body)), -1, limit)
+    default:
+        return nil, fmt.Errorf("warc: unsupported content encoding %q", coding)
+    }
+}
+
+// readAtMost reads r to the end, failing with a TooLargeError as soon as
+// more than limit bytes have been read; a negative limit means none. size,
+// when known, bounds the input and sizes the buffer.
+func readAtMost(r io.Reader, size, limit int64) ([]byte, error) {
+    if limit >= 0 {
+        r = io.LimitReader(r, limit+1)
+        if size < 0 || size > limit+1 {
+            size = limit + 1
+        }
+    }
+    var buf bytes.Buffer
+    if size > 0 {
+        buf.Grow(int(size))
+    }
+    _, err := buf.ReadFrom(r)
+    if limit >= 0 && int64(buf.Len()) > limit {
+        return nil, TooLargeError{Limit: limit}
+    }
+    return buf.Bytes(), err
+}
+
+// readFields reads a WARC version line and its named fields. Field names
+// are lower-cased. It returns io.EOF at a clean end of input.
+func readFields(r *bufio.Reader) (map[string]string, error) {
+    if err := skipBlankLines(r); err != nil {
+        return nil, err
+    }
+    line, err := r.ReadString('\n')
+    if err != nil {
+        if err == io.EOF && line == "" {
+            return nil, io.EOF
+        }
+        return nil, err
+    }
+    if !strings.HasPrefix(line, "WARC/") {
+        return nil, fmt.Errorf("not a WARC record: %q", strings.TrimSpace(line))
+    }
+    fields := map[string]string{}
+    for {
+        l, err := r.ReadString('\n')
+        if err != nil {
+            return nil, err
+        }
+        l = strings.TrimRight(l, "\r\n")
+        if l == "" {
+            return fields, nil
+        }
+        k, v, ok := strings.Cut(l, ":")
+        if !ok {
+            continue
+        }
+        fields[strings.ToLower(strings.TrimSpace(k))] = strings.TrimSpace(v)
+    }
+}
+
+// skipBlankLines consumes the CRLF pairs that end records.
+func skipBlankLines(r *bufio.Reader) error {
+    for {
+        b, err := r.Peek(1)
+        if err == io.EOF {
+            return nil
+        }
+        if err != nil {
+            return err
+        }
+        if b[0] != '\r' && b[0] != '\n' {
+            return nil
+        }
+        _, _ = r.ReadByte()
+    }
+}
+
+// trimURI drops the angle brackets some WARC 1.1 writers put around
+// WARC-Target-URI.
+func trimURI(s string) string {
+    return strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(s), "<"), ">")
+}
+
+// indexKey normalizes a URL for lookup: scheme and host are lower-cased,
+// default ports and fragments dropped, and an empty path becomes "/".
+func indexKey(raw string) string {
+    raw = trimURI(raw)
+    u, err := url.Parse(raw)
+    if err != nil || u.Host == "" {
+        return raw
+    }
+    u.Scheme = strings.ToLower(u.Scheme)
+    host := strings.ToLower(u.Hostname())
+    port := u.Port()
+    if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+        port = ""
+    }
+    if strings.Contains(host, ":") {
+        host = "[" + host + "]"
+    }
+    if port != "" {
+        host += ":" + port
+    }
+    u.Host = host
+    u.Fragment = ""
+    u.RawFragment = ""
+    if u.Path == "" {
+        u.Path = "/"
+    }
+    return u.String()
+}
+
+type countingReader struct {
+    r io.Reader
+    n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+    n, err := c.r.Read(p)
+    c.n += int64(n)
+    return n, err
+}
@@ -0,0 +1,170 @@
+package warc
+
+import (
+    "bytes"
+    "compress/gzip"
+    "errors"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "path/filepath"
+    "strconv"
+    "testing"
+)
+
+func TestArchive_ServesRecordedRedirectChain(t *testing.T) {
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path == "/old" {
+            http.Redirect(w, r, "/new", http.StatusFound)
+            return
+        }
+        w.Header().Set("Content-Type", "text/html; charset=utf-8")
+        _, _ = w.Write([]byte("<p>archived</p>"))
+    }))
+    defer srv.Close()
+
+    path := filepath.Join(t.TempDir(), "crawl.warc.gz")
+    w, err := Create(path, "test/1.0")
+    if err != nil {
+        t.Fatalf("create: %v", err)
+    }
+    resp, err := Client(srv.Client(), w).Get(srv.URL + "/old")
+    if err != nil {
+        t.Fatalf("get: %v", err)
+    }
+    _, _ = io.ReadAll(resp.Body)
+    resp.Body.Close()
+    _ = w.Close()
+
+    a, err := Open(path)
+    if err != nil {
+        t.Fatalf("open: %v", err)
+    }
+    if a.Len() != 2 {
+        t.Fatalf("expected 2 indexed URLs, got %d", a.Len())
+    }
+    got, err := a.Fetch(srv.URL + "/old#frag")
+    if err != nil {
+        t.Fatalf("fetch: %v", err)
+    }
+    if got.StatusCode != 200 || string(got.Body) != "<p>archived</p>" {
+        t.Fatalf("unexpected response: %d %q", got.StatusCode, got.Body)
+    }
+    if got.URL != srv.URL+"/new" || got.Header.Get("Content-Type") != "text/html; charset=utf-8" {
+        t.Fatalf("unexpected final url or type: %s %s", got.URL, got.Header.Get("Content-Type"))
+    }
+    if got.RecordID == "" {
+        t.Fatalf("expected record id")
+    }
+    if _, err := a.Fetch(srv.URL + "/missing"); !errors.Is(err, ErrNotFound) {
+        t.Fatalf("expected ErrNotFound, got %v", err)
+    }
+}
+
+// rawRecord frames a response record the way third-party crawlers do.
+func rawRecord(uri, id string, http string) string {
+    return "WARC/1.0\r\nWARC-Type: response\r\nWARC-Target-URI: <" + uri + ">\r\nWARC-Record-ID: " + id +
+        "\r\nContent-Type: application/http; msgtype=response\r\nContent-Length: " + strconv.Itoa(len(http)) + "\r\n\r\n" + http + "\r\n\r\n"
+}
+
+func TestArchive_ReadsUncompressedAndWholeFileGzip(t *testing.T) {
+    var gzBody bytes.Buffer
+    zw := gzip.NewWriter(&gzBody)
+    _, _ = zw.Write([]byte("compressed page"))
+    _ = zw.Close()
+    first := rawRecord("HTTP://Example.com:80", "<urn:uuid:1>", "HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\nContent-Encoding: gzip\r\nContent-Length: "+strconv.Itoa(gzBody.Len())+"\r\n\r\n"+gzBody.String())
+    second := rawRecord("https://example.org/b", "<urn:uuid:2>", "HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\nContent-Length: 6\r\n\r\nsecond")
+    dir := t.TempDir()
+
+    plain := filepath.Join(dir, "plain.warc")
+    if err := os.WriteFile(plain, []byte(first+second), 0o644); err != nil {
+        t.Fatalf("write: %v", err)
+    }
+    // One gzip stream around several records, as some tools produce.
+    var whole bytes.Buffer
+    zw = gzip.NewWriter(&whole)
+    _, _ = zw.Write([]byte(first + second))
+    _ = zw.Close()
+    single := filepath.Join(dir, "single.warc.gz")
+    if err := os.WriteFile(single, whole.Bytes(), 0o644); err != nil {
+        t.Fatalf("write: %v", err)
+    }
+
+    for _, p := range []string{plain, single} {
+        a, err := Open(p)
+        if err != nil {
+            t.Fatalf("%s: open: %v", p, err)
+        }
+        got, err := a.Fetch("http://example.com/")
+        if err != nil || string(got.Body) != "compressed page" || got.Header.Get("Content-Encoding") != "" {
+            t.Fatalf("%s: first record: %v %+v", p, err, got)
+        }
+        got, err = a.Fetch("https://example.org/b")
+        if err != nil || string(got.Body) != "second" || got.RecordID != "<urn:uuid:2>" {
+            t.Fatalf("%s: second record: %v %+v", p, err, got)
+        }
+    }
+}
+
+// Test a body closed before its end is recorded as truncated and never
+// replayed.
+func TestArchive_SkipsTruncatedRecords(t *testing.T) {
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Header().Set("Content-Type", "text/html")
+        _, _ = w.Write(bytes.Repeat([]byte("x"), 64<<10))
+    }))
+    defer srv.Close()
+
+    path := filepath.Join(t.TempDir(), "crawl.warc.gz")
+    w, err := Create(path, "test/1.0")
+    if err != nil {
+        t.Fatalf("create: %v", err)
+    }
+    resp, err := Client(srv.Client(), w).Get(srv.URL)
+    if err != nil {
+        t.Fatalf("get: %v", err)
+    }
+    _, _ = io.ReadFull(resp.Body, make([]byte, 100))
+    resp.Body.Close()
+    _ = w.Close()
+
+    a, err := Open(path)
+    if err != nil {
+        t.Fatalf("open: %v", err)
+    }
+    if _, err := a.Fetch(srv.URL); !errors.Is(err, ErrNotFound) {
+        t.Fatalf("expected truncated record to be skipped, got %v", err)
+    }
+}
+
+// Test BodyLimit rejects a long recorded body, declared or not and before
+// or after content decoding, and keeps one within the limit.
+func TestArchive_AppliesBodyLimit(t *testing.T) {
+    var gzBody bytes.Buffer
+    zw := gzip.NewWriter(&gzBody)
+    _, _ = zw.Write(bytes.Repeat([]byte("z"), 4096))
+    _ = zw.Close()
+    records := rawRecord("https://example.com/declared", "<urn:uuid:1>", "HTTP/1.1 200 OK\r\nContent-Type: application/pdf\r\nContent-Length: 4096\r\n\r\n"+string(bytes.Repeat([]byte("p"), 4096))) +
+        rawRecord("https://example.com/undeclared", "<urn:uuid:2>", "HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\n\r\n"+string(bytes.Repeat([]byte("t"), 4096))) +
+        rawRecord("https://example.com/gzip", "<urn:uuid:3>", "HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\nContent-Encoding: gzip\r\nContent-Length: "+strconv.Itoa(gzBody.Len())+"\r\n\r\n"+gzBody.String()) +
+        rawRecord("https://example.com/small", "<urn:uuid:4>", "HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\nContent-Length: 5\r\n\r\nsmall")
+    path := filepath.Join(t.TempDir(), "limits.warc")
+    if err := os.WriteFile(path, []byte(records), 0o644); err != nil {
+        t.Fatalf("write: %v", err)
+    }
+    a, err := Open(path)
+    if err != nil {
+        t.Fatalf("open: %v", err)
+    }
+    a.BodyLimit = func(string) int64 { return 1024 }
+    for _, u := range []string{"https://example.com/declared", "https://example.com/undeclared", "https://example.com/gzip"} {
+        var tl TooLargeError
+        if _, err := a.Fetch(u); !errors.As(err, &tl) || tl.Limit != 1024 {
+            t.Fatalf("%s: expected a TooLargeError, got %v", u, err)
+        }
+    }
+    if got, err := a.Fetch("https://example.com/small"); err != nil || string(got.Body) != "small" {
+        t.Fatalf("small record: %v %+v", err, got)
+    }
+}
@@ -0,0 +1,168 @@
+package warc
+
+import (
+    "bytes"
+    "context"
+    "io"
+    "net/http"
+    "net/http/httputil"
+    "sync"
+
+    "github.com/rs/zerolog/log"
+)
+
+// Transport is an http.RoundTripper that records every exchange it carries,
+// including each redirect hop, as a response (or, for 304, revisit) record
+// followed by the request record that produced it.
+type Transport struct {
+    Base   http.RoundTripper
+    Writer *Writer
+}
+
+func (t *Transport) base() http.RoundTripper {
+    if t.Base != nil {
+        return t.Base
+    }
+    return http.DefaultTransport
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+    if t.Writer == nil {
+        return t.base().RoundTrip(req)
+    }
+    resp, err := t.base().RoundTrip(req)
+    if err != nil {
+        return resp, err
+    }
+    // Serialize the request as the transport sends it. Bodies are not
+    // recorded: the clients wrapped here only issue GETs.
+    reqDump, derr := httputil.DumpRequestOut(req, false)
+    if derr != nil {
+        log.Warn().Err(derr).Str("url", req.URL.String()).Msg("warc: dump request failed")
+        return resp, nil
+    }
+    rec := &recording{t: t, req: req, reqDump: reqDump, resp: resp, body: resp.Body}
+    if resp.StatusCode == http.StatusNotModified {
+        rec.finish(false)
+        return resp, nil
+    }
+    // The body is recorded as the caller reads it, so the caller's size
+    // limits bound what is buffered here too.
+    resp.Body = rec
+    return resp, nil
+}
+
+// recording tees a response body into a buffer and writes the exchange
+// once the body is read to the end or closed. A body closed early is
+// recorded as far as it was read and marked truncated.
+type recording struct {
+    t       *Transport
+    req     *http.Request
+    reqDump []byte
+    resp    *http.Response
+    body    io.ReadCloser
+    buf     bytes.Buffer
+    once    sync.Once
+}
+
+func (r *recording) Read(p []byte) (int, error) {
+    n, err := r.body.Read(p)
+    r.buf.Write(p[:n])
+    if err == io.EOF {
+        r.once.Do(func() { r.finish(true) })
+    }
+    return n, err
+}
+
+func (r *recording) Close() error {
+    r.once.Do(func() { r.finish(false) })
+    return r.body.Close()
+}
+
+// finish writes the response (or, for 304, revisit) record and the request
+// record that produced it. complete reports whether the body was read to
+// the end.
+func (r *recording) finish(complete bool) {
+    uri := r.req.URL.String()
+    rec := Record{Type: TypeResponse, TargetURI: uri, ContentType: "application/http;msgtype=response"}
+    withBody := true
+    if r.resp.StatusCode == http.StatusNotModified {
+        rec.Type = TypeRevisit
+        rec.Headers = map[string]string{"WARC-Profile": profileServerNotModified, "WARC-Refers-To-Target-URI": uri}
+        withBody = false
+    }
+    // Serialize a copy carrying the bytes read so far; the caller's
+    // response keeps streaming from the original body.
+    resp := *r.resp
+    if withBody {
+        resp.Body = io.NopCloser(bytes.NewReader(r.buf.Bytes()))
+        if !complete {
+            resp.ContentLength = int64(r.buf.Len())
+            rec.Headers = map[string]string{"WARC-Truncated": "length"}
+        }
+    }
+    respDump, derr := httputil.DumpResponse(&resp, withBody)
+    if derr != nil {
+        log.Warn().Err(derr).Str("url", uri).Msg("warc: dump response failed")
+        return
+    }
+    rec.Block = respDump
+    id, werr := r.t.Writer.Write(rec)
+    if werr != nil {
+        log.Warn().Err(werr).Str("url", uri).Msg("warc: write response failed")
+        return
+    }
+    if _, werr := r.t.Writer.Write(Record{Type: TypeRequest, TargetURI: uri, ContentType: "application/http;msgtype=request", Headers: map[string]string{"WARC-Concurrent-To": id}, Block: r.reqDump}); werr != nil {
+        log.Warn().Err(werr).Str("url", uri).Msg("warc: write request failed")
+    }
+    if tr, ok := r.req.Context().Value(trackerKey{}).(*Tracker); ok {
+        tr.set(id)
+    }
+}
+
+// Client returns a shallow copy of c whose transport records to w. A nil w
+// returns c unchanged.
+func Client(c *http.Client, w *Writer) *http.Client {
+    if w == nil {
+        return c
+    }
+    if c == nil {
+        c = &http.Client{}
+    }
+    out := *c
+    out.Transport = &Transport{Base: c.Transport, Writer: w}
+    return &out
+}
+
+type trackerKey struct{}
+
+// Tracker collects the record ID of the last response recorded for requests
+// made with its context, which after redirects is the final response.
+type Tracker struct {
+    mu sync.Mutex
+    id string
+}
+
+// Track returns a context whose recorded responses are reported to the
+// returned Tracker.
+func Track(ctx context.Context) (context.Context, *Tracker) {
+    t := &Tracker{}
+    return context.WithValue(ctx, trackerKey{}, t), t
+}
+
+func (t *Tracker) set(id string) {
+    t.mu.Lock()
+    t.id = id
+    t.mu.Unlock()
+}
+
+// ResponseID returns the WARC-Record-ID of the last recorded response, or ""
+// when nothing was recorded.
+func (t *Tracker) ResponseID() string {
+    if t == nil {
+        return ""
+    }
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    return t.id
+}
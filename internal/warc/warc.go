@@ -0,0 +1,164 @@
+// Package warc records HTTP exchanges as WARC/1.1 files (ISO 28500), the
+// format read by pywb, warcio and other web archive tools, and serves
+// recorded responses back from such files.
+//
+// Each record is written as its own gzip member, so the output is a standard
+// .warc.gz that tools can seek into by record offset.
+package warc
+
+import (
+    "bytes"
+    "compress/gzip"
+    "crypto/rand"
+    "crypto/sha1"
+    "encoding/base32"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "sort"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+)
+
+// Record types written by this package.
+const (
+    TypeWarcinfo = "warcinfo"
+    TypeRequest  = "request"
+    TypeResponse = "response"
+    TypeRevisit  = "revisit"
+)
+
+// profileServerNotModified marks a revisit record for a 304 response.
+const profileServerNotModified = "http://netpreserve.org/warc/1.1/revisit/server-not-modified"
+
+// Record is one WARC record. Headers holds extra named fields such as
+// WARC-Concurrent-To; the mandatory fields are filled in by the Writer.
+type Record struct {
+    Type        string
+    TargetURI   string
+    ContentType string
+    Headers     map[string]string
+    Block       []byte
+}
+
+// Writer appends gzip-compressed WARC records to a file. It is safe for
+// concurrent use.
+type Writer struct {
+    mu   sync.Mutex
+    f    *os.File
+    path string
+    now  func() time.Time
+}
+
+// Create creates the WARC file at path, replacing any existing file, and
+// writes a warcinfo record describing the software that produced it.
+func Create(path string, software string) (*Writer, error) {
+    if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+        return nil, fmt.Errorf("warc: mkdir: %w", err)
+    }
+    f, err := os.Create(path)
+    if err != nil {
+        return nil, fmt.Errorf("warc: create: %w", err)
+    }
+    w := &Writer{f: f, path: path, now: time.Now}
+    info := "software: " + software + "\r\nformat: WARC File Format 1.1\r\nconformsTo: http://iipc.github.io/warc-specifications/specifications/warc-format/warc-1.1/\r\n"
+    if _, err := w.Write(Record{Type: TypeWarcinfo, ContentType: "application/warc-fields", Headers: map[string]string{"WARC-Filename": filepath.Base(path)}, Block: []byte(info)}); err != nil {
+        f.Close()
+        return nil, err
+    }
+    return w, nil
+}
+
+// Path returns the file the writer appends to.
+func (w *Writer) Path() string { return w.path }
+
+// Write appends rec and returns its WARC-Record-ID.
+func (w *Writer) Write(rec Record) (string, error) {
+    id := NewRecordID()
+    var head strings.Builder
+    head.WriteString("WARC/1.1\r\n")
+    writeField(&head, "WARC-Type", rec.Type)
+    writeField(&head, "WARC-Record-ID", id)
+    writeField(&head, "WARC-Date", w.now().UTC().Format(time.RFC3339Nano))
+    if rec.TargetURI != "" {
+        writeField(&head, "WARC-Target-URI", rec.TargetURI)
+    }
+    // Extra fields in a stable order so files are reproducible.
+    names := make([]string, 0, len(rec.Headers))
+    for k := range rec.Headers {
+        names = append(names, k)
+    }
+    sort.Strings(names)
+    for _, k := range names {
+        writeField(&head, k, rec.Headers[k])
+    }
+    if rec.Type != TypeWarcinfo {
+        writeField(&head, "WARC-Block-Digest", blockDigest(rec.Block))
+    }
+    if rec.ContentType != "" {
+        writeField(&head, "Content-Type", rec.ContentType)
+    }
+    writeField(&head, "Content-Length", strconv.Itoa(len(rec.Block)))
+    head.WriteString("\r\n")
+
+    var buf bytes.Buffer
+    gz := gzip.NewWriter(&buf)
+    _, _ = io.WriteString(gz, head.String())
+    _, _ = gz.Write(rec.Block)
+    _, _ = io.WriteString(gz, "\r\n\r\n")
+    if err := gz.Close(); err != nil {
+        return "", fmt.Errorf("warc: compress: %w", err)
+    }
+
+    w.mu.Lock()
+    defer w.mu.Unlock()
+    if w.f == nil {
+        return "", fmt.Errorf("warc: writer closed")
+    }
+    if _, err := w.f.Write(buf.Bytes()); err != nil {
+        return "", fmt.Errorf("warc: write: %w", err)
+    }
+    return id, nil
+}
+
+// Close flushes and closes the file. It is safe to call more than once.
+func (w *Writer) Close() error {
+    if w == nil {
+        return nil
+    }
+    w.mu.Lock()
+    defer w.mu.Unlock()
+    if w.f == nil {
+        return nil
+    }
+    err := w.f.Close()
+    w.f = nil
+    return err
+}
+
+// NewRecordID returns a fresh WARC-Record-ID of the form <urn:uuid:...>.
+func NewRecordID() string {
+    var b [16]byte
+    _, _ = rand.Read(b[:])
+    b[6] = (b[6] & 0x0f) | 0x40 // version 4
+    b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+    return fmt.Sprintf("<urn:uuid:%x-%x-%x-%x-%x>", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+func writeField(b *strings.Builder, name, value string) {
+    // Header values must not break the record framing.
+    value = strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+    b.WriteString(name)
+    b.WriteString(": ")
+    b.WriteString(value)
+    b.WriteString("\r\n")
+}
+
+// blockDigest uses the SHA-1/base32 form most archive tools expect.
+func blockDigest(block []byte) string {
+    sum := sha1.Sum(block)
+    return "sha1:" + base32.StdEncoding.EncodeToString(sum[:])
+}
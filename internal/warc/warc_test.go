@@ -0,0 +1,174 @@
+package warc
+
+import (
+    "bufio"
+    "compress/gzip"
+    "context"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+    "testing"
+)
+
+type readRecord struct {
+    fields map[string]string
+    block  string
+}
+
+// readAll parses every record of a .warc.gz written by Writer.
+func readAll(t *testing.T, path string) []readRecord {
+    t.Helper()
+    f, err := os.Open(path)
+    if err != nil {
+        t.Fatalf("open: %v", err)
+    }
+    defer f.Close()
+    gz, err := gzip.NewReader(f)
+    if err != nil {
+        t.Fatalf("gzip: %v", err)
+    }
+    br := bufio.NewReader(gz)
+    var out []readRecord
+    for {
+        line, err := br.ReadString('\n')
+        if err == io.EOF {
+            return out
+        }
+        if err != nil {
+            t.Fatalf("read: %v", err)
+        }
+        if line != "WARC/1.1\r\n" {
+            t.Fatalf("unexpected version line %q", line)
+        }
+        rec := readRecord{fields: map[string]string{}}
+        for {
+            l, err := br.ReadString('\n')
+            if err != nil {
+                t.Fatalf("read header: %v", err)
+            }
+            if l == "\r\n" {
+                break
+            }
+            k, v, _ := strings.Cut(strings.TrimRight(l, "\r\n"), ": ")
+            rec.fields[k] = v
+        }
+        n, _ := strconv.Atoi(rec.fields["Content-Length"])
+        block := make([]byte, n+4)
+        if _, err := io.ReadFull(br, block); err != nil {
+            t.Fatalf("read block: %v", err)
+        }
+        if string(block[n:]) != "\r\n\r\n" {
+            t.Fatalf("missing record terminator")
+        }
+        rec.block = string(block[:n])
+        out = append(out, rec)
+    }
+}
+
+func TestTransport_RecordsRedirectChainAndTracksFinalResponse(t *testing.T) {
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.URL.Path == "/old" {
+            http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+            return
+        }
+        w.Header().Set("Content-Type", "text/html")
+        _, _ = w.Write([]byte("<p>hello</p>"))
+    }))
+    defer srv.Close()
+
+    path := filepath.Join(t.TempDir(), "out.warc.gz")
+    w, err := Create(path, "test/1.0")
+    if err != nil {
+        t.Fatalf("create: %v", err)
+    }
+    ctx, tr := Track(context.Background())
+    req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/old", nil)
+    resp, err := Client(srv.Client(), w).Do(req)
+    if err != nil {
+        t.Fatalf("get: %v", err)
+    }
+    body, _ := io.ReadAll(resp.Body)
+    resp.Body.Close()
+    if string(body) != "<p>hello</p>" {
+        t.Fatalf("caller body changed: %q", body)
+    }
+    if err := w.Close(); err != nil {
+        t.Fatalf("close: %v", err)
+    }
+
+    recs := readAll(t, path)
+    var types []string
+    for _, r := range recs {
+        types = append(types, r.fields["WARC-Type"])
+    }
+    if got := strings.Join(types, ","); got != "warcinfo,response,request,response,request" {
+        t.Fatalf("record types = %s", got)
+    }
+    if recs[0].fields["WARC-Filename"] != "out.warc.gz" || !strings.Contains(recs[0].block, "software: test/1.0") {
+        t.Fatalf("unexpected warcinfo: %+v", recs[0])
+    }
+    if !strings.HasPrefix(recs[1].block, "HTTP/1.1 301") || recs[1].fields["WARC-Target-URI"] != srv.URL+"/old" {
+        t.Fatalf("first response should be the redirect: %+v", recs[1])
+    }
+    for _, i := range []int{2, 4} {
+        if recs[i].fields["WARC-Concurrent-To"] != recs[i-1].fields["WARC-Record-ID"] {
+            t.Fatalf("request %d not linked to its response", i)
+        }
+        if !strings.HasPrefix(recs[i].block, "GET ") {
+            t.Fatalf("request block = %q", recs[i].block)
+        }
+    }
+    final := recs[3]
+    if !strings.HasSuffix(final.block, "<p>hello</p>") || final.fields["WARC-Target-URI"] != srv.URL+"/new" {
+        t.Fatalf("final response not recorded with body: %+v", final)
+    }
+    if got := tr.ResponseID(); got == "" || got != final.fields["WARC-Record-ID"] {
+        t.Fatalf("tracker id = %q, want %q", got, final.fields["WARC-Record-ID"])
+    }
+    if final.fields["WARC-Block-Digest"] != blockDigest([]byte(final.block)) {
+        t.Fatalf("block digest mismatch")
+    }
+}
+
+func TestTransport_RecordsNotModifiedAsRevisit(t *testing.T) {
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(http.StatusNotModified)
+    }))
+    defer srv.Close()
+
+    path := filepath.Join(t.TempDir(), "out.warc.gz")
+    w, err := Create(path, "test/1.0")
+    if err != nil {
+        t.Fatalf("create: %v", err)
+    }
+    resp, err := Client(srv.Client(), w).Get(srv.URL)
+    if err != nil {
+        t.Fatalf("get: %v", err)
+    }
+    resp.Body.Close()
+    _ = w.Close()
+
+    recs := readAll(t, path)
+    if len(recs) != 3 {
+        t.Fatalf("expected 3 records, got %d", len(recs))
+    }
+    rv := recs[1]
+    if rv.fields["WARC-Type"] != TypeRevisit || rv.fields["WARC-Profile"] != profileServerNotModified {
+        t.Fatalf("expected server-not-modified revisit, got %+v", rv.fields)
+    }
+}
+
+func TestNilWriterLeavesClientUnchanged(t *testing.T) {
+    c := &http.Client{}
+    if Client(c, nil) != c {
+        t.Fatalf("nil writer should return the client as is")
+    }
+    var w *Writer
+    if err := w.Close(); err != nil {
+        t.Fatalf("nil close: %v", err)
+    }
+}